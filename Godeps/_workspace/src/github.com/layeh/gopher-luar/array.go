@@ -0,0 +1,47 @@
+package luar
+
+import (
+	"reflect"
+
+	"github.com/yuin/gopher-lua"
+)
+
+func arrayLen(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	array := reflect.Indirect(reflect.ValueOf(ud.Value))
+	L.Push(lua.LNumber(array.Len()))
+	return 1
+}
+
+func arrayNewIndex(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	index := L.CheckInt(2)
+	value := L.CheckAny(3)
+
+	array := reflect.Indirect(reflect.ValueOf(ud.Value))
+	if index < 1 || index > array.Len() {
+		L.ArgError(2, "index out-of-range")
+	}
+	if array.CanSet() {
+		array.Index(index - 1).Set(lValueToReflect(L, value, array.Type().Elem()))
+		return 0
+	}
+	// arrays are values, so set the element on a copy and keep that
+	copied := reflect.New(array.Type()).Elem()
+	copied.Set(array)
+	copied.Index(index - 1).Set(lValueToReflect(L, value, array.Type().Elem()))
+	ud.Value = copied.Interface()
+	return 0
+}
+
+func arrayIndex(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	index := L.CheckInt(2)
+
+	array := reflect.Indirect(reflect.ValueOf(ud.Value))
+	if index < 1 || index > array.Len() {
+		L.ArgError(2, "index out-of-range")
+	}
+	L.Push(New(L, array.Index(index-1).Interface()))
+	return 1
+}
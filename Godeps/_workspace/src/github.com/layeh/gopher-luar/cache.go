@@ -0,0 +1,100 @@
+package luar
+
+import (
+	"reflect"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// Each struct type (and pointer type) handed to a state gets a typeInfo of
+// its own, made the first time a value of the type is: its metatable, and
+// what field and method lookups need to know, so handlers that touch the same
+// fields on every call don't walk reflect each time. The typeInfos live in
+// the state's registry.
+
+type typeInfo struct {
+	metatable *lua.LTable
+	fields    structFields // of the struct, or the struct pointed to
+	methods   map[string]*typeMethod
+}
+
+// typeMethod is a method of the type, or of a pointer to it
+type typeMethod struct {
+	index int            // in the method set
+	ptr   bool           // in the pointer's method set, not the value's
+	fn    *lua.LFunction // what indexing a value with the method's name gives
+	name  string         // for errors, like Lua calls it (e.g. Event:Reply)
+}
+
+const typeInfoKey = lua.LString("github.com/layeh/gopher-luar/types")
+
+// getTypeInfo returns the state's typeInfo for t, making it if it's new
+func getTypeInfo(L *lua.LState, t reflect.Type) *typeInfo {
+	ud, ok := L.G.Registry.RawGetH(typeInfoKey).(*lua.LUserData)
+	if !ok {
+		ud = L.NewUserData()
+		ud.Value = make(map[reflect.Type]*typeInfo)
+		L.G.Registry.RawSetH(typeInfoKey, ud)
+	}
+	types := ud.Value.(map[reflect.Type]*typeInfo)
+	if info, ok := types[t]; ok {
+		return info
+	}
+	info := newTypeInfo(L, t)
+	types[t] = info
+	return info
+}
+
+// clearTypeInfo forgets the state's typeInfos, for when the Config they were
+// made with changes
+func clearTypeInfo(L *lua.LState) {
+	L.G.Registry.RawSetH(typeInfoKey, lua.LNil)
+}
+
+func newTypeInfo(L *lua.LState, t reflect.Type) *typeInfo {
+	info := &typeInfo{
+		metatable: L.NewTable(),
+		methods:   make(map[string]*typeMethod),
+	}
+	kind := "struct"
+	structType := t
+	if t.Kind() == reflect.Ptr {
+		kind = "ptr"
+		structType = t.Elem()
+	}
+	generic := ensureMetatable(L).RawGetH(lua.LString(kind)).(*lua.LTable)
+	generic.ForEach(func(key, value lua.LValue) {
+		info.metatable.RawSetH(key, value)
+	})
+	if structType.Kind() != reflect.Struct {
+		return info
+	}
+
+	info.fields = structFieldsOf(L, structType)
+	for i := 0; i < t.NumMethod(); i++ {
+		info.addMethod(L, structType, t.Method(i).Name, i, false)
+	}
+	if t.Kind() == reflect.Struct {
+		// pointer methods are called on a copy of a struct value
+		ptrType := reflect.PtrTo(t)
+		for i := 0; i < ptrType.NumMethod(); i++ {
+			if _, ok := info.methods[ptrType.Method(i).Name]; !ok {
+				info.addMethod(L, structType, ptrType.Method(i).Name, i, true)
+			}
+		}
+	}
+	info.addOperators(L, t)
+	if structType == timeType {
+		info.addTimeMethods(L)
+	}
+	return info
+}
+
+func (info *typeInfo) addMethod(L *lua.LState, structType reflect.Type, name string, index int, ptr bool) {
+	info.methods[name] = &typeMethod{
+		index: index,
+		ptr:   ptr,
+		fn:    L.NewClosure(structMethod, lua.LString(name)),
+		name:  structType.Name() + ":" + name,
+	}
+}
@@ -3,12 +3,16 @@ package luar
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/yuin/gopher-lua"
 )
 
 func chanToString(L *lua.LState) int {
 	ud := L.CheckUserData(1)
+	if stringerToString(L, ud.Value) {
+		return 1
+	}
 	value := reflect.ValueOf(ud.Value)
 
 	str := fmt.Sprintf("userdata: luar: %s (%p)", value.Type(), ud.Value)
@@ -21,24 +25,66 @@ func chanSend(L *lua.LState) int {
 	channel := reflect.ValueOf(ud.Value)
 
 	lData := L.Get(2)
-	data := lValueToReflect(lData, channel.Type().Elem())
+	data := lValueToReflect(L, lData, channel.Type().Elem())
 	channel.Send(data)
 
 	return 0
 }
 
+// chanReceive receives from the channel, waiting at most the number of
+// seconds given, if any
 func chanReceive(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 	channel := reflect.ValueOf(ud.Value)
 
-	value, ok := channel.Recv()
-	if !ok {
+	if L.GetTop() < 2 {
+		checkBlocking(L, "receive")
+		return chanRecv(L, channel, -1)
+	}
+	seconds := float64(L.CheckNumber(2))
+	if seconds < 0 {
+		L.ArgError(2, "timeout must not be negative")
+	}
+	if seconds > 0 {
+		checkBlocking(L, "receive")
+	}
+	return chanRecv(L, channel, time.Duration(seconds*float64(time.Second)))
+}
+
+func chanTryReceive(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	channel := reflect.ValueOf(ud.Value)
+	return chanRecv(L, channel, 0)
+}
+
+// chanRecv receives from the channel, waiting at most timeout (forever if
+// it's negative, not at all if it's 0). It pushes the value and true, nil
+// and false if the channel's closed, or nil and nil if nothing came.
+func chanRecv(L *lua.LState, channel reflect.Value, timeout time.Duration) int {
+	cases := []reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: channel},
+	}
+	switch {
+	case timeout == 0:
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	case timeout > 0:
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)})
+	}
+
+	chosen, value, ok := reflect.Select(cases)
+	switch {
+	case chosen != 0:
+		L.Push(lua.LNil)
+		L.Push(lua.LNil)
+	case !ok:
 		L.Push(lua.LNil)
 		L.Push(lua.LBool(false))
-		return 2
+	default:
+		L.Push(New(L, value.Interface()))
+		L.Push(lua.LBool(true))
 	}
-	L.Push(New(L, value.Interface()))
-	L.Push(lua.LBool(true))
 	return 2
 }
 
@@ -57,6 +103,8 @@ func chanIndex(L *lua.LState) int {
 		L.Push(L.NewFunction(chanSend))
 	case "receive":
 		L.Push(L.NewFunction(chanReceive))
+	case "tryreceive":
+		L.Push(L.NewFunction(chanTryReceive))
 	case "close":
 		L.Push(L.NewFunction(chanClose))
 	default:
@@ -64,3 +112,42 @@ func chanIndex(L *lua.LState) int {
 	}
 	return 1
 }
+
+// Select waits for a value from any of the channels it's given, like a Go
+// select statement with a receive case for each of them. It returns the
+// position of the channel that fired (counting from 1), the value, and
+// false instead of true if it fired because it was closed. Like in Go, nil
+// channels are never ready.
+func Select(L *lua.LState) int {
+	n := L.GetTop()
+	if n == 0 {
+		L.RaiseError("select needs at least one channel")
+	}
+	checkBlocking(L, "select")
+	cases := make([]reflect.SelectCase, n)
+	for i := 1; i <= n; i++ {
+		cases[i-1].Dir = reflect.SelectRecv
+		if L.Get(i) == lua.LNil {
+			continue
+		}
+		ud, ok := L.Get(i).(*lua.LUserData)
+		if !ok {
+			L.ArgError(i, "channel expected")
+		}
+		channel := reflect.ValueOf(ud.Value)
+		if channel.Kind() != reflect.Chan || channel.Type().ChanDir()&reflect.RecvDir == 0 {
+			L.ArgError(i, "channel expected")
+		}
+		cases[i-1].Chan = channel
+	}
+
+	chosen, value, ok := reflect.Select(cases)
+	L.Push(lua.LNumber(chosen + 1))
+	if ok {
+		L.Push(New(L, value.Interface()))
+	} else {
+		L.Push(lua.LNil)
+	}
+	L.Push(lua.LBool(ok))
+	return 3
+}
@@ -0,0 +1,61 @@
+package luar
+
+import (
+	"github.com/yuin/gopher-lua"
+)
+
+// FieldNames is how struct fields without a luar tag are named in Lua.
+type FieldNames int
+
+const (
+	// GoNames names fields by their Go names (the default).
+	GoNames FieldNames = iota
+	// JSONNames names fields by their json tags, where they have one, so
+	// ChannelID `json:"channel_id"` is channel_id; fields tagged json:"-" are
+	// hidden.
+	JSONNames
+)
+
+// Config is how luar behaves in a state.
+type Config struct {
+	// ErrorMode is how Go functions return errors (see SetErrorMode).
+	ErrorMode ErrorMode
+	// FieldNames is how struct fields are named.
+	FieldNames FieldNames
+	// NoBlocking makes receive and select raise an error rather than wait,
+	// for states that mustn't be held up (tryreceive, and receive(0), still
+	// work).
+	NoBlocking bool
+}
+
+const fieldNamesKey = lua.LString("github.com/layeh/gopher-luar/fieldnames")
+const noBlockingKey = lua.LString("github.com/layeh/gopher-luar/noblocking")
+
+// SetConfig sets the state's configuration.
+func SetConfig(L *lua.LState, config Config) {
+	SetErrorMode(L, config.ErrorMode)
+	L.G.Registry.RawSetH(fieldNamesKey, lua.LNumber(config.FieldNames))
+	L.G.Registry.RawSetH(noBlockingKey, lua.LBool(config.NoBlocking))
+	// the cached types have their fields named the old way
+	clearTypeInfo(L)
+}
+
+// GetConfig returns the state's configuration.
+func GetConfig(L *lua.LState) Config {
+	config := Config{
+		ErrorMode: GetErrorMode(L),
+	}
+	if names, ok := L.G.Registry.RawGetH(fieldNamesKey).(lua.LNumber); ok {
+		config.FieldNames = FieldNames(names)
+	}
+	config.NoBlocking = lua.LVAsBool(L.G.Registry.RawGetH(noBlockingKey))
+	return config
+}
+
+// checkBlocking raises an error if the state can't block (see
+// Config.NoBlocking)
+func checkBlocking(L *lua.LState, what string) {
+	if lua.LVAsBool(L.G.Registry.RawGetH(noBlockingKey)) {
+		L.RaiseError("%s would wait, which isn't allowed in this state", what)
+	}
+}
@@ -14,22 +14,70 @@
 //  New(L, "Hello World") -> lua.LString("Hello World")
 //  New(L, uint(834))     -> lua.LNumber(uint(834))
 //
+// Other values become userdata. tostring (and so print) gives what a value's
+// Error or String method returns, if it's an error or a fmt.Stringer, and
+// something like "userdata: luar: main.Person {Name:John} (0xc20802a0c0)"
+// if it's neither.
+//
+// Array types
+//
+// Like slices, arrays can be indexed (one-based), modified, iterated over,
+// and have their length queried. Arrays are values, so modifying one from Lua modifies the
+// Lua value's copy (pass a pointer to the array to modify the original).
+//
+// Example:
+//  type Packet struct {
+//    Header [4]byte
+//  }
+//  p := Packet{Header: [4]byte{0xCA, 0xFE, 0xBA, 0xBE}}
+//  L.SetGlobal("p", New(L, p))
+//  ---
+//  print(#p.Header)   -- prints "4"
+//  print(p.Header[1]) -- prints "202"
+//
 // Channel types
 //
 // Channel types have the following methods defined:
-//  receive():    Receives data from the channel. Returns nil plus false if the
-//                channel is closed.
-//  send(data):   Sends data to the channel.
-//  close():      Closes the channel.
+//  receive([timeout]): Receives data from the channel. Returns nil plus false
+//                      if the channel is closed. With a timeout (in seconds),
+//                      returns nil plus nil if nothing was received in time.
+//  tryreceive():       Receives data from the channel if there's some ready,
+//                      without waiting. Returns nil plus nil if there isn't.
+//  send(data):         Sends data to the channel.
+//  close():            Closes the channel.
 //
 // Example:
 //  ch := make(chan string)
 //  L.SetGlobal("ch", New(L, ch))
 //  ---
-//  ch:receive()      -- equivalent to v, ok := ch
+//  ch:receive()      -- equivalent to v, ok := <-ch
+//  ch:receive(0.5)   -- equivalent to a select on <-ch and time.After(500 * time.Millisecond)
+//  ch:tryreceive()   -- equivalent to a select on <-ch with a default case
 //  ch:send("hello")  -- equivalent to ch <- "hello"
 //  ch:close()        -- equivalent to close(ch)
 //
+// A plain receive blocks the whole Lua state until something's sent, so code
+// that can't wait forever (an event handler, say) should use a timeout or
+// tryreceive. A state that mustn't wait at all can be set up with
+// Config{NoBlocking: true}, and then receive (with or without a timeout) and
+// select raise an error instead; tryreceive still works.
+//
+// To wait on several channels at once, Select (which is luar.select in Lua,
+// once Loader is preloaded) returns whichever fires first: its position in
+// the arguments, the value received, and false if the channel was closed:
+//  L.PreloadModule("luar", Loader)
+//  L.SetGlobal("replies", New(L, replies))
+//  L.SetGlobal("timeout", New(L, time.After(time.Second)))
+//  ---
+//  local luar = require("luar")
+//  local i, reply, ok = luar.select(replies, timeout)
+//  if i == 2 then
+//    print("no reply in time")
+//  end
+//
+// New passes lua.LGFunctions (like Select) through as Lua functions, rather
+// than wrapping them like other Go functions.
+//
 // Function types
 //
 // Function types can be called from Lua. Its arguments and returned values
@@ -43,13 +91,121 @@
 //  ---
 //  print(fn("Tim", 5)) -- prints "Hello Tim, age 5"
 //
+// Extra arguments to a variadic function are collected into its variadic
+// slice. A Go slice passed as the last argument is used as the variadic slice
+// itself, as with f(s...) in Go, unless it could be one of its elements (as
+// with ...interface{}).
+//
+// Example:
+//  fn := func(sep string, parts ...string) string {
+//    return strings.Join(parts, sep)
+//  }
+//  L.SetGlobal("fn", New(L, fn))
+//  L.SetGlobal("parts", New(L, []string{"a", "b"}))
+//  ---
+//  print(fn("-", "a", "b", "c")) -- prints "a-b-c"
+//  print(fn("-", parts))         -- prints "a-b"
+//
+// By default, a function's trailing error return value is returned to Lua
+// like any other value. SetErrorMode changes that for a state: with
+// ErrorRaise, a non-nil error is raised as a Lua error (so it can be caught
+// with pcall); with ErrorNil, nil and the error's message are returned
+// instead, as Lua's own libraries do. Either way, a nil error is left off the
+// returned values.
+//
+// Example:
+//  fn := func(s string) (int, error) {
+//    return strconv.Atoi(s)
+//  }
+//  L.SetGlobal("fn", New(L, fn))
+//  SetErrorMode(L, ErrorNil)
+//  ---
+//  print(fn("5"))    -- prints "5"
+//  print(fn("five")) -- prints "nil  strconv.Atoi: parsing "five": invalid syntax"
+//
+// A Lua table can be passed where a function expects a struct (or a pointer
+// to one). A new struct is created, and the table's values are assigned to
+// the fields of the same name; other keys are ignored.
+//
+// Example:
+//  type Person struct {
+//    Name string
+//    Age  int
+//  }
+//  fn := func(p Person) string {
+//    return fmt.Sprintf("Hello %s, age %d", p.Name, p.Age)
+//  }
+//  L.SetGlobal("fn", New(L, fn))
+//  ---
+//  print(fn({Name = "Tim", Age = 5})) -- prints "Hello Tim, age 5"
+//
+// Tables are also converted wherever a map, slice, or array is expected
+// (function arguments, struct fields, map values, ...), along with their
+// contents. Slices and arrays take sequences ({1, 2, 3}). If a value cannot
+// be converted, an error saying where it is in the table is raised.
+//
+// Example:
+//  type Config struct {
+//    Labels map[string]string
+//    Ports  []int
+//  }
+//  L.SetGlobal("config", New(L, &Config{}))
+//  ---
+//  config.Labels = {env = "prod"}
+//  config.Ports = {80, "https"} -- error: cannot use string as int (at [2])
+//
+// When the value is a function argument, the error names the function and
+// says which argument it is, so a script that passes the wrong thing fails
+// only that call, with an error it can catch with pcall. So does calling a
+// function with the wrong number of arguments. Methods are named like Lua
+// calls them (e.g. Post:Send).
+//
+// Example:
+//  L.SetGlobal("rep", New(L, strings.Repeat))
+//  ---
+//  rep("ab", {}) -- error: bad argument #2 to strings.Repeat (cannot use table as int)
+//  rep("ab")     -- error: wrong number of arguments to strings.Repeat (2 expected, got 1)
+//
+// A Lua function can be passed where a Go function is expected (e.g. a
+// callback). Its arguments are converted to Lua values, and its return values
+// back to Go ones; a string can be returned for an error. An error raised by
+// the Lua function is returned if the Go function returns an error, otherwise
+// it panics. The Lua function runs on the LState it came from, so the Go
+// function must only be called when nothing else is using that LState.
+//
+// Example:
+//  fn := func(names []string, check func(string) error) error {
+//    for _, name := range names {
+//      if err := check(name); err != nil {
+//        return err
+//      }
+//    }
+//    return nil
+//  }
+//  L.SetGlobal("fn", New(L, fn))
+//  ---
+//  local err = fn({"Tim", ""}, function(name)
+//    if name == "" then return "empty name" end
+//  end)
+//  print(err:Error()) -- prints "empty name"
+//
 // Map types
 //
 // Map types can be accessed and modified like a normal Lua table a meta table.
 // Its length can also be queried using the # operator.
 //
-// Rather than using pairs to create an map iterator, calling the value (e.g.
-// map_variable()) will return an iterator for the map.
+// pairs iterates over a map, in key order if its keys are numbers or strings.
+// Calling the value (e.g. map_variable()) also returns an iterator for the
+// map, as it did before pairs worked. (gopher-lua's pairs and ipairs don't
+// look for __pairs and __ipairs, so the first time a state is given a Go
+// value, luar wraps them with ones that do.)
+//
+// The following methods are defined for maps (an item with the same name
+// hides a method):
+//  keys():    Returns a slice of the map's keys. Numbers and strings are
+//             sorted.
+//  values():  Returns a slice of the map's values, in the same order as
+//             keys().
 //
 // Example:
 //  places := map[string]string{
@@ -61,30 +217,49 @@
 //  print(#places)       -- prints "2"
 //  print(places.NA)     -- prints "North America"
 //  print(places["EU"])  -- prints "European Union"
-//  for k, v = places() do
+//  for k, v in pairs(places) do
 //    print(k .. ": " .. v)
 //  end
+//  print(places:keys()[1])  -- prints "EU"
 //
 // Slice types
 //
 // Like map types, slices be accessed, be modified, and have their length
-// queried. Additionally, the following methods are defined for slices:
+// queried, and ipairs (or pairs) iterates over them. Additionally, the
+// following methods are defined for slices:
 //  append(items...):   Appends the items to the slice. Returns a slice with
 //                      the items appended.
 //  capacity():         Returns the slice capacity.
+//  insert([i,] item):  Inserts the item at index i (or at the end). Returns a
+//                      new slice with the item inserted.
+//  remove([i]):        Removes the item at index i (or the last item).
+//                      Returns a new slice without the item, and the item.
+//  sub(i [, j]):       Returns the slice from index i to index j (or the
+//                      end), inclusive. Negative indices count back from the
+//                      end, as with string.sub. The result shares the
+//                      original slice's items.
+//  sort([less]):       Sorts the slice in place. less(a, b) says whether a
+//                      goes before b; without it, numbers and strings are
+//                      sorted in ascending order.
 //
-// For consistency with other Lua code, slices use one-based indexing.
+// For consistency with other Lua code, slices use one-based indexing, and
+// insert, remove and sort work like table.insert, table.remove and
+// table.sort.
 //
 // Example:
 //  letters := []string{"a", "e", "i"}
 //  L.SetGlobal("letters", New(L, letters))
 //  ---
 //  letters = letters:append("o", "u")
+//  letters = letters:insert(1, "y")
+//  letters, removed = letters:remove()  -- removed is "u"
+//  print(#letters:sub(2, -2))           -- prints "3"
+//  letters:sort(function(a, b) return a > b end)
 //
 // Struct types
 //
 // Struct types can have their fields accessed and modified and their methods
-// called.
+// called. pairs iterates over their fields, in name order.
 //
 // Example:
 //  type Person {
@@ -99,6 +274,88 @@
 //  ---
 //  tim:SayHello()
 //
+// Methods with pointer receivers can be called on struct values too. Like
+// arrays, struct values are copied, so those methods (and setting fields)
+// change the Lua value's copy, not the original; pass a pointer to change the
+// original.
+//
+// Example:
+//  func (p *Person) Rename(name string) {
+//    p.Name = name
+//  }
+//
+//  L.SetGlobal("tim", New(L, tim))
+//  ---
+//  tim:Rename("Timothy")
+//  tim:SayHello() -- prints "Hello, Timothy"
+//
+// Structs (and pointers to them) can opt into Lua's operators with methods
+// named for them: Add (+), Sub (-), Mul (*), Div (/), Mod (%), Neg (unary -),
+// Equal (==), Less (<), LessOrEqual (<=, which is "not b < a" without it) and
+// Concat (..). The method is called on the left operand, with the right one
+// as its argument; a left operand of another type is converted to the
+// struct's type first, except with .., where it's just concatenated with
+// tostring of the right. As in Lua, == only calls Equal for two values of the
+// same type.
+//
+// Example:
+//  type Money struct {
+//    Cents int
+//  }
+//  func (m Money) Add(other Money) Money { return Money{m.Cents + other.Cents} }
+//  func (m Money) Less(other Money) bool { return m.Cents < other.Cents }
+//
+//  L.SetGlobal("price", New(L, Money{1999}))
+//  L.SetGlobal("tax", New(L, Money{160}))
+//  ---
+//  local total = price + tax
+//  print(tax < total)  -- prints "true"
+//
+// Fields and methods of embedded structs (and pointers to them) are promoted
+// the way they are in Go: the least deeply embedded field of a name wins, and
+// two at the same depth hide each other. A field promoted through a nil
+// embedded pointer reads as nil, and setting it allocates the pointer.
+//
+// Example:
+//  type BaseEvent struct {
+//    Timestamp string
+//  }
+//  type Message struct {
+//    *BaseEvent
+//    Text string
+//  }
+//
+//  L.SetGlobal("msg", New(L, &Message{&BaseEvent{"1445632320.000002"}, "hi"}))
+//  ---
+//  print(msg.Timestamp)  -- prints "1445632320.000002"
+//
+// Fields can be given a Lua name with a luar struct tag, or hidden from Lua
+// (and from tables converted to the struct) with luar:"-". Unexported fields
+// are never visible.
+//
+// Example:
+//  type Account struct {
+//    Name  string `luar:"name"`
+//    Token string `luar:"-"`
+//  }
+//
+//  L.SetGlobal("account", New(L, &Account{"tim", "secret"}))
+//  ---
+//  print(account.name)   -- prints "tim"
+//  print(account.Token)  -- prints "nil"
+//
+// Structs that already carry json tags can be named by them instead, by
+// setting the state's Config:
+//
+//  type Message struct {
+//    ChannelID string `json:"channel_id"`
+//  }
+//
+//  SetConfig(L, Config{FieldNames: JSONNames})
+//  L.SetGlobal("msg", New(L, &Message{"C024BE91L"}))
+//  ---
+//  print(msg.channel_id)  -- prints "C024BE91L"
+//
 // Type types
 //
 // Type constructors can be created using NewType. When called, it returns a
@@ -113,4 +370,86 @@
 //  p = Person()
 //  p.Name = "John"
 //  print("Hello, " .. p.Name)  // prints "Hello, John"
+//
+// Like make(), a channel type's constructor takes an optional buffer size, a
+// map type's an optional size, and a slice type's an optional length and
+// capacity. Struct, array, map, and slice types can be given a table to start
+// with instead, converted as it would be for a function argument.
+//
+// Example:
+//  L.SetGlobal("Person", NewType(L, Person{}))
+//  L.SetGlobal("Names", NewType(L, []string{}))
+//  ---
+//  p = Person({Name = "John"})
+//  names = Names({"John", "Tim"})
+//  buffer = Names(0, 10)
+//
+// Modules
+//
+// NewModule makes a Lua module out of a map of Go values, so a whole API can
+// be required under one name instead of set as globals one at a time. Values
+// are converted with New, and nested maps become tables of their own.
+//
+// Example:
+//  L.PreloadModule("text", NewModule(map[string]interface{}{
+//    "upper": strings.ToUpper,
+//    "rep":   strings.Repeat,
+//    "unicode": map[string]interface{}{
+//      "is_letter": unicode.IsLetter,
+//    },
+//  }))
+//  ---
+//  local text = require "text"
+//  print(text.upper("hi"))  -- prints "HI"
+//
+// Module preloads one in a single call, and PackageOf builds the map from a
+// list of functions, named as they are in Go:
+//  Module(L, "strings", PackageOf(strings.ToUpper, strings.Split))
+//  ---
+//  local strings = require "strings"
+//  print(strings.ToUpper("hi"))  -- prints "HI"
+//
+// Custom conversions
+//
+// A type can choose its own Lua representation by implementing LuaMarshaler,
+// whose ToLua New calls instead of making userdata, and be made from Lua
+// values by implementing LuaUnmarshaler, whose FromLua is called (on a new
+// value) wherever a Lua value is converted to the type. Types from other
+// packages, like time.Time, can be given them by wrapping them in a type of
+// your own.
+//
+// Example:
+//  type Color struct {
+//    R, G, B uint8
+//  }
+//  func (c Color) ToLua(L *lua.LState) lua.LValue {
+//    return lua.LString(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+//  }
+//  func (c *Color) FromLua(v lua.LValue) error {
+//    _, err := fmt.Sscanf(lua.LVAsString(v), "#%02x%02x%02x", &c.R, &c.G, &c.B)
+//    return err
+//  }
+//
+//  L.SetGlobal("red", New(L, Color{R: 255}))
+//  L.SetGlobal("paint", New(L, func(c Color) { ... }))
+//  ---
+//  print(red)         -- prints "#ff0000"
+//  paint("#00ff00")
+//
+// Times and durations
+//
+// A time.Duration is a number of seconds in Lua, and a Lua number or a string
+// like "1h30m" can be used where a time.Duration is expected. A time.Time is
+// userdata with format([layout]), unix(), add(d), sub(t or d), before(t) and
+// after(t) methods (as well as its Go ones), and a time plus or minus a
+// duration, or minus another time, works as you'd expect. A Lua number
+// (seconds since the epoch) or an RFC 3339 string can be used where a
+// time.Time is expected.
+//
+// Example:
+//  L.SetGlobal("start", New(L, time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)))
+//  L.SetGlobal("sleep", New(L, time.Sleep))
+//  ---
+//  print((start + 90):format("15:04:05"))  -- prints "09:01:30"
+//  sleep("250ms")
 package luar
@@ -1,7 +1,11 @@
 package luar_test
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/layeh/gopher-luar"
 	"github.com/yuin/gopher-lua"
@@ -405,3 +409,904 @@ func ExampleNewType() {
 	// Output:
 	// Tycho - Montana
 }
+
+func Example_12() {
+	const code = `
+	print(#p.Header)
+	print(p.Header[1], p.Header[4])
+
+	h = p.Header
+	h[1] = 1
+	print(h[1], p.Header[1])
+
+	id[2] = 7
+	print(id[1], id[2], #id)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	type Packet struct {
+		Header [4]byte
+	}
+
+	p := Packet{Header: [4]byte{0xCA, 0xFE, 0xBA, 0xBE}}
+	id := [2]int{1, 2}
+
+	L.SetGlobal("p", luar.New(L, p))
+	L.SetGlobal("id", luar.New(L, &id))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(id)
+	// Output:
+	// 4
+	// 202	190
+	// 1	202
+	// 1	7	2
+	// [1 7]
+}
+
+func Example_13() {
+	const code = `
+	print(describe({Name = "Tim", Age = 5}))
+	print(describe({Name = "John", Friend = {Name = "Tim"}, Unknown = true}))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	describe := func(p *Person) string {
+		if p.Friend != nil {
+			return fmt.Sprintf("%s is friends with %s", p.Name, p.Friend.Name)
+		}
+		return fmt.Sprintf("%s is %d", p.Name, p.Age)
+	}
+
+	L.SetGlobal("describe", luar.New(L, describe))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Tim is 5
+	// John is friends with Tim
+}
+
+func Example_14() {
+	const code = `
+	config.Labels = {env = "prod", team = "infra"}
+	config.Ports = {80, 443}
+	config.Weights = {0.5, 0.25}
+	print(config.Labels.env, #config.Ports, config.Ports[2], config.Weights[1])
+
+	local ok, err = pcall(function()
+		config.Ports = {80, "https"}
+	end)
+	print(ok, err:match("cannot use [^\n]*"))
+
+	ok, err = pcall(function()
+		config.Labels = {env = {}}
+	end)
+	print(ok, err:match("cannot use [^\n]*"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	type Config struct {
+		Labels  map[string]string
+		Ports   []int
+		Weights [2]float64
+	}
+
+	config := &Config{}
+	L.SetGlobal("config", luar.New(L, config))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(len(config.Labels), config.Ports, config.Weights)
+	// Output:
+	// prod	2	443	0.5
+	// false	cannot use string as int (at [2])
+	// false	cannot use table as string (at ["env"])
+	// 2 [80 443] [0.5 0.25]
+}
+
+type Hooks struct {
+	handlers []func(string, int) bool
+}
+
+func (h *Hooks) On(fn func(string, int) bool) {
+	h.handlers = append(h.handlers, fn)
+}
+
+func (h *Hooks) Fire(name string, n int) (handled int) {
+	for _, fn := range h.handlers {
+		if fn(name, n) {
+			handled++
+		}
+	}
+	return
+}
+
+func Example_15() {
+	const code = `
+	hooks:On(function(name, n)
+		print("got", name, n)
+		return n > 1
+	end)
+
+	print(validate({"tim", "john"}, function(name) end))
+	local err = validate({"tim", ""}, function(name)
+		if name == "" then
+			return "empty name"
+		end
+	end)
+	print(err:Error())
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	hooks := &Hooks{}
+	L.SetGlobal("hooks", luar.New(L, hooks))
+	L.SetGlobal("validate", luar.New(L, func(names []string, check func(string) error) error {
+		for _, name := range names {
+			if err := check(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(hooks.Fire("deploy", 2))
+	// Output:
+	// nil
+	// empty name
+	// got	deploy	2
+	// 1
+}
+
+func Example_16() {
+	const code = `
+	print(atoi("5"))
+	local ok, err = pcall(atoi, "five")
+	print(ok, err:match("strconv[^\n]*"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("atoi", luar.New(L, strconv.Atoi))
+	luar.SetErrorMode(L, luar.ErrorRaise)
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+
+	luar.SetErrorMode(L, luar.ErrorNil)
+	if err := L.DoString(`print(atoi("five"))`); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 5
+	// false	strconv.Atoi: parsing "five": invalid syntax
+	// nil	strconv.Atoi: parsing "five": invalid syntax
+}
+
+func Example_17() {
+	const code = `
+	print(join("-", "a", "b", "c"))
+	print(join("-"))
+	print(join("-", parts))
+	print(sprintf("%s has %d items", "cart", 2))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("join", luar.New(L, func(sep string, parts ...string) string {
+		return strings.Join(parts, sep)
+	}))
+	L.SetGlobal("sprintf", luar.New(L, fmt.Sprintf))
+	L.SetGlobal("parts", luar.New(L, []string{"x", "y"}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// a-b-c
+	//
+	// x-y
+	// cart has 2 items
+}
+
+type Counter struct {
+	Name  string
+	Count int
+}
+
+func (c *Counter) Add(n int) int {
+	c.Count += n
+	return c.Count
+}
+
+func (c Counter) String() string {
+	return fmt.Sprintf("%s=%d", c.Name, c.Count)
+}
+
+func Example_18() {
+	const code = `
+	print(counter:Add(2), counter:Add(3))
+	counter.Name = "visits"
+	print(counter:String())
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	counter := Counter{Name: "hits"}
+	L.SetGlobal("counter", luar.New(L, counter))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(counter)
+	// Output:
+	// 2	5
+	// visits=5
+	// hits=0
+}
+
+type Account struct {
+	Name    string `luar:"name"`
+	Token   string `luar:"-"`
+	Retries int    `luar:"retries"`
+}
+
+func Example_19() {
+	const code = `
+	print(account.name, account.Name, account.Token)
+	account.retries = 3
+	print((pcall(function() account.Token = "stolen" end)))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	account := &Account{Name: "tim", Token: "secret"}
+	L.SetGlobal("account", luar.New(L, account))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(account.Retries, account.Token)
+	// Output:
+	// tim	nil	nil
+	// false
+	// 3 secret
+}
+
+type Message struct {
+	ChannelID string `json:"channel_id"`
+	Text      string `json:"text,omitempty"`
+	Raw       []byte `json:"-"`
+	User      string `json:"user" luar:"from"`
+	Edited    bool
+}
+
+func Example_20() {
+	const code = `
+	print(msg.channel_id, msg.text, msg.from, msg.Edited, msg.Raw, msg.ChannelID)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	luar.SetConfig(L, luar.Config{FieldNames: luar.JSONNames})
+	L.SetGlobal("msg", luar.New(L, &Message{ChannelID: "C024BE91L", Text: "hi", Raw: []byte("{}"), User: "U0G9QF9C6"}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// C024BE91L	hi	U0G9QF9C6	false	nil	nil
+}
+
+type BaseEvent struct {
+	Type      string
+	Timestamp string
+}
+
+func (e BaseEvent) Kind() string {
+	return "event:" + e.Type
+}
+
+type Reactions struct {
+	Count int
+	Type  string
+}
+
+type SlackMessage struct {
+	*BaseEvent
+	Reactions
+	Text string
+}
+
+func Example_21() {
+	const code = `
+	print(msg.Timestamp, msg.Text, msg.Count, msg.Type, msg:Kind())
+	print(empty.Timestamp)
+	empty.Timestamp = "2"
+	print(empty.Timestamp)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	msg := &SlackMessage{&BaseEvent{"message", "1"}, Reactions{3, "thumbsup"}, "hi"}
+	L.SetGlobal("msg", luar.New(L, msg))
+	L.SetGlobal("empty", luar.New(L, &SlackMessage{}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 1	hi	3	nil	event:message
+	// nil
+	// 2
+}
+
+type Post struct {
+	Channel string
+	Text    string
+}
+
+func (p *Post) Send(times int) string {
+	return strings.Repeat(p.Channel+": "+p.Text+"\n", times)
+}
+
+func Example_22() {
+	const code = `
+	local ok, err = pcall(rep, "ab", {})
+	print(ok, err:match("bad argument [^\n]*"))
+	ok, err = pcall(rep, "ab")
+	print(ok, err:match("wrong number [^\n]*"))
+	ok, err = pcall(post.Send, post, "twice")
+	print(ok, err:match("bad argument [^\n]*"))
+	ok, err = pcall(newPost, {Channel = "general", Text = {}})
+	print(ok, err:match("bad argument [^\n]*"))
+	print(rep("ab", 2))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("rep", luar.New(L, strings.Repeat))
+	L.SetGlobal("post", luar.New(L, &Post{"general", "hi"}))
+	L.SetGlobal("newPost", luar.New(L, func(p Post) *Post { return &p }))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// false	bad argument #2 to strings.Repeat (cannot use table as int)
+	// false	wrong number of arguments to strings.Repeat (2 expected, got 1)
+	// false	bad argument #1 to Post:Send (cannot use string as int)
+	// false	bad argument #1 to gopher-luar_test.Example_22.func1 (cannot use table as string (at .Text))
+	// abab
+}
+
+func Example_23() {
+	const code = `
+	s = Song({Title = "Montana", Artist = "Tycho"})
+	print(s.Artist .. " - " .. s.Title)
+
+	playlist = Playlist({s, Song({Title = "Awake"})})
+	print(#playlist, playlist[2].Title)
+
+	queue = Playlist(0, 10)
+	print(#queue)
+
+	plays = Plays({Montana = 3})
+	print(plays.Montana)
+
+	local ok, err = pcall(function() return Song({Title = "Montana", Artist = {}}) end)
+	print(ok, err:match("bad argument [^\n]*"))
+	ok, err = pcall(function() return Playlist(-1) end)
+	print(ok, err:match("bad length [^\n]*"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	type Song struct {
+		Title  string
+		Artist string
+	}
+
+	L.SetGlobal("Song", luar.NewType(L, Song{}))
+	L.SetGlobal("Playlist", luar.NewType(L, []*Song{}))
+	L.SetGlobal("Plays", luar.NewType(L, map[string]int{}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// Tycho - Montana
+	// 2	Awake
+	// 0
+	// 3
+	// false	bad argument #1 to luar_test.Song (cannot use table as string (at .Artist))
+	// false	bad length (-1) or capacity (-1) for []*luar_test.Song
+}
+
+func ExampleNewModule() {
+	const code = `
+	local text = require "text"
+	print(text.upper("hi"), text.rep("ab", 2), text.separator)
+	print(text.fields.count("a b c"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.PreloadModule("text", luar.NewModule(map[string]interface{}{
+		"upper":     strings.ToUpper,
+		"rep":       strings.Repeat,
+		"separator": "|",
+		"fields": map[string]interface{}{
+			"count": func(s string) int { return len(strings.Fields(s)) },
+		},
+	}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// HI	abab	|
+	// 3
+}
+
+func Example_24() {
+	const code = `
+	print(ch:tryreceive())
+	print(ch:receive(0.01))
+	ready:send(true)
+	print(ch:receive(5))
+	print(ch:receive(5))
+	local ok, err = pcall(function() return ch:receive(-1) end)
+	print(ok, err:match("bad argument [^\n]*"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	ch := make(chan string)
+	ready := make(chan bool)
+	go func() {
+		<-ready
+		ch <- "Tim"
+		close(ch)
+	}()
+
+	L.SetGlobal("ch", luar.New(L, ch))
+	L.SetGlobal("ready", luar.New(L, ready))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// nil	nil
+	// nil	nil
+	// Tim	true
+	// nil	false
+	// false	bad argument #2 to receive (timeout must not be negative)
+}
+
+func Example_25() {
+	const code = `
+	local luar = require("luar")
+	print(luar.select(nil, replies))
+	print(luar.select(nil, replies, timeout))
+	local ok, err = pcall(luar.select, replies, "soon")
+	print(ok, err:match("bad argument [^\n]*"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	replies := make(chan string, 1)
+	replies <- "pong"
+	timeout := make(chan bool)
+	close(timeout)
+
+	L.PreloadModule("luar", luar.Loader)
+	L.SetGlobal("replies", luar.New(L, replies))
+	L.SetGlobal("timeout", luar.New(L, (<-chan bool)(timeout)))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 2	pong	true
+	// 3	nil	false
+	// false	bad argument #2 to anonymous function (channel expected)
+}
+
+func Example_26() {
+	const code = `
+	local function show(s)
+		local items = {}
+		for i = 1, #s do
+			items[i] = s[i]
+		end
+		print(table.concat(items, " "))
+	end
+	letters = letters:insert(1, "y")
+	letters = letters:insert("o")
+	show(letters)
+	local removed
+	letters, removed = letters:remove(2)
+	print(removed, #letters)
+	letters, removed = letters:remove()
+	print(removed, #letters)
+	show(letters:sub(2, -2))
+	show(letters:sub(-2))
+	print(#letters:sub(4))
+	letters:sub(2)[1] = "u"
+	show(letters)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	letters := []string{"a", "e", "i"}
+	L.SetGlobal("letters", luar.New(L, letters))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(letters)
+	// Output:
+	// y a e i o
+	// a	4
+	// o	3
+	// e
+	// e i
+	// 0
+	// y u i
+	// [a e i]
+}
+
+func ExamplePackageOf() {
+	const code = `
+	local strings = require "strings"
+	local strconv = require "strconv"
+	print(strings.ToUpper("hi"), strings.Repeat("ab", 2))
+	print(strconv.Itoa(42) .. "!", strconv.Quote("hi"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	luar.Module(L, "strings", luar.PackageOf(strings.ToUpper, strings.Repeat))
+	luar.Module(L, "strconv", luar.PackageOf(strconv.Itoa, strconv.Quote))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// HI	abab
+	// 42!	"hi"
+}
+
+func Example_27() {
+	const code = `
+	letters:sort()
+	numbers:sort(function(a, b) return a > b end)
+	people:sort(function(a, b) return a.Age < b.Age end)
+	local ok, err = pcall(function() people:sort() end)
+	print(ok, err:match("bad argument [^\n]*"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	letters := []string{"i", "a", "e"}
+	numbers := []float64{2.5, 10, -1}
+	people := []*Person{{Name: "Tim", Age: 30}, {Name: "John", Age: 16}}
+	L.SetGlobal("letters", luar.New(L, letters))
+	L.SetGlobal("numbers", luar.New(L, numbers))
+	L.SetGlobal("people", luar.New(L, people))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	fmt.Println(letters, numbers, people[0].Name, people[1].Name)
+	// Output:
+	// false	bad argument #2 to sort (comparator expected, as *luar_test.Person has no natural order)
+	// [a e i] [10 2.5 -1] John Tim
+}
+
+func Example_28() {
+	const code = `
+	local keys = places:keys()
+	print(#keys, keys[1], keys[2], places:values()[1])
+	print(ids:keys()[1], ids:values()[2])
+	print(methods.keys)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	places := map[string]string{
+		"NA": "North America",
+		"EU": "European Union",
+	}
+	ids := map[int]string{2: "b", 1: "a"}
+	methods := map[string]string{"keys": "shadowed"}
+	L.SetGlobal("places", luar.New(L, places))
+	L.SetGlobal("ids", luar.New(L, ids))
+	L.SetGlobal("methods", luar.New(L, methods))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 2	EU	NA	European Union
+	// 1	b
+	// shadowed
+}
+
+func Example_29() {
+	const code = `
+	for k, v in pairs(places) do
+		print(k, v)
+	end
+	for i, name in ipairs(names) do
+		print(i, name)
+	end
+	for field, value in pairs(p) do
+		print(field, field == "Friend" and value.Name or value)
+	end
+	for k, v in pairs({"plain", "table"}) do
+		print(k, v)
+	end
+	for k, v in places() do
+		print(k == "EU" or k == "NA")
+	end
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	places := map[string]string{
+		"NA": "North America",
+		"EU": "European Union",
+	}
+	names := []string{"John", "Tim"}
+	p := Person{Name: "John", Age: 16, Friend: &Person{Name: "Tim"}}
+	L.SetGlobal("places", luar.New(L, places))
+	L.SetGlobal("names", luar.New(L, names))
+	L.SetGlobal("p", luar.New(L, &p))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// EU	European Union
+	// NA	North America
+	// 1	John
+	// 2	Tim
+	// Age	16
+	// Friend	Tim
+	// Name	John
+	// 1	plain
+	// 2	table
+	// true
+	// true
+}
+
+type Temperature struct {
+	Celsius float64
+}
+
+func (t Temperature) String() string {
+	return fmt.Sprintf("%.1f°C", t.Celsius)
+}
+
+type Reading struct {
+	Where string
+}
+
+func (r *Reading) String() string {
+	return "reading from " + r.Where
+}
+
+func Example_30() {
+	const code = `
+	print(tostring(temp))
+	print(reading)
+	print(err)
+	print(tostring(nothing):match("^userdata: luar: %*luar_test.Reading") ~= nil)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("temp", luar.New(L, Temperature{21.5}))
+	L.SetGlobal("reading", luar.New(L, &Reading{Where: "the lab"}))
+	L.SetGlobal("err", luar.New(L, errors.New("too hot")))
+	L.SetGlobal("nothing", luar.New(L, (*Reading)(nil)))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 21.5°C
+	// reading from the lab
+	// too hot
+	// true
+}
+
+type Money struct {
+	Cents int
+}
+
+func (m Money) Add(other Money) Money  { return Money{m.Cents + other.Cents} }
+func (m Money) Sub(other Money) Money  { return Money{m.Cents - other.Cents} }
+func (m Money) Neg() Money             { return Money{-m.Cents} }
+func (m Money) Less(other Money) bool  { return m.Cents < other.Cents }
+func (m Money) Equal(other Money) bool { return m.Cents == other.Cents }
+func (m Money) Concat(s string) string { return m.String() + s }
+func (m Money) String() string         { return fmt.Sprintf("$%d.%02d", m.Cents/100, m.Cents%100) }
+
+func Example_31() {
+	const code = `
+	local total = price + tax
+	print(total, (-price).Cents, price - tax)
+	print(tax < price, price <= tax, total == tax + price)
+	print(total .. " each", "total: " .. total)
+	local ok, err = pcall(function() return 5 + price end)
+	print(ok)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("price", luar.New(L, Money{1999}))
+	L.SetGlobal("tax", luar.New(L, Money{160}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// $21.59	-1999	$18.39
+	// true	false	true
+	// $21.59 each	total: $21.59
+	// false
+}
+
+type Color struct {
+	R, G, B uint8
+}
+
+func (c Color) ToLua(L *lua.LState) lua.LValue {
+	return lua.LString(fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B))
+}
+
+func (c *Color) FromLua(v lua.LValue) error {
+	s, ok := v.(lua.LString)
+	if !ok {
+		return errors.New("colors are strings like #ff0000")
+	}
+	_, err := fmt.Sscanf(string(s), "#%02x%02x%02x", &c.R, &c.G, &c.B)
+	return err
+}
+
+func Example_32() {
+	const code = `
+	print(type(red), red)
+	print(mix("#00ff00", red))
+	print(mix(red, red))
+	print(brightest({"#101010", "#808080", "#202020"}))
+	local ok, err = pcall(mix, 42, red)
+	print(ok, err:match("%((.-)%)"))
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	L.SetGlobal("red", luar.New(L, Color{R: 255}))
+	L.SetGlobal("mix", luar.New(L, func(a, b Color) Color {
+		return Color{a.R/2 + b.R/2, a.G/2 + b.G/2, a.B/2 + b.B/2}
+	}))
+	L.SetGlobal("brightest", luar.New(L, func(colors []Color) Color {
+		var best Color
+		for _, c := range colors {
+			if int(c.R)+int(c.G)+int(c.B) > int(best.R)+int(best.G)+int(best.B) {
+				best = c
+			}
+		}
+		return best
+	}))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// string	#ff0000
+	// #7f7f00
+	// #fe0000
+	// #808080
+	// false	cannot use number as luar_test.Color: colors are strings like #ff0000
+}
+
+func Example_33() {
+	const code = `
+	print(start:format(), start:unix())
+	local finish = start + 90
+	print(finish:format("15:04:05"), finish - start, start < finish)
+	print(start:add("1h30m"):format("15:04"), (finish - "30s"):format("15:04:05"))
+	print(timeout, wait(2.5), wait("1m"))
+	print(since("2026-10-15T09:00:00Z"), since(start:unix() - 60))
+	print("started at " .. start)
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	start := time.Date(2026, 10, 15, 9, 0, 0, 0, time.UTC)
+	L.SetGlobal("start", luar.New(L, start))
+	L.SetGlobal("timeout", luar.New(L, 90*time.Second))
+	L.SetGlobal("wait", luar.New(L, func(d time.Duration) string { return d.String() }))
+	L.SetGlobal("since", luar.New(L, func(t time.Time) time.Duration { return start.Sub(t) }))
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// 2026-10-15T09:00:00Z	1792054800
+	// 09:01:30	90	true
+	// 10:30	09:01:00
+	// 90	2.5s	1m0s
+	// 0	60
+	// started at 2026-10-15 09:00:00 +0000 UTC
+}
+
+func Example_34() {
+	const code = `
+	local ok, err = pcall(ch.receive, ch)
+	print(ok, err:match("receive [^\n]*"))
+	ok, err = pcall(ch.receive, ch, 5)
+	print(ok, err:match("receive [^\n]*"))
+	ok, err = pcall(luar.select, ch)
+	print(ok, err:match("select [^\n]*"))
+	print(ch:tryreceive())
+	`
+
+	L := lua.NewState()
+	defer L.Close()
+
+	ch := make(chan string, 1)
+	L.PreloadModule("luar", luar.Loader)
+	L.DoString(`luar = require("luar")`)
+	L.SetGlobal("ch", luar.New(L, ch))
+	luar.SetConfig(L, luar.Config{NoBlocking: true})
+	ch <- "ready"
+
+	if err := L.DoString(code); err != nil {
+		panic(err)
+	}
+	// Output:
+	// false	receive would wait, which isn't allowed in this state
+	// false	receive would wait, which isn't allowed in this state
+	// false	select would wait, which isn't allowed in this state
+	// ready	true
+}
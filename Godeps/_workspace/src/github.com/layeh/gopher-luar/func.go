@@ -1,33 +1,118 @@
 package luar
 
 import (
+	"errors"
 	"reflect"
+	"runtime"
+	"strings"
 
 	"github.com/yuin/gopher-lua"
 )
 
-func funcEvaluate(L *lua.LState, fn reflect.Value) int {
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// ErrorMode is how a Go function's trailing error return value is given to
+// Lua.
+type ErrorMode int
+
+const (
+	// ErrorValue returns the error as a value, like any other (the default).
+	ErrorValue ErrorMode = iota
+	// ErrorRaise raises a non-nil error as a Lua error, and otherwise leaves
+	// it off the returned values.
+	ErrorRaise
+	// ErrorNil returns nil (in place of each other value) and the error's
+	// message for a non-nil error, and otherwise leaves it off the returned
+	// values (returning true if there are none).
+	ErrorNil
+)
+
+const errorModeKey = lua.LString("github.com/layeh/gopher-luar/errormode")
+
+// SetErrorMode sets how functions called from the state return errors.
+func SetErrorMode(L *lua.LState, mode ErrorMode) {
+	L.G.Registry.RawSetH(errorModeKey, lua.LNumber(mode))
+}
+
+// GetErrorMode returns how functions called from the state return errors.
+func GetErrorMode(L *lua.LState) ErrorMode {
+	if mode, ok := L.G.Registry.RawGetH(errorModeKey).(lua.LNumber); ok {
+		return ErrorMode(mode)
+	}
+	return ErrorValue
+}
+
+// funcEvaluate calls fn with the arguments on the stack, converted to its
+// parameter types, and pushes its return values. name is what fn is called in
+// errors.
+func funcEvaluate(L *lua.LState, fn reflect.Value, name string) int {
+	if fn.IsNil() {
+		L.RaiseError("cannot call nil function %s", name)
+	}
 	fnType := fn.Type()
 	top := L.GetTop()
 	expected := fnType.NumIn()
 	variadic := fnType.IsVariadic()
 	if !variadic && top != expected {
-		L.RaiseError("invalid number of function argument (%d expected, got %d)", expected, top)
+		L.RaiseError("wrong number of arguments to %s (%d expected, got %d)", name, expected, top)
 	}
 	if variadic && top < expected-1 {
-		L.RaiseError("invalid number of function argument (%d or more expected, got %d)", expected-1, top)
+		L.RaiseError("wrong number of arguments to %s (%d or more expected, got %d)", name, expected-1, top)
+	}
+	// a Go slice passed as the last argument to a variadic function is used
+	// as the variadic slice, like f(s...), unless it could be one of its
+	// elements (e.g. for ...interface{})
+	spread := false
+	if variadic && top == expected {
+		if ud, ok := L.Get(top).(*lua.LUserData); ok && ud.Value != nil {
+			t := reflect.TypeOf(ud.Value)
+			spread = t.AssignableTo(fnType.In(expected-1)) && !t.AssignableTo(fnType.In(expected-1).Elem())
+		}
 	}
 	args := make([]reflect.Value, top)
 	for i := 0; i < L.GetTop(); i++ {
 		var hint reflect.Type
-		if variadic && i >= expected-1 {
+		if variadic && i >= expected-1 && !spread {
 			hint = fnType.In(expected - 1).Elem()
 		} else {
 			hint = fnType.In(i)
 		}
-		args[i] = lValueToReflect(L.Get(i+1), hint)
+		args[i] = lValueToArg(L, L.Get(i+1), hint, i+1, name)
+		if !args[i].IsValid() {
+			args[i] = reflect.Zero(hint)
+		}
+	}
+	var ret []reflect.Value
+	if spread {
+		ret = fn.CallSlice(args)
+	} else {
+		ret = fn.Call(args)
+	}
+	if n := len(ret); n > 0 && fnType.Out(n-1) == errorType {
+		switch GetErrorMode(L) {
+		case ErrorRaise:
+			if err := ret[n-1]; !err.IsNil() {
+				L.RaiseError("%s", err.Interface().(error).Error())
+			}
+			ret = ret[:n-1]
+		case ErrorNil:
+			if err := ret[n-1]; !err.IsNil() {
+				nils := n - 1
+				if nils == 0 {
+					nils = 1
+				}
+				for i := 0; i < nils; i++ {
+					L.Push(lua.LNil)
+				}
+				L.Push(lua.LString(err.Interface().(error).Error()))
+				return nils + 1
+			}
+			if ret = ret[:n-1]; n == 1 {
+				L.Push(lua.LTrue)
+				return 1
+			}
+		}
 	}
-	ret := fn.Call(args)
 	for _, val := range ret {
 		L.Push(New(L, val.Interface()))
 	}
@@ -35,8 +120,80 @@ func funcEvaluate(L *lua.LState, fn reflect.Value) int {
 }
 
 func funcWrapper(L *lua.LState, fn reflect.Value) *lua.LFunction {
+	name := funcName(fn)
 	wrapper := func(L *lua.LState) int {
-		return funcEvaluate(L, fn)
+		return funcEvaluate(L, fn, name)
 	}
 	return L.NewFunction(wrapper)
 }
+
+// funcName is the function's name without its package's path (e.g.
+// strings.Repeat), or its type if it has no name.
+func funcName(fn reflect.Value) string {
+	if fn.IsNil() {
+		return fn.Type().String()
+	}
+	f := runtime.FuncForPC(fn.Pointer())
+	if f == nil {
+		return fn.Type().String()
+	}
+	name := strings.TrimSuffix(f.Name(), "-fm") // method values
+	if slash := strings.LastIndex(name, "/"); slash >= 0 {
+		name = name[slash+1:]
+	}
+	return name
+}
+
+// funcFromLua makes a Go function of type t that calls fn. Its arguments are
+// converted to Lua values, and fn's return values back to t's return types
+// (a string becomes an error where one is expected). If fn raises an error,
+// it is returned if t's last return type is error, otherwise the function
+// panics with it.
+//
+// The function runs fn on L, so it must only be called when nothing else is
+// using L (e.g. from the goroutine running L).
+func funcFromLua(L *lua.LState, fn *lua.LFunction, t reflect.Type) reflect.Value {
+	return reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		ret := make([]reflect.Value, t.NumOut())
+		call := func(L *lua.LState) int {
+			if t.IsVariadic() && len(args) > 0 {
+				last := args[len(args)-1]
+				args = args[:len(args)-1]
+				for i := 0; i < last.Len(); i++ {
+					args = append(args, last.Index(i))
+				}
+			}
+			L.Push(fn)
+			for _, arg := range args {
+				L.Push(New(L, arg.Interface()))
+			}
+			top := L.GetTop() - len(args) - 1
+			L.Call(len(args), len(ret))
+			for i := range ret {
+				value := L.Get(top + i + 1)
+				if str, ok := value.(lua.LString); ok && t.Out(i) == errorType {
+					ret[i] = reflect.ValueOf(errors.New(string(str)))
+				} else {
+					ret[i] = lValueToReflect(L, value, t.Out(i))
+				}
+				if !ret[i].IsValid() {
+					ret[i] = reflect.Zero(t.Out(i))
+				} else if ret[i].Type() != t.Out(i) {
+					// e.g. a concrete type returned as an interface
+					ret[i] = ret[i].Convert(t.Out(i))
+				}
+			}
+			return 0
+		}
+		if err := L.CallByParam(lua.P{Fn: L.NewFunction(call), Protect: true}); err != nil {
+			if len(ret) == 0 || t.Out(len(ret)-1) != errorType {
+				panic(err)
+			}
+			for i := range ret {
+				ret[i] = reflect.Zero(t.Out(i))
+			}
+			ret[len(ret)-1] = reflect.ValueOf(&err).Elem()
+		}
+		return ret
+	})
+}
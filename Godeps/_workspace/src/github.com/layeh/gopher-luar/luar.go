@@ -3,6 +3,7 @@ package luar
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/yuin/gopher-lua"
 )
@@ -11,6 +12,15 @@ var typeMetatable map[string]map[string]lua.LGFunction
 
 func init() {
 	typeMetatable = map[string]map[string]lua.LGFunction{
+		"array": {
+			"__index":    arrayIndex,
+			"__newindex": arrayNewIndex,
+			"__len":      arrayLen,
+			"__pairs":    indexedPairs,
+			"__ipairs":   indexedPairs,
+			"__tostring": baseToString,
+			"__eq":       baseEqual,
+		},
 		"chan": {
 			"__index":    chanIndex,
 			"__tostring": chanToString,
@@ -21,12 +31,15 @@ func init() {
 			"__newindex": mapNewIndex,
 			"__len":      mapLen,
 			"__call":     mapCall,
+			"__pairs":    mapPairs,
 			"__tostring": baseToString,
 			"__eq":       baseEqual,
 		},
 		"ptr": {
 			"__index":    ptrIndex,
 			"__newindex": ptrNewIndex,
+			"__len":      ptrLen,
+			"__pairs":    ptrPairs,
 			"__tostring": ptrToString,
 			"__eq":       baseEqual,
 		},
@@ -34,12 +47,15 @@ func init() {
 			"__index":    sliceIndex,
 			"__newindex": sliceNewIndex,
 			"__len":      sliceLen,
+			"__pairs":    indexedPairs,
+			"__ipairs":   indexedPairs,
 			"__tostring": baseToString,
 			"__eq":       baseEqual,
 		},
 		"struct": {
 			"__index":    structIndex,
 			"__newindex": structNewIndex,
+			"__pairs":    structPairs,
 			"__tostring": baseToString,
 		},
 		"type": {
@@ -49,8 +65,32 @@ func init() {
 	}
 }
 
+// stringerToString pushes what the value's Error or String method returns,
+// if it has one (like fmt, preferring Error), returning false if it doesn't
+// or the method panicked (on a nil pointer, say)
+func stringerToString(L *lua.LState, value interface{}) (ok bool) {
+	var str string
+	defer func() {
+		if recover() != nil {
+			ok = false
+		} else if ok {
+			L.Push(lua.LString(str))
+		}
+	}()
+	switch v := value.(type) {
+	case error:
+		str, ok = v.Error(), true
+	case fmt.Stringer:
+		str, ok = v.String(), true
+	}
+	return
+}
+
 func baseToString(L *lua.LState) int {
 	ud := L.CheckUserData(1)
+	if stringerToString(L, ud.Value) {
+		return 1
+	}
 	value := reflect.ValueOf(ud.Value)
 
 	str := fmt.Sprintf("userdata: luar: %s %+v (%p)", value.Type(), value.Interface(), ud.Value)
@@ -81,6 +121,7 @@ func ensureMetatable(L *lua.LState) *lua.LTable {
 		newTable.RawSetH(lua.LString(typeName), typeTable)
 	}
 	L.G.Registry.RawSetH(metatableKey, newTable)
+	installPairs(L)
 	return newTable
 }
 
@@ -89,6 +130,7 @@ func ensureMetatable(L *lua.LState) *lua.LTable {
 // The following types are supported:
 //  reflect.Kind    gopher-lua Type
 //  nil             LNil
+//  Array           *LUserData
 //  Bool            LBool
 //  Int             LNumber
 //  Int8            LNumber
@@ -117,6 +159,18 @@ func New(L *lua.LState, value interface{}) lua.LValue {
 	if lval, ok := value.(lua.LValue); ok {
 		return lval
 	}
+	if fn, ok := value.(lua.LGFunction); ok {
+		return L.NewFunction(fn)
+	}
+	if fn, ok := value.(func(*lua.LState) int); ok {
+		return L.NewFunction(fn)
+	}
+	if lval, ok := marshal(L, value); ok {
+		return lval
+	}
+	if d, ok := value.(time.Duration); ok {
+		return lua.LNumber(d.Seconds())
+	}
 	table := ensureMetatable(L)
 
 	val := reflect.ValueOf(value)
@@ -129,6 +183,11 @@ func New(L *lua.LState, value interface{}) lua.LValue {
 		return lua.LNumber(float64(val.Uint()))
 	case reflect.Float32, reflect.Float64:
 		return lua.LNumber(val.Float())
+	case reflect.Array:
+		ud := L.NewUserData()
+		ud.Value = val.Interface()
+		ud.Metatable = table.RawGetH(lua.LString("array"))
+		return ud
 	case reflect.Chan:
 		ud := L.NewUserData()
 		ud.Value = val.Interface()
@@ -148,7 +207,7 @@ func New(L *lua.LState, value interface{}) lua.LValue {
 	case reflect.Ptr:
 		ud := L.NewUserData()
 		ud.Value = val.Interface()
-		ud.Metatable = table.RawGetH(lua.LString("ptr"))
+		ud.Metatable = getTypeInfo(L, val.Type()).metatable
 		return ud
 	case reflect.Slice:
 		ud := L.NewUserData()
@@ -160,7 +219,7 @@ func New(L *lua.LState, value interface{}) lua.LValue {
 	case reflect.Struct:
 		ud := L.NewUserData()
 		ud.Value = val.Interface()
-		ud.Metatable = table.RawGetH(lua.LString("struct"))
+		ud.Metatable = getTypeInfo(L, val.Type()).metatable
 		return ud
 	case reflect.UnsafePointer:
 		ud := L.NewUserData()
@@ -184,27 +243,178 @@ func NewType(L *lua.LState, value interface{}) lua.LValue {
 	return ud
 }
 
-func lValueToReflect(v lua.LValue, hint reflect.Type) reflect.Value {
+func lValueToReflect(L *lua.LState, v lua.LValue, hint reflect.Type) reflect.Value {
+	return lValueConvert(L, v, hint, "", "")
+}
+
+// lValueToArg converts v, the nth argument to the named Go function, to a
+// value of the hint type. Errors say which argument could not be converted.
+func lValueToArg(L *lua.LState, v lua.LValue, hint reflect.Type, n int, name string) reflect.Value {
+	return lValueConvert(L, v, hint, fmt.Sprintf("#%d to %s", n, name), "")
+}
+
+// lValueConvert converts v to a value of the hint type, raising an error if
+// it cannot. arg is the argument v is (or is in) if it is being passed to a
+// function (e.g. #2 to strings.Repeat), and path is where v is in the value being converted
+// (e.g. [2].Name), for error messages.
+func lValueConvert(L *lua.LState, v lua.LValue, hint reflect.Type, arg string, path string) reflect.Value {
+	if value, ok := unmarshal(L, v, hint, arg, path); ok {
+		return value
+	}
+	if value, ok := timeConvert(L, v, hint, arg, path); ok {
+		return value
+	}
+	var value reflect.Value
 	switch converted := v.(type) {
 	case lua.LBool:
-		return reflect.ValueOf(bool(converted))
+		value = reflect.ValueOf(bool(converted))
 	case lua.LChannel:
-		return reflect.ValueOf(converted)
+		value = reflect.ValueOf(converted)
 	case lua.LNumber:
-		return reflect.ValueOf(converted).Convert(hint)
+		value = reflect.ValueOf(converted)
+		if hint != nil {
+			if !value.Type().ConvertibleTo(hint) {
+				raiseConvertError(L, v, hint, arg, path)
+			}
+			value = value.Convert(hint)
+		}
 	case *lua.LFunction:
-		return reflect.ValueOf(converted)
+		if hint != nil && hint.Kind() == reflect.Func {
+			value = funcFromLua(L, converted, hint)
+		} else {
+			value = reflect.ValueOf(converted)
+		}
 	case *lua.LNilType:
+		if hint == nil {
+			return reflect.Value{}
+		}
 		return reflect.Zero(hint)
 	case *lua.LState:
-		return reflect.ValueOf(converted)
+		value = reflect.ValueOf(converted)
 	case lua.LString:
-		return reflect.ValueOf(string(converted))
+		value = reflect.ValueOf(string(converted))
+		if hint != nil && hint.Kind() == reflect.String {
+			value = value.Convert(hint)
+		}
 	case *lua.LTable:
-		return reflect.ValueOf(converted)
+		value = tableToReflect(L, converted, hint, arg, path)
 	case *lua.LUserData:
-		return reflect.ValueOf(converted.Value)
+		value = reflect.ValueOf(converted.Value)
+	default:
+		if hint == nil {
+			raiseConversion(L, arg, path, "cannot convert %s to a Go value", v.Type())
+		}
+		raiseConvertError(L, v, hint, arg, path)
+	}
+	if hint == nil {
+		return value
+	}
+	if !value.IsValid() {
+		// e.g. userdata holding nil
+		return reflect.Zero(hint)
+	}
+	if !value.Type().AssignableTo(hint) {
+		raiseConvertError(L, v, hint, arg, path)
+	}
+	return value
+}
+
+func raiseConvertError(L *lua.LState, v lua.LValue, hint reflect.Type, arg string, path string) {
+	raiseConversion(L, arg, path, "cannot use %s as %s", v.Type(), hint)
+}
+
+// raiseConversion raises a conversion error, saying which argument (if there
+// is one) and where in it the error is.
+func raiseConversion(L *lua.LState, arg string, path string, format string, args ...interface{}) {
+	message := fmt.Sprintf(format, args...) + tablePathSuffix(path)
+	if arg != "" {
+		L.RaiseError("bad argument %s (%s)", arg, message)
+	}
+	L.RaiseError("%s", message)
+}
+
+// tableToReflect converts the table to a struct, map, slice, or array of the
+// hint type, converting its contents as well. Other hints get the table
+// itself.
+func tableToReflect(L *lua.LState, table *lua.LTable, hint reflect.Type, arg string, path string) reflect.Value {
+	if hint == nil {
+		return reflect.ValueOf(table)
+	}
+	switch hint.Kind() {
+	case reflect.Struct:
+		return tableToStruct(L, table, hint, arg, path)
+	case reflect.Ptr:
+		if hint.Elem().Kind() == reflect.Struct {
+			return tableToStruct(L, table, hint.Elem(), arg, path).Addr()
+		}
+	case reflect.Map:
+		value := reflect.MakeMap(hint)
+		table.ForEach(func(key, lValue lua.LValue) {
+			where := path + tableKeyPath(key)
+			value.SetMapIndex(lValueConvert(L, key, hint.Key(), arg, where), lValueConvert(L, lValue, hint.Elem(), arg, where))
+		})
+		return value
+	case reflect.Slice, reflect.Array:
+		length := table.Len()
+		keys := 0
+		table.ForEach(func(key, lValue lua.LValue) {
+			keys++
+		})
+		if keys != length {
+			raiseConversion(L, arg, path, "cannot use table with non-sequence keys as %s", hint)
+		}
+		var value reflect.Value
+		if hint.Kind() == reflect.Slice {
+			value = reflect.MakeSlice(hint, length, length)
+		} else {
+			if length > hint.Len() {
+				raiseConversion(L, arg, path, "cannot use table of length %d as %s", length, hint)
+			}
+			value = reflect.New(hint).Elem()
+		}
+		for i := 1; i <= length; i++ {
+			where := fmt.Sprintf("%s[%d]", path, i)
+			value.Index(i - 1).Set(lValueConvert(L, table.RawGetInt(i), hint.Elem(), arg, where))
+		}
+		return value
+	}
+	return reflect.ValueOf(table)
+}
+
+// tableToStruct creates a new value of the struct type t, with its fields
+// set from the table's values of the same name (as renamed by luar tags).
+// Keys that are not the name of a field Lua can see are ignored.
+func tableToStruct(L *lua.LState, table *lua.LTable, t reflect.Type, arg string, path string) reflect.Value {
+	value := reflect.New(t).Elem()
+	fields := structFieldsOf(L, t)
+	table.ForEach(func(key, lValue lua.LValue) {
+		name, ok := key.(lua.LString)
+		if !ok {
+			return
+		}
+		index, ok := fields[string(name)]
+		if !ok {
+			return
+		}
+		field, ok := structField(value, index, true)
+		if !ok {
+			return
+		}
+		field.Set(lValueConvert(L, lValue, field.Type(), arg, path+"."+string(name)))
+	})
+	return value
+}
+
+func tableKeyPath(key lua.LValue) string {
+	if str, ok := key.(lua.LString); ok {
+		return fmt.Sprintf("[%q]", string(str))
+	}
+	return "[" + key.String() + "]"
+}
+
+func tablePathSuffix(path string) string {
+	if path == "" {
+		return ""
 	}
-	panic("fatal lValueToReflect error")
-	return reflect.Value{}
+	return " (at " + path + ")"
 }
@@ -2,6 +2,7 @@ package luar
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/yuin/gopher-lua"
 )
@@ -18,23 +19,70 @@ func mapIndex(L *lua.LState) int {
 	lKey := L.Get(2)
 
 	value := reflect.ValueOf(ud.Value)
-	key := lValueToReflect(lKey, value.Type().Key())
+	method, isMethod := mapMethods[lua.LVAsString(lKey)]
+	if _, isString := lKey.(lua.LString); !isString {
+		isMethod = false
+	}
+	if isMethod && value.Type().Key().Kind() != reflect.String {
+		L.Push(L.NewFunction(method))
+		return 1
+	}
+	key := lValueToReflect(L, lKey, value.Type().Key())
 	item := value.MapIndex(key)
 	if !item.IsValid() {
+		// an item with the same name as a method hides it
+		if isMethod {
+			L.Push(L.NewFunction(method))
+			return 1
+		}
 		return 0
 	}
 	L.Push(New(L, item.Interface()))
 	return 1
 }
 
+var mapMethods = map[string]lua.LGFunction{
+	"keys":   mapKeys,
+	"values": mapValues,
+}
+
+// sortedKeys returns the map's keys, in order if they're numbers or strings
+func sortedKeys(value reflect.Value) reflect.Value {
+	keys := reflect.MakeSlice(reflect.SliceOf(value.Type().Key()), 0, value.Len())
+	keys = reflect.Append(keys, value.MapKeys()...)
+	if less := naturalLess(keys); less != nil {
+		sort.Slice(keys.Interface(), less)
+	}
+	return keys
+}
+
+func mapKeys(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.ValueOf(ud.Value)
+	L.Push(New(L, sortedKeys(value).Interface()))
+	return 1
+}
+
+func mapValues(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.ValueOf(ud.Value)
+	keys := sortedKeys(value)
+	values := reflect.MakeSlice(reflect.SliceOf(value.Type().Elem()), keys.Len(), keys.Len())
+	for i := 0; i < keys.Len(); i++ {
+		values.Index(i).Set(value.MapIndex(keys.Index(i)))
+	}
+	L.Push(New(L, values.Interface()))
+	return 1
+}
+
 func mapNewIndex(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 	lKey := L.Get(2)
 	lValue := L.Get(3)
 
 	value := reflect.ValueOf(ud.Value)
-	key := lValueToReflect(lKey, value.Type().Key())
-	mapValue := lValueToReflect(lValue, value.Type().Elem())
+	key := lValueToReflect(L, lKey, value.Type().Key())
+	mapValue := lValueToReflect(L, lValue, value.Type().Elem())
 	value.SetMapIndex(key, mapValue)
 	return 0
 }
@@ -0,0 +1,64 @@
+package luar
+
+import (
+	"reflect"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// LuaMarshaler is implemented by types that choose their own Lua value. New
+// returns what ToLua does instead of wrapping the value in userdata.
+type LuaMarshaler interface {
+	ToLua(L *lua.LState) lua.LValue
+}
+
+// LuaUnmarshaler is implemented by types that can be made from Lua values.
+// When a Lua value is converted to one (as a function argument, a field, a
+// table element, ...), FromLua is called on a new value instead of the usual
+// conversion. FromLua is not called for nil, or for userdata already holding
+// the type.
+type LuaUnmarshaler interface {
+	FromLua(v lua.LValue) error
+}
+
+var unmarshalerType = reflect.TypeOf((*LuaUnmarshaler)(nil)).Elem()
+
+// marshal returns what value's ToLua does, if it has one. Nil pointers are
+// left alone, as ToLua might not expect them.
+func marshal(L *lua.LState, value interface{}) (lua.LValue, bool) {
+	m, ok := value.(LuaMarshaler)
+	if !ok {
+		return nil, false
+	}
+	if val := reflect.ValueOf(value); val.Kind() == reflect.Ptr && val.IsNil() {
+		return nil, false
+	}
+	return m.ToLua(L), true
+}
+
+// unmarshal converts v to a hint with FromLua, if hint (or a pointer to it)
+// has one
+func unmarshal(L *lua.LState, v lua.LValue, hint reflect.Type, arg string, path string) (reflect.Value, bool) {
+	if hint == nil || v == lua.LNil {
+		return reflect.Value{}, false
+	}
+	if ud, ok := v.(*lua.LUserData); ok && ud.Value != nil && reflect.TypeOf(ud.Value).AssignableTo(hint) {
+		return reflect.Value{}, false
+	}
+	var ptr reflect.Value
+	switch {
+	case reflect.PtrTo(hint).Implements(unmarshalerType):
+		ptr = reflect.New(hint)
+	case hint.Kind() == reflect.Ptr && hint.Implements(unmarshalerType):
+		ptr = reflect.New(hint.Elem())
+	default:
+		return reflect.Value{}, false
+	}
+	if err := ptr.Interface().(LuaUnmarshaler).FromLua(v); err != nil {
+		raiseConversion(L, arg, path, "cannot use %s as %s: %s", v.Type(), hint, err)
+	}
+	if hint.Kind() == reflect.Ptr && ptr.Type() == hint {
+		return ptr, true
+	}
+	return ptr.Elem(), true
+}
@@ -0,0 +1,100 @@
+package luar
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// NewModule returns a loader for a Lua module made of the given values, for
+// L.PreloadModule. When the module is required, each value is converted with
+// New and set in the module's table under its name; a map[string]interface{}
+// becomes a table of its own, converted the same way.
+//
+// The module's table is made once per require, so each state that requires
+// it gets its own.
+func NewModule(values map[string]interface{}) lua.LGFunction {
+	return func(L *lua.LState) int {
+		L.Push(moduleTable(L, values))
+		return 1
+	}
+}
+
+// Module preloads a Lua module made of the given values in L, so scripts can
+// require it by name. It's short for:
+//
+//	L.PreloadModule(name, NewModule(values))
+func Module(L *lua.LState, name string, values map[string]interface{}) {
+	L.PreloadModule(name, NewModule(values))
+}
+
+// anonymous matches the names the runtime gives closures and method values
+var anonymous = regexp.MustCompile(`(\.func\d+(\.\d+)*|-fm)$`)
+
+// PackageOf returns a map of the given functions by their Go names, for
+// NewModule or Module, so a package's functions can be offered without
+// naming each one twice:
+//
+//	luar.Module(L, "strings", luar.PackageOf(strings.ToUpper, strings.Split))
+//	---
+//	local strings = require "strings"
+//	print(strings.ToUpper("hi"))
+//
+// It panics if a value isn't a named function.
+func PackageOf(fns ...interface{}) map[string]interface{} {
+	values := make(map[string]interface{}, len(fns))
+	for _, fn := range fns {
+		value := reflect.ValueOf(fn)
+		if value.Kind() != reflect.Func || value.IsNil() {
+			panic(fmt.Sprintf("luar: PackageOf given %T, not a function", fn))
+		}
+		f := runtime.FuncForPC(value.Pointer())
+		if f == nil || anonymous.MatchString(f.Name()) {
+			panic(fmt.Sprintf("luar: PackageOf given an anonymous %T", fn))
+		}
+		name := f.Name()
+		if i := lastDot(name); i >= 0 {
+			name = name[i+1:]
+		}
+		values[name] = fn
+	}
+	return values
+}
+
+// lastDot is the index of the last dot in a function's name that isn't in
+// its package's import path
+func lastDot(name string) int {
+	for i := len(name) - 1; i >= 0 && name[i] != '/'; i-- {
+		if name[i] == '.' {
+			return i
+		}
+	}
+	return -1
+}
+
+// Loader loads a Lua module of luar's helpers, for L.PreloadModule:
+//
+//	L.PreloadModule("luar", luar.Loader)
+//	---
+//	local luar = require("luar")
+//	local i, v, ok = luar.select(replies, timeout)
+func Loader(L *lua.LState) int {
+	return NewModule(map[string]interface{}{
+		"select": Select,
+	})(L)
+}
+
+func moduleTable(L *lua.LState, values map[string]interface{}) *lua.LTable {
+	table := L.CreateTable(0, len(values))
+	for name, value := range values {
+		if nested, ok := value.(map[string]interface{}); ok {
+			table.RawSetH(lua.LString(name), moduleTable(L, nested))
+			continue
+		}
+		table.RawSetH(lua.LString(name), New(L, value))
+	}
+	return table
+}
@@ -0,0 +1,98 @@
+package luar
+
+import (
+	"reflect"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// Struct types (and pointers to them) opt into Lua's operators by having
+// methods with these names. Each is called on the left operand, with the
+// right one as its argument; a left operand that isn't the type (the 2 in
+// 2 + money, say) is converted to it first.
+var operatorMethods = map[string]string{
+	"__add":    "Add",
+	"__sub":    "Sub",
+	"__mul":    "Mul",
+	"__div":    "Div",
+	"__mod":    "Mod",
+	"__unm":    "Neg",
+	"__eq":     "Equal",
+	"__lt":     "Less",
+	"__le":     "LessOrEqual",
+	"__concat": "Concat",
+}
+
+// addOperators sets the metamethods for the operators t has methods for
+func (info *typeInfo) addOperators(L *lua.LState, t reflect.Type) {
+	for event, name := range operatorMethods {
+		method, ok := info.methods[name]
+		if !ok {
+			continue
+		}
+		var fn lua.LGFunction
+		switch event {
+		case "__unm":
+			fn = func(L *lua.LState) int {
+				L.Push(operatorCall(L, t, method, L.Get(1)))
+				return 1
+			}
+		case "__concat":
+			fn = func(L *lua.LState) int {
+				left, right := L.Get(1), L.Get(2)
+				if !isType(left, t) {
+					// "total: " .. money is just a string
+					L.Push(lua.LString(lua.LVAsString(left) + operatorString(L, right)))
+					return 1
+				}
+				L.Push(operatorCall(L, t, method, left, right))
+				return 1
+			}
+		default:
+			fn = func(L *lua.LState) int {
+				L.Push(operatorCall(L, t, method, L.Get(1), L.Get(2)))
+				return 1
+			}
+		}
+		info.metatable.RawSetH(lua.LString(event), L.NewFunction(fn))
+	}
+
+	// a <= b is not b < a, without LessOrEqual
+	if less, ok := info.methods["Less"]; ok {
+		if _, ok := info.methods["LessOrEqual"]; !ok {
+			info.metatable.RawSetH(lua.LString("__le"), L.NewFunction(func(L *lua.LState) int {
+				L.Push(lua.LBool(!lua.LVAsBool(operatorCall(L, t, less, L.Get(2), L.Get(1)))))
+				return 1
+			}))
+		}
+	}
+}
+
+// isType says whether the Lua value is a Go value of type t
+func isType(value lua.LValue, t reflect.Type) bool {
+	ud, ok := value.(*lua.LUserData)
+	return ok && ud.Value != nil && reflect.TypeOf(ud.Value) == t
+}
+
+// operatorCall calls the method on self (converted to t, if it isn't one),
+// returning its first result
+func operatorCall(L *lua.LState, t reflect.Type, method *typeMethod, self lua.LValue, args ...lua.LValue) lua.LValue {
+	if !isType(self, t) {
+		self = New(L, lValueToReflect(L, self, t).Interface())
+	}
+	L.CallByParam(lua.P{Fn: method.fn, NRet: 1}, append([]lua.LValue{self}, args...)...)
+	ret := L.Get(-1)
+	L.Pop(1)
+	return ret
+}
+
+// operatorString is the value as tostring gives it
+func operatorString(L *lua.LState, value lua.LValue) string {
+	if fn, ok := L.GetMetaField(value, "__tostring").(*lua.LFunction); ok {
+		L.CallByParam(lua.P{Fn: fn, NRet: 1}, value)
+		ret := L.Get(-1)
+		L.Pop(1)
+		return lua.LVAsString(ret)
+	}
+	return value.String()
+}
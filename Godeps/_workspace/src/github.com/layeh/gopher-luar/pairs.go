@@ -0,0 +1,120 @@
+package luar
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// Maps and structs have __pairs, and slices and arrays __ipairs, so the usual
+// for loops work on them. gopher-lua's pairs and ipairs don't look for those
+// metamethods, so the first time a state is given a Go value, its pairs and
+// ipairs are wrapped with ones that do (as Lua 5.2's do).
+
+// installPairs wraps L's pairs and ipairs to call __pairs and __ipairs
+func installPairs(L *lua.LState) {
+	for name, event := range map[string]string{"pairs": "__pairs", "ipairs": "__ipairs"} {
+		original, ok := L.GetGlobal(name).(*lua.LFunction)
+		if !ok {
+			continue
+		}
+		event := event
+		L.SetGlobal(name, L.NewFunction(func(L *lua.LState) int {
+			value := L.CheckAny(1)
+			fn, ok := L.GetMetaField(value, event).(*lua.LFunction)
+			if !ok {
+				fn = original
+			}
+			L.CallByParam(lua.P{Fn: fn, NRet: 3}, value)
+			return 3
+		}))
+	}
+}
+
+// mapPairs iterates over the map's keys and values, in key order if the keys
+// are numbers or strings
+func mapPairs(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.ValueOf(ud.Value)
+	keys := sortedKeys(value)
+	i := 0
+	fn := func(L *lua.LState) int {
+		for ; i < keys.Len(); i++ {
+			item := value.MapIndex(keys.Index(i))
+			if !item.IsValid() {
+				// deleted while iterating
+				continue
+			}
+			L.Push(New(L, keys.Index(i).Interface()))
+			L.Push(New(L, item.Interface()))
+			i++
+			return 2
+		}
+		return 0
+	}
+	L.Push(L.NewFunction(fn))
+	return 1
+}
+
+// structPairs iterates over the struct's fields, by their Lua names, in order
+func structPairs(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.ValueOf(ud.Value)
+	info := getTypeInfo(L, value.Type())
+	value = reflect.Indirect(value)
+
+	names := make([]string, 0, len(info.fields))
+	for name := range info.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	i := 0
+	fn := func(L *lua.LState) int {
+		for ; i < len(names); i++ {
+			field, ok := structField(value, info.fields[names[i]], false)
+			if !ok {
+				// promoted through a nil embedded pointer
+				continue
+			}
+			L.Push(lua.LString(names[i]))
+			L.Push(New(L, field.Interface()))
+			i++
+			return 2
+		}
+		return 0
+	}
+	L.Push(L.NewFunction(fn))
+	return 1
+}
+
+// indexedPairs iterates over a slice or array (or a pointer to one) by index
+func indexedPairs(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.Indirect(reflect.ValueOf(ud.Value))
+	i := 0
+	fn := func(L *lua.LState) int {
+		if i >= value.Len() {
+			return 0
+		}
+		i++
+		L.Push(lua.LNumber(i))
+		L.Push(New(L, value.Index(i-1).Interface()))
+		return 2
+	}
+	L.Push(L.NewFunction(fn))
+	return 1
+}
+
+func ptrPairs(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.ValueOf(ud.Value).Elem()
+	switch value.Kind() {
+	case reflect.Array:
+		return indexedPairs(L)
+	case reflect.Struct:
+		return structPairs(L)
+	}
+	L.RaiseError("unsupported pointer type")
+	return 0
+}
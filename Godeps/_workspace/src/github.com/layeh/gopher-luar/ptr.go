@@ -9,6 +9,9 @@ import (
 
 func ptrToString(L *lua.LState) int {
 	ud := L.CheckUserData(1)
+	if stringerToString(L, ud.Value) {
+		return 1
+	}
 	value := reflect.ValueOf(ud.Value)
 
 	str := fmt.Sprintf("userdata: luar: %s %+v (%p)", value.Type(), value.Interface(), ud.Value)
@@ -20,6 +23,8 @@ func ptrIndex(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 	value := reflect.ValueOf(ud.Value).Elem()
 	switch value.Kind() {
+	case reflect.Array:
+		return arrayIndex(L)
 	case reflect.Struct:
 		return structIndex(L)
 	}
@@ -31,9 +36,22 @@ func ptrNewIndex(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 	value := reflect.ValueOf(ud.Value).Elem()
 	switch value.Kind() {
+	case reflect.Array:
+		return arrayNewIndex(L)
 	case reflect.Struct:
 		return structNewIndex(L)
 	}
 	L.RaiseError("unsupported pointer type")
 	return 0
 }
+
+func ptrLen(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	value := reflect.ValueOf(ud.Value).Elem()
+	switch value.Kind() {
+	case reflect.Array:
+		return arrayLen(L)
+	}
+	L.RaiseError("unsupported pointer type")
+	return 0
+}
@@ -2,6 +2,7 @@ package luar
 
 import (
 	"reflect"
+	"sort"
 
 	"github.com/yuin/gopher-lua"
 )
@@ -20,7 +21,7 @@ func sliceAppend(L *lua.LState) int {
 	hint := slice.Type().Elem()
 	values := make([]reflect.Value, L.GetTop()-1)
 	for i := 2; i <= L.GetTop(); i++ {
-		values[i-2] = lValueToReflect(L.Get(i), hint)
+		values[i-2] = lValueToReflect(L, L.Get(i), hint)
 	}
 
 	newSlice := reflect.Append(slice, values...)
@@ -28,6 +29,124 @@ func sliceAppend(L *lua.LState) int {
 	return 1
 }
 
+func sliceInsert(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	slice := reflect.ValueOf(ud.Value)
+
+	// insert(v) appends, like table.insert
+	index := slice.Len() + 1
+	value := L.Get(2)
+	if L.GetTop() > 2 {
+		index = L.CheckInt(2)
+		value = L.Get(3)
+		if index < 1 || index > slice.Len()+1 {
+			L.ArgError(2, "index out-of-range")
+		}
+	}
+
+	newSlice := reflect.MakeSlice(slice.Type(), slice.Len()+1, slice.Len()+1)
+	reflect.Copy(newSlice, slice.Slice(0, index-1))
+	newSlice.Index(index - 1).Set(lValueToReflect(L, value, slice.Type().Elem()))
+	reflect.Copy(newSlice.Slice(index, newSlice.Len()), slice.Slice(index-1, slice.Len()))
+	L.Push(New(L, newSlice.Interface()))
+	return 1
+}
+
+func sliceRemove(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	slice := reflect.ValueOf(ud.Value)
+
+	// remove() removes the last item, like table.remove
+	index := L.OptInt(2, slice.Len())
+	if slice.Len() == 0 && L.GetTop() < 2 {
+		L.Push(ud)
+		L.Push(lua.LNil)
+		return 2
+	}
+	if index < 1 || index > slice.Len() {
+		L.ArgError(2, "index out-of-range")
+	}
+
+	removed := slice.Index(index - 1).Interface()
+	newSlice := reflect.MakeSlice(slice.Type(), slice.Len()-1, slice.Len()-1)
+	reflect.Copy(newSlice, slice.Slice(0, index-1))
+	reflect.Copy(newSlice.Slice(index-1, newSlice.Len()), slice.Slice(index, slice.Len()))
+	L.Push(New(L, newSlice.Interface()))
+	L.Push(New(L, removed))
+	return 2
+}
+
+func sliceSub(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	slice := reflect.ValueOf(ud.Value)
+	length := slice.Len()
+
+	// negative indices count back from the end, like string.sub
+	i := L.OptInt(2, 1)
+	j := L.OptInt(3, -1)
+	if i < 0 {
+		i += length + 1
+	}
+	if j < 0 {
+		j += length + 1
+	}
+	if i < 1 {
+		i = 1
+	}
+	if j > length {
+		j = length
+	}
+	if i > j {
+		L.Push(New(L, slice.Slice(0, 0).Interface()))
+		return 1
+	}
+	L.Push(New(L, slice.Slice(i-1, j).Interface()))
+	return 1
+}
+
+func sliceSort(L *lua.LState) int {
+	ud := L.CheckUserData(1)
+	slice := reflect.ValueOf(ud.Value)
+
+	var less func(i, j int) bool
+	if L.GetTop() > 1 {
+		fn := L.CheckFunction(2)
+		less = func(i, j int) bool {
+			L.CallByParam(lua.P{Fn: fn, NRet: 1},
+				New(L, slice.Index(i).Interface()),
+				New(L, slice.Index(j).Interface()))
+			ret := L.Get(-1)
+			L.Pop(1)
+			return lua.LVAsBool(ret)
+		}
+	} else {
+		less = naturalLess(slice)
+		if less == nil {
+			L.ArgError(2, "comparator expected, as "+slice.Type().Elem().String()+" has no natural order")
+		}
+	}
+
+	// (sorts in place, like table.sort)
+	sort.Slice(slice.Interface(), less)
+	return 0
+}
+
+// naturalLess orders the slice's items by their values, if they're numbers or
+// strings
+func naturalLess(slice reflect.Value) func(i, j int) bool {
+	switch slice.Type().Elem().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(i, j int) bool { return slice.Index(i).Int() < slice.Index(j).Int() }
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(i, j int) bool { return slice.Index(i).Uint() < slice.Index(j).Uint() }
+	case reflect.Float32, reflect.Float64:
+		return func(i, j int) bool { return slice.Index(i).Float() < slice.Index(j).Float() }
+	case reflect.String:
+		return func(i, j int) bool { return slice.Index(i).String() < slice.Index(j).String() }
+	}
+	return nil
+}
+
 func sliceLen(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 	slice := reflect.ValueOf(ud.Value)
@@ -44,7 +163,7 @@ func sliceNewIndex(L *lua.LState) int {
 	if index < 1 || index > slice.Len() {
 		L.ArgError(2, "index out-of-range")
 	}
-	slice.Index(index - 1).Set(lValueToReflect(value, slice.Type().Elem()))
+	slice.Index(index - 1).Set(lValueToReflect(L, value, slice.Type().Elem()))
 	return 0
 }
 
@@ -66,6 +185,14 @@ func sliceIndex(L *lua.LState) int {
 			L.Push(L.NewFunction(sliceCapacity))
 		case "append":
 			L.Push(L.NewFunction(sliceAppend))
+		case "insert":
+			L.Push(L.NewFunction(sliceInsert))
+		case "remove":
+			L.Push(L.NewFunction(sliceRemove))
+		case "sub":
+			L.Push(L.NewFunction(sliceSub))
+		case "sort":
+			L.Push(L.NewFunction(sliceSort))
 		default:
 			return 0
 		}
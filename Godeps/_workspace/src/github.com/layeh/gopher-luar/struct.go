@@ -2,6 +2,8 @@ package luar
 
 import (
 	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/yuin/gopher-lua"
 )
@@ -13,13 +15,27 @@ func structMethod(L *lua.LState) int {
 	L.Remove(1)
 
 	value := reflect.ValueOf(ud.Value)
-	if value.Kind() == reflect.Ptr {
-		method := value.MethodByName(name)
-		return funcEvaluate(L, method)
+	method, ok := getTypeInfo(L, value.Type()).methods[name]
+	if !ok {
+		L.RaiseError("unknown method %s", name)
+	}
+	if method.ptr {
+		// a method with a pointer receiver: call it on an addressable copy,
+		// and keep whatever it changed
+		ptr := structAddressable(value)
+		ret := funcEvaluate(L, ptr.Method(method.index), method.name)
+		ud.Value = ptr.Elem().Interface()
+		return ret
 	}
+	return funcEvaluate(L, value.Method(method.index), method.name)
+}
 
-	method := value.MethodByName(name)
-	return funcEvaluate(L, method)
+// structAddressable returns a pointer to a copy of the struct value, so its
+// fields can be set and its pointer methods called
+func structAddressable(value reflect.Value) reflect.Value {
+	ptr := reflect.New(value.Type())
+	ptr.Elem().Set(value)
+	return ptr
 }
 
 func structIndex(L *lua.LState) int {
@@ -27,24 +43,22 @@ func structIndex(L *lua.LState) int {
 	name := L.CheckString(2)
 
 	value := reflect.ValueOf(ud.Value)
+	info := getTypeInfo(L, value.Type())
+	if method, ok := info.methods[name]; ok {
+		L.Push(method.fn)
+		return 1
+	}
 	if value.Kind() == reflect.Ptr {
-		if method := value.MethodByName(name); method.IsValid() {
-			L.Push(L.NewClosure(structMethod, lua.LString(name)))
-			return 1
-		}
 		value = value.Elem()
 	}
 
-	if method := value.MethodByName(name); method.IsValid() {
-		L.Push(L.NewClosure(structMethod, lua.LString(name)))
-		return 1
-	}
-
-	field := value.FieldByName(name)
-	if field.IsValid() {
-		if val := New(L, field.Interface()); val != nil {
-			L.Push(val)
-			return 1
+	if index, ok := info.fields[name]; ok {
+		// a field promoted through a nil embedded pointer reads as nil
+		if field, ok := structField(value, index, false); ok {
+			if val := New(L, field.Interface()); val != nil {
+				L.Push(val)
+				return 1
+			}
 		}
 	}
 
@@ -59,9 +73,153 @@ func structNewIndex(L *lua.LState) int {
 	value := reflect.ValueOf(ud.Value)
 	if value.Kind() == reflect.Ptr {
 		value = value.Elem()
+	} else {
+		// struct values aren't addressable, so set the field on a copy
+		ptr := structAddressable(value)
+		defer func() { ud.Value = ptr.Elem().Interface() }()
+		value = ptr.Elem()
 	}
 
-	field := value.FieldByName(name)
-	field.Set(lValueToReflect(lValue, field.Type()))
+	index, ok := getTypeInfo(L, reflect.TypeOf(ud.Value)).fields[name]
+	if !ok {
+		L.RaiseError("unknown field %s", name)
+	}
+	field, ok := structField(value, index, true)
+	if !ok {
+		L.RaiseError("cannot set field %s", name)
+	}
+	field.Set(lValueToReflect(L, lValue, field.Type()))
 	return 0
 }
+
+// structField returns the field of value at index, following embedded
+// pointers on the way. A nil one is allocated if alloc is set (and it can
+// be); otherwise, or if it can't, ok is false.
+func structField(value reflect.Value, index []int, alloc bool) (field reflect.Value, ok bool) {
+	for i, x := range index {
+		if i > 0 && value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				if !alloc || !value.CanSet() {
+					return reflect.Value{}, false
+				}
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(x)
+	}
+	return value, true
+}
+
+// structFields maps the Lua names of a struct type's exported fields to their
+// indexes, including the fields promoted from embedded structs (or pointers
+// to them) by Go's rules. A field is renamed with a `luar:"name"` tag, and
+// hidden from Lua with `luar:"-"`; with JSONNames, json tags do the same for
+// fields with no luar tag.
+type structFields map[string][]int
+
+type structFieldsKey struct {
+	t     reflect.Type
+	names FieldNames
+}
+
+var structFieldCache = struct {
+	sync.Mutex
+	types map[structFieldsKey]structFields
+}{types: make(map[structFieldsKey]structFields)}
+
+// structFieldsOf returns the fields of the struct type t as the state names
+// them, working them out the first time the type is seen
+func structFieldsOf(L *lua.LState, t reflect.Type) structFields {
+	key := structFieldsKey{t, GetConfig(L).FieldNames}
+	structFieldCache.Lock()
+	defer structFieldCache.Unlock()
+	if fields, ok := structFieldCache.types[key]; ok {
+		return fields
+	}
+	fields := structCollectFields(t, key.names)
+	structFieldCache.types[key] = fields
+	return fields
+}
+
+// structCollectFields finds t's fields a level of embedding at a time. Like
+// Go, a field hides any of the same name further down, and two of the same
+// name at the same level hide each other.
+func structCollectFields(t reflect.Type, names FieldNames) structFields {
+	type embedded struct {
+		t     reflect.Type
+		index []int
+	}
+	fields := make(structFields)
+	hidden := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	level := []embedded{{t, nil}}
+	for len(level) > 0 {
+		var next []embedded
+		found := make(structFields)
+		count := make(map[string]int)
+		for _, e := range level {
+			if visited[e.t] {
+				continue
+			}
+			visited[e.t] = true
+			for i := 0; i < e.t.NumField(); i++ {
+				field := e.t.Field(i)
+				name, tagged := structFieldName(field, names)
+				if name == "-" {
+					continue
+				}
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				if field.Anonymous && !tagged {
+					// an unexported embedded struct still promotes its
+					// exported fields
+					embeddedType := field.Type
+					if embeddedType.Kind() == reflect.Ptr {
+						embeddedType = embeddedType.Elem()
+					}
+					if embeddedType.Kind() == reflect.Struct {
+						next = append(next, embedded{embeddedType, index})
+					}
+				}
+				if field.PkgPath != "" {
+					continue
+				}
+				found[name] = index
+				count[name]++
+			}
+		}
+		for name, index := range found {
+			if _, ok := fields[name]; ok || hidden[name] {
+				continue
+			}
+			if count[name] > 1 {
+				hidden[name] = true
+				continue
+			}
+			fields[name] = index
+		}
+		level = next
+	}
+	return fields
+}
+
+// structFieldName is the field's Lua name ("-" if it is hidden), and whether
+// a tag gave it that name
+func structFieldName(field reflect.StructField, names FieldNames) (string, bool) {
+	if tag := field.Tag.Get("luar"); tag != "" {
+		return tag, true
+	}
+	if names == JSONNames {
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			return tag, true
+		}
+		if name := strings.Split(tag, ",")[0]; name != "" {
+			return name, true
+		}
+	}
+	return field.Name, false
+}
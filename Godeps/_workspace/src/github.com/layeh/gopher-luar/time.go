@@ -0,0 +1,174 @@
+package luar
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/yuin/gopher-lua"
+)
+
+// Durations are numbers of seconds in Lua, as they are to os.time and
+// friends, and Lua numbers and strings like "5s" or "1h30m" can be used where
+// Go expects a time.Duration. Times are userdata with time.Time's methods,
+// Lua-style ones (format, unix, add, sub, before and after), and operators:
+// a time plus or minus a duration is a time, one time minus another is the
+// seconds between them, and times compare with < and ==. Lua numbers (seconds
+// since the epoch) and RFC 3339 strings can be used where Go expects a
+// time.Time.
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// timeMethods are what times have besides time.Time's own methods
+var timeMethods map[string]lua.LGFunction
+
+func init() {
+	timeMethods = map[string]lua.LGFunction{
+		"format": timeFormat,
+		"unix":   timeUnix,
+		"add":    timeAdd,
+		"sub":    timeSub,
+		"before": timeBefore,
+		"after":  timeAfter,
+	}
+}
+
+// addTimeMethods gives time.Time (and *time.Time) its Lua methods and
+// operators
+func (info *typeInfo) addTimeMethods(L *lua.LState) {
+	for name, fn := range timeMethods {
+		info.methods[name] = &typeMethod{
+			index: -1,
+			fn:    L.NewFunction(fn),
+			name:  "Time:" + name,
+		}
+	}
+	for event, fn := range map[string]lua.LGFunction{
+		"__add":    timeAddOperator,
+		"__sub":    timeSub,
+		"__lt":     timeBefore,
+		"__le":     timeNotAfter,
+		"__concat": timeConcat,
+	} {
+		info.metatable.RawSetH(lua.LString(event), L.NewFunction(fn))
+	}
+}
+
+// toTime returns the time the Lua value is, if it's one
+func toTime(value lua.LValue) (time.Time, bool) {
+	if ud, ok := value.(*lua.LUserData); ok {
+		switch t := ud.Value.(type) {
+		case time.Time:
+			return t, true
+		case *time.Time:
+			if t != nil {
+				return *t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func checkTime(L *lua.LState, n int) time.Time {
+	t, ok := toTime(L.Get(n))
+	if !ok {
+		L.ArgError(n, "time expected")
+	}
+	return t
+}
+
+func checkDuration(L *lua.LState, n int, name string) time.Duration {
+	return lValueToArg(L, L.Get(n), durationType, n, name).Interface().(time.Duration)
+}
+
+// timeFormat formats the time with a Go layout (RFC 3339 by default)
+func timeFormat(L *lua.LState) int {
+	t := checkTime(L, 1)
+	L.Push(lua.LString(t.Format(L.OptString(2, time.RFC3339))))
+	return 1
+}
+
+// timeUnix returns the seconds since the epoch
+func timeUnix(L *lua.LState) int {
+	L.Push(lua.LNumber(checkTime(L, 1).Unix()))
+	return 1
+}
+
+func timeAdd(L *lua.LState) int {
+	L.Push(New(L, checkTime(L, 1).Add(checkDuration(L, 2, "Time:add"))))
+	return 1
+}
+
+// timeAddOperator adds a duration to a time, whichever side it's on
+func timeAddOperator(L *lua.LState) int {
+	if _, ok := toTime(L.Get(1)); !ok {
+		L.Push(New(L, checkTime(L, 2).Add(checkDuration(L, 1, "Time:add"))))
+		return 1
+	}
+	return timeAdd(L)
+}
+
+// timeSub returns the seconds between two times, or the time a duration
+// before the time
+func timeSub(L *lua.LState) int {
+	t := checkTime(L, 1)
+	if u, ok := toTime(L.Get(2)); ok {
+		L.Push(New(L, t.Sub(u)))
+		return 1
+	}
+	L.Push(New(L, t.Add(-checkDuration(L, 2, "Time:sub"))))
+	return 1
+}
+
+func timeBefore(L *lua.LState) int {
+	L.Push(lua.LBool(checkTime(L, 1).Before(checkTime(L, 2))))
+	return 1
+}
+
+func timeAfter(L *lua.LState) int {
+	L.Push(lua.LBool(checkTime(L, 1).After(checkTime(L, 2))))
+	return 1
+}
+
+func timeNotAfter(L *lua.LState) int {
+	L.Push(lua.LBool(!checkTime(L, 1).After(checkTime(L, 2))))
+	return 1
+}
+
+func timeConcat(L *lua.LState) int {
+	L.Push(lua.LString(operatorString(L, L.Get(1)) + operatorString(L, L.Get(2))))
+	return 1
+}
+
+// timeConvert converts Lua numbers and strings to durations and times, when
+// hint is one
+func timeConvert(L *lua.LState, v lua.LValue, hint reflect.Type, arg string, path string) (reflect.Value, bool) {
+	switch hint {
+	case durationType:
+		switch converted := v.(type) {
+		case lua.LNumber:
+			return reflect.ValueOf(time.Duration(float64(converted) * float64(time.Second))), true
+		case lua.LString:
+			d, err := time.ParseDuration(string(converted))
+			if err != nil {
+				raiseConversion(L, arg, path, "cannot use %q as time.Duration (try \"90s\" or \"1h30m\")", string(converted))
+			}
+			return reflect.ValueOf(d), true
+		}
+	case timeType:
+		switch converted := v.(type) {
+		case lua.LNumber:
+			seconds := float64(converted)
+			return reflect.ValueOf(time.Unix(int64(seconds), int64((seconds-float64(int64(seconds)))*1e9))), true
+		case lua.LString:
+			t, err := time.Parse(time.RFC3339, string(converted))
+			if err != nil {
+				raiseConversion(L, arg, path, "cannot use %q as time.Time (try \"2006-01-02T15:04:05Z\")", string(converted))
+			}
+			return reflect.ValueOf(t), true
+		}
+	}
+	return reflect.Value{}, false
+}
@@ -16,23 +16,51 @@ func typeToString(L *lua.LState) int {
 	return 1
 }
 
+// typeCall makes a new value of the type, like make() for channels (with an
+// optional buffer size), maps (with an optional size) and slices (with an
+// optional length and capacity), and new() for anything else. Maps, slices,
+// arrays and structs can be given a table to start with instead.
 func typeCall(L *lua.LState) int {
 	ud := L.CheckUserData(1)
 
 	refType := ud.Value.(reflect.Type)
+	name := refType.String()
+	init := L.Get(2)
+	_, isTable := init.(*lua.LTable)
 	var value reflect.Value
 	switch refType.Kind() {
 	case reflect.Chan:
 		buffer := L.OptInt(2, 0)
+		if buffer < 0 {
+			L.RaiseError("negative buffer size for %s", name)
+		}
 		value = reflect.MakeChan(refType, buffer)
 	case reflect.Map:
-		value = reflect.MakeMap(refType)
+		if isTable {
+			value = lValueToArg(L, init, refType, 1, name)
+			break
+		}
+		size := L.OptInt(2, 0)
+		if size < 0 {
+			L.RaiseError("negative size for %s", name)
+		}
+		value = reflect.MakeMapWithSize(refType, size)
 	case reflect.Slice:
-		length := L.CheckInt(2)
+		if isTable {
+			value = lValueToArg(L, init, refType, 1, name)
+			break
+		}
+		length := L.OptInt(2, 0)
 		capacity := L.OptInt(3, length)
+		if length < 0 || capacity < length {
+			L.RaiseError("bad length (%d) or capacity (%d) for %s", length, capacity, name)
+		}
 		value = reflect.MakeSlice(refType, length, capacity)
 	default:
 		value = reflect.New(refType)
+		if init != lua.LNil {
+			value.Elem().Set(lValueToArg(L, init, refType, 1, name))
+		}
 	}
 	L.Push(New(L, value.Interface()))
 	return 1
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// brain looks at (and cleans up) the brain lazlo is configured to use,
+// without starting the bot.
+//
+//   lazlo brain ls [prefix]
+//   lazlo brain get <key>
+//   lazlo brain del <key>...
+//   lazlo brain stats
+//   lazlo brain rotate
+func brain(args []string) error {
+	usage := fmt.Errorf("usage: lazlo brain ls [prefix] | get <key> | del <key>... | stats | rotate")
+	if len(args) == 0 {
+		return usage
+	}
+	b, config, err := lazlo.OpenBrain()
+	if err != nil {
+		return err
+	}
+	defer b.Close()
+	if config.RedisURL == `` {
+		fmt.Fprintln(os.Stderr, "(lazlo's using the in-memory brain, which only lasts as long as lazlo does; set LAZLO_REDIS_URL to look at redis)")
+	}
+
+	switch args[0] {
+	case `ls`:
+		keys, err := b.Keys()
+		if err != nil {
+			return err
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if len(args) < 2 || strings.HasPrefix(key, args[1]) {
+				fmt.Println(key)
+			}
+		}
+	case `get`:
+		if len(args) != 2 {
+			return usage
+		}
+		data, err := b.Get(args[1])
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(data)
+		fmt.Println()
+	case `del`:
+		if len(args) < 2 {
+			return usage
+		}
+		for _, key := range args[1:] {
+			if err := b.Delete(key); err != nil {
+				return err
+			}
+			fmt.Println("deleted", key)
+		}
+	case `stats`:
+		stats, err := lazlo.BrainStats(b, config)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "namespace\tkeys\tbytes\tlimits")
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", s.Namespace, s.Keys, s.Bytes, s)
+		}
+		w.Flush()
+	case `rotate`:
+		rotated, err := lazlo.RotateBrain(b)
+		if err != nil {
+			return err
+		}
+		fmt.Println("re-encrypted", rotated, "values")
+	default:
+		return usage
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	lua "github.com/yuin/gopher-lua"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// doctor checks lazlo's configuration, and the lua scripts it would load,
+// without starting the bot, and prints what to do about anything that's
+// broken.
+//
+//   lazlo doctor [-channel #lazlo-test] [-lua lua]
+func doctor(args []string) error {
+	flags := flag.NewFlagSet(`doctor`, flag.ExitOnError)
+	channel := flags.String(`channel`, ``, `a channel to test posting, reacting and uploading in`)
+	luaDir := flags.String(`lua`, `lua`, `the lua plugin directory`)
+	flags.Parse(args)
+
+	checks := lazlo.Diagnose(*channel)
+	checks = append(checks, checkLua(*luaDir)...)
+
+	failed := 0
+	for _, check := range checks {
+		if check.Err == nil {
+			fmt.Printf("  ok    %s\n", check.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("  FAIL  %s: %s\n        -> %s\n", check.Name, strings.TrimSpace(check.Err.Error()), check.Advice)
+	}
+	if *channel == `` {
+		fmt.Println("(use -channel to check posting, reacting and uploading too)")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(checks))
+	}
+	fmt.Println("everything looks good")
+	return nil
+}
+
+// checkLua compiles (without running) every script in the lua directory
+func checkLua(dir string) []lazlo.Checkup {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return []lazlo.Checkup{{
+			Name:   `lua scripts`,
+			Err:    err,
+			Advice: `create the lua directory next to the lazlo binary, or point -lua at it`,
+		}}
+	}
+	var checks []lazlo.Checkup
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		file := filepath.Join(dir, f.Name())
+		L := lua.NewState()
+		_, err := L.LoadFile(file)
+		L.Close()
+		checks = append(checks, lazlo.Checkup{
+			Name:   `lua script ` + file,
+			Err:    err,
+			Advice: `fix the syntax error (LuaMod will refuse to start until you do)`,
+		})
+	}
+	return checks
+}
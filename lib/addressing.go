@@ -0,0 +1,97 @@
+package lib
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Respond callbacks only hear messages addressed to lazlo, and how people
+// address it can be different in each channel:
+//
+//	casual    "hustlebot, deploy api", "@hustlebot deploy api" or a mention (the default)
+//	mention   only a mention: "<@hustlebot> deploy api"
+//	! or .    a prefix (any punctuation will do): "!deploy api", or a mention
+//
+// LAZLO_ADDRESSING picks the style for every channel, and a channel's
+// addressing attr in the registry (kind "channel") picks it for that channel.
+// Modules that need to know whether a message was meant for lazlo (outside
+// of a respond callback) should ask Addressed rather than matching the
+// bot's name themselves.
+
+// Addressing styles (anything else is a prefix)
+const (
+	AddressCasual  = `casual`
+	AddressMention = `mention`
+)
+
+// Addressing is how people address lazlo in the channel
+func (b *Broker) Addressing(channel string) string {
+	style := b.Config.Addressing
+	if b.Registry != nil && b.SlackMeta != nil {
+		if c := b.SlackMeta.GetChannel(channel); c != nil {
+			if entity := b.Registry.Get(`channel`, c.Name); entity != nil && entity.Attrs[`addressing`] != `` {
+				style = entity.Attrs[`addressing`]
+			}
+		}
+	}
+	style = strings.TrimSpace(style)
+	switch {
+	case style == AddressCasual, style == AddressMention:
+		return style
+	case style == ``, strings.IndexFunc(style, func(r rune) bool { return !unicode.IsPunct(r) && !unicode.IsSymbol(r) }) >= 0:
+		if style != `` {
+			Logger.Debug(`Broker:: unknown addressing style `, style, ` for `, channel, `; using casual`)
+		}
+		return AddressCasual
+	}
+	return style
+}
+
+// addressPattern matches the start of a message addressed to lazlo in the
+// channel, up to whatever comes after the address
+func (b *Broker) addressPattern(channel string) string {
+	name := `(?i:` + regexp.QuoteMeta(b.Config.Name) + `)`
+	var mention []string
+	if b.SlackMeta != nil && b.SlackMeta.Self.ID != `` {
+		mention = append(mention, `<@`+regexp.QuoteMeta(b.SlackMeta.Self.ID)+`>[:,]?\s+`)
+	}
+	if len(mention) == 0 || !b.slackMarkup() {
+		// without slack's markup, a mention is just @name
+		mention = append(mention, `@`+name+`[:,]?\s+`)
+	}
+	switch style := b.Addressing(channel); style {
+	case AddressCasual:
+		return `^(?:` + strings.Join(append(mention, `@?`+name+`[:,]?\s+`), `|`) + `)`
+	case AddressMention:
+		return `^(?:` + strings.Join(mention, `|`) + `)`
+	default:
+		return `^(?:` + strings.Join(append(mention, regexp.QuoteMeta(style)+`\s*`), `|`) + `)`
+	}
+}
+
+// Addressed says whether the text, said in the channel, was addressed to
+// lazlo, and returns what was said to it
+func (b *Broker) Addressed(channel string, text string) (string, bool) {
+	loc := regexp.MustCompile(b.addressPattern(channel)).FindStringIndex(text)
+	if loc == nil {
+		return text, false
+	}
+	return text[loc[1]:], true
+}
+
+// addressText addresses the text to lazlo the way the channel does, for
+// handing to the message callbacks as if someone had said it
+func (b *Broker) addressText(channel string, text string) string {
+	switch style := b.Addressing(channel); style {
+	case AddressCasual:
+		return b.Config.Name + ` ` + text
+	case AddressMention:
+		if b.SlackMeta != nil && b.SlackMeta.Self.ID != `` {
+			return `<@` + b.SlackMeta.Self.ID + `> ` + text
+		}
+		return `@` + b.Config.Name + ` ` + text
+	default:
+		return style + text
+	}
+}
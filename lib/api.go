@@ -3,7 +3,6 @@ package lib
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/fatih/structs"
 	"github.com/gorilla/websocket"
 	"net/http"
 	"net/url"
@@ -24,6 +23,9 @@ func MakeAPIReq(req ApiRequest) (*ApiResponse, error) {
 	if req.Values.Get(`token`) == `` {
 		req.Values.Set(`token`, req.Broker.Config.Token)
 	}
+	if req.Values.Get(`team_id`) == `` && req.Broker.Config.TeamID != `` {
+		req.Values.Set(`team_id`, req.Broker.Config.TeamID)
+	}
 	if req.Values.Get(`as_user`) == `` {
 		req.Values.Set(`as_user`, req.Broker.Config.Name)
 	}
@@ -100,8 +102,12 @@ func (meta *ApiResponse) GetUser(id string) *User {
 }
 
 // GetUserByName is a convience function to return a pointer to a user
-// object given its Name
+// object given its Name. If users in several workspaces have the name, the
+// one in our workspace wins.
 func (meta *ApiResponse) GetUserByName(name string) *User {
+	if u := meta.GetUserInTeam(name, meta.Team.ID); u != nil {
+		return u
+	}
 	for _, user := range meta.Users {
 		if user.Name == name {
 			return &user
@@ -140,16 +146,31 @@ func (event *Event) Reply(s string) chan map[string]interface{} {
 
 // Respond is a convienence function to RESPOND to a given event object
 func (event *Event) Respond(s string) chan map[string]interface{} {
-	return event.Broker.Send(&Event{
+	return event.send(&Event{
 		Type:    event.Type,
 		Channel: event.Channel,
 		Text:    s,
 	})
 }
 
+// RespondInThread is a convienence function to RESPOND to a given event
+// object in its thread (starting one if it isn't in one already)
+func (event *Event) RespondInThread(s string) chan map[string]interface{} {
+	thread := event.ThreadTs
+	if thread == `` {
+		thread = event.Ts
+	}
+	return event.send(&Event{
+		Type:     `message`,
+		Channel:  event.Channel,
+		Text:     s,
+		ThreadTs: thread,
+	})
+}
+
 // RespondAttachments is a function to RESPOND WITH ATTACHMENTS to a given event object
 func (event *Event) RespondAttachments(a []Attachment) chan map[string]interface{} {
-	return event.Broker.Send(&Event{
+	return event.send(&Event{
 		Type:        event.Type,
 		Channel:     event.Channel,
 		Text:        "",
@@ -166,7 +187,7 @@ func (event *Event) GetDM(s string) string {
 // doesn't seem to support their own markup syntax. So anything that looks
 // like it has markup in it is sent into this function by the write thread
 // instead of into the websocket where it belongs.
-func apiPostMessage(e Event) {
+func apiPostMessage(e Event, persona *Persona) {
 	Logger.Debug(`Posting through api`)
 	var req = ApiRequest{
 		URL:    `https://slack.com/api/chat.postMessage`,
@@ -175,19 +196,43 @@ func apiPostMessage(e Event) {
 	}
 	req.Values.Set(`channel`, e.Channel)
 	req.Values.Set(`text`, e.Text)
+	if e.ThreadTs != `` {
+		req.Values.Set(`thread_ts`, e.ThreadTs)
+	}
 	if e.Attachments != nil {
 		aJson, _ := json.Marshal(e.Attachments)
 		req.Values.Set(`attachments`, string(aJson))
 	}
+	if e.Blocks != nil {
+		bJson, _ := json.Marshal(e.Blocks)
+		req.Values.Set(`blocks`, string(bJson))
+	}
 	req.Values.Set(`id`, strconv.Itoa(int(e.ID)))
 	req.Values.Set(`as_user`, e.Broker.Config.Name)
+	if persona != nil {
+		persona.set(req.Values)
+	}
 	req.Values.Set(`pretty`, `1`)
-	authResp, _ := MakeAPIReq(req)
-	s := structs.New(authResp) // convert this to a map[string]interface{} why not? hax.
-	resp := s.Map()
-	if replyVal, isReply := resp[`reply_to`]; isReply {
-		if replyVal != nil {
-			e.Broker.handleApiReply(resp)
+	req.Values.Set(`token`, e.Broker.Config.Token)
+
+	// chat.postMessage answers with the channel as a string, so decode the
+	// reply into a map rather than an ApiResponse, and hand it to whoever's
+	// waiting on it the same way a websocket reply would be
+	resp := make(map[string]interface{})
+	reply, err := http.PostForm(req.URL, req.Values)
+	if err != nil {
+		Logger.Error(`couldn't post through api: `, err)
+		resp[`ok`] = false
+		resp[`error`] = err.Error()
+	} else {
+		defer reply.Body.Close()
+		if err := json.NewDecoder(reply.Body).Decode(&resp); err != nil {
+			Logger.Error(`couldn't decode chat.postMessage reply: `, err)
+			resp[`ok`] = false
+		} else if ok, _ := resp[`ok`].(bool); !ok {
+			Logger.Error(`chat.postMessage failed: `, resp[`error`])
 		}
 	}
+	resp[`reply_to`] = float64(e.ID)
+	e.Broker.handleApiReply(resp)
 }
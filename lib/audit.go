@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+// The audit log records who had lazlo do what, for the things that matter
+// (running commands on servers, changing permissions). Entries are appended
+// to LAZLO_AUDIT_LOG as JSON, one per line, and logged as well.
+
+// An AuditEntry is one thing somebody had lazlo do
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	User    string    `json:"user"`
+	Module  string    `json:"module"`
+	Action  string    `json:"action"`
+	Target  string    `json:"target,omitempty"`
+	Detail  string    `json:"detail,omitempty"`
+	Allowed bool      `json:"allowed"`
+	ID      string    `json:"id,omitempty"`     // for an action that can be undone
+	Undoes  string    `json:"undoes,omitempty"` // the ID of the action this undid
+}
+
+var auditLock sync.Mutex
+
+// Audit records that the user had lazlo do action (or tried to, if it
+// wasn't allowed) to target
+func (b *Broker) Audit(user string, action string, target string, detail string, allowed bool) {
+	entry := AuditEntry{
+		Time:    time.Now(),
+		User:    user,
+		Module:  b.callerModule(),
+		Action:  action,
+		Target:  target,
+		Detail:  detail,
+		Allowed: allowed,
+	}
+	b.audit(entry)
+}
+
+// audit logs the entry, with the user's name
+func (b *Broker) audit(entry AuditEntry) {
+	if name := b.SlackMeta.GetUserName(entry.User); name != `` {
+		entry.User = entry.User + ` (` + name + `)`
+	}
+	data, _ := json.Marshal(entry)
+	Logger.Info(`Audit:: `, string(data))
+	if b.Config.AuditLog == `` {
+		return
+	}
+	auditLock.Lock()
+	defer auditLock.Unlock()
+	f, err := os.OpenFile(b.Config.AuditLog, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		Logger.Error(`Audit:: couldn't open the audit log: `, err)
+		return
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		Logger.Error(`Audit:: couldn't write the audit log: `, err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/garyburd/redigo/redis"
 	"net/url"
+	"strings"
 )
 
 // Top-level exported Store interface for storage backends to implement
@@ -13,6 +14,14 @@ type Brain interface {
 	Get(string) ([]byte, error)
 	Set(key string, data []byte) error
 	Delete(string) error
+	Keys() ([]string, error)
+}
+
+// UserKey builds a brain key for data that belongs to a specific user, so it
+// can be found again if the user asks us to forget about them
+// (see Broker.ExpungeUser)
+func UserKey(namespace string, userID string, parts ...string) string {
+	return strings.Join(append([]string{namespace, userID}, parts...), `:`)
 }
 
 // NewStore returns an initialized store
@@ -30,6 +39,22 @@ func (b *Broker) newBrain() (Brain, error) {
 			return brain, err
 		}
 	}
+	b.brainGC = newGCBrain(brain, b.Config)
+	brain = b.brainGC
+	key, err := b.Secret(`LAZLO_BRAIN_KEY`)
+	if err != nil {
+		return brain, err
+	}
+	if key != `` {
+		Logger.Debug(`Brain:: encrypting brain values at rest`)
+		oldKeys, err := b.Secret(`LAZLO_BRAIN_OLD_KEYS`)
+		if err != nil {
+			return brain, err
+		}
+		if brain, err = newCryptBrain(brain, key, splitList(oldKeys)...); err != nil {
+			return brain, err
+		}
+	}
 	return brain, nil
 }
 
@@ -75,6 +100,14 @@ func (rb *ramBrain) Delete(key string) error {
 	return nil
 }
 
+func (rb *ramBrain) Keys() ([]string, error) {
+	keys := make([]string, 0, len(rb.data))
+	for key := range rb.data {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
 //redisbrain backend storage implementation
 type redisBrain struct {
 	url       string
@@ -157,6 +190,19 @@ func (rb *redisBrain) Delete(key string) error {
 	return nil
 }
 
+func (rb *redisBrain) Keys() ([]string, error) {
+	res, err := redis.Strings(rb.client.Do("KEYS", rb.namespace(`*`)))
+	if err != nil {
+		return nil, err
+	}
+	prefix := rb.namespace(``)
+	keys := make([]string, len(res))
+	for i, key := range res {
+		keys[i] = strings.TrimPrefix(key, prefix)
+	}
+	return keys, nil
+}
+
 func (rb *redisBrain) namespace(key string) string {
 	return fmt.Sprintf("%s:%s", rb.nameSpace, key)
 }
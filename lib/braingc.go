@@ -0,0 +1,288 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Some of what modules keep in the brain is only worth keeping for a while,
+// and a module with a bug can fill it up. LAZLO_BRAIN_TTLS says how long
+// keys in a namespace (the part of the key before the first colon) live
+// after they were last written (history=720h,...), and LAZLO_BRAIN_QUOTAS
+// caps how many keys a namespace can have (the oldest go first). Redis
+// expires keys itself; for brains that can't, a background GC deletes them
+// every LAZLO_BRAIN_GC_INTERVAL minutes. Keys that were already there when
+// lazlo started count as written then.
+
+// An ExpiringBrain can expire keys by itself
+type ExpiringBrain interface {
+	// Expire deletes the key after ttl, unless it's written again first
+	Expire(key string, ttl time.Duration) error
+}
+
+// A NamespaceStats describes a brain namespace
+type NamespaceStats struct {
+	Namespace string
+	Keys      int
+	Bytes     int
+	TTL       time.Duration // 0 if keys don't expire
+	Quota     int           // 0 if there isn't one
+}
+
+// gcBrain wraps a brain backend, remembering when keys were written so they
+// can be expired and held to their quotas
+type gcBrain struct {
+	Brain
+	lock    sync.Mutex
+	native  ExpiringBrain // nil if the backend can't expire keys
+	written map[string]time.Time
+	ttls    map[string]time.Duration
+	quotas  map[string]int
+}
+
+func newGCBrain(brain Brain, c *Config) *gcBrain {
+	gb := &gcBrain{
+		Brain:   brain,
+		written: make(map[string]time.Time),
+		ttls:    brainTTLs(c),
+		quotas:  brainQuotas(c),
+	}
+	gb.native, _ = brain.(ExpiringBrain)
+	return gb
+}
+
+// brainTTLs reads LAZLO_BRAIN_TTLS
+func brainTTLs(c *Config) map[string]time.Duration {
+	ttls := make(map[string]time.Duration)
+	for _, item := range splitList(c.BrainTTLs) {
+		parts := strings.SplitN(item, `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		ttl, err := time.ParseDuration(strings.TrimSpace(parts[1]))
+		if err != nil || ttl <= 0 {
+			Logger.Error(`Brain:: bad TTL for `, parts[0], `: `, parts[1])
+			continue
+		}
+		ttls[strings.TrimSpace(parts[0])] = ttl
+	}
+	return ttls
+}
+
+// brainQuotas reads LAZLO_BRAIN_QUOTAS
+func brainQuotas(c *Config) map[string]int {
+	quotas := make(map[string]int)
+	for _, item := range splitList(c.BrainQuotas) {
+		parts := strings.SplitN(item, `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		quota, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || quota <= 0 {
+			Logger.Error(`Brain:: bad quota for `, parts[0], `: `, parts[1])
+			continue
+		}
+		quotas[strings.TrimSpace(parts[0])] = quota
+	}
+	return quotas
+}
+
+// brainNamespace is the part of the key before the first colon
+func brainNamespace(key string) string {
+	if i := strings.Index(key, `:`); i >= 0 {
+		return key[:i]
+	}
+	return key
+}
+
+func (gb *gcBrain) Get(key string) ([]byte, error) {
+	gb.lock.Lock()
+	defer gb.lock.Unlock()
+	return gb.Brain.Get(key)
+}
+
+func (gb *gcBrain) Set(key string, data []byte) error {
+	gb.lock.Lock()
+	defer gb.lock.Unlock()
+	if err := gb.Brain.Set(key, data); err != nil {
+		return err
+	}
+	gb.written[key] = time.Now()
+	if ttl, ok := gb.ttls[brainNamespace(key)]; ok && gb.native != nil {
+		return gb.native.Expire(key, ttl)
+	}
+	return nil
+}
+
+func (gb *gcBrain) Delete(key string) error {
+	gb.lock.Lock()
+	defer gb.lock.Unlock()
+	delete(gb.written, key)
+	return gb.Brain.Delete(key)
+}
+
+func (gb *gcBrain) Keys() ([]string, error) {
+	gb.lock.Lock()
+	defer gb.lock.Unlock()
+	return gb.Brain.Keys()
+}
+
+// GC deletes the keys that have outlived their TTL, and the oldest keys in
+// namespaces over their quota. It returns how many keys it deleted.
+func (gb *gcBrain) GC() (int, error) {
+	gb.lock.Lock()
+	defer gb.lock.Unlock()
+	keys, err := gb.Brain.Keys()
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now()
+	byNamespace := make(map[string][]string)
+	deleted := 0
+	for _, key := range keys {
+		when, ok := gb.written[key]
+		if !ok {
+			// we haven't seen this one written, so it's new to us
+			when = now
+			gb.written[key] = when
+			if ttl, ok := gb.ttls[brainNamespace(key)]; ok && gb.native != nil {
+				if err := gb.native.Expire(key, ttl); err != nil {
+					Logger.Error(`Brain:: couldn't set a TTL on `, key, `: `, err)
+				}
+			}
+		}
+		ttl, ok := gb.ttls[brainNamespace(key)]
+		if ok && gb.native == nil && now.Sub(when) > ttl {
+			if err := gb.Brain.Delete(key); err == nil {
+				delete(gb.written, key)
+				deleted++
+			}
+			continue
+		}
+		byNamespace[brainNamespace(key)] = append(byNamespace[brainNamespace(key)], key)
+	}
+	for namespace, quota := range gb.quotas {
+		keys := byNamespace[namespace]
+		if len(keys) <= quota {
+			continue
+		}
+		sort.Sort(&byWritten{keys, gb.written})
+		for _, key := range keys[:len(keys)-quota] {
+			if err := gb.Brain.Delete(key); err == nil {
+				delete(gb.written, key)
+				deleted++
+			}
+		}
+		Logger.Info(`Brain:: `, namespace, ` had `, len(keys), ` keys, over its quota of `, quota)
+	}
+	// forget about keys that were deleted behind our back
+	if len(gb.written) > len(keys) {
+		present := make(map[string]bool, len(keys))
+		for _, key := range keys {
+			present[key] = true
+		}
+		for key := range gb.written {
+			if !present[key] {
+				delete(gb.written, key)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// byWritten sorts keys oldest first
+type byWritten struct {
+	keys    []string
+	written map[string]time.Time
+}
+
+func (w *byWritten) Len() int      { return len(w.keys) }
+func (w *byWritten) Swap(i, j int) { w.keys[i], w.keys[j] = w.keys[j], w.keys[i] }
+func (w *byWritten) Less(i, j int) bool {
+	return w.written[w.keys[i]].Before(w.written[w.keys[j]])
+}
+
+// runGC collects the brain's garbage every LAZLO_BRAIN_GC_INTERVAL minutes
+// (never, if that's 0)
+func (gb *gcBrain) runGC(interval time.Duration) {
+	if interval <= 0 || len(gb.ttls) == 0 && len(gb.quotas) == 0 {
+		return
+	}
+	// see what's there now, so it ages from here
+	gb.GC()
+	for range time.Tick(interval) {
+		deleted, err := gb.GC()
+		if err != nil {
+			Logger.Error(`Brain:: GC: `, err)
+			continue
+		}
+		if deleted > 0 {
+			Logger.Info(`Brain:: GC deleted `, deleted, ` keys`)
+		}
+	}
+}
+
+func (rb *redisBrain) Expire(key string, ttl time.Duration) error {
+	_, err := rb.client.Do("EXPIRE", rb.namespace(key), int64(ttl/time.Second))
+	return err
+}
+
+// OpenBrain opens the brain lazlo is configured to use, for tools that work
+// on it without starting the bot
+func OpenBrain() (Brain, *Config, error) {
+	b := &Broker{Config: newConfig()}
+	brain, err := b.newBrain()
+	if err != nil {
+		return nil, nil, err
+	}
+	return brain, b.Config, brain.Open()
+}
+
+// BrainStats counts the keys, and the bytes in them, in each namespace of
+// the brain
+func BrainStats(brain Brain, c *Config) ([]NamespaceStats, error) {
+	keys, err := brain.Keys()
+	if err != nil {
+		return nil, err
+	}
+	ttls, quotas := brainTTLs(c), brainQuotas(c)
+	stats := make(map[string]*NamespaceStats)
+	for _, key := range keys {
+		namespace := brainNamespace(key)
+		s, ok := stats[namespace]
+		if !ok {
+			s = &NamespaceStats{Namespace: namespace, TTL: ttls[namespace], Quota: quotas[namespace]}
+			stats[namespace] = s
+		}
+		s.Keys++
+		if data, err := brain.Get(key); err == nil {
+			s.Bytes += len(data)
+		}
+	}
+	var names []string
+	for name := range stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var out []NamespaceStats
+	for _, name := range names {
+		out = append(out, *stats[name])
+	}
+	return out, nil
+}
+
+// String describes the namespace's TTL and quota
+func (s NamespaceStats) String() string {
+	var limits []string
+	if s.TTL > 0 {
+		limits = append(limits, fmt.Sprintf("keys live %s", s.TTL))
+	}
+	if s.Quota > 0 {
+		limits = append(limits, fmt.Sprintf("at most %d keys", s.Quota))
+	}
+	return strings.Join(limits, `, `)
+}
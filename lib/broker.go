@@ -6,22 +6,31 @@ import (
 	"fmt"
 	"github.com/ccding/go-logging/logging"
 	"github.com/gorilla/websocket"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net"
+	"net/http"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Logger is a global reference to our logging object
 var Logger = newLogger()
 
-// These contstats define the four types of callbacks that lazlo can hand you
+// These contstats define the types of callbacks that lazlo can hand you
 const M = "messages"
 const E = "events"
 const T = "timers"
 const L = "links"
 const Q = "questions"
+const P = "topics"
+const A = "actions"
+const V = "views"
 
 // Broker is the all-knowing repository of references
 type Broker struct {
@@ -40,14 +49,59 @@ type Broker struct {
 	SigChan        chan os.Signal
 	SyncChan       chan bool
 	ThreadCount    int32
+	Redactors      []*Redactor
+	History        *History
+	expungeHooks   []ExpungeHook
+	certs          *certStore
+	traces         *traceBuffer
+	cbSeq          int64
+	Fetcher        *Fetcher
+	prompts        *promptStore
+	Registry       *Registry
+	templates      *templateFuncs
+	fake           *fakeAdapter // stands in for slack in plugin tests
+	home           *homeSections
+	Emoji          *EmojiCatalog
+	Experiments    *Experiments
+	Ignore         *IgnoreList
+	brainGC        *gcBrain
+	ConfigSync     *ConfigSync
+	started        time.Time
+	plugins        map[string]string // plugin name -> version
+	pluginLock     sync.Mutex
+	handover       *handover // what the old lazlo left us, if we're an upgrade
+	listeners      map[string]net.Listener
+	listenLock     sync.Mutex
+	inflight       sync.WaitGroup
+	upgrading      int32
+	readDone       chan struct{}
+	lastTs         string // the newest event we've read
+	watchdog       *watchdog
+	socketLock     sync.Mutex // guards Socket, which the watchdog swaps
+	delegations    *delegations
+	ResponseCache  *ResponseCache
+	Jobs           *JobManager
+	Metrics        *Metrics
+	once           *onceLog
+	undo           *undoStack
+	startup        *startupGate                  // holds events until the modules are ready
+	moduleStats    map[string]func() interface{} // name -> what it adds to RuntimeStats
+	statsLock      sync.Mutex
+	deprecations   map[string]*Deprecation // plugin+api -> its uses
+	deprecLock     sync.Mutex
+	LangDetector   LanguageDetector // guesses what language messages are in (nil for the built-in one)
 }
 
 // The Module type represents a user-defined plug-in. Build one of these
 // and add it to loadModules.go for Lazlo to run your thingy on startup
 type Module struct {
-	Name  string
-	Usage string
-	Run   func(*Broker)
+	Name    string
+	Usage   string
+	Version string // optional, reported by BuildInfo
+	Run     func(*Broker)
+	// ExternalSafe modules hear from people from other orgs in shared
+	// channels (see policy.go)
+	ExternalSafe bool
 }
 
 // The WriteThread serielizes and sends messages to the slack RTM interface
@@ -71,17 +125,17 @@ type ReadFilter struct {
 	Run   func(thingy map[string]interface{}) map[string]interface{}
 }
 
-// WriteFilter is a yet-to-be-implemented hook run on all outbound
-// events from slack before the broker gets a hold of them
+// WriteFilter is a hook run on every outbound event before it's formatted
+// and sent, after the decorators (see decorators.go). Filters run in the
+// order they were registered.
 type WriteFilter struct {
 	Name  string
 	Usage string
 	Run   func(e *Event)
 }
 
-// NewBroker instantiates a new broker
-func NewBroker() (*Broker, error) {
-
+// newBroker makes a broker that isn't connected to anything yet
+func newBroker() *Broker {
 	broker := &Broker{
 		MID:          0,
 		Config:       newConfig(),
@@ -95,19 +149,51 @@ func NewBroker() (*Broker, error) {
 		QuestionThread: &QuestionThread{
 			userdex: make(map[string]QuestionQueue),
 		},
-		SigChan:  make(chan os.Signal),
-		SyncChan: make(chan bool),
+		SigChan:   make(chan os.Signal),
+		SyncChan:  make(chan bool),
+		started:   time.Now(),
+		plugins:   make(map[string]string),
+		listeners: make(map[string]net.Listener),
+		readDone:  make(chan struct{}),
 	}
-	//correctly set the log level
-	Logger.SetLevel(logging.GetLevelValue(strings.ToUpper(broker.Config.LogLevel)))
-
 	broker.cbIndex[M] = make(map[string]interface{})
 	broker.cbIndex[E] = make(map[string]interface{})
 	broker.cbIndex[T] = make(map[string]interface{})
 	broker.cbIndex[L] = make(map[string]interface{})
 	broker.cbIndex[Q] = make(map[string]interface{})
+	broker.cbIndex[P] = make(map[string]interface{})
+	broker.cbIndex[A] = make(map[string]interface{})
+	broker.cbIndex[V] = make(map[string]interface{})
 	broker.WriteThread.broker = broker
 	broker.QuestionThread.broker = broker
+	broker.home = new(homeSections)
+	broker.Emoji = newEmojiCatalog(broker)
+	broker.Experiments = newExperiments(broker)
+	broker.watchdog = newWatchdog(broker)
+	broker.delegations = newDelegations()
+	broker.ResponseCache = newResponseCache()
+	broker.Metrics = newMetrics(broker)
+	broker.undo = newUndoStack()
+	return broker
+}
+
+// NewBroker instantiates a new broker
+func NewBroker() (*Broker, error) {
+	broker := newBroker()
+	broker.handover = inheritHandover()
+	if broker.Config.SyncRepo != `` {
+		// the config repo can hold settings too, so read them again
+		broker.ConfigSync = newConfigSync(broker)
+		broker.Config = newConfig()
+	}
+	//correctly set the log level
+	Logger.SetLevel(logging.GetLevelValue(strings.ToUpper(broker.Config.LogLevel)))
+	broker.Redactors = newRedactors(broker.Config)
+	broker.History = newHistory(broker.Config.HistorySize)
+	broker.OnExpunge(broker.History.forget)
+	broker.traces = newTraceBuffer(broker.Config.TraceSize)
+	broker.Fetcher = newFetcher(broker.Config)
+	broker.prompts = newPromptStore(broker.SyncPath(`prompts`, broker.Config.PromptDir))
 
 	//connect to slack and establish an RTM websocket
 	socket, meta, err := broker.getASocket()
@@ -117,6 +203,13 @@ func NewBroker() (*Broker, error) {
 	broker.Socket = socket
 	broker.SlackMeta = meta
 
+	for _, name := range splitList(broker.Config.SensitiveChannels) {
+		if c := meta.GetChannelByName(strings.TrimPrefix(name, `#`)); c != nil {
+			name = c.ID
+		}
+		broker.History.SetSensitive(name, true)
+	}
+
 	broker.Brain, err = broker.newBrain()
 	if err != nil {
 		return nil, err
@@ -126,11 +219,21 @@ func NewBroker() (*Broker, error) {
 		Logger.Error(`couldn't open mah brain! `, err)
 		return broker, err
 	}
+	go broker.brainGC.runGC(time.Duration(broker.Config.BrainGCInterval) * time.Minute)
+	broker.Registry = newRegistry(broker)
+	broker.templates = newTemplateFuncs(broker)
+	broker.Ignore = newIgnoreList(broker)
+	broker.Jobs = newJobManager(broker)
+	broker.once = newOnceLog(broker)
 	return broker, nil
 }
 
 // Stop gracefully stops lazlo
 func (broker *Broker) Stop() {
+	if !broker.handingOver() {
+		// (if we handed over, the new lazlo has the totals now)
+		broker.Metrics.stop()
+	}
 	// make sure the write thread finishes before we stop
 	broker.WriteThread.SyncChan <- true
 }
@@ -140,18 +243,78 @@ func (broker *Broker) Start() {
 	go broker.StartHttp()
 	go broker.WriteThread.Start()
 	go broker.QuestionThread.Start()
+	go broker.watchdog.run()
+	go broker.Metrics.run()
+	if broker.handover != nil {
+		grace := time.Duration(broker.Config.UpgradeGrace) * time.Second
+		broker.handover.takeOver(grace)
+		if broker.Config.RedisURL == `` {
+			if err := broker.handover.restoreBrain(broker.Brain, grace); err != nil {
+				Logger.Error(`Upgrade:: couldn't restore the brain: `, err)
+			}
+		}
+	}
 	Logger.Debug(`Broker:: entering read-loop`)
 	for {
 		thingy := make(map[string]interface{})
-		broker.Socket.ReadJSON(&thingy)
-		go broker.This(thingy)
+		socket := broker.socket()
+		err := socket.ReadJSON(&thingy)
+		if err != nil && broker.handingOver() {
+			close(broker.readDone)
+			return
+		}
+		if err != nil {
+			// nothing to dispatch, and unless slack just sent us something
+			// we couldn't decode, the socket's gone
+			switch err.(type) {
+			case *json.SyntaxError, *json.UnmarshalTypeError:
+				Logger.Error(`Broker:: couldn't decode an event: `, err)
+			default:
+				if !broker.watchdog.reconnect(socket, fmt.Sprintf("lost the connection to slack (%v)", err)) {
+					time.Sleep(watchdogRetry)
+				}
+			}
+			continue
+		}
+		broker.watchdog.saw()
+		if broker.handover.seen(thingy) {
+			continue
+		}
+		if ts := eventTs(thingy); ts > broker.lastTs {
+			broker.lastTs = ts
+		}
+		if broker.startup.hold(thingy) {
+			continue
+		}
+		broker.dispatch(thingy)
 	}
 }
 
-// StartModules launches each user-provided plugin registered in loadMOdules.go
+// dispatch hands an event from slack on to be handled
+func (broker *Broker) dispatch(thingy map[string]interface{}) {
+	broker.inflight.Add(1)
+	go func() {
+		defer broker.inflight.Done()
+		broker.This(thingy)
+	}()
+}
+
+// StartModules launches each user-provided plugin registered in loadMOdules.go,
+// holding events from slack until they're all ready (see startup.go)
 func (b *Broker) StartModules() {
+	b.startup = newStartupGate(b)
+	time.AfterFunc(time.Duration(b.Config.StartupTimeout)*time.Second, func() {
+		b.startup.release(`I've waited long enough`)
+	})
+	if len(b.Modules) == 0 {
+		b.startup.release(``)
+	}
 	for _, module := range b.Modules {
-		go module.Run(b)
+		go func(m *Module) {
+			m.Run(b)
+			// (a module that gave up early isn't worth waiting for)
+			b.startup.ready(m.Name)
+		}(module)
 	}
 }
 
@@ -162,25 +325,19 @@ func (w *WriteThread) Start() {
 	for !stop {
 		select {
 		case e := <-w.Chan:
-			Logger.Debug(`WriteThread:: Outbound `, e.Type, ` channel: `, e.Channel, `. text: `, e.Text)
+			Logger.Debug(`WriteThread:: Outbound `, e.Type, ` channel: `, e.Channel, `. text: `, w.broker.Redact(e.Text))
 			ejson := stupidUTFHack(e)
-			if len(ejson) >= 16000 {
-				e = Event{
-					ID:      e.ID,
-					Type:    e.Type,
-					Channel: e.Channel,
-					Text:    fmt.Sprintf("ERROR! Response too large. %v Bytes!", len(ejson)),
-				}
-				ejson = stupidUTFHack(e)
-			}
-			if matches, _ := regexp.MatchString(`<[hH#@].+>`, string(ejson)); matches || e.Attachments != nil {
+			// the websocket can't post under another name, or take more than
+			// 16k, either
+			persona := w.broker.Persona(e.Persona, e.Channel)
+			if matches, _ := regexp.MatchString(`<[hH#@].+>`, string(ejson)); matches || e.Attachments != nil || e.Blocks != nil || persona != nil || len(ejson) >= 16000 {
 				Logger.Debug(`message formatting detected; sending via api`)
 				e.Broker = w.broker
-				apiPostMessage(e)
+				apiPostMessage(e, persona)
 			} else {
-				w.broker.Socket.WriteMessage(1, ejson)
+				w.broker.socket().WriteMessage(1, ejson)
 			}
-			Logger.Debug(string(ejson))
+			Logger.Debug(w.broker.Redact(string(ejson)))
 			time.Sleep(time.Second * 1)
 		case stop = <-w.SyncChan:
 			stop = true
@@ -316,6 +473,10 @@ func (b *Broker) Register(things ...interface{}) {
 			w := thing.(*WriteFilter)
 			Logger.Debug(`registered Write Filter: `, w.Name)
 			b.WriteFilters = append(b.WriteFilters, w)
+		case *Redactor:
+			r := thing.(*Redactor)
+			Logger.Debug(`registered Redactor: `, r.Name)
+			b.Redactors = append(b.Redactors, r)
 		default:
 			weirdType := fmt.Sprintf(`%T`, t)
 			Logger.Error(`sorry I cant register this handler because I don't know what a `, weirdType, ` is`)
@@ -333,7 +494,6 @@ func (b *Broker) handleApiReply(thingy map[string]interface{}) {
 		//dont leak channels
 		Logger.Debug(`deleting callback: `, chanID)
 		close(callBackChannel)
-		<-callBackChannel
 		delete(b.ApiResponses, chanID)
 	} else {
 		Logger.Debug(`no such channel: `, chanID)
@@ -346,15 +506,38 @@ func (b *Broker) handleMessage(thingy map[string]interface{}) {
 	if b.cbIndex[M] == nil {
 		return
 	}
+	if why := b.Ignore.Ignored(thingy); why != `` {
+		Logger.Debug(`Broker:: ignoring a message because of `, why)
+		return
+	}
 	message := new(Event)
 	jthingy, _ := json.Marshal(thingy)
 	json.Unmarshal(jthingy, message)
 	message.Broker = b
-	botNamePat := fmt.Sprintf(`^(?:@?%s[:,]?)\s+(?:${1})`, b.Config.Name)
-	for _, cbInterface := range b.cbIndex[M] {
-		callback := cbInterface.(*MessageCallback)
+	message.annotations = &annotations{data: make(map[string]interface{})}
+	message.Entities = b.ExtractEntities(message.Text)
+	message.Lang = b.messageLang(message)
+	if message.User != `` && b.SlackMeta.GetUser(message.User) == nil {
+		// probably someone from another workspace in a shared channel
+		b.LookupUser(message.User)
+	}
+	message.External = b.eventExternal(thingy)
 
+	remembered := *message
+	remembered.Text = b.Redact(message.Text)
+	b.History.record(remembered)
+	if b.handleDelegation(message) {
+		return
+	}
+	b.Metrics.Add(messagesCounter, ``, 1)
+
+	botNamePat := b.addressPattern(message.Channel) + `(?:${1})`
+	answered := false // by a respond callback
+	for _, callback := range b.sortedMessageCallbacks() {
 		Logger.Debug(`Broker:: checking callback: `, callback.ID)
+		if message.External && !b.externalAllowed(callback.module) {
+			continue
+		}
 		if callback.SlackChan != `` {
 			if callback.SlackChan != message.Channel {
 				Logger.Debug(`Broker:: dropping message because chan mismatch: `, callback.ID)
@@ -370,9 +553,48 @@ func (b *Broker) handleMessage(thingy map[string]interface{}) {
 			r = regexp.MustCompile(callback.Pattern)
 		}
 		if r.MatchString(message.Text) {
+			answered = answered || callback.Respond
 			match := r.FindAllStringSubmatch(message.Text, -1)[0]
+			pm := PatternMatch{Event: message, Match: match}
+			if callback.Once {
+				record, run := b.once.claim(callback, match, message)
+				if record != nil {
+					Logger.Debug(`Broker:: not running callback `, callback.ID, ` again for `, record.Key)
+					b.once.answer(record, message)
+					continue
+				}
+				once := *pm.Event
+				once.once = run
+				pm.Event = &once
+			}
+			if callback.Cache > 0 {
+				if b.ResponseCache.replay(callback, match, message) {
+					Logger.Debug(`Broker:: answered from the cache for callback: `, callback.ID)
+					continue
+				}
+				cached := *pm.Event
+				cached.cache = b.ResponseCache.start(callback, match, message.User)
+				pm.Event = &cached
+			}
 			Logger.Debug(`Broker:: firing callback: `, callback.ID)
-			callback.Chan <- PatternMatch{Event: message, Match: match}
+			b.Metrics.Add(`invocations`, callback.module, 1)
+			if callback.Blocking {
+				pm.done = make(chan struct{}, 1)
+			}
+			callback.Chan <- pm
+			if callback.Blocking {
+				select {
+				case <-pm.done:
+				case <-time.After(pipelineTimeout):
+					Logger.Error(`Broker:: gave up waiting for callback `, callback.ID, ` to call Done()`)
+				}
+			}
+		}
+	}
+	if !answered {
+		addressed := regexp.MustCompile(strings.Replace(botNamePat, "${1}", `(.+)`, 1))
+		if m := addressed.FindStringSubmatch(message.Text); m != nil {
+			go b.suggest(message, m[1])
 		}
 	}
 }
@@ -381,10 +603,18 @@ func (b *Broker) handleEvent(thingy map[string]interface{}) {
 	if b.cbIndex[E] == nil {
 		return
 	}
+	if why := b.Ignore.Ignored(thingy); why != `` {
+		Logger.Debug(`Broker:: ignoring a `, thingy[`type`], ` event because of `, why)
+		return
+	}
+	external := b.eventExternal(thingy)
 	for _, cbInterface := range b.cbIndex[E] {
 		callback := cbInterface.(*EventCallback)
-		if keyVal, keyExists := thingy[callback.Key]; keyExists && keyVal != nil {
-			if matches, _ := regexp.MatchString(callback.Val, keyVal.(string)); matches {
+		if external && !b.externalAllowed(callback.module) {
+			continue
+		}
+		if keyVal, keyExists := thingy[callback.Key].(string); keyExists {
+			if matches, _ := regexp.MatchString(callback.Val, keyVal); matches {
 				Logger.Debug(`Broker:: firing callback: `, callback.ID)
 				callback.Chan <- thingy
 			}
@@ -394,13 +624,44 @@ func (b *Broker) handleEvent(thingy map[string]interface{}) {
 
 // this is the primary interface to Slack's write socket. Use this to send events.
 func (b *Broker) Send(e *Event) chan map[string]interface{} {
+	b.writeFilter(e)
+	e.Text = b.ExpandTemplate(e.Text, e.Channel)
+	parts := b.Format(e)
+	if len(parts) > 1 {
+		return b.sendLong(parts)
+	}
+	return b.send(parts[0])
+}
+
+// send sends one (formatted) message
+func (b *Broker) send(e *Event) chan map[string]interface{} {
 	e.ID = b.NextMID()
+	if reply, ok := b.captureDelegated(e); ok {
+		return reply
+	}
+	if b.fake != nil {
+		return b.fake.send(*e)
+	}
 	b.ApiResponses[e.ID] = make(chan map[string]interface{}, 1)
 	Logger.Debug(`created APIResponse: `, e.ID)
 	b.WriteThread.Chan <- *e
 	return b.ApiResponses[e.ID]
 }
 
+// Publish hands data to every TopicCallback subscribed to the topic. Each
+// subscriber gets the message on its own goroutine, so publishing never
+// blocks (and subscribers shouldn't count on messages arriving in order).
+func (b *Broker) Publish(topic string, data interface{}) {
+	msg := TopicMessage{Topic: topic, Data: data}
+	for _, cbInterface := range b.cbIndex[P] {
+		callback := cbInterface.(*TopicCallback)
+		if callback.Topic == topic {
+			Logger.Debug(`Broker:: publishing `, topic, ` to: `, callback.ID)
+			go func(c chan TopicMessage) { c <- msg }(callback.Chan)
+		}
+	}
+}
+
 // Say something in the named channel (or the default channel if none specified)
 func (b *Broker) Say(s string, channel ...string) chan map[string]interface{} {
 	var c string
@@ -418,7 +679,10 @@ func (b *Broker) Say(s string, channel ...string) chan map[string]interface{} {
 }
 
 // send a reply to any sort of thingy that contains an ID and Channel attribute
+//
+// Deprecated: use Event.Respond, or Event.Reply to address the user.
 func (b *Broker) Respond(text string, thing *interface{}, isReply bool) chan map[string]interface{} {
+	b.deprecated(`Broker.Respond`, `Event.Respond or Event.Reply`)
 	var id, channel string
 	var exists bool
 
@@ -462,6 +726,9 @@ func (b *Broker) Respond(text string, thing *interface{}, isReply bool) chan map
 
 //Get a direct message channel ID so we can DM the given user
 func (b *Broker) GetDM(ID string) string {
+	if b.fake != nil {
+		return `D` + ID
+	}
 	req := ApiRequest{ //use the web api so we don't block waiting for the read thread
 		URL:    `https://slack.com/api/im.open`,
 		Values: make(url.Values),
@@ -477,6 +744,114 @@ func (b *Broker) GetDM(ID string) string {
 	}
 }
 
+// React adds an emoji reaction (by name, without the colons) to a message
+func (b *Broker) React(channel string, ts string, emoji string) error {
+	req := ApiRequest{
+		URL:    `https://slack.com/api/reactions.add`,
+		Values: make(url.Values),
+		Broker: b,
+	}
+	req.Values.Set(`channel`, channel)
+	req.Values.Set(`timestamp`, ts)
+	req.Values.Set(`name`, emoji)
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return err
+	}
+	if !reply.Ok && reply.Error != `already_reacted` {
+		return fmt.Errorf("couldn't react with %s: %s", emoji, reply.Error)
+	}
+	return nil
+}
+
+// Upload shares a file in the given channel, with an optional comment
+func (b *Broker) Upload(channel string, filename string, data []byte, comment string) error {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	form.WriteField(`token`, b.Config.Token)
+	form.WriteField(`channels`, channel)
+	form.WriteField(`filename`, filename)
+	if comment != `` {
+		form.WriteField(`initial_comment`, comment)
+	}
+	file, _ := form.CreateFormFile(`file`, filename)
+	file.Write(data)
+	form.Close()
+
+	reply, err := http.Post(`https://slack.com/api/files.upload`, form.FormDataContentType(), &body)
+	if err != nil {
+		return err
+	}
+	defer reply.Body.Close()
+	resp := new(ApiResponse)
+	if err := json.NewDecoder(reply.Body).Decode(resp); err != nil {
+		return fmt.Errorf("Couldn't decode json. ERR: %v", err)
+	}
+	if !resp.Ok {
+		return fmt.Errorf("couldn't upload %s: %s", filename, resp.Error)
+	}
+	return nil
+}
+
+// Download fetches a file someone shared, refusing ones bigger than max
+// bytes (if max isn't 0)
+func (b *Broker) Download(file File, max int) ([]byte, error) {
+	if max > 0 && file.Size > max {
+		return nil, fmt.Errorf("%s is too big (%d bytes)", file.Name, file.Size)
+	}
+	req, err := http.NewRequest(`GET`, file.URLPrivate, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(`Authorization`, `Bearer `+b.Config.Token)
+	reply, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer reply.Body.Close()
+	if reply.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("couldn't download %s: %s", file.Name, reply.Status)
+	}
+	var body io.Reader = reply.Body
+	if max > 0 {
+		body = io.LimitReader(body, int64(max)+1)
+	}
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, err
+	}
+	if max > 0 && len(data) > max {
+		return nil, fmt.Errorf("%s is too big", file.Name)
+	}
+	return data, nil
+}
+
+// Permalink returns a link to the given message
+func (b *Broker) Permalink(channel string, ts string) string {
+	return fmt.Sprintf("https://%s.slack.com/archives/%s/p%s", b.SlackMeta.Team.Domain, channel, strings.Replace(ts, `.`, ``, 1))
+}
+
+// IsAdmin returns true if the given user may run admin commands. If
+// LAZLO_ADMINS is set (for the user's workspace) only the users listed there
+// are admins, otherwise we defer to the user's Slack admin/owner status.
+// Users from other orgs are never admins unless they're listed.
+func (b *Broker) IsAdmin(ID string) bool {
+	user := b.SlackMeta.GetUser(ID)
+	config := b.Config
+	if user != nil {
+		config = b.ConfigFor(user.TeamID)
+	}
+	if admins := splitList(config.Admins); admins != nil {
+		for _, admin := range admins {
+			if admin == ID || (user != nil && admin == user.Name) {
+				return true
+			}
+		}
+		return false
+	}
+	return user != nil && !b.IsExternal(ID) && (user.IsAdmin || user.IsOwner || user.IsPrimaryOwner)
+}
+
 //returns the Team's default channel
 func (b *Broker) DefaultChannel() string {
 	for _, c := range b.SlackMeta.Channels {
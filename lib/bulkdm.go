@@ -0,0 +1,164 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// A BulkDM sends the same (templated) direct message to a bunch of users.
+// The template is a text/template that's handed a map with the recipient's
+// ID, Name, FirstName and RealName, plus any per-user Fields.
+type BulkDM struct {
+	Template   string
+	Recipients []string                     // user IDs
+	Fields     map[string]map[string]string // extra template fields by user ID
+	PerMinute  int                          // rate limit (LAZLO_BULK_DM_RATE if 0)
+}
+
+// A RenderedDM is the message one recipient of a BulkDM will get
+type RenderedDM struct {
+	User string
+	Text string
+}
+
+// Render renders the template for every recipient without sending anything
+// (a dry-run). It fails if the template refers to a field some recipient
+// doesn't have.
+func (bd *BulkDM) Render(b *Broker) ([]RenderedDM, error) {
+	tmpl, err := template.New(`bulkdm`).Option(`missingkey=error`).Parse(bd.Template)
+	if err != nil {
+		return nil, err
+	}
+	var out []RenderedDM
+	for _, id := range bd.Recipients {
+		data := map[string]string{`ID`: id}
+		if user := b.SlackMeta.GetUser(id); user != nil {
+			data[`Name`] = user.Name
+			data[`RealName`] = user.RealName
+			data[`FirstName`] = user.Profile.FirstName
+			if data[`FirstName`] == `` {
+				data[`FirstName`] = user.Name
+			}
+		}
+		for key, val := range bd.Fields[id] {
+			data[key] = val
+		}
+		var text bytes.Buffer
+		if err := tmpl.Execute(&text, data); err != nil {
+			return nil, fmt.Errorf("couldn't render message for %s: %v", id, err)
+		}
+		out = append(out, RenderedDM{User: id, Text: text.String()})
+	}
+	return out, nil
+}
+
+// Send renders and sends every message, no faster than PerMinute. It returns
+// the number of messages sent.
+func (bd *BulkDM) Send(b *Broker) (int, error) {
+	msgs, err := bd.Render(b)
+	if err != nil {
+		return 0, err
+	}
+	rate := bd.PerMinute
+	if rate <= 0 {
+		rate = b.Config.BulkDMRate
+	}
+	var delay time.Duration
+	if rate > 0 {
+		delay = time.Minute / time.Duration(rate)
+	}
+	sent := 0
+	for i, msg := range msgs {
+		if i > 0 {
+			time.Sleep(delay)
+		}
+		dm := b.GetDM(msg.User)
+		if dm == `` {
+			Logger.Error(`BulkDM:: couldn't open a DM with `, msg.User)
+			continue
+		}
+		b.Say(msg.Text, dm)
+		sent++
+	}
+	Logger.Info(`BulkDM:: sent `, sent, ` of `, len(msgs), ` messages`)
+	return sent, nil
+}
+
+var (
+	userRefPat    = regexp.MustCompile(`^<@(\w+)(?:\|[^>]*)?>$`)
+	channelRefPat = regexp.MustCompile(`^<#(\w+)(?:\|[^>]*)?>$`)
+	groupRefPat   = regexp.MustCompile(`^<!subteam\^(\w+)(?:\|[^>]*)?>$`)
+)
+
+// ResolveUsers turns a list of user mentions, channel mentions, usergroup
+// mentions and plain user names into a de-duplicated list of user IDs.
+// Channels and usergroups are expanded to their members.
+func (b *Broker) ResolveUsers(refs []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	for _, ref := range refs {
+		if m := userRefPat.FindStringSubmatch(ref); m != nil {
+			add(m[1])
+		} else if m := channelRefPat.FindStringSubmatch(ref); m != nil {
+			channel := b.SlackMeta.GetChannel(m[1])
+			if channel == nil {
+				return nil, fmt.Errorf("I don't know the channel %s", ref)
+			}
+			for _, id := range channel.Members {
+				add(id)
+			}
+		} else if m := groupRefPat.FindStringSubmatch(ref); m != nil {
+			members, err := b.usergroupMembers(m[1])
+			if err != nil {
+				return nil, err
+			}
+			for _, id := range members {
+				add(id)
+			}
+		} else if user := b.SlackMeta.GetUserByName(strings.TrimPrefix(ref, `@`)); user != nil {
+			add(user.ID)
+		} else {
+			return nil, fmt.Errorf("I don't know who %s is", ref)
+		}
+	}
+	return ids, nil
+}
+
+// usergroupMembers asks slack for the members of a usergroup. The reply's
+// "users" is a list of IDs rather than user objects, so it doesn't fit in an
+// ApiResponse.
+func (b *Broker) usergroupMembers(id string) ([]string, error) {
+	values := url.Values{}
+	values.Set(`token`, b.Config.Token)
+	values.Set(`usergroup`, id)
+	reply, err := http.PostForm(`https://slack.com/api/usergroups.users.list`, values)
+	if err != nil {
+		return nil, err
+	}
+	defer reply.Body.Close()
+	resp := struct {
+		Ok    bool     `json:"ok"`
+		Error string   `json:"error"`
+		Users []string `json:"users"`
+	}{}
+	if err := json.NewDecoder(reply.Body).Decode(&resp); err != nil {
+		return nil, fmt.Errorf("Couldn't decode json. ERR: %v", err)
+	}
+	if !resp.Ok {
+		return nil, fmt.Errorf("couldn't list usergroup %s: %s", id, resp.Error)
+	}
+	return resp.Users, nil
+}
@@ -0,0 +1,196 @@
+package lib
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Some commands are expensive to answer and don't change much ("who's on
+// call", the weather). Set Cache on their MessageCallback:
+//
+//	cb := b.MessageCallback(`(?i)weather in (.+)`, true)
+//	cb.Cache = 10 * time.Minute
+//
+// and what the module says in answer (with Respond, Reply and friends) is
+// remembered, by the command and its arguments (the regex's groups, lowercased,
+// with the spaces squashed), and said again for the same command until the
+// time's up, without bothering the module. Only cache commands whose answer
+// is the same for everyone who asks. b.ResponseCache.Bust forgets answers,
+// and so does "lazlo cache bust".
+
+// how long after a command what the module says counts as its answer
+const cacheRecordWindow = 30 * time.Second
+
+// the number of cached answers after which we start throwing out stale ones
+const maxResponseCache = 1000
+
+// A CachedResponse is what a module said in answer to a command
+type CachedResponse struct {
+	Module  string
+	Command string // the normalized command
+	Stored  time.Time
+	TTL     time.Duration
+	user    string // whose name replies were addressed to
+	said    []Event
+	run     *cacheRun
+}
+
+// ResponseCache remembers what cached message callbacks said
+type ResponseCache struct {
+	lock    sync.Mutex
+	entries map[string]*CachedResponse
+}
+
+// a cacheRun records what a module says in answer to one message
+type cacheRun struct {
+	cache   *ResponseCache
+	key     string
+	module  string
+	command string
+	user    string
+	ttl     time.Duration
+	started time.Time
+}
+
+func newResponseCache() *ResponseCache {
+	return &ResponseCache{entries: make(map[string]*CachedResponse)}
+}
+
+// cacheCommand normalizes the command a callback matched: its arguments,
+// case-folded, with the spaces squashed
+func cacheCommand(match []string) string {
+	return Fold(strings.Join(strings.Fields(strings.Join(match[1:], ` `)), ` `))
+}
+
+// start begins recording what the callback's module says in answer to the
+// message
+func (rc *ResponseCache) start(callback *MessageCallback, match []string, user string) *cacheRun {
+	command := cacheCommand(match)
+	return &cacheRun{
+		cache:   rc,
+		key:     callback.module + "\x00" + callback.Pattern + "\x00" + command,
+		module:  callback.module,
+		command: command,
+		user:    user,
+		ttl:     callback.Cache,
+		started: time.Now(),
+	}
+}
+
+// record adds something the module said to its answer
+func (run *cacheRun) record(e Event) {
+	if time.Since(run.started) > cacheRecordWindow {
+		return
+	}
+	rc := run.cache
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	entry := rc.entries[run.key]
+	if entry == nil || entry.run != run {
+		if len(rc.entries) >= maxResponseCache {
+			rc.prune()
+		}
+		entry = &CachedResponse{Module: run.module, Command: run.command, Stored: time.Now(), TTL: run.ttl, user: run.user, run: run}
+		rc.entries[run.key] = entry
+	}
+	entry.said = append(entry.said, e)
+}
+
+// prune throws out stale answers, or everything if none are
+func (rc *ResponseCache) prune() {
+	for key, entry := range rc.entries {
+		if time.Since(entry.Stored) > entry.TTL {
+			delete(rc.entries, key)
+		}
+	}
+	if len(rc.entries) >= maxResponseCache {
+		rc.entries = make(map[string]*CachedResponse)
+	}
+}
+
+// replay says the cached answer to the command again, in answer to the
+// message, returning false if there isn't a fresh one
+func (rc *ResponseCache) replay(callback *MessageCallback, match []string, message *Event) bool {
+	run := rc.start(callback, match, message.User)
+	rc.lock.Lock()
+	entry := rc.entries[run.key]
+	if entry == nil || time.Since(entry.Stored) > entry.TTL {
+		rc.lock.Unlock()
+		return false
+	}
+	said := append([]Event{}, entry.said...)
+	rc.lock.Unlock()
+
+	b := message.Broker
+	was, now := b.SlackMeta.GetUserName(entry.user)+`: `, b.SlackMeta.GetUserName(message.User)+`: `
+	for _, e := range said {
+		e.Channel = message.Channel
+		if e.ThreadTs != `` {
+			e.ThreadTs = message.ThreadTs
+			if e.ThreadTs == `` {
+				e.ThreadTs = message.Ts
+			}
+		}
+		if strings.HasPrefix(e.Text, was) {
+			e.Text = now + strings.TrimPrefix(e.Text, was)
+		}
+		b.Send(&e)
+	}
+	return true
+}
+
+// Bust forgets the cached answers of the module, or to commands that start
+// with what (or every answer, if what is empty). It returns how many it
+// forgot.
+func (rc *ResponseCache) Bust(what string) int {
+	what = strings.ToLower(strings.TrimSpace(what))
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	busted := 0
+	for key, entry := range rc.entries {
+		if what == `` || strings.ToLower(entry.Module) == what || strings.HasPrefix(entry.Command, what) {
+			delete(rc.entries, key)
+			busted++
+		}
+	}
+	return busted
+}
+
+// Entries lists the fresh cached answers, by module and command
+func (rc *ResponseCache) Entries() []CachedResponse {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+	var entries []CachedResponse
+	for _, entry := range rc.entries {
+		if time.Since(entry.Stored) <= entry.TTL {
+			entries = append(entries, *entry)
+		}
+	}
+	sort.Sort(byModuleCommand(entries))
+	return entries
+}
+
+type byModuleCommand []CachedResponse
+
+func (l byModuleCommand) Len() int      { return len(l) }
+func (l byModuleCommand) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l byModuleCommand) Less(i, j int) bool {
+	if l[i].Module != l[j].Module {
+		return l[i].Module < l[j].Module
+	}
+	return l[i].Command < l[j].Command
+}
+
+// send sends something said in answer to the event, remembering it if the
+// answer's being cached (or is the answer to an exactly-once command)
+func (event *Event) send(e *Event) chan map[string]interface{} {
+	if event.cache != nil {
+		event.cache.record(*e)
+	}
+	if event.once != nil {
+		event.once.record(*e)
+	}
+	return event.Broker.Send(e)
+}
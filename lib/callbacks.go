@@ -10,19 +10,27 @@ type MessageCallback struct {
 	Pattern   string
 	Respond   bool // if true, only respond if the bot is mentioned by name
 	Chan      chan PatternMatch
-	SlackChan string // if set filter message callbacks to this Slack channel
+	SlackChan string        // if set filter message callbacks to this Slack channel
+	Priority  int           // callbacks with lower priorities see messages first
+	Blocking  bool          // if true, later callbacks wait for PatternMatch.Done()
+	Cache     time.Duration // if set, answers are said again for the same command for this long (see cache.go)
+	Once      bool          // if true, the same command isn't run twice (see once.go)
+	seq       int64
+	module    string // the module that registered it
 }
 
 type PatternMatch struct {
 	Event *Event
 	Match []string
+	done  chan struct{}
 }
 
 type EventCallback struct {
-	ID   string
-	Key  string
-	Val  string
-	Chan chan map[string]interface{}
+	ID     string
+	Key    string
+	Val    string
+	Chan   chan map[string]interface{}
+	module string // the module that registered it
 }
 
 type TimerCallback struct {
@@ -43,6 +51,34 @@ type QuestionCallback struct {
 	asked    bool
 }
 
+type TopicCallback struct {
+	ID    string
+	Topic string
+	Chan  chan TopicMessage
+}
+
+type TopicMessage struct {
+	Topic string
+	Data  interface{}
+}
+
+// An ActionCallback gets clicks on the buttons lazlo posted with its action ID
+type ActionCallback struct {
+	ID     string
+	Action string
+	Chan   chan Action
+}
+
+// A ModalCallback gets the (validated) submissions of the modal it defines
+// (see modal.go)
+type ModalCallback struct {
+	ID     string
+	Name   string
+	Modal  *Modal
+	Chan   chan ModalSubmission
+	broker *Broker
+}
+
 type QuestionQueue struct {
 	in chan *QuestionCallback
 }
@@ -59,7 +95,9 @@ func (b *Broker) RegisterCallback(callback interface{}) error {
 		Logger.Debug("New Callback Registered, id:", e.ID)
 	case *TimerCallback:
 		t := callback.(*TimerCallback)
-		t.Start()
+		if err := t.Start(); err != nil {
+			return err
+		}
 		b.cbIndex[T][t.ID] = callback
 		Logger.Debug("New Callback Registered, id:", t.ID)
 	case *LinkCallback:
@@ -70,6 +108,18 @@ func (b *Broker) RegisterCallback(callback interface{}) error {
 		q := callback.(*QuestionCallback)
 		b.cbIndex[Q][q.ID] = callback
 		Logger.Debug("New Callback Registered, id:", q.ID)
+	case *TopicCallback:
+		p := callback.(*TopicCallback)
+		b.cbIndex[P][p.ID] = callback
+		Logger.Debug("New Callback Registered, id:", p.ID)
+	case *ActionCallback:
+		a := callback.(*ActionCallback)
+		b.cbIndex[A][a.ID] = callback
+		Logger.Debug("New Callback Registered, id:", a.ID)
+	case *ModalCallback:
+		v := callback.(*ModalCallback)
+		b.cbIndex[V][v.ID] = callback
+		Logger.Debug("New Callback Registered, id:", v.ID)
 	default:
 		err := fmt.Errorf("unknown type in register callback: %T", callback)
 		Logger.Error(err)
@@ -102,6 +152,18 @@ func (b *Broker) DeRegisterCallback(callback interface{}) error {
 		q := callback.(*QuestionCallback)
 		delete(b.cbIndex[Q], q.ID)
 		Logger.Debug("De-Registered callback, id: ", q.ID)
+	case *TopicCallback:
+		p := callback.(*TopicCallback)
+		delete(b.cbIndex[P], p.ID)
+		Logger.Debug("De-Registered callback, id: ", p.ID)
+	case *ActionCallback:
+		a := callback.(*ActionCallback)
+		delete(b.cbIndex[A], a.ID)
+		Logger.Debug("De-Registered callback, id: ", a.ID)
+	case *ModalCallback:
+		v := callback.(*ModalCallback)
+		delete(b.cbIndex[V], v.ID)
+		Logger.Debug("De-Registered callback, id: ", v.ID)
 	default:
 		err := fmt.Errorf("unknown type in de-register callback: %T", callback)
 		Logger.Error(err)
@@ -116,6 +178,8 @@ func (b *Broker) MessageCallback(pattern string, respond bool, channel ...string
 		Pattern: pattern,
 		Respond: respond,
 		Chan:    make(chan PatternMatch),
+		seq:     b.nextSeq(),
+		module:  b.callerModule(),
 	}
 
 	if channel != nil {
@@ -131,10 +195,11 @@ func (b *Broker) MessageCallback(pattern string, respond bool, channel ...string
 
 func (b *Broker) EventCallback(key string, val string) *EventCallback {
 	callback := &EventCallback{
-		ID:   fmt.Sprintf("event:%d", len(b.cbIndex[E])),
-		Key:  key,
-		Val:  val,
-		Chan: make(chan map[string]interface{}),
+		ID:     fmt.Sprintf("event:%d", len(b.cbIndex[E])),
+		Key:    key,
+		Val:    val,
+		Chan:   make(chan map[string]interface{}),
+		module: b.callerModule(),
 	}
 
 	if err := b.RegisterCallback(callback); err != nil {
@@ -146,7 +211,7 @@ func (b *Broker) EventCallback(key string, val string) *EventCallback {
 
 func (b *Broker) TimerCallback(schedule string) *TimerCallback {
 	callback := &TimerCallback{
-		ID:       fmt.Sprintf("timer:%d", len(b.cbIndex[E])),
+		ID:       fmt.Sprintf("timer:%d", len(b.cbIndex[T])),
 		Schedule: schedule,
 		Chan:     make(chan time.Time),
 	}
@@ -171,4 +236,52 @@ func (b *Broker) QuestionCallback(user string, prompt string) *QuestionCallback
 	return callback
 }
 
+// TopicCallback subscribes to messages other modules (or lua scripts)
+// publish to the given topic with broker.Publish()
+func (b *Broker) TopicCallback(topic string) *TopicCallback {
+	callback := &TopicCallback{
+		ID:    fmt.Sprintf("topic:%d", len(b.cbIndex[P])),
+		Topic: topic,
+		Chan:  make(chan TopicMessage),
+	}
+	if err := b.RegisterCallback(callback); err != nil {
+		Logger.Debug("error registering callback ", callback.ID, ":: ", err)
+		return nil
+	}
+	return callback
+}
+
+// ActionCallback gets clicks on buttons with the given action ID (see
+// ButtonsBlock)
+func (b *Broker) ActionCallback(action string) *ActionCallback {
+	callback := &ActionCallback{
+		ID:     fmt.Sprintf("action:%d", len(b.cbIndex[A])),
+		Action: action,
+		Chan:   make(chan Action),
+	}
+	if err := b.RegisterCallback(callback); err != nil {
+		Logger.Debug("error registering callback ", callback.ID, ":: ", err)
+		return nil
+	}
+	return callback
+}
+
+// ModalCallback defines a modal (a form in a pop-up) called name. Open it
+// with ModalCallback.Open, or put ModalCallback.Button in a message, and
+// submissions come out of Chan once they pass validation.
+func (b *Broker) ModalCallback(name string, modal *Modal) *ModalCallback {
+	callback := &ModalCallback{
+		ID:     fmt.Sprintf("view:%d", len(b.cbIndex[V])),
+		Name:   name,
+		Modal:  modal,
+		Chan:   make(chan ModalSubmission),
+		broker: b,
+	}
+	if err := b.RegisterCallback(callback); err != nil {
+		Logger.Debug("error registering callback ", callback.ID, ":: ", err)
+		return nil
+	}
+	return callback
+}
+
 // LinkCallback() def is in httpserver.go because it includes net/http (sorry)
@@ -0,0 +1,119 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// slack lets us read channel history about once a second
+const historyPause = 1200 * time.Millisecond
+
+// An InactiveChannel is a channel nobody's said anything in for a while
+type InactiveChannel struct {
+	ID      string
+	Name    string
+	Last    time.Time // when someone last said something (or when it was created)
+	Members int       // how many people are in it
+}
+
+// LastActivity returns when someone last said something in the channel.
+// People joining and leaving doesn't count. It's the zero time if nobody's
+// said anything recently enough for slack to tell us.
+func (b *Broker) LastActivity(channel string) (time.Time, error) {
+	req := ApiRequest{
+		URL:    `https://slack.com/api/conversations.history`,
+		Values: make(url.Values),
+		Broker: b,
+	}
+	req.Values.Set(`channel`, channel)
+	req.Values.Set(`limit`, `20`)
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if !reply.Ok {
+		return time.Time{}, fmt.Errorf("couldn't read %s: %s", b.channelName(channel), reply.Error)
+	}
+	for _, msg := range reply.Messages {
+		switch msg.Subtype {
+		case `channel_join`, `channel_leave`, `group_join`, `group_leave`:
+			continue
+		}
+		return tsTime(msg.Ts), nil
+	}
+	return time.Time{}, nil
+}
+
+// InactiveChannels returns the unarchived channels nobody's said anything in
+// for the given number of days, quietest first. The general channel, and
+// channels in exclude (names, IDs, or name prefixes ending in *), are left
+// out. It reads every channel's history, so it takes a while.
+func (b *Broker) InactiveChannels(days int, exclude []string) ([]InactiveChannel, error) {
+	cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	var inactive []InactiveChannel
+	for _, c := range b.SlackMeta.Channels {
+		if c.IsArchived || c.IsGeneral || channelExcluded(c, exclude) {
+			continue
+		}
+		last, err := b.LastActivity(c.ID)
+		if err != nil {
+			return inactive, err
+		}
+		if last.IsZero() {
+			last = time.Unix(int64(c.Created), 0)
+		}
+		if last.Before(cutoff) {
+			inactive = append(inactive, InactiveChannel{ID: c.ID, Name: c.Name, Last: last, Members: len(c.Members)})
+		}
+		time.Sleep(historyPause)
+	}
+	sort.Sort(byLast(inactive))
+	return inactive, nil
+}
+
+// channelExcluded says whether the channel is on the exclusion list
+func channelExcluded(c Channel, exclude []string) bool {
+	for _, ex := range exclude {
+		ex = strings.TrimPrefix(ex, `#`)
+		switch {
+		case ex == c.ID, ex == c.Name:
+			return true
+		case strings.HasSuffix(ex, `*`) && strings.HasPrefix(c.Name, strings.TrimSuffix(ex, `*`)):
+			return true
+		}
+	}
+	return false
+}
+
+// byLast sorts inactive channels quietest first
+type byLast []InactiveChannel
+
+func (c byLast) Len() int           { return len(c) }
+func (c byLast) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+func (c byLast) Less(i, j int) bool { return c[i].Last.Before(c[j].Last) }
+
+// ArchiveChannel archives the channel
+func (b *Broker) ArchiveChannel(channel string) error {
+	req := ApiRequest{
+		URL:    `https://slack.com/api/conversations.archive`,
+		Values: make(url.Values),
+		Broker: b,
+	}
+	req.Values.Set(`channel`, channel)
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return err
+	}
+	if !reply.Ok && reply.Error != `already_archived` {
+		return fmt.Errorf("couldn't archive %s: %s", b.channelName(channel), reply.Error)
+	}
+	for i := range b.SlackMeta.Channels {
+		if b.SlackMeta.Channels[i].ID == channel {
+			b.SlackMeta.Channels[i].IsArchived = true
+		}
+	}
+	return nil
+}
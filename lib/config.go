@@ -4,6 +4,7 @@ import (
 	"github.com/ccding/go-logging/logging"
 	"github.com/danryan/env"
 	"os"
+	"strings"
 	"time"
 )
 
@@ -16,6 +17,196 @@ type Config struct {
 	RedisURL string `env:"key=LAZLO_REDIS_URL"`
 	RedisPW  string `env:"key=LAZLO_REDIS_PW"`
 	Port     string `env:"key=PORT"`
+	// comma-separated list of user names or IDs allowed to run admin commands
+	Admins string `env:"key=LAZLO_ADMINS"`
+	Redact bool   `env:"key=LAZLO_REDACT default=true"`
+	// space-separated list of extra regexes to redact
+	RedactPatterns string `env:"key=LAZLO_REDACT_PATTERNS"`
+	HistorySize    int    `env:"key=LAZLO_HISTORY_SIZE default=100"`
+	// comma-separated list of channels we never keep history for
+	SensitiveChannels  string `env:"key=LAZLO_SENSITIVE_CHANNELS"`
+	SlackSigningSecret string `env:"key=LAZLO_SLACK_SIGNING_SECRET"`
+	// comma-separated list of cert.pem:key.pem pairs to serve https with
+	TLSCerts    string `env:"key=LAZLO_TLS_CERTS"`
+	TLSPort     string `env:"key=LAZLO_TLS_PORT default=443"`
+	TLSClientCA string `env:"key=LAZLO_TLS_CLIENT_CA"`
+	TLSRedirect bool   `env:"key=LAZLO_TLS_REDIRECT default=true"`
+	TraceSize   int    `env:"key=LAZLO_TRACE_SIZE default=20"`
+	BulkDMRate  int    `env:"key=LAZLO_BULK_DM_RATE default=20"`
+	// shared http fetcher: cache lifetime (seconds), requests per minute per
+	// host, and how many failures in a row trip the breaker (for how long)
+	FetchTTL        int    `env:"key=LAZLO_FETCH_TTL default=300"`
+	FetchRate       int    `env:"key=LAZLO_FETCH_RATE default=60"`
+	FetchBreakAfter int    `env:"key=LAZLO_FETCH_BREAK_AFTER default=5"`
+	FetchCooldown   int    `env:"key=LAZLO_FETCH_COOLDOWN default=60"`
+	TriageChannel   string `env:"key=LAZLO_TRIAGE_CHANNEL"`
+	// cron schedule for the triage summary (weekdays at 9am if unset)
+	TriageSummary string `env:"key=LAZLO_TRIAGE_SUMMARY"`
+	PromptDir     string `env:"key=LAZLO_PROMPT_DIR default=prompts"`
+	// sentiment model endpoint (the built-in word list is used if unset)
+	SentimentURL string `env:"key=LAZLO_SENTIMENT_URL"`
+	// cron schedule for the weekly mood report (mondays at 9am if unset)
+	MoodReport string `env:"key=LAZLO_MOOD_REPORT"`
+	// comma-separated list of channels where long conversations belong in threads
+	ThreadChannels string `env:"key=LAZLO_THREAD_CHANNELS"`
+	ThreadMessages int    `env:"key=LAZLO_THREAD_MESSAGES default=6"`
+	ThreadUsers    int    `env:"key=LAZLO_THREAD_USERS default=3"`
+	ThreadWindow   int    `env:"key=LAZLO_THREAD_WINDOW default=10"`
+	ThreadMode     string `env:"key=LAZLO_THREAD_MODE default=nudge"`
+	RegistryFile   string `env:"key=LAZLO_REGISTRY_FILE default=registry.json"`
+	// git repo to pull config, lua scripts, prompts and the registry from
+	SyncRepo     string `env:"key=LAZLO_SYNC_REPO"`
+	SyncBranch   string `env:"key=LAZLO_SYNC_BRANCH default=master"`
+	SyncDir      string `env:"key=LAZLO_SYNC_DIR default=sync"`
+	SyncInterval int    `env:"key=LAZLO_SYNC_INTERVAL default=5"`
+	SyncSecret   string `env:"key=LAZLO_SYNC_SECRET"`
+	// bearer token for the /debug endpoints (they're localhost-only without one)
+	DebugToken string `env:"key=LAZLO_DEBUG_TOKEN"`
+	// seconds a new binary gets to take over during an upgrade (and the old
+	// one gets to finish up)
+	UpgradeGrace int `env:"key=LAZLO_UPGRADE_GRACE default=30"`
+	// lua plugins whose specs fail, or cover less than this percentage of
+	// their patterns, aren't loaded (0 turns the check off)
+	LuaMinCoverage int `env:"key=LAZLO_LUA_MIN_COVERAGE default=0"`
+	// a user token with admin.emoji:write, for adding custom emoji (enterprise
+	// workspaces only let admins do that through the API)
+	EmojiToken string `env:"key=LAZLO_EMOJI_TOKEN"`
+	// channels nobody's talked in for this many days are proposed for archival
+	CleanupDays int `env:"key=LAZLO_CLEANUP_DAYS default=90"`
+	// channels the cleanup never proposes archiving (names, IDs or name* prefixes)
+	CleanupExclude string `env:"key=LAZLO_CLEANUP_EXCLUDE"`
+	// what each unit of a paid integration costs (unit=price,...), and the
+	// monthly budgets (total=, user=, channel=, or a @user or #channel)
+	CostRates   string `env:"key=LAZLO_COST_RATES"`
+	CostBudgets string `env:"key=LAZLO_COST_BUDGETS"`
+	// what happens when a budget runs out: cutoff or downgrade
+	CostOver string `env:"key=LAZLO_COST_OVER default=cutoff"`
+	// how dates and numbers are formatted for people who haven't said (see
+	// Broker.Locale), and their timezone (the system's if it's empty)
+	Locale string `env:"key=LAZLO_LOCALE default=en-US"`
+	TZ     string `env:"key=LAZLO_TZ"`
+	// the workspace an org-wide (Enterprise Grid) install acts in
+	TeamID string `env:"key=LAZLO_TEAM_ID"`
+	// what modules hear from people from other orgs in shared channels
+	// (restrict, ignore or allow), and the brain key prefixes they can see
+	ExternalPolicy string `env:"key=LAZLO_EXTERNAL_POLICY default=restrict"`
+	ExternalBrain  string `env:"key=LAZLO_EXTERNAL_BRAIN"`
+	// the percentage of users each experiment is on for (name=percent,...)
+	Experiments string `env:"key=LAZLO_EXPERIMENTS"`
+	// how long keys live in each brain namespace (namespace=duration,...), how
+	// many keys each can have (namespace=count,...), and how often (in
+	// minutes) they're enforced for brains that can't expire keys themselves
+	// (0 never does)
+	BrainTTLs       string `env:"key=LAZLO_BRAIN_TTLS"`
+	BrainQuotas     string `env:"key=LAZLO_BRAIN_QUOTAS"`
+	BrainGCInterval int    `env:"key=LAZLO_BRAIN_GC_INTERVAL default=10"`
+	// what lazlo's talking to, which decides how messages are formatted
+	// (slack, mattermost or text)
+	Adapter string `env:"key=LAZLO_ADAPTER default=slack"`
+	// what happens to messages too long to send in one go: thread, pages or
+	// split
+	LongMessages string `env:"key=LAZLO_LONG_MESSAGES default=thread"`
+	// reconnect if we hear nothing from slack for this many minutes, and it
+	// doesn't answer a ping (0 turns the watchdog off)
+	WatchdogMinutes int `env:"key=LAZLO_WATCHDOG_MINUTES default=5"`
+	// where the watchdog says it had to reconnect
+	WatchdogWebhook string `env:"key=LAZLO_WATCHDOG_WEBHOOK"`
+	// comma-separated users (IDs, names or bot IDs), app IDs and channels
+	// (IDs or names) to ignore messages and events from
+	IgnoreUsers    string `env:"key=LAZLO_IGNORE_USERS"`
+	IgnoreApps     string `env:"key=LAZLO_IGNORE_APPS"`
+	IgnoreChannels string `env:"key=LAZLO_IGNORE_CHANNELS"`
+	// a regex; messages that match it are ignored
+	IgnorePatterns string `env:"key=LAZLO_IGNORE_PATTERNS"`
+	// a bot that says more than this in a channel in a minute is ignored
+	// there until the minute's up (0 turns this off)
+	BotLoopLimit int `env:"key=LAZLO_BOT_LOOP_LIMIT default=10"`
+	// the secret lazlos that hand each other commands sign them with, and
+	// the channel they do it in (see delegate.go)
+	DelegateSecret  string `env:"key=LAZLO_DELEGATE_SECRET"`
+	DelegateChannel string `env:"key=LAZLO_DELEGATE_CHANNEL"`
+	// comma-separated names of the lazlos we take commands from (and give
+	// them to)
+	DelegatePeers string `env:"key=LAZLO_DELEGATE_PEERS"`
+	// a regex matching the commands other lazlos can have us run
+	DelegateCommands string `env:"key=LAZLO_DELEGATE_COMMANDS"`
+	// where the audit log is appended to (it's only logged if this isn't set)
+	AuditLog string `env:"key=LAZLO_AUDIT_LOG"`
+	// comma-separated hosts the SSH module can run commands on, as
+	// name=[user@]address[:port] (hosts in the registry count too)
+	SSHHosts string `env:"key=LAZLO_SSH_HOSTS"`
+	// the role you need to run commands on hosts that don't say (admins
+	// only, if it isn't set)
+	SSHRole string `env:"key=LAZLO_SSH_ROLE"`
+	// a regex matching the commands the SSH module will run
+	SSHCommands string `env:"key=LAZLO_SSH_COMMANDS"`
+	// the private key ssh uses, and how many minutes a command gets
+	SSHKey     string `env:"key=LAZLO_SSH_KEY"`
+	SSHTimeout int    `env:"key=LAZLO_SSH_TIMEOUT default=10"`
+	// the token infra plans have to be POSTed with (as a bearer token); plans
+	// can't be POSTed if it isn't set
+	PlanToken string `env:"key=LAZLO_PLAN_TOKEN"`
+	// the channel POSTed plans are reviewed in (the default channel, if it
+	// isn't set)
+	PlanChannel string `env:"key=LAZLO_PLAN_CHANNEL"`
+	// the role you need to approve a plan (admins only, if it isn't set)
+	PlanRole string `env:"key=LAZLO_PLAN_ROLE"`
+	// the webhook that applies approved plans, and the bearer token it's
+	// called with
+	PlanApplyURL   string `env:"key=LAZLO_PLAN_APPLY_URL"`
+	PlanApplyToken string `env:"key=LAZLO_PLAN_APPLY_TOKEN"`
+	// how many hours a plan waits for approval
+	PlanExpiry int `env:"key=LAZLO_PLAN_EXPIRY default=24"`
+	// comma-separated cloud accounts the Cloud module can look at, as
+	// name=[role-arn@]region (accounts in the registry count too)
+	CloudAccounts string `env:"key=LAZLO_CLOUD_ACCOUNTS"`
+	// the role you need to ask about accounts that don't say (admins only, if
+	// it isn't set)
+	CloudRole string `env:"key=LAZLO_CLOUD_ROLE"`
+	// how many minutes the Cloud module's answers are cached for
+	CloudCache int `env:"key=LAZLO_CLOUD_CACHE default=5"`
+	// the hosts (host[:port], comma-separated) the Certs module watches
+	CertDomains string `env:"key=LAZLO_CERT_DOMAINS"`
+	// when the Certs module checks them (cron syntax)
+	CertSchedule string `env:"key=LAZLO_CERT_SCHEDULE"`
+	// how many days before expiry the Certs module alerts (comma-separated)
+	CertThresholds string `env:"key=LAZLO_CERT_THRESHOLDS default=30,14,7,1"`
+	// where the Certs module alerts (the default channel if empty)
+	CertChannel string `env:"key=LAZLO_CERT_CHANNEL"`
+	// what the Uptime module checks (name=url, comma-separated)
+	UptimeChecks string `env:"key=LAZLO_UPTIME_CHECKS"`
+	// when the Uptime module checks them (cron syntax)
+	UptimeSchedule string `env:"key=LAZLO_UPTIME_SCHEDULE"`
+	// where the Uptime module says something's down (the default channel if empty)
+	UptimeChannel string `env:"key=LAZLO_UPTIME_CHANNEL"`
+	// how many checks in a row have to fail before something's down
+	UptimeFailures int `env:"key=LAZLO_UPTIME_FAILURES default=2"`
+	// how often (in minutes) to force a GC for long-lived lua states (0 never does)
+	LuaGCInterval int `env:"key=LAZLO_LUA_GC_INTERVAL default=0"`
+	// where lazlo tells admins about problems it finds (like lua plugins that don't lint)
+	AdminChannel string `env:"key=LAZLO_ADMIN_CHANNEL"`
+	// how many typos lazlo forgives when suggesting a command for one it didn't understand (0 turns suggestions off)
+	SuggestDistance int `env:"key=LAZLO_SUGGEST_DISTANCE default=2"`
+	// how often (in minutes) lazlo saves its counters to the brain, so they survive restarts (0 doesn't)
+	MetricsInterval int `env:"key=LAZLO_METRICS_INTERVAL default=0"`
+	// how long (in minutes) the same exactly-once command from the same person counts as a double-send
+	OnceWindow int `env:"key=LAZLO_ONCE_WINDOW default=10"`
+	// how long (in seconds) events are held at startup, waiting for every module to be ready
+	StartupTimeout int `env:"key=LAZLO_STARTUP_TIMEOUT default=30"`
+	// how many events are held at startup before lazlo stops waiting for the modules
+	StartupQueue int `env:"key=LAZLO_STARTUP_QUEUE default=1000"`
+	// how many bytes each lua plugin can keep in the brain (0 is as many as it likes)
+	LuaStorageQuota int `env:"key=LAZLO_LUA_STORAGE_QUOTA default=1048576"`
+	// how people address lazlo: casual, mention, or a prefix like ! (see addressing.go)
+	Addressing string `env:"key=LAZLO_ADDRESSING default=casual"`
+	// where lazlo's own secrets come from: env or file:<dir>
+	Secrets string `env:"key=LAZLO_SECRETS default=env"`
+	// comma-separated list of hostnames to get tls certs from Let's Encrypt for
+	TLSAutocert string `env:"key=LAZLO_TLS_AUTOCERT"`
+	// where certs from Let's Encrypt are kept
+	TLSAutocertCache string `env:"key=LAZLO_TLS_AUTOCERT_CACHE default=autocert"`
+	// the contact address Let's Encrypt warns about expiring certs
+	TLSAutocertEmail string `env:"key=LAZLO_TLS_AUTOCERT_EMAIL"`
 }
 
 func newConfig() *Config {
@@ -24,6 +215,18 @@ func newConfig() *Config {
 	return c
 }
 
+// splitList breaks a comma-separated config value into its trimmed,
+// non-empty elements
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, `,`) {
+		if item = strings.TrimSpace(item); item != `` {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
 func newLogger() *logging.Logger {
 	format := "%25s [%s] %8s: %s\n time,name,levelname,message"
 	timeFormat := time.RFC3339
@@ -0,0 +1,301 @@
+package lib
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	lua "github.com/yuin/gopher-lua"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ConfigSync keeps lazlo's configuration in a git repo (LAZLO_SYNC_REPO).
+// The repo can hold any of:
+//
+//	config.env      LAZLO_* settings, one KEY=value per line
+//	registry.json   the registry (replaces LAZLO_REGISTRY_FILE)
+//	prompts/        prompt versions (replaces LAZLO_PROMPT_DIR)
+//	lua/            lua scripts (replaces ./lua)
+//	runbooks/       anything else modules want (see Broker.SyncPath)
+//
+// Each commit is checked out into its own directory under LAZLO_SYNC_DIR and
+// validated before anything uses it. If the registry or prompts won't load
+// from the new commit, lazlo goes back to the one it had.
+type ConfigSync struct {
+	sync.RWMutex // guards applied
+	syncing      sync.Mutex
+	broker       *Broker
+	repo         string
+	branch       string
+	dir          string
+	applied      string // the commit we're running with
+	rejected     string // the last commit that wasn't valid
+	env          map[string]string
+}
+
+func newConfigSync(b *Broker) *ConfigSync {
+	cs := &ConfigSync{
+		broker: b,
+		repo:   b.Config.SyncRepo,
+		branch: b.Config.SyncBranch,
+		dir:    b.Config.SyncDir,
+	}
+	if _, err := cs.Sync(); err != nil {
+		Logger.Error(`ConfigSync:: `, err)
+		// carry on with the last commit that worked, if we have it
+		if sha, err := ioutil.ReadFile(filepath.Join(cs.dir, `applied`)); err == nil {
+			cs.setApplied(strings.TrimSpace(string(sha)))
+			Logger.Info(`ConfigSync:: using the last good config, from `, cs.applied)
+		}
+	}
+	if cs.applied != `` {
+		cs.setEnv(cs.env)
+	}
+	return cs
+}
+
+// git runs a git command against our copy of the repo
+func (cs *ConfigSync) git(args ...string) (string, error) {
+	cmd := exec.Command(`git`, append([]string{`--git-dir`, filepath.Join(cs.dir, `repo.git`)}, args...)...)
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return ``, fmt.Errorf("git %s: %v: %s", args[0], err, strings.TrimSpace(out.String()))
+	}
+	return strings.TrimSpace(out.String()), nil
+}
+
+// fetch brings our copy of the repo up to date and returns the commit at the
+// tip of the branch
+func (cs *ConfigSync) fetch() (string, error) {
+	if _, err := os.Stat(filepath.Join(cs.dir, `repo.git`)); os.IsNotExist(err) {
+		if err := os.MkdirAll(cs.dir, 0700); err != nil {
+			return ``, err
+		}
+		out, err := exec.Command(`git`, `clone`, `--quiet`, `--bare`, cs.repo, filepath.Join(cs.dir, `repo.git`)).CombinedOutput()
+		if err != nil {
+			return ``, fmt.Errorf("git clone: %v: %s", err, strings.TrimSpace(string(out)))
+		}
+	}
+	if _, err := cs.git(`fetch`, `--quiet`, `origin`, fmt.Sprintf("+refs/heads/%s:refs/heads/%s", cs.branch, cs.branch)); err != nil {
+		return ``, err
+	}
+	return cs.git(`rev-parse`, `refs/heads/`+cs.branch)
+}
+
+// checkout writes out the files in the commit to their own directory
+func (cs *ConfigSync) checkout(sha string) (string, error) {
+	path := filepath.Join(cs.dir, sha)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	tmp := path + `.tmp`
+	os.RemoveAll(tmp)
+	if err := os.MkdirAll(tmp, 0700); err != nil {
+		return ``, err
+	}
+	if _, err := cs.git(`--work-tree`, tmp, `checkout`, `--force`, sha, `--`, `.`); err != nil {
+		os.RemoveAll(tmp)
+		return ``, err
+	}
+	return path, os.Rename(tmp, path)
+}
+
+// Sync fetches the repo, and applies the newest commit if it's new and
+// valid. It returns the commit lazlo's running with afterward.
+func (cs *ConfigSync) Sync() (string, error) {
+	cs.syncing.Lock()
+	defer cs.syncing.Unlock()
+	sha, err := cs.fetch()
+	if err != nil {
+		return cs.applied, err
+	}
+	if sha == cs.applied || sha == cs.rejected {
+		return cs.applied, nil
+	}
+	path, err := cs.checkout(sha)
+	if err != nil {
+		return cs.applied, err
+	}
+	env, err := validateSync(path)
+	if err != nil {
+		os.RemoveAll(path)
+		cs.rejected = sha
+		return cs.applied, fmt.Errorf("commit %s isn't valid: %v", short(sha), err)
+	}
+
+	previous := cs.applied
+	cs.setApplied(sha)
+	if err := cs.reload(); err != nil {
+		cs.setApplied(previous)
+		cs.rejected = sha
+		if previous != `` {
+			if rerr := cs.reload(); rerr != nil {
+				Logger.Error(`ConfigSync:: couldn't roll back to `, previous, `: `, rerr)
+			}
+		}
+		os.RemoveAll(path)
+		return cs.applied, fmt.Errorf("couldn't apply commit %s, rolled back: %v", short(sha), err)
+	}
+	if previous != `` && (cs.changed(previous, sha, `lua`) || cs.changed(previous, sha, `config.env`)) {
+		Logger.Info(`ConfigSync:: commit `, short(sha), ` changes lua scripts or settings, which take effect when lazlo restarts`)
+	}
+	cs.env = env
+	ioutil.WriteFile(filepath.Join(cs.dir, `applied`), []byte(sha+"\n"), 0600)
+	cs.prune(previous)
+	Logger.Info(`ConfigSync:: applied config from commit `, short(sha))
+	return sha, nil
+}
+
+func (cs *ConfigSync) setApplied(sha string) {
+	cs.Lock()
+	cs.applied = sha
+	cs.Unlock()
+}
+
+// reload points the registry and prompts at the applied commit and reloads
+// them. The first sync happens before either exists.
+func (cs *ConfigSync) reload() error {
+	b := cs.broker
+	if b.Registry != nil {
+		b.Registry.Lock()
+		b.Registry.file = b.SyncPath(`registry.json`, b.Config.RegistryFile)
+		b.Registry.Unlock()
+		if err := b.Registry.load(); err != nil {
+			return err
+		}
+	}
+	if b.prompts != nil {
+		b.prompts.Lock()
+		b.prompts.dir = b.SyncPath(`prompts`, b.Config.PromptDir)
+		b.prompts.Unlock()
+		if err := b.prompts.load(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changed tells us if a file or directory differs between two commits
+func (cs *ConfigSync) changed(from string, to string, path string) bool {
+	out, err := cs.git(`diff`, `--name-only`, from, to, `--`, path)
+	return err != nil || out != ``
+}
+
+// prune removes checkouts of every commit but the current and previous ones
+func (cs *ConfigSync) prune(previous string) {
+	dirs, _ := ioutil.ReadDir(cs.dir)
+	for _, d := range dirs {
+		if !d.IsDir() || d.Name() == `repo.git` || d.Name() == cs.applied || d.Name() == previous {
+			continue
+		}
+		os.RemoveAll(filepath.Join(cs.dir, d.Name()))
+	}
+}
+
+// setEnv puts the settings from config.env in the environment, unless
+// they're already set there (so secrets can stay out of the repo)
+func (cs *ConfigSync) setEnv(env map[string]string) {
+	if env == nil {
+		env, _ = readSyncEnv(filepath.Join(cs.dir, cs.applied, `config.env`))
+	}
+	for k, v := range env {
+		if _, set := os.LookupEnv(k); !set {
+			os.Setenv(k, v)
+		}
+	}
+}
+
+// validateSync checks that everything in a checkout will load, and returns
+// the settings in its config.env
+func validateSync(path string) (map[string]string, error) {
+	env, err := readSyncEnv(filepath.Join(path, `config.env`))
+	if err != nil {
+		return nil, err
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(path, `registry.json`)); err == nil {
+		var file map[string]map[string]map[string]string
+		if err := json.Unmarshal(data, &file); err != nil {
+			return nil, fmt.Errorf("registry.json: %v", err)
+		}
+	}
+	ps := &promptStore{dir: filepath.Join(path, `prompts`)}
+	if err := ps.load(); err != nil {
+		return nil, err
+	}
+	scripts, _ := ioutil.ReadDir(filepath.Join(path, `lua`))
+	for _, f := range scripts {
+		if f.IsDir() {
+			continue
+		}
+		file := filepath.Join(path, `lua`, f.Name())
+		L := lua.NewState()
+		_, err := L.LoadFile(file)
+		L.Close()
+		if err != nil {
+			return nil, fmt.Errorf("lua/%s: %s", f.Name(), strings.TrimSpace(err.Error()))
+		}
+	}
+	return env, nil
+}
+
+// readSyncEnv reads KEY=value lines (blank lines and #comments are skipped).
+// Only LAZLO_ settings are allowed.
+func readSyncEnv(file string) (map[string]string, error) {
+	env := make(map[string]string)
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return env, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == `` || strings.HasPrefix(line, `#`) {
+			continue
+		}
+		kv := strings.SplitN(line, `=`, 2)
+		if len(kv) != 2 || !strings.HasPrefix(kv[0], `LAZLO_`) {
+			return nil, fmt.Errorf("config.env line %d: expected LAZLO_SOMETHING=value", n)
+		}
+		env[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return env, scanner.Err()
+}
+
+func short(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// ConfigCommit returns the commit of LAZLO_SYNC_REPO lazlo's configured from,
+// or "" if it isn't syncing its config
+func (b *Broker) ConfigCommit() string {
+	if b.ConfigSync == nil {
+		return ``
+	}
+	b.ConfigSync.RLock()
+	defer b.ConfigSync.RUnlock()
+	return b.ConfigSync.applied
+}
+
+// SyncPath returns where to find name (a file or directory in the config
+// repo) if lazlo's syncing its config, and def if it isn't
+func (b *Broker) SyncPath(name string, def string) string {
+	sha := b.ConfigCommit()
+	if sha == `` {
+		return def
+	}
+	return filepath.Join(b.ConfigSync.dir, sha, name)
+}
@@ -0,0 +1,82 @@
+package lib
+
+import (
+	"strings"
+	"time"
+)
+
+// Modules that are about to do something that can't be taken back ask first,
+// one of two ways. Confirm asks the person who asked for it, in a DM, and
+// waits for their yes or no. An Approval is for things someone else (an
+// admin, say) has to sign off on, maybe hours later: it's a Workflow that
+// posts a proposal, and goes ahead when someone allowed to reacts to it
+// with the approve reaction, so it survives restarts.
+
+// the reactions approvals use unless they say otherwise
+const (
+	ApproveReaction = `white_check_mark`
+	RejectReaction  = `x`
+)
+
+// Confirm asks the user the yes/no question in a DM and says whether they
+// said yes
+func (b *Broker) Confirm(user string, question string) bool {
+	q := b.QuestionCallback(user, question+` (yes/no)`)
+	if q == nil {
+		return false
+	}
+	answer := strings.TrimSpace(strings.ToLower(<-q.Answer))
+	return answer == `yes` || answer == `y`
+}
+
+// An Approval is a proposal that waits for someone to approve or reject it
+// by reacting, until it expires. Propose posts it (and sets the instance's
+// MessageTs to the message to react to); then the instance moves to the
+// approved, rejected or expired state, and Approved, Rejected or Expired
+// runs. Whoever approved or rejected it is in Data["approver"] or
+// Data["rejecter"].
+type Approval struct {
+	Name       string
+	Propose    func(b *Broker, wi *WorkflowInstance)
+	Approve    string // the reaction that approves it (ApproveReaction if "")
+	Reject     string // the reaction that rejects it (RejectReaction if "")
+	CanApprove func(wi *WorkflowInstance, t *WorkflowTrigger) bool
+	CanReject  func(wi *WorkflowInstance, t *WorkflowTrigger) bool
+	Expiry     time.Duration // 0 never expires
+	Approved   func(b *Broker, wi *WorkflowInstance)
+	Rejected   func(b *Broker, wi *WorkflowInstance)
+	Expired    func(b *Broker, wi *WorkflowInstance)
+}
+
+// Approval starts a workflow engine for the approval (see Workflow)
+func (b *Broker) Approval(a *Approval) (*WorkflowEngine, error) {
+	approve, reject := a.Approve, a.Reject
+	if approve == `` {
+		approve = ApproveReaction
+	}
+	if reject == `` {
+		reject = RejectReaction
+	}
+	decided := func(key string) func(b *Broker, wi *WorkflowInstance, t *WorkflowTrigger) {
+		return func(b *Broker, wi *WorkflowInstance, t *WorkflowTrigger) {
+			wi.Data[key] = t.User
+		}
+	}
+	transitions := []*Transition{
+		{To: `approved`, Reaction: approve, Guard: a.CanApprove, Action: decided(`approver`)},
+		{To: `rejected`, Reaction: reject, Guard: a.CanReject, Action: decided(`rejecter`)},
+	}
+	if a.Expiry > 0 {
+		transitions = append(transitions, &Transition{To: `expired`, After: a.Expiry})
+	}
+	return b.Workflow(&Workflow{
+		Name:  a.Name,
+		Start: `proposed`,
+		States: map[string]*WorkflowState{
+			`proposed`: {OnEnter: a.Propose, Transitions: transitions},
+			`approved`: {OnEnter: a.Approved, Final: true},
+			`rejected`: {OnEnter: a.Rejected, Final: true},
+			`expired`:  {OnEnter: a.Expired, Final: true},
+		},
+	})
+}
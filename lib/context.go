@@ -0,0 +1,141 @@
+package lib
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Not every handler is set off by a chat message: timers fire, and webhooks
+// come in. Handlers that serve more than one of those take a Context, which
+// says what set them off, instead of a message that's nil half the time:
+//
+//	func remind(ctx *lazlo.Context) {
+//		if ctx.Trigger == lazlo.TriggerSchedule {
+//			...
+//		}
+//		ctx.Reply(`time for standup`)
+//	}
+//
+// Reply answers the message, if there was one, and otherwise talks in the
+// context's Channel (the default channel, unless the handler says otherwise).
+// Expand fills in a message template with the context as its data, so
+// templates can say {{.Trigger}} or {{if .User}}...{{end}} too.
+
+// What can set a handler off
+const (
+	TriggerMessage  = `message`
+	TriggerSchedule = `schedule`
+	TriggerWebhook  = `webhook`
+)
+
+// A Context is what set a handler off
+type Context struct {
+	Trigger  string
+	Time     time.Time
+	Channel  string          // where Reply talks, if there's no message
+	User     string          // who sent the message ("" if there wasn't one)
+	Lang     string          // the language the message is in, if it's known
+	Event    *Event          // the message, for TriggerMessage
+	Match    []string        // what the message's pattern matched
+	Schedule string          // the cron expression, for TriggerSchedule
+	Request  *ContextRequest // the request, for TriggerWebhook
+	event    *Event          // the message Reply answers, even if Event is swapped for a copy
+	broker   *Broker
+}
+
+// A ContextRequest is the webhook request that set a handler off
+type ContextRequest struct {
+	Method     string
+	Path       string
+	RemoteAddr string
+	Query      map[string]string
+	Headers    map[string]string
+	Body       string
+}
+
+// MessageContext is the context for a message that matched a pattern
+func (b *Broker) MessageContext(pm PatternMatch) *Context {
+	c := &Context{
+		Trigger: TriggerMessage,
+		Time:    time.Now(),
+		Match:   pm.Match,
+		Event:   pm.Event,
+		event:   pm.Event,
+		broker:  b,
+	}
+	if pm.Event != nil {
+		c.Channel, c.User, c.Lang = pm.Event.Channel, pm.Event.User, pm.Event.Lang
+	}
+	return c
+}
+
+// ScheduleContext is the context for a timer on the schedule going off at t
+func (b *Broker) ScheduleContext(schedule string, t time.Time) *Context {
+	return &Context{
+		Trigger:  TriggerSchedule,
+		Time:     t,
+		Channel:  b.DefaultChannel(),
+		Schedule: schedule,
+		broker:   b,
+	}
+}
+
+// WebhookContext is the context for a request to a link callback. The
+// request's body is read into the context.
+func (b *Broker) WebhookContext(req *http.Request) *Context {
+	r := &ContextRequest{
+		Method:     req.Method,
+		Path:       req.URL.Path,
+		RemoteAddr: req.RemoteAddr,
+		Query:      make(map[string]string),
+		Headers:    make(map[string]string),
+	}
+	for name, values := range req.URL.Query() {
+		if !strings.HasPrefix(name, `:`) {
+			r.Query[name] = strings.Join(values, `,`)
+		}
+	}
+	for name, values := range req.Header {
+		r.Headers[name] = strings.Join(values, `,`)
+	}
+	if body, err := readBody(req); err == nil {
+		r.Body = string(body)
+	} else {
+		Logger.Error(`Context:: couldn't read the body of a request to `, r.Path, `: `, err)
+	}
+	return &Context{
+		Trigger: TriggerWebhook,
+		Time:    time.Now(),
+		Channel: b.DefaultChannel(),
+		Request: r,
+		broker:  b,
+	}
+}
+
+// IsMessage says whether a message set the handler off
+func (c *Context) IsMessage() bool {
+	return c.Event != nil
+}
+
+// Text is the message's text ("" if there wasn't one)
+func (c *Context) Text() string {
+	if c.Event == nil {
+		return ``
+	}
+	return c.Event.Text
+}
+
+// Reply answers the message, or says something in the context's channel if
+// there wasn't one
+func (c *Context) Reply(s string) chan map[string]interface{} {
+	if c.event != nil {
+		return c.event.Reply(s)
+	}
+	return c.broker.Say(s, c.Channel)
+}
+
+// Expand fills in the template with the context as its data
+func (c *Context) Expand(text string) string {
+	return c.broker.expandTemplate(text, c, c.Channel)
+}
@@ -0,0 +1,98 @@
+package lib
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Handlers often want more than the one message: what was said before it,
+// whether the user's in the middle of a workflow there, how they like
+// things. Event.Conversation gathers all that up the first time a handler
+// asks for it, and every handler that gets the same message shares it.
+
+// A Conversation is where a message was said (its thread, DM or channel) and
+// what's going on there
+type Conversation struct {
+	ID        string // the channel, or channel:thread_ts for a thread
+	Channel   string
+	ThreadTs  string // "" outside of threads
+	DM        bool
+	User      string             // who said the message
+	Recent    []Event            // the (redacted) messages before it, oldest first
+	Workflows []WorkflowInstance // workflow instances running here
+	Prefs     map[string]string  // the user's
+}
+
+// Conversation returns the message's conversation, working it out the first
+// time it's asked for
+func (event *Event) Conversation() *Conversation {
+	if event.annotations == nil {
+		event.annotations = &annotations{data: make(map[string]interface{})}
+	}
+	event.annotations.Lock()
+	defer event.annotations.Unlock()
+	if event.annotations.conversation == nil {
+		event.annotations.conversation = event.Broker.conversation(event)
+	}
+	return event.annotations.conversation
+}
+
+// conversation gathers up the conversation event was said in. The broker
+// can be nil, which leaves out everything but where it was said.
+func (b *Broker) conversation(event *Event) *Conversation {
+	c := &Conversation{
+		ID:       event.Channel,
+		Channel:  event.Channel,
+		ThreadTs: event.ThreadTs,
+		DM:       strings.HasPrefix(event.Channel, `D`),
+		User:     event.User,
+		Prefs:    make(map[string]string),
+	}
+	if c.ThreadTs != `` {
+		c.ID += `:` + c.ThreadTs
+	}
+	if b == nil {
+		return c
+	}
+	for _, e := range b.History.Get(event.Channel) {
+		if e.Ts != event.Ts && c.has(e.Ts, e.ThreadTs) {
+			c.Recent = append(c.Recent, e)
+		}
+	}
+	if event.User != `` {
+		c.Prefs = b.Prefs(event.User)
+	}
+	c.Workflows = b.conversationWorkflows(c)
+	return c
+}
+
+// has says whether a message (by its ts and thread_ts) is part of the
+// conversation: in a thread, its replies and the message that started it;
+// elsewhere, anything that isn't a thread reply
+func (c *Conversation) has(ts string, threadTs string) bool {
+	if c.ThreadTs != `` {
+		return threadTs == c.ThreadTs || ts == c.ThreadTs
+	}
+	return threadTs == `` || threadTs == ts
+}
+
+// conversationWorkflows reads the workflow instances in the conversation from
+// the brain, since each engine's goroutine owns its in-memory ones
+func (b *Broker) conversationWorkflows(c *Conversation) []WorkflowInstance {
+	keys, _ := b.Brain.Keys()
+	var instances []WorkflowInstance
+	for _, key := range keys {
+		if !strings.HasPrefix(key, `workflow:`) {
+			continue
+		}
+		data, err := b.Brain.Get(key)
+		var wi WorkflowInstance
+		if err != nil || json.Unmarshal(data, &wi) != nil || wi.Channel != c.Channel {
+			continue
+		}
+		if c.ThreadTs == `` || wi.MessageTs == c.ThreadTs {
+			instances = append(instances, wi)
+		}
+	}
+	return instances
+}
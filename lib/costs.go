@@ -0,0 +1,309 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Costs keep track of what lazlo's paid integrations (language model tokens,
+// translated characters, text messages...) are costing, per user and per
+// channel, so each month's spending can be held to the budgets in
+// LAZLO_COST_BUDGETS. Modules record what they've used with Spend, and ask
+// CanSpend first. Each unit's price comes from LAZLO_COST_RATES; units
+// without one are counted but cost nothing.
+//
+// Spending is kept in the brain under costs:<month>:user:<id>,
+// costs:<month>:channel:<id> and costs:<month>:total.
+
+// A CostDecision is what CanSpend thinks of spending some more
+type CostDecision int
+
+const (
+	CostOK        CostDecision = iota
+	CostDowngrade              // over budget: use something cheaper if you can
+	CostCutoff                 // over budget: don't
+)
+
+// A CostLedger is what one user, channel, or the whole workspace spent in a
+// month
+type CostLedger struct {
+	Units map[string]float64 `json:"units"` // how much of each unit was used
+	Spent float64            `json:"spent"`
+}
+
+// costLock serializes reading and writing ledgers
+var costLock sync.Mutex
+
+func costMonth(t time.Time) string {
+	return t.Format(`2006-01`)
+}
+
+func costKey(month string, scope ...string) string {
+	return strings.Join(append([]string{`costs`, month}, scope...), `:`)
+}
+
+// CostRates returns the price of each unit, from LAZLO_COST_RATES
+// (unit=price,...)
+func (b *Broker) CostRates() map[string]float64 {
+	rates := make(map[string]float64)
+	for _, item := range splitList(b.Config.CostRates) {
+		parts := strings.SplitN(item, `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if rate, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64); err == nil {
+			rates[strings.TrimSpace(parts[0])] = rate
+		}
+	}
+	return rates
+}
+
+// CostBudgets returns the monthly budgets from LAZLO_COST_BUDGETS, by scope:
+// total (the whole workspace), user and channel (every user or channel), or
+// a particular user or channel by ID
+func (b *Broker) CostBudgets() map[string]float64 {
+	budgets := make(map[string]float64)
+	for _, item := range splitList(b.Config.CostBudgets) {
+		parts := strings.SplitN(item, `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		budget, err := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if err != nil {
+			continue
+		}
+		scope := strings.TrimSpace(parts[0])
+		switch {
+		case strings.HasPrefix(scope, `#`):
+			if c := b.SlackMeta.GetChannelByName(strings.TrimPrefix(scope, `#`)); c != nil {
+				scope = c.ID
+			}
+		case strings.HasPrefix(scope, `@`):
+			if u := b.SlackMeta.GetUserByName(strings.TrimPrefix(scope, `@`)); u != nil {
+				scope = u.ID
+			}
+		}
+		budgets[scope] = budget
+	}
+	return budgets
+}
+
+// Ledger returns what the user or channel (by ID), or the whole workspace
+// (for "total"), spent in the month (like 2006-01)
+func (b *Broker) Ledger(month string, id string) CostLedger {
+	costLock.Lock()
+	defer costLock.Unlock()
+	return b.ledger(costKey(month, costScope(id)...))
+}
+
+// costScope turns an ID into the last segments of its ledger's key
+func costScope(id string) []string {
+	switch {
+	case id == `total`:
+		return []string{`total`}
+	case strings.HasPrefix(id, `C`), strings.HasPrefix(id, `G`), strings.HasPrefix(id, `D`):
+		return []string{`channel`, id}
+	}
+	return []string{`user`, id}
+}
+
+func (b *Broker) ledger(key string) CostLedger {
+	l := CostLedger{Units: make(map[string]float64)}
+	if data, err := b.Brain.Get(key); err == nil && data != nil {
+		json.Unmarshal(data, &l)
+	}
+	if l.Units == nil {
+		l.Units = make(map[string]float64)
+	}
+	return l
+}
+
+// Spend records that a module used qty of unit on behalf of the user, in the
+// channel. Either can be empty.
+func (b *Broker) Spend(unit string, qty float64, user string, channel string) {
+	cost := qty * b.CostRates()[unit]
+	month := costMonth(time.Now())
+	keys := []string{costKey(month, `total`)}
+	if user != `` {
+		keys = append(keys, costKey(month, `user`, user))
+	}
+	if channel != `` {
+		keys = append(keys, costKey(month, `channel`, channel))
+	}
+	costLock.Lock()
+	defer costLock.Unlock()
+	for _, key := range keys {
+		l := b.ledger(key)
+		l.Units[unit] += qty
+		l.Spent += cost
+		data, _ := json.Marshal(l)
+		if err := b.Brain.Set(key, data); err != nil {
+			Logger.Error(`Costs:: couldn't record `, unit, ` for `, key, `: `, err)
+		}
+	}
+}
+
+// CanSpend checks whether spending qty more of unit on behalf of the user,
+// in the channel, would go over any budget that applies. If it would, the
+// decision is LAZLO_COST_OVER's (cutoff, unless it's set to downgrade), and
+// the error says which budget.
+func (b *Broker) CanSpend(unit string, qty float64, user string, channel string) (CostDecision, error) {
+	budgets := b.CostBudgets()
+	if len(budgets) == 0 {
+		return CostOK, nil
+	}
+	cost := qty * b.CostRates()[unit]
+	month := costMonth(time.Now())
+	check := func(id string, budget float64, name string) error {
+		if spent := b.Ledger(month, id).Spent; spent+cost > budget {
+			return fmt.Errorf("%s has spent %.2f of its %.2f budget this month", name, spent, budget)
+		}
+		return nil
+	}
+	var err error
+	if budget, ok := budgets[`total`]; ok {
+		err = check(`total`, budget, `lazlo`)
+	}
+	if user != `` && err == nil {
+		if budget, ok := budgets[user]; ok {
+			err = check(user, budget, `<@`+user+`>`)
+		} else if budget, ok := budgets[`user`]; ok {
+			err = check(user, budget, `<@`+user+`>`)
+		}
+	}
+	if channel != `` && err == nil {
+		if budget, ok := budgets[channel]; ok {
+			err = check(channel, budget, `<#`+channel+`>`)
+		} else if budget, ok := budgets[`channel`]; ok {
+			err = check(channel, budget, `<#`+channel+`>`)
+		}
+	}
+	switch {
+	case err == nil:
+		return CostOK, nil
+	case b.Config.CostOver == `downgrade`:
+		return CostDowngrade, err
+	}
+	return CostCutoff, err
+}
+
+// A CostReport is a month's spending
+type CostReport struct {
+	Month    string
+	Total    CostLedger
+	Budget   float64 // the total budget, if there is one
+	Users    map[string]CostLedger
+	Channels map[string]CostLedger
+	broker   *Broker
+}
+
+// CostReport gathers up the month's (like 2006-01) spending
+func (b *Broker) CostReport(month string) (*CostReport, error) {
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		return nil, err
+	}
+	r := &CostReport{
+		Month:    month,
+		Budget:   b.CostBudgets()[`total`],
+		Users:    make(map[string]CostLedger),
+		Channels: make(map[string]CostLedger),
+		broker:   b,
+	}
+	costLock.Lock()
+	defer costLock.Unlock()
+	r.Total = b.ledger(costKey(month, `total`))
+	prefix := costKey(month) + `:`
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(key, prefix), `:`)
+		if len(parts) != 2 {
+			continue
+		}
+		switch parts[0] {
+		case `user`:
+			r.Users[parts[1]] = b.ledger(key)
+		case `channel`:
+			r.Channels[parts[1]] = b.ledger(key)
+		}
+	}
+	return r, nil
+}
+
+// Top returns the IDs of the n biggest spenders in the ledgers
+func (r *CostReport) Top(ledgers map[string]CostLedger, n int) []string {
+	var ids []string
+	for id := range ledgers {
+		ids = append(ids, id)
+	}
+	sort.Sort(bySpent{ids, ledgers})
+	if len(ids) > n {
+		ids = ids[:n]
+	}
+	return ids
+}
+
+// String renders the report as plain text
+func (r *CostReport) String() string {
+	return r.Result().PlainText()
+}
+
+// Result renders the report as a Result: the spending by unit, and the
+// biggest spenders in a table
+func (r *CostReport) Result() *Result {
+	res := &Result{Title: fmt.Sprintf("Costs for %s", r.Month)}
+	spent := fmt.Sprintf("%.2f", r.Total.Spent)
+	if r.Budget > 0 {
+		spent += fmt.Sprintf(" of %.2f (%.0f%%)", r.Budget, 100*r.Total.Spent/r.Budget)
+		res.Status = ResultOK
+		if r.Total.Spent > r.Budget {
+			res.Status = ResultError
+		} else if r.Total.Spent > r.Budget*0.8 {
+			res.Status = ResultWarning
+		}
+	}
+	res.Field(`Spent`, spent)
+	var units []string
+	for unit := range r.Total.Units {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		res.Field(unit, fmt.Sprintf("%g", r.Total.Units[unit]))
+	}
+	if len(units) == 0 {
+		res.Text = `nothing's been spent`
+		return res
+	}
+	res.Table = &ResultTable{Header: []string{`who`, `spent`}}
+	for _, id := range r.Top(r.Users, 5) {
+		name := r.broker.SlackMeta.GetUserName(id)
+		if name == `` {
+			name = id
+		}
+		res.Row(`@`+name, fmt.Sprintf("%.2f", r.Users[id].Spent))
+	}
+	for _, id := range r.Top(r.Channels, 5) {
+		res.Row(r.broker.channelName(id), fmt.Sprintf("%.2f", r.Channels[id].Spent))
+	}
+	return res
+}
+
+// bySpent sorts IDs biggest spender first
+type bySpent struct {
+	ids     []string
+	ledgers map[string]CostLedger
+}
+
+func (s bySpent) Len() int      { return len(s.ids) }
+func (s bySpent) Swap(i, j int) { s.ids[i], s.ids[j] = s.ids[j], s.ids[i] }
+func (s bySpent) Less(i, j int) bool {
+	return s.ledgers[s.ids[i]].Spent > s.ledgers[s.ids[j]].Spent
+}
@@ -0,0 +1,211 @@
+package lib
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// every value written by the cryptBrain starts with this header so we can
+// tell encrypted values apart from plaintext written before encryption was
+// turned on. Values sealed with lzc1 only bound the key id into the
+// additional data, so they could be copied from one name to another; they're
+// still read, and RotateBrain re-seals them as lzc2, which binds the name
+// they're stored under too.
+var (
+	cryptMagic       = []byte("lzc2")
+	cryptLegacyMagic = []byte("lzc1")
+)
+
+// length of the key fingerprint stored after the magic header
+const cryptKeyIDLen = 4
+
+//cryptbrain wraps any other Brain and transparently encrypts values at rest
+type cryptBrain struct {
+	brain   Brain
+	current *cryptKey
+	keys    map[string]*cryptKey // every known key, indexed by fingerprint
+}
+
+type cryptKey struct {
+	id   []byte
+	aead cipher.AEAD
+}
+
+// newCryptBrain wraps the given brain with AES-GCM encryption. The first key
+// is used for all new writes, every other key is only used to read values
+// that were written before the key was rotated.
+func newCryptBrain(brain Brain, current string, old ...string) (Brain, error) {
+	cb := &cryptBrain{
+		brain: brain,
+		keys:  make(map[string]*cryptKey),
+	}
+	for i, encoded := range append([]string{current}, old...) {
+		key, err := newCryptKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			cb.current = key
+		}
+		cb.keys[string(key.id)] = key
+	}
+	return cb, nil
+}
+
+// newCryptKey parses a base64 encoded 16, 24 or 32 byte AES key
+func newCryptKey(encoded string) (*cryptKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("brain key is not valid base64: %v", err)
+	}
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, fmt.Errorf("brain key is not a valid AES key: %v", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(raw)
+	return &cryptKey{id: sum[:cryptKeyIDLen], aead: aead}, nil
+}
+
+func (cb *cryptBrain) Open() error {
+	return cb.brain.Open()
+}
+
+func (cb *cryptBrain) Close() error {
+	return cb.brain.Close()
+}
+
+// Get decrypts the stored value. Plaintext values (written before
+// encryption was turned on) are returned as they are; RotateBrain encrypts
+// them.
+func (cb *cryptBrain) Get(key string) ([]byte, error) {
+	data, err := cb.brain.Get(key)
+	if err != nil {
+		return data, err
+	}
+	if !cryptSealed(data) {
+		return data, nil
+	}
+	plain, _, err := cb.decrypt(key, data)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't decrypt %s: %v", key, err)
+	}
+	return plain, nil
+}
+
+func (cb *cryptBrain) Set(key string, data []byte) error {
+	sealed, err := cb.encrypt(key, data)
+	if err != nil {
+		return err
+	}
+	return cb.brain.Set(key, sealed)
+}
+
+func (cb *cryptBrain) Delete(key string) error {
+	return cb.brain.Delete(key)
+}
+
+func (cb *cryptBrain) Keys() ([]string, error) {
+	return cb.brain.Keys()
+}
+
+// rotate re-encrypts every value that isn't sealed with the current key the
+// current way: plaintext, values written with a retired key, and lzc1
+// values. It returns how many it re-wrote.
+func (cb *cryptBrain) rotate() (int, error) {
+	keys, err := cb.brain.Keys()
+	if err != nil {
+		return 0, err
+	}
+	rotated := 0
+	for _, key := range keys {
+		data, err := cb.brain.Get(key)
+		if err != nil {
+			return rotated, err
+		}
+		plain := data
+		if cryptSealed(data) {
+			var usedKey *cryptKey
+			if plain, usedKey, err = cb.decrypt(key, data); err != nil {
+				return rotated, fmt.Errorf("couldn't decrypt %s: %v", key, err)
+			}
+			if usedKey == cb.current && bytes.HasPrefix(data, cryptMagic) {
+				continue
+			}
+		}
+		if err := cb.Set(key, plain); err != nil {
+			return rotated, err
+		}
+		rotated++
+	}
+	return rotated, nil
+}
+
+// RotateBrain re-encrypts the brain's values with the current brain key (see
+// cryptBrain.rotate)
+func RotateBrain(brain Brain) (int, error) {
+	cb, ok := brain.(*cryptBrain)
+	if !ok {
+		return 0, fmt.Errorf("the brain isn't encrypted (set LAZLO_BRAIN_KEY)")
+	}
+	return cb.rotate()
+}
+
+// cryptSealed says whether the value was written by a cryptBrain
+func cryptSealed(data []byte) bool {
+	return bytes.HasPrefix(data, cryptMagic) || bytes.HasPrefix(data, cryptLegacyMagic)
+}
+
+// cryptAD is the additional data a value is sealed with: the key id and the
+// name it's stored under, so it can't be moved to another name
+func cryptAD(key *cryptKey, storageKey string) []byte {
+	return append(append([]byte{}, key.id...), storageKey...)
+}
+
+// encrypt returns magic|keyID|nonce|ciphertext
+func (cb *cryptBrain) encrypt(storageKey string, data []byte) ([]byte, error) {
+	nonce := make([]byte, cb.current.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte{}, cryptMagic...)
+	out = append(out, cb.current.id...)
+	out = append(out, nonce...)
+	return cb.current.aead.Seal(out, nonce, data, cryptAD(cb.current, storageKey)), nil
+}
+
+func (cb *cryptBrain) decrypt(storageKey string, data []byte) ([]byte, *cryptKey, error) {
+	legacy := bytes.HasPrefix(data, cryptLegacyMagic)
+	data = data[len(cryptMagic):]
+	if len(data) < cryptKeyIDLen {
+		return nil, nil, fmt.Errorf("value too short")
+	}
+	key, ok := cb.keys[string(data[:cryptKeyIDLen])]
+	if !ok {
+		return nil, nil, fmt.Errorf("value was encrypted with an unknown key")
+	}
+	data = data[cryptKeyIDLen:]
+	nonceSize := key.aead.NonceSize()
+	if len(data) < nonceSize {
+		return nil, nil, fmt.Errorf("value too short")
+	}
+	ad := cryptAD(key, storageKey)
+	if legacy {
+		ad = key.id
+	}
+	plain, err := key.aead.Open(nil, data[:nonceSize], data[nonceSize:], ad)
+	if err != nil {
+		return nil, nil, err
+	}
+	return plain, key, nil
+}
@@ -0,0 +1,214 @@
+package lib
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// testCryptKey is a new random base64 AES key of the given size
+func testCryptKey(t *testing.T, size int) string {
+	raw := make([]byte, size)
+	if _, err := rand.Read(raw); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+// testCryptBrain is a cryptBrain over a fresh ram brain
+func testCryptBrain(t *testing.T, current string, old ...string) (*cryptBrain, Brain) {
+	ram, _ := newRAMBrain(nil)
+	brain, err := newCryptBrain(ram, current, old...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return brain.(*cryptBrain), ram
+}
+
+// sealLegacy seals data the way lzc1 did, with only the key id as the
+// additional data
+func sealLegacy(t *testing.T, key *cryptKey, data []byte) []byte {
+	nonce := make([]byte, key.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		t.Fatal(err)
+	}
+	out := append(append(append([]byte{}, cryptLegacyMagic...), key.id...), nonce...)
+	return key.aead.Seal(out, nonce, data, key.id)
+}
+
+func TestNewCryptBrain(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		err  string
+	}{
+		{`aes-128`, testCryptKey(t, 16), ``},
+		{`aes-192`, testCryptKey(t, 24), ``},
+		{`aes-256`, testCryptKey(t, 32), ``},
+		{`padded`, " " + testCryptKey(t, 32) + "\n", ``},
+		{`not base64`, `not a key!`, `not valid base64`},
+		{`wrong size`, testCryptKey(t, 20), `not a valid AES key`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ram, _ := newRAMBrain(nil)
+			_, err := newCryptBrain(ram, test.key)
+			switch {
+			case test.err == `` && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != `` && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got error %v, want one about %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestCryptBrainGet(t *testing.T) {
+	current, retired := testCryptKey(t, 32), testCryptKey(t, 32)
+	tests := []struct {
+		name   string
+		stored func(cb *cryptBrain, old *cryptBrain) []byte // what's in the ram brain under "k"
+		want   string
+		err    string
+	}{
+		{
+			name:   `round trip`,
+			stored: func(cb, old *cryptBrain) []byte { v, _ := cb.encrypt(`k`, []byte(`hello`)); return v },
+			want:   `hello`,
+		},
+		{
+			name:   `empty value`,
+			stored: func(cb, old *cryptBrain) []byte { v, _ := cb.encrypt(`k`, nil); return v },
+			want:   ``,
+		},
+		{
+			name:   `plaintext from before encryption`,
+			stored: func(cb, old *cryptBrain) []byte { return []byte(`plain`) },
+			want:   `plain`,
+		},
+		{
+			name:   `retired key`,
+			stored: func(cb, old *cryptBrain) []byte { v, _ := old.encrypt(`k`, []byte(`older`)); return v },
+			want:   `older`,
+		},
+		{
+			name:   `lzc1`,
+			stored: func(cb, old *cryptBrain) []byte { return sealLegacy(t, cb.current, []byte(`legacy`)) },
+			want:   `legacy`,
+		},
+		{
+			name:   `moved from another name`,
+			stored: func(cb, old *cryptBrain) []byte { v, _ := cb.encrypt(`other`, []byte(`secret`)); return v },
+			err:    `couldn't decrypt k`,
+		},
+		{
+			name: `tampered`,
+			stored: func(cb, old *cryptBrain) []byte {
+				v, _ := cb.encrypt(`k`, []byte(`hello`))
+				v[len(v)-1] ^= 1
+				return v
+			},
+			err: `couldn't decrypt k`,
+		},
+		{
+			name: `unknown key`,
+			stored: func(cb, old *cryptBrain) []byte {
+				stranger, _ := testCryptBrain(t, testCryptKey(t, 32))
+				v, _ := stranger.encrypt(`k`, []byte(`hello`))
+				return v
+			},
+			err: `unknown key`,
+		},
+		{
+			name:   `truncated`,
+			stored: func(cb, old *cryptBrain) []byte { return append([]byte{}, cryptMagic...) },
+			err:    `too short`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cb, ram := testCryptBrain(t, current, retired)
+			old, _ := testCryptBrain(t, retired)
+			ram.Set(`k`, test.stored(cb, old))
+			got, err := cb.Get(`k`)
+			switch {
+			case test.err == `` && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != `` && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got %q, %v, want an error about %q", got, err, test.err)
+			case test.err == `` && string(got) != test.want:
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestCryptBrainSetSeals(t *testing.T) {
+	cb, ram := testCryptBrain(t, testCryptKey(t, 32))
+	if err := cb.Set(`k`, []byte(`hello`)); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := ram.Get(`k`)
+	if !bytes.HasPrefix(stored, cryptMagic) || bytes.Contains(stored, []byte(`hello`)) {
+		t.Errorf("stored %q, which isn't sealed", stored)
+	}
+	cb.Set(`k`, []byte(`hello`))
+	if again, _ := ram.Get(`k`); bytes.Equal(stored, again) {
+		t.Errorf("sealed the same value the same way twice (reused a nonce?)")
+	}
+}
+
+func TestRotateBrain(t *testing.T) {
+	current, retired := testCryptKey(t, 32), testCryptKey(t, 16)
+	cb, ram := testCryptBrain(t, current, retired)
+	old, _ := testCryptBrain(t, retired)
+	fresh, _ := cb.encrypt(`fresh`, []byte(`already current`))
+	retiredValue, _ := old.encrypt(`retired`, []byte(`retired key`))
+	values := map[string][]byte{
+		`fresh`:   fresh,
+		`plain`:   []byte(`plaintext`),
+		`retired`: retiredValue,
+		`legacy`:  sealLegacy(t, cb.current, []byte(`lzc1`)),
+	}
+	want := map[string]string{
+		`fresh`:   `already current`,
+		`plain`:   `plaintext`,
+		`retired`: `retired key`,
+		`legacy`:  `lzc1`,
+	}
+	for key, value := range values {
+		ram.Set(key, value)
+	}
+
+	rotated, err := RotateBrain(cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rotated != 3 {
+		t.Errorf("rotated %d values, want 3 (all but fresh)", rotated)
+	}
+	current2, _ := testCryptBrain(t, current)
+	for key, value := range want {
+		stored, _ := ram.Get(key)
+		if !bytes.HasPrefix(stored, append(append([]byte{}, cryptMagic...), cb.current.id...)) {
+			t.Errorf("%s isn't sealed as lzc2 with the current key: %q", key, stored)
+		}
+		// (and the retired key isn't needed to read it any more)
+		ram2, _ := newRAMBrain(nil)
+		ram2.Set(key, stored)
+		current2.brain = ram2
+		if got, err := current2.Get(key); err != nil || string(got) != value {
+			t.Errorf("%s is %q, %v after rotating, want %q", key, got, err, value)
+		}
+	}
+
+	if rotated, err = RotateBrain(cb); err != nil || rotated != 0 {
+		t.Errorf("rotating again rotated %d, %v, want 0", rotated, err)
+	}
+	ram2, _ := newRAMBrain(nil)
+	if _, err := RotateBrain(ram2); err == nil {
+		t.Errorf("rotated a brain that isn't encrypted")
+	}
+}
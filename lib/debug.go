@@ -0,0 +1,160 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"strconv"
+	"time"
+)
+
+// The longest CPU profile we'll take over http
+const maxCPUProfile = 120 * time.Second
+
+// We don't import net/http/pprof, because it quietly registers its handlers
+// on the default mux, which is the one lazlo serves on PORT. These do the
+// same thing, but only with LAZLO_DEBUG_TOKEN as a bearer token, and aren't
+// served at all without one. (Coming from localhost doesn't count for
+// anything: behind a reverse proxy, everybody does.)
+
+// debugOnly lets a request through if it carries the debug token
+func (b *Broker) debugOnly(handler http.HandlerFunc) http.HandlerFunc {
+	token := BearerToken(b.Config.DebugToken)
+	return func(res http.ResponseWriter, req *http.Request) {
+		if err := token(req, nil); err != nil {
+			Logger.Info(`rejected `, req.URL.Path, ` from `, req.RemoteAddr, `: `, err)
+			http.Error(res, `forbidden`, http.StatusForbidden)
+			return
+		}
+		handler(res, req)
+	}
+}
+
+// pprofHandler serves a named profile (goroutine, heap, threadcreate or
+// block), or a CPU profile for ?seconds=N (30 by default) as "profile".
+// ?debug=1 or 2 gets the text version instead of the binary one.
+func (b *Broker) pprofHandler(res http.ResponseWriter, req *http.Request) {
+	name := req.URL.Query().Get(`:name`)
+	if name == `profile` {
+		seconds, _ := strconv.Atoi(req.URL.Query().Get(`seconds`))
+		d := time.Duration(seconds) * time.Second
+		if d <= 0 {
+			d = 30 * time.Second
+		}
+		if d > maxCPUProfile {
+			d = maxCPUProfile
+		}
+		res.Header().Set(`Content-Type`, `application/octet-stream`)
+		if err := pprof.StartCPUProfile(res); err != nil {
+			http.Error(res, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		time.Sleep(d)
+		pprof.StopCPUProfile()
+		return
+	}
+	debug, _ := strconv.Atoi(req.URL.Query().Get(`debug`))
+	data, err := Profile(name, debug)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusNotFound)
+		return
+	}
+	if debug > 0 {
+		res.Header().Set(`Content-Type`, `text/plain; charset=utf-8`)
+	} else {
+		res.Header().Set(`Content-Type`, `application/octet-stream`)
+	}
+	res.Write(data)
+}
+
+// Profile takes a snapshot of one of the runtime's profiles (goroutine,
+// heap, threadcreate or block). debug is 0 for the binary format go tool
+// pprof reads, or 1 or 2 for text (2 gives full goroutine stacks).
+func Profile(name string, debug int) ([]byte, error) {
+	p := pprof.Lookup(name)
+	if p == nil {
+		return nil, fmt.Errorf("no such profile: %s", name)
+	}
+	if name == `heap` {
+		runtime.GC() // so the numbers are up to date
+	}
+	var buf bytes.Buffer
+	if err := p.WriteTo(&buf, debug); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RuntimeStats is a quick look at the runtime's health
+type RuntimeStats struct {
+	Goroutines int    `json:"goroutines"`
+	Threads    int    `json:"threads"`
+	HeapAlloc  uint64 `json:"heap_alloc"`
+	HeapSys    uint64 `json:"heap_sys"`
+	HeapObjs   uint64 `json:"heap_objects"`
+	NumGC      uint32 `json:"num_gc"`
+	PauseTotal string `json:"gc_pause_total"`
+	Uptime     string `json:"uptime"`
+	// whatever modules added with AddRuntimeStats, by name
+	Modules map[string]interface{} `json:"modules,omitempty"`
+	// lazlo's counters (see metrics.go)
+	Counters []Counter `json:"counters,omitempty"`
+}
+
+// AddRuntimeStats has stats called for numbers to add to the runtime stats
+// (and /debug/runtime) under name. It's called whenever they're gathered, on
+// whichever goroutine is gathering them.
+func (b *Broker) AddRuntimeStats(name string, stats func() interface{}) {
+	b.statsLock.Lock()
+	defer b.statsLock.Unlock()
+	if b.moduleStats == nil {
+		b.moduleStats = make(map[string]func() interface{})
+	}
+	b.moduleStats[name] = stats
+}
+
+// RuntimeStats gathers the runtime's numbers
+func (b *Broker) RuntimeStats() *RuntimeStats {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	threads, _ := runtime.ThreadCreateProfile(nil)
+	rs := &RuntimeStats{
+		Goroutines: runtime.NumGoroutine(),
+		Threads:    threads,
+		HeapAlloc:  m.HeapAlloc,
+		HeapSys:    m.HeapSys,
+		HeapObjs:   m.HeapObjects,
+		NumGC:      m.NumGC,
+		PauseTotal: time.Duration(m.PauseTotalNs).String(),
+		Uptime:     (time.Since(b.started) / time.Second * time.Second).String(),
+	}
+	if b.Metrics != nil {
+		rs.Counters = b.Metrics.Counters()
+	}
+	b.statsLock.Lock()
+	stats := make(map[string]func() interface{})
+	for name, fn := range b.moduleStats {
+		stats[name] = fn
+	}
+	b.statsLock.Unlock()
+	for name, fn := range stats {
+		if rs.Modules == nil {
+			rs.Modules = make(map[string]interface{})
+		}
+		rs.Modules[name] = fn()
+	}
+	return rs
+}
+
+func (rs *RuntimeStats) String() string {
+	return fmt.Sprintf("%d goroutines, %d threads, %.1fMB heap in use (%.1fMB reserved, %d objects), %d GCs (%s paused), up %s",
+		rs.Goroutines, rs.Threads, float64(rs.HeapAlloc)/1e6, float64(rs.HeapSys)/1e6, rs.HeapObjs, rs.NumGC, rs.PauseTotal, rs.Uptime)
+}
+
+func (b *Broker) runtimeHandler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set(`Content-Type`, `application/json`)
+	json.NewEncoder(res).Encode(b.RuntimeStats())
+}
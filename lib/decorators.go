@@ -0,0 +1,140 @@
+package lib
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Decorators dress up what lazlo says in some channels, or for some modules:
+// a "[staging]" in front of everything a staging lazlo says, a signature
+// after the deploy module's announcements, a code fence around the ssh
+// module's output. They're decorator entities in the registry:
+//
+//	"decorator": {
+//	  "staging": {"prefix": "[staging] ", "order": "1"},
+//	  "ssh-output": {"modules": "SSH", "fence": "true"},
+//	  "deploys": {"channels": "#deploys", "modules": "Deploy", "suffix": "\n— deploybot"}
+//	}
+//
+// A decorator applies to messages in its channels (names or IDs; all of
+// them, if it doesn't say) from its modules (all of them, if it doesn't
+// say; a message said with SayAs is from the module it names). They're
+// applied to the message's text in order (by their order attr, then their
+// name), each one fencing the text if it says to and then adding its prefix
+// and suffix, so the first decorator ends up innermost.
+// Decorators run before the message's templates are expanded, so a prefix
+// can have {{ }} in it too.
+//
+// Decorators are the first of the outbound middleware; WriteFilters that
+// modules register run after them, in the order they were registered.
+
+// A Decorator is a decorator entity from the registry
+type Decorator struct {
+	Name     string
+	Channels []string
+	Modules  []string
+	Prefix   string
+	Suffix   string
+	Fence    bool
+	Order    int
+}
+
+type byDecoratorOrder []*Decorator
+
+func (d byDecoratorOrder) Len() int      { return len(d) }
+func (d byDecoratorOrder) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byDecoratorOrder) Less(i, j int) bool {
+	if d[i].Order != d[j].Order {
+		return d[i].Order < d[j].Order
+	}
+	return d[i].Name < d[j].Name
+}
+
+// Decorators returns the decorators in the registry, in the order they're
+// applied
+func (b *Broker) Decorators() []*Decorator {
+	if b.Registry == nil {
+		return nil
+	}
+	var decorators []*Decorator
+	for _, e := range b.Registry.List(`decorator`) {
+		d := &Decorator{
+			Name:     e.Name,
+			Channels: splitList(e.Attrs[`channels`]),
+			Modules:  splitList(e.Attrs[`modules`]),
+			Prefix:   e.Attrs[`prefix`],
+			Suffix:   e.Attrs[`suffix`],
+		}
+		d.Fence, _ = strconv.ParseBool(e.Attrs[`fence`])
+		if order := e.Attrs[`order`]; order != `` {
+			n, err := strconv.Atoi(order)
+			if err != nil {
+				Logger.Error(`Decorators:: `, e.Name, `'s order isn't a number: `, order)
+			}
+			d.Order = n
+		}
+		decorators = append(decorators, d)
+	}
+	sort.Sort(byDecoratorOrder(decorators))
+	return decorators
+}
+
+// applies says whether the decorator applies to a message from the module in
+// the channel
+func (d *Decorator) applies(b *Broker, channel string, module string) bool {
+	if d.Modules != nil && !foldContains(d.Modules, module) {
+		return false
+	}
+	if d.Channels == nil {
+		return true
+	}
+	name := strings.TrimPrefix(b.channelName(channel), `#`)
+	for _, c := range d.Channels {
+		c = strings.TrimPrefix(c, `#`)
+		if c == channel || strings.EqualFold(c, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Decorator) decorate(text string) string {
+	if d.Fence {
+		text = "```\n" + strings.Trim(text, "\n") + "\n```"
+	}
+	return d.Prefix + text + d.Suffix
+}
+
+func foldContains(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// writeFilter runs the outbound middleware on a message: the decorators that
+// apply to it, then the WriteFilters
+func (b *Broker) writeFilter(e *Event) {
+	if e.Type == `message` && e.Text != `` {
+		module, found := ``, false
+		for _, d := range b.Decorators() {
+			if d.Modules != nil && !found {
+				// (walking the stack isn't free, so only if we need to)
+				module, found = b.callerModule(), true
+				if module == `` {
+					// said with SayAs or RespondAs
+					module = e.Persona
+				}
+			}
+			if d.applies(b, e.Channel, module) {
+				e.Text = d.decorate(e.Text)
+			}
+		}
+	}
+	for _, filter := range b.WriteFilters {
+		filter.Run(e)
+	}
+}
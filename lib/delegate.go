@@ -0,0 +1,329 @@
+package lib
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Two lazlos in the same workspace can hand each other commands: the prod
+// bot can ask the staging bot to run a check, and get back what it said.
+// They talk in LAZLO_DELEGATE_CHANNEL (a channel they're both in), in
+// messages like
+//
+//	lazlo:delegate <base64 JSON>.<signature>
+//
+// signed with LAZLO_DELEGATE_SECRET, which both bots share. A bot only takes
+// commands from the bots in its LAZLO_DELEGATE_PEERS (by name), and only the
+// commands that match LAZLO_DELEGATE_COMMANDS (a regex), which it runs as if
+// the other bot had said them to it, collecting what it says back. Requests
+// more than five minutes old, or seen before, are dropped.
+
+const delegatePrefix = `lazlo:delegate `
+
+// delegated commands "say" things into pseudo-channels like this, so what
+// they say can be sent back instead
+const delegateChannelPrefix = `delegate:`
+
+const (
+	delegateTimeout = 45 * time.Second // how long to wait for the other bot
+	delegateRunTime = 30 * time.Second // how long a delegated command gets
+	delegateQuiet   = 3 * time.Second  // a command is done when it's said nothing for this long
+)
+
+// A Delegation is a command one bot hands another, or the answer to one
+type Delegation struct {
+	ID      string   `json:"id"`
+	From    string   `json:"from"`
+	To      string   `json:"to"`
+	Command string   `json:"command,omitempty"`
+	Reply   []string `json:"reply,omitempty"` // what the command said
+	Error   string   `json:"error,omitempty"`
+	Sent    int64    `json:"sent"` // unix time
+}
+
+// delegations keeps track of the commands we're waiting on, and running
+type delegations struct {
+	lock    sync.Mutex
+	pending map[string]chan Delegation // the answers we're waiting for, by ID
+	running map[string]*delegatedRun   // the commands we're running, by pseudo-channel
+	seen    map[string]time.Time       // requests we've seen, against replays
+}
+
+// a delegatedRun collects what a delegated command says
+type delegatedRun struct {
+	lock  sync.Mutex
+	said  []string
+	heard chan struct{}
+}
+
+func newDelegations() *delegations {
+	return &delegations{
+		pending: make(map[string]chan Delegation),
+		running: make(map[string]*delegatedRun),
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// delegateSign signs the payload with LAZLO_DELEGATE_SECRET
+func (b *Broker) delegateSign(payload string) string {
+	mac := hmac.New(sha256.New, []byte(b.Config.DelegateSecret))
+	mac.Write([]byte(payload))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// delegateChannel is the channel ID of LAZLO_DELEGATE_CHANNEL
+func (b *Broker) delegateChannel() string {
+	name := strings.TrimPrefix(b.Config.DelegateChannel, `#`)
+	if c := b.SlackMeta.GetChannelByName(name); c != nil {
+		return c.ID
+	}
+	return name
+}
+
+// delegatePeer returns true if the bot is in LAZLO_DELEGATE_PEERS
+func (b *Broker) delegatePeer(name string) bool {
+	for _, peer := range splitList(b.Config.DelegatePeers) {
+		if strings.EqualFold(peer, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// postDelegation signs and posts a request or answer
+func (b *Broker) postDelegation(d Delegation, thread string) error {
+	d.Sent = time.Now().Unix()
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(data)
+	sent := b.Send(&Event{
+		Type:     `message`,
+		Channel:  b.delegateChannel(),
+		Text:     delegatePrefix + payload + `.` + b.delegateSign(payload),
+		ThreadTs: thread,
+	})
+	select {
+	case reply := <-sent:
+		if ok, answered := reply[`ok`].(bool); answered && !ok {
+			return fmt.Errorf("couldn't post to %s: %v", b.Config.DelegateChannel, reply[`error`])
+		}
+	case <-time.After(delegateQuiet):
+		// slack doesn't always answer on the websocket
+	}
+	return nil
+}
+
+// Delegate asks another lazlo (one of LAZLO_DELEGATE_PEERS) to run the
+// command, and returns what it said
+func (b *Broker) Delegate(peer string, command string) ([]string, error) {
+	if b.Config.DelegateSecret == `` || b.Config.DelegateChannel == `` {
+		return nil, fmt.Errorf("delegation isn't set up (see LAZLO_DELEGATE_SECRET and LAZLO_DELEGATE_CHANNEL)")
+	}
+	if !b.delegatePeer(peer) {
+		return nil, fmt.Errorf("%s isn't one of my peers", peer)
+	}
+	id := make([]byte, 8)
+	rand.Read(id)
+	d := Delegation{ID: hex.EncodeToString(id), From: b.Config.Name, To: peer, Command: command}
+	answer := make(chan Delegation, 1)
+	b.delegations.lock.Lock()
+	b.delegations.pending[d.ID] = answer
+	b.delegations.lock.Unlock()
+	defer func() {
+		b.delegations.lock.Lock()
+		delete(b.delegations.pending, d.ID)
+		b.delegations.lock.Unlock()
+	}()
+	if err := b.postDelegation(d, ``); err != nil {
+		return nil, err
+	}
+	select {
+	case a := <-answer:
+		if a.Error != `` {
+			return a.Reply, fmt.Errorf("%s", a.Error)
+		}
+		return a.Reply, nil
+	case <-time.After(delegateTimeout):
+		return nil, fmt.Errorf("%s didn't answer", peer)
+	}
+}
+
+// handleDelegation handles a delegation message, returning false if the
+// message isn't one
+func (b *Broker) handleDelegation(message *Event) bool {
+	if !strings.HasPrefix(message.Text, delegatePrefix) {
+		return false
+	}
+	if b.Config.DelegateSecret == `` {
+		return true
+	}
+	d, err := b.readDelegation(strings.TrimPrefix(message.Text, delegatePrefix))
+	if err != nil {
+		Logger.Error(`Delegate:: dropping a delegation: `, err)
+		return true
+	}
+	if !strings.EqualFold(d.To, b.Config.Name) {
+		return true
+	}
+	if !b.delegatePeer(d.From) {
+		Logger.Error(`Delegate:: `, d.From, ` isn't one of my peers`)
+		return true
+	}
+	if d.Command == `` {
+		b.delegations.lock.Lock()
+		answer, ok := b.delegations.pending[d.ID]
+		b.delegations.lock.Unlock()
+		if ok {
+			select {
+			case answer <- *d:
+			default: // we've already got one
+			}
+		}
+		return true
+	}
+	go b.runDelegation(d, message)
+	return true
+}
+
+// readDelegation checks a delegation's signature and age, and decodes it
+func (b *Broker) readDelegation(text string) (*Delegation, error) {
+	i := strings.LastIndex(text, `.`)
+	if i < 0 {
+		return nil, fmt.Errorf("it isn't signed")
+	}
+	payload, sig := text[:i], text[i+1:]
+	if !hmac.Equal([]byte(b.delegateSign(payload)), []byte(sig)) {
+		return nil, fmt.Errorf("bad signature")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, err
+	}
+	d := new(Delegation)
+	if err := json.Unmarshal(data, d); err != nil {
+		return nil, err
+	}
+	if math.Abs(time.Since(time.Unix(d.Sent, 0)).Minutes()) > 5 {
+		return nil, fmt.Errorf("it's stale")
+	}
+	if d.Command != `` {
+		b.delegations.lock.Lock()
+		defer b.delegations.lock.Unlock()
+		if _, seen := b.delegations.seen[d.ID]; seen {
+			return nil, fmt.Errorf("we've seen %s before", d.ID)
+		}
+		for id, when := range b.delegations.seen {
+			if time.Since(when) > 10*time.Minute {
+				delete(b.delegations.seen, id)
+			}
+		}
+		b.delegations.seen[d.ID] = time.Now()
+	}
+	return d, nil
+}
+
+// runDelegation runs a delegated command, if it's allowed, and answers it
+// in the request's thread
+func (b *Broker) runDelegation(d *Delegation, message *Event) {
+	answer := Delegation{ID: d.ID, From: b.Config.Name, To: d.From}
+	allowed := false
+	if b.Config.DelegateCommands != `` {
+		if r, err := regexp.Compile(`^(?:` + b.Config.DelegateCommands + `)$`); err == nil {
+			allowed = r.MatchString(d.Command)
+		} else {
+			Logger.Error(`Delegate:: bad LAZLO_DELEGATE_COMMANDS: `, err)
+		}
+	}
+	if allowed {
+		Logger.Info(`Delegate:: `, d.From, ` asked me to `, d.Command)
+		answer.Reply = b.runDelegated(d, message)
+	} else {
+		Logger.Error(`Delegate:: `, d.From, ` asked me to `, d.Command, `, which isn't allowed`)
+		answer.Error = fmt.Sprintf("%s won't do that for you", b.Config.Name)
+	}
+	if err := b.postDelegation(answer, message.Ts); err != nil {
+		Logger.Error(`Delegate:: couldn't answer `, d.From, `: `, err)
+	}
+}
+
+// runDelegated hands the command to the message callbacks as if the other
+// bot had said it to us, in a pseudo-channel, and collects what's said there
+func (b *Broker) runDelegated(d *Delegation, message *Event) []string {
+	channel := delegateChannelPrefix + d.ID
+	run := &delegatedRun{heard: make(chan struct{}, 1)}
+	b.delegations.lock.Lock()
+	b.delegations.running[channel] = run
+	b.delegations.lock.Unlock()
+	b.History.SetSensitive(channel, true)
+	defer func() {
+		b.delegations.lock.Lock()
+		delete(b.delegations.running, channel)
+		b.delegations.lock.Unlock()
+		b.History.SetSensitive(channel, false)
+	}()
+
+	go b.handleMessage(map[string]interface{}{
+		`type`:    `message`,
+		`user`:    message.User,
+		`channel`: channel,
+		`text`:    b.addressText(channel, d.Command),
+		`ts`:      message.Ts,
+	})
+	deadline := time.After(delegateRunTime)
+	quiet := time.After(delegateRunTime)
+wait:
+	for {
+		select {
+		case <-run.heard:
+			quiet = time.After(delegateQuiet)
+		case <-quiet:
+			break wait
+		case <-deadline:
+			break wait
+		}
+	}
+	run.lock.Lock()
+	defer run.lock.Unlock()
+	return run.said
+}
+
+// captureDelegated keeps what's said in a delegated command's
+// pseudo-channel, returning false if the event isn't said in one
+func (b *Broker) captureDelegated(e *Event) (chan map[string]interface{}, bool) {
+	if !strings.HasPrefix(e.Channel, delegateChannelPrefix) {
+		return nil, false
+	}
+	b.delegations.lock.Lock()
+	run := b.delegations.running[e.Channel]
+	b.delegations.lock.Unlock()
+	reply := make(chan map[string]interface{}, 1)
+	reply <- map[string]interface{}{`ok`: true, `channel`: e.Channel}
+	close(reply)
+	if run == nil {
+		return reply, true
+	}
+	text := e.Text
+	if e.Blocks != nil {
+		text = blocksMrkdwn(e.Blocks, ``)
+	}
+	run.lock.Lock()
+	run.said = append(run.said, text)
+	run.lock.Unlock()
+	select {
+	case run.heard <- struct{}{}:
+	default:
+	}
+	return reply, true
+}
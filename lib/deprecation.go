@@ -0,0 +1,89 @@
+package lib
+
+import (
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+)
+
+// Before the bot API can be called stable, the parts we want rid of have to
+// be found and moved off of. A deprecated API still works, but calling it
+// records who called it: the first time each plugin (or module) uses each
+// deprecated API, we log a warning with what to use instead, and every use
+// shows up in BuildInfo and the !modules report.
+
+// Deprecation is a plugin's (or module's) use of a deprecated API
+type Deprecation struct {
+	Plugin      string    `json:"plugin"`
+	API         string    `json:"api"`
+	Replacement string    `json:"replacement,omitempty"`
+	Uses        int       `json:"uses"`
+	First       time.Time `json:"first"`
+	Last        time.Time `json:"last"`
+}
+
+type byPluginAPI []Deprecation
+
+func (d byPluginAPI) Len() int      { return len(d) }
+func (d byPluginAPI) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byPluginAPI) Less(i, j int) bool {
+	if d[i].Plugin != d[j].Plugin {
+		return d[i].Plugin < d[j].Plugin
+	}
+	return d[i].API < d[j].API
+}
+
+// Deprecated records that plugin used a deprecated api, and warns about it
+// the first time. Replacement is what to use instead ("" if there's nothing).
+// The lua module calls this for lua plugins; go APIs call deprecated.
+func (b *Broker) Deprecated(plugin string, api string, replacement string) {
+	now := time.Now()
+	key := plugin + "\x00" + api
+	b.deprecLock.Lock()
+	if b.deprecations == nil {
+		b.deprecations = make(map[string]*Deprecation)
+	}
+	d, seen := b.deprecations[key]
+	if !seen {
+		d = &Deprecation{Plugin: plugin, API: api, Replacement: replacement, First: now}
+		b.deprecations[key] = d
+	}
+	d.Uses++
+	d.Last = now
+	b.deprecLock.Unlock()
+	if seen {
+		return
+	}
+	if replacement != `` {
+		Logger.Warning(plugin, ` uses `, api, `, which is deprecated; use `, replacement, ` instead`)
+	} else {
+		Logger.Warning(plugin, ` uses `, api, `, which is deprecated`)
+	}
+}
+
+// deprecated is Deprecated for the broker's own APIs. The module is the one
+// whose Run function is up the call stack, or failing that (a goroutine the
+// module started, say) the file the call came from.
+func (b *Broker) deprecated(api string, replacement string) {
+	module := b.callerModule()
+	if module == `` {
+		module = `unknown`
+		if _, file, _, ok := runtime.Caller(2); ok {
+			module = filepath.Base(file)
+		}
+	}
+	b.Deprecated(module, api, replacement)
+}
+
+// Deprecations is every use of a deprecated API so far, by plugin
+func (b *Broker) Deprecations() []Deprecation {
+	b.deprecLock.Lock()
+	var list []Deprecation
+	for _, d := range b.deprecations {
+		list = append(list, *d)
+	}
+	b.deprecLock.Unlock()
+	sort.Sort(byPluginAPI(list))
+	return list
+}
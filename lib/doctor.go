@@ -0,0 +1,218 @@
+package lib
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A Checkup is the outcome of one of the doctor's checks. If it failed,
+// Advice says what to do about it.
+type Checkup struct {
+	Name   string
+	Err    error
+	Advice string
+}
+
+// the scopes lazlo needs, and what breaks without them
+var neededScopes = map[string]string{
+	`chat:write`:      `lazlo can't talk`,
+	`reactions:write`: `lazlo can't add reactions`,
+	`files:write`:     `lazlo can't upload files`,
+	`users:read`:      `lazlo can't look up users`,
+	`channels:read`:   `lazlo can't look up channels`,
+	`im:write`:        `lazlo can't DM anyone`,
+}
+
+// Diagnose checks lazlo's configuration without starting the bot: the slack
+// token and its scopes, whether we can post, react and upload in the given
+// channel, the brain, TLS certs, and whether LAZLO_URL actually reaches us.
+func Diagnose(channel string) []Checkup {
+	b := &Broker{Config: newConfig()}
+	var checks []Checkup
+	check := func(name string, advice string, err error) bool {
+		checks = append(checks, Checkup{Name: name, Err: err, Advice: advice})
+		return err == nil
+	}
+
+	if b.Config.Token == `` {
+		check(`slack token`, `set LAZLO_TOKEN to your bot's token (Apps > your app > OAuth & Permissions)`, fmt.Errorf("LAZLO_TOKEN isn't set"))
+	} else if _, header, err := b.doctorCall(`auth.test`, nil); check(`slack token`, `check LAZLO_TOKEN: it should be a bot token (xoxb-...) that hasn't been revoked, and that this host can reach slack.com`, err) {
+		check(`slack scopes`, `add the missing scopes to your app and reinstall it to your workspace`, missingScopes(header.Get(`X-OAuth-Scopes`)))
+		if channel != `` {
+			b.checkChannel(channel, check)
+		}
+	}
+
+	brain, err := b.newBrain()
+	if err == nil {
+		err = brain.Open()
+	}
+	if check(`brain`, `check LAZLO_REDIS_URL and LAZLO_REDIS_PW, and that redis is up`, err) {
+		check(`brain read/write`, `check LAZLO_BRAIN_KEY and LAZLO_BRAIN_OLD_KEYS (see docs/configuration.md), and that redis isn't read-only`, brainRoundTrip(brain))
+		brain.Close()
+	}
+
+	if certs := splitList(b.Config.TLSCerts); certs != nil {
+		_, err := newCertStore(certs)
+		check(`tls certificates`, `check the cert:key pairs in LAZLO_TLS_CERTS exist and are readable`, err)
+	}
+	if b.Config.TLSAutocert != `` {
+		err := os.MkdirAll(b.Config.TLSAutocertCache, 0700)
+		check(`tls certificate cache`, `make sure lazlo can write to LAZLO_TLS_AUTOCERT_CACHE`, err)
+	}
+	if b.Config.Port != `` {
+		check(`http listener`, `make sure LAZLO_URL (plus PORT) is how the outside world reaches this host, and that nothing's in the way (firewalls, proxies)`, b.checkListener())
+	}
+	return checks
+}
+
+// doctorCall calls a slack api method and returns the decoded reply and its
+// headers (which is where slack tells us our scopes)
+func (b *Broker) doctorCall(method string, values url.Values) (map[string]interface{}, http.Header, error) {
+	if values == nil {
+		values = url.Values{}
+	}
+	values.Set(`token`, b.Config.Token)
+	reply, err := http.PostForm(`https://slack.com/api/`+method, values)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer reply.Body.Close()
+	resp := make(map[string]interface{})
+	if err := json.NewDecoder(reply.Body).Decode(&resp); err != nil {
+		return nil, nil, fmt.Errorf("Couldn't decode json. ERR: %v", err)
+	}
+	if ok, _ := resp[`ok`].(bool); !ok {
+		return resp, reply.Header, fmt.Errorf("%s failed: %v", method, resp[`error`])
+	}
+	return resp, reply.Header, nil
+}
+
+// missingScopes complains about any scopes we need that aren't in the list
+// slack gave us. Classic bot tokens just have "bot", which covers everything.
+func missingScopes(granted string) error {
+	if granted == `` {
+		return nil
+	}
+	have := make(map[string]bool)
+	for _, scope := range strings.Split(granted, `,`) {
+		have[strings.TrimSpace(scope)] = true
+	}
+	if have[`bot`] {
+		return nil
+	}
+	var missing []string
+	for scope, why := range neededScopes {
+		if !have[scope] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", scope, why))
+		}
+	}
+	if missing != nil {
+		sort.Strings(missing)
+		return fmt.Errorf("missing %s", strings.Join(missing, `, `))
+	}
+	return nil
+}
+
+// checkChannel posts, reacts and uploads in the channel, and cleans up after
+// itself
+func (b *Broker) checkChannel(channel string, check func(string, string, error) bool) {
+	resp, _, err := b.doctorCall(`chat.postMessage`, url.Values{
+		`channel`: {channel},
+		`text`:    {`lazlo doctor checking in (this message will self-destruct)`},
+		`as_user`: {`true`},
+	})
+	if !check(`post to `+channel, `invite the bot to `+channel+` (/invite @`+b.Config.Name+`) and check it has chat:write`, err) {
+		return
+	}
+	channelID, _ := resp[`channel`].(string)
+	ts, _ := resp[`ts`].(string)
+	defer b.doctorCall(`chat.delete`, url.Values{`channel`: {channelID}, `ts`: {ts}})
+
+	_, _, err = b.doctorCall(`reactions.add`, url.Values{
+		`channel`:   {channelID},
+		`timestamp`: {ts},
+		`name`:      {`white_check_mark`},
+	})
+	check(`react in `+channel, `add the reactions:write scope`, err)
+
+	resp, _, err = b.doctorCall(`files.upload`, url.Values{
+		`channels`: {channelID},
+		`content`:  {`lazlo doctor test upload`},
+		`filename`: {`lazlo-doctor.txt`},
+	})
+	if check(`upload to `+channel, `add the files:write scope`, err) {
+		if file, ok := resp[`file`].(map[string]interface{}); ok {
+			id, _ := file[`id`].(string)
+			b.doctorCall(`files.delete`, url.Values{`file`: {id}})
+		}
+	}
+}
+
+// brainRoundTrip writes a key, reads it back and deletes it
+func brainRoundTrip(brain Brain) error {
+	key := `doctor:` + nonce()
+	if err := brain.Set(key, []byte(`ok`)); err != nil {
+		return err
+	}
+	defer brain.Delete(key)
+	data, err := brain.Get(key)
+	if err != nil {
+		return err
+	}
+	if string(data) != `ok` {
+		return fmt.Errorf("wrote %q, but read back %q", `ok`, data)
+	}
+	return nil
+}
+
+// checkListener makes sure requests to LAZLO_URL:PORT end up here. If lazlo's
+// already running we just make sure it answers; otherwise we listen on PORT
+// ourselves and make sure a request with a random path makes it back to us.
+func (b *Broker) checkListener() error {
+	base := fmt.Sprintf("%s:%s", b.Config.URL, b.Config.Port)
+	client := &http.Client{Timeout: 10 * time.Second}
+	listener, err := net.Listen(`tcp`, `:`+b.Config.Port)
+	if err != nil {
+		Logger.Info(`doctor:: couldn't listen on `, b.Config.Port, ` (is lazlo running?), checking `, base, ` instead`)
+		reply, getErr := client.Get(base + `/`)
+		if getErr != nil {
+			return fmt.Errorf("can't listen on port %s (%v), and %s doesn't answer (%v)", b.Config.Port, err, base, getErr)
+		}
+		reply.Body.Close()
+		return nil
+	}
+	defer listener.Close()
+	path := `/doctor/` + nonce()
+	go http.Serve(listener, http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == path {
+			fmt.Fprint(res, path)
+		}
+	}))
+	reply, err := client.Get(base + path)
+	if err != nil {
+		return fmt.Errorf("couldn't reach %s: %v", base, err)
+	}
+	defer reply.Body.Close()
+	body, _ := ioutil.ReadAll(reply.Body)
+	if string(body) != path {
+		return fmt.Errorf("%s answered, but it isn't us (%s)", base, reply.Status)
+	}
+	return nil
+}
+
+func nonce() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
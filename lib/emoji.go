@@ -0,0 +1,180 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EmojiCatalog is lazlo's copy of the workspace's custom emoji, so modules
+// can check an emoji exists (before they build a workflow around reacting
+// with it, say) without asking slack every time. It's fetched the first time
+// it's needed, and the Emoji module keeps it up to date.
+type EmojiCatalog struct {
+	sync.RWMutex
+	broker  *Broker
+	emoji   map[string]string // name -> image url, or alias:<name>
+	fetched time.Time
+}
+
+func newEmojiCatalog(b *Broker) *EmojiCatalog {
+	return &EmojiCatalog{broker: b}
+}
+
+// Sync fetches the custom emoji from slack
+func (c *EmojiCatalog) Sync() error {
+	req := ApiRequest{
+		URL:    `https://slack.com/api/emoji.list`,
+		Values: make(url.Values),
+		Broker: c.broker,
+	}
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return err
+	}
+	if !reply.Ok {
+		return fmt.Errorf("emoji.list failed: %s", reply.Error)
+	}
+	c.Lock()
+	defer c.Unlock()
+	c.emoji = reply.Emoji
+	c.fetched = time.Now()
+	Logger.Debug(`Emoji:: synced `, len(c.emoji), ` custom emoji`)
+	return nil
+}
+
+// Fetched is when the catalog was last synced
+func (c *EmojiCatalog) Fetched() time.Time {
+	c.RLock()
+	defer c.RUnlock()
+	return c.fetched
+}
+
+// load syncs the catalog if it never has been
+func (c *EmojiCatalog) load() {
+	if c.Fetched().IsZero() {
+		if err := c.Sync(); err != nil {
+			Logger.Error(`Emoji:: couldn't fetch the custom emoji: `, err)
+		}
+	}
+}
+
+// Set records a custom emoji (or an alias:<name>) without waiting for the next
+// sync. An empty value removes it.
+func (c *EmojiCatalog) Set(name string, value string) {
+	c.Lock()
+	defer c.Unlock()
+	if c.emoji == nil {
+		c.emoji = make(map[string]string)
+	}
+	if value == `` {
+		delete(c.emoji, name)
+	} else {
+		c.emoji[name] = value
+	}
+}
+
+// Custom returns the image url of the named custom emoji (following aliases),
+// and whether there is one. Colons around the name are fine.
+func (c *EmojiCatalog) Custom(name string) (string, bool) {
+	c.load()
+	c.RLock()
+	defer c.RUnlock()
+	name = strings.Trim(name, `:`)
+	// aliases can point at aliases, but not forever
+	for i := 0; i < 10; i++ {
+		value, ok := c.emoji[name]
+		if !ok {
+			return ``, false
+		}
+		if !strings.HasPrefix(value, `alias:`) {
+			return value, true
+		}
+		name = strings.TrimPrefix(value, `alias:`)
+	}
+	return ``, false
+}
+
+// AliasOf returns the emoji the named one is an alias of, or ""
+func (c *EmojiCatalog) AliasOf(name string) string {
+	c.load()
+	c.RLock()
+	defer c.RUnlock()
+	value := c.emoji[strings.Trim(name, `:`)]
+	if !strings.HasPrefix(value, `alias:`) {
+		return ``
+	}
+	return strings.TrimPrefix(value, `alias:`)
+}
+
+// Names returns the names of the custom emoji containing substr (all of
+// them if it's empty), sorted
+func (c *EmojiCatalog) Names(substr string) []string {
+	c.load()
+	c.RLock()
+	defer c.RUnlock()
+	var names []string
+	for name := range c.emoji {
+		if strings.Contains(Fold(name), Fold(substr)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Add adds a custom emoji from an image url. It needs LAZLO_EMOJI_TOKEN.
+func (c *EmojiCatalog) Add(name string, image string) error {
+	name = strings.Trim(name, `:`)
+	values := make(url.Values)
+	values.Set(`name`, name)
+	values.Set(`url`, image)
+	if err := c.admin(`admin.emoji.add`, values); err != nil {
+		return err
+	}
+	c.Set(name, image)
+	return nil
+}
+
+// Alias adds name as another name for an existing emoji. It needs
+// LAZLO_EMOJI_TOKEN.
+func (c *EmojiCatalog) Alias(name string, existing string) error {
+	name = strings.Trim(name, `:`)
+	existing = strings.Trim(existing, `:`)
+	values := make(url.Values)
+	values.Set(`name`, name)
+	values.Set(`alias_for`, existing)
+	if err := c.admin(`admin.emoji.addAlias`, values); err != nil {
+		return err
+	}
+	c.Set(name, `alias:`+existing)
+	return nil
+}
+
+// admin calls one of slack's admin.emoji methods with the emoji token
+func (c *EmojiCatalog) admin(method string, values url.Values) error {
+	if c.broker.Config.EmojiToken == `` {
+		return fmt.Errorf("LAZLO_EMOJI_TOKEN isn't set, so I can't change the custom emoji")
+	}
+	values.Set(`token`, c.broker.Config.EmojiToken)
+	req := ApiRequest{
+		URL:    `https://slack.com/api/` + method,
+		Values: values,
+		Broker: c.broker,
+	}
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return err
+	}
+	if !reply.Ok {
+		switch reply.Error {
+		case `not_allowed`, `not_an_admin`, `missing_scope`, `not_authed`, `invalid_auth`:
+			return fmt.Errorf("%s isn't permitted with the emoji token (%s)", method, reply.Error)
+		}
+		return fmt.Errorf("%s failed: %s", method, reply.Error)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package lib
+
+import (
+	"net"
+	"regexp"
+	"strings"
+)
+
+// MessageEntities are the things in a message modules most often go looking
+// for. The broker fills them in on every message before any callback sees it,
+// so modules (and pipeline callbacks like intent classifiers) don't all have
+// to parse the text themselves.
+type MessageEntities struct {
+	Emails    []string
+	URLs      []string
+	IPs       []string
+	IssueKeys []string // like OPS-123
+	Mentions  []string // user IDs
+	Channels  []string // channel IDs
+}
+
+var (
+	// slack's markup: <@U123>, <#C123|general>, <mailto:a@b.com|a@b.com>, <https://x|x>
+	entityMarkupPat = regexp.MustCompile(`<([^<>|]+)(?:\|([^<>]*))?>`)
+	entityEmailPat  = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	entityURLPat    = regexp.MustCompile(`\bhttps?://[^\s<>"]+[^\s<>".,;:!?)\]]`)
+	entityIPv4Pat   = regexp.MustCompile(`\b\d{1,3}(?:\.\d{1,3}){3}\b`)
+	entityIPv6Pat   = regexp.MustCompile(`(?i)\b[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}\b`)
+	entityIssuePat  = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-[1-9]\d*\b`)
+	entityAtPat     = regexp.MustCompile(`(?:^|\s)@([\w.-]+)`)
+	entityHashPat   = regexp.MustCompile(`(?:^|\s)#([\w-]+)`)
+)
+
+// things that look like issue keys but aren't
+var notIssueKeys = map[string]bool{
+	`UTF`: true, `SHA`: true, `AES`: true, `ISO`: true, `RFC`: true, `CVE`: true, `TLS`: true, `HTTP`: true,
+}
+
+// ExtractEntities finds the emails, URLs, IPs, issue keys, mentions and
+// channels in text. Both slack's markup and plain text (like @dinah or
+// #general) are understood; plain names are only counted if they're a user
+// or channel we know about.
+func (b *Broker) ExtractEntities(text string) MessageEntities {
+	var e MessageEntities
+	add := func(list *[]string, value string) {
+		for _, v := range *list {
+			if v == value {
+				return
+			}
+		}
+		*list = append(*list, value)
+	}
+
+	// pull out the markup first, so we don't find things inside it twice
+	plain := entityMarkupPat.ReplaceAllStringFunc(text, func(markup string) string {
+		parts := entityMarkupPat.FindStringSubmatch(markup)
+		target, label := parts[1], parts[2]
+		switch {
+		case strings.HasPrefix(target, `@`):
+			add(&e.Mentions, target[1:])
+		case strings.HasPrefix(target, `#`):
+			add(&e.Channels, target[1:])
+		case strings.HasPrefix(target, `mailto:`):
+			add(&e.Emails, strings.TrimPrefix(target, `mailto:`))
+		case strings.HasPrefix(target, `http://`), strings.HasPrefix(target, `https://`):
+			add(&e.URLs, target)
+			if host := urlHost(target); net.ParseIP(host) != nil {
+				add(&e.IPs, host)
+			}
+			if label != target {
+				// the link text's part of what was said (it might be an issue key)
+				return ` ` + label + ` `
+			}
+		}
+		return ` `
+	})
+
+	for _, url := range entityURLPat.FindAllString(plain, -1) {
+		add(&e.URLs, url)
+		if host := urlHost(url); net.ParseIP(host) != nil {
+			add(&e.IPs, host)
+		}
+	}
+	plain = entityURLPat.ReplaceAllString(plain, ` `)
+	for _, email := range entityEmailPat.FindAllString(plain, -1) {
+		add(&e.Emails, email)
+	}
+	plain = entityEmailPat.ReplaceAllString(plain, ` `)
+	for _, ip := range entityIPv4Pat.FindAllString(plain, -1) {
+		if net.ParseIP(ip) != nil {
+			add(&e.IPs, ip)
+		}
+	}
+	for _, ip := range entityIPv6Pat.FindAllString(plain, -1) {
+		if strings.Contains(ip, `::`) || strings.Count(ip, `:`) == 7 {
+			if net.ParseIP(ip) != nil {
+				add(&e.IPs, ip)
+			}
+		}
+	}
+	for _, key := range entityIssuePat.FindAllString(plain, -1) {
+		if !notIssueKeys[key[:strings.Index(key, `-`)]] {
+			add(&e.IssueKeys, key)
+		}
+	}
+	if b.SlackMeta != nil {
+		for _, m := range entityAtPat.FindAllStringSubmatch(plain, -1) {
+			if u := b.SlackMeta.GetUserByName(strings.TrimRight(m[1], `.`)); u != nil {
+				add(&e.Mentions, u.ID)
+			}
+		}
+		for _, m := range entityHashPat.FindAllStringSubmatch(plain, -1) {
+			if c := b.SlackMeta.GetChannelByName(m[1]); c != nil {
+				add(&e.Channels, c.ID)
+			}
+		}
+	}
+	return e
+}
+
+// urlHost returns the host part of a url, without the port or brackets
+func urlHost(url string) string {
+	host := url[strings.Index(url, `://`)+3:]
+	if i := strings.IndexAny(host, `/?#`); i >= 0 {
+		host = host[:i]
+	}
+	if i := strings.LastIndex(host, `@`); i >= 0 {
+		host = host[i+1:]
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return strings.Trim(host, `[]`)
+}
@@ -0,0 +1,37 @@
+package lib
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// Some events (like app_home_opened) only come through slack's Events API,
+// not the RTM websocket. If LAZLO_SLACK_SIGNING_SECRET is set, lazlo takes
+// them at linkcb/events (point your slack app's event subscriptions there)
+// and hands them to event callbacks, just like events from the websocket.
+
+// eventsHandler answers slack's url_verification challenge and passes
+// event_callback events on to the event callbacks
+func (b *Broker) eventsHandler(res http.ResponseWriter, req *http.Request) {
+	var payload struct {
+		Type      string                 `json:"type"`
+		Challenge string                 `json:"challenge"`
+		Event     map[string]interface{} `json:"event"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&payload); err != nil {
+		http.Error(res, "bad event", http.StatusBadRequest)
+		return
+	}
+	b.watchdog.saw()
+	switch payload.Type {
+	case `url_verification`:
+		res.Header().Set(`Content-Type`, `text/plain`)
+		res.Write([]byte(payload.Challenge))
+	case `event_callback`:
+		// slack wants an answer within 3 seconds, so don't wait for the callbacks
+		if payload.Event != nil {
+			Logger.Debug(`EventsAPI:: got a `, payload.Event[`type`], ` event`)
+			go b.handleEvent(payload.Event)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Experiments let modules try a change out on some of their users before
+// everyone gets it:
+//
+//	if b.Experiments.Enabled(`new-help-format`, e.User) {
+//
+// LAZLO_EXPERIMENTS says what percentage of users each experiment is on for
+// (new-help-format=10,...), and an "experiment" entity in the registry with
+// a percent attr overrides it, so rollouts can be changed without a restart.
+// Everyone gets a bucket (0-99) per experiment the first time they're
+// exposed to it, kept in the brain, and the experiment is on for them while
+// their bucket is under its percentage; so the same people stay in it, and
+// turning it up only adds people.
+//
+// Exposures (someone seeing one side or the other for the first time) are
+// logged and counted, and modules count what people then do with Track, so
+// the two sides can be compared (see Report).
+
+// An ExperimentArm is one side of an experiment
+type ExperimentArm struct {
+	Exposed int            `json:"exposed"` // users who've seen this side
+	Metrics map[string]int `json:"metrics"` // what they did, by metric
+}
+
+// Experiments hands out the sides of experiments, and counts what happens
+type Experiments struct {
+	lock   sync.Mutex
+	broker *Broker
+}
+
+func newExperiments(b *Broker) *Experiments {
+	return &Experiments{broker: b}
+}
+
+func experimentKey(name string) string {
+	return `experiments:` + name
+}
+
+// Allocation returns the percentage of users the experiment is on for (0 if
+// there's no such experiment)
+func (x *Experiments) Allocation(name string) int {
+	b := x.broker
+	if b.Registry != nil {
+		if e := b.Registry.Get(`experiment`, name); e != nil {
+			if percent, err := strconv.Atoi(e.Attrs[`percent`]); err == nil {
+				return percent
+			}
+		}
+	}
+	return x.allocations()[name]
+}
+
+// allocations are the experiments in LAZLO_EXPERIMENTS
+func (x *Experiments) allocations() map[string]int {
+	allocations := make(map[string]int)
+	for _, item := range splitList(x.broker.Config.Experiments) {
+		parts := strings.SplitN(item, `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if percent, err := strconv.Atoi(strings.TrimSpace(parts[1])); err == nil {
+			allocations[strings.TrimSpace(parts[0])] = percent
+		}
+	}
+	return allocations
+}
+
+// Names returns the names of every configured experiment
+func (x *Experiments) Names() []string {
+	seen := x.allocations()
+	if reg := x.broker.Registry; reg != nil {
+		for _, e := range reg.List(`experiment`) {
+			seen[e.Name] = 0
+		}
+	}
+	var names []string
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Enabled returns true if the experiment is on for the user. The first time
+// the user sees either side of it counts as an exposure.
+func (x *Experiments) Enabled(name string, user string) bool {
+	percent := x.Allocation(name)
+	if percent <= 0 || user == `` {
+		return false
+	}
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	bucket, seen := x.bucket(name, user)
+	on := bucket < percent
+	arm := experimentArm(on)
+	if seen != arm {
+		x.expose(name, user, bucket, arm)
+	}
+	return on
+}
+
+// bucket returns the user's bucket for the experiment (picking one if they
+// haven't got one), and the side they were last exposed to
+func (x *Experiments) bucket(name string, user string) (int, string) {
+	b := x.broker
+	data, err := b.Brain.Get(UserKey(`experiments`, user, name))
+	if err == nil && data != nil {
+		parts := strings.Fields(string(data))
+		if len(parts) == 0 {
+			parts = []string{``}
+		}
+		if bucket, err := strconv.Atoi(parts[0]); err == nil {
+			if len(parts) > 1 {
+				return bucket, parts[1]
+			}
+			return bucket, ``
+		}
+	}
+	h := fnv.New32a()
+	h.Write([]byte(name + `:` + user))
+	return int(h.Sum32() % 100), ``
+}
+
+// expose records that the user has seen the arm of the experiment
+func (x *Experiments) expose(name string, user string, bucket int, arm string) {
+	b := x.broker
+	if err := b.Brain.Set(UserKey(`experiments`, user, name), []byte(fmt.Sprintf("%d %s", bucket, arm))); err != nil {
+		Logger.Error(`Experiments:: `, err)
+		return
+	}
+	Logger.Info(`Experiments:: `, user, ` sees `, name, ` `, arm)
+	arms := x.load(name)
+	arms[arm].Exposed++
+	x.save(name, arms)
+}
+
+// Track counts a metric (a click, a follow-up question, a thumbs up) for the
+// side of the experiment the user is on. Users who haven't been exposed to
+// it aren't counted.
+func (x *Experiments) Track(name string, user string, metric string) {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	_, arm := x.bucket(name, user)
+	if arm == `` {
+		return
+	}
+	arms := x.load(name)
+	arms[arm].Metrics[metric]++
+	x.save(name, arms)
+}
+
+// Arms returns both sides of the experiment, by "on" and "off"
+func (x *Experiments) Arms(name string) map[string]*ExperimentArm {
+	x.lock.Lock()
+	defer x.lock.Unlock()
+	return x.load(name)
+}
+
+func (x *Experiments) load(name string) map[string]*ExperimentArm {
+	arms := make(map[string]*ExperimentArm)
+	if data, err := x.broker.Brain.Get(experimentKey(name)); err == nil && data != nil {
+		if err := json.Unmarshal(data, &arms); err != nil {
+			Logger.Error(`Experiments:: `, name, `: `, err)
+		}
+	}
+	for _, arm := range []string{`on`, `off`} {
+		if arms[arm] == nil {
+			arms[arm] = new(ExperimentArm)
+		}
+		if arms[arm].Metrics == nil {
+			arms[arm].Metrics = make(map[string]int)
+		}
+	}
+	return arms
+}
+
+func (x *Experiments) save(name string, arms map[string]*ExperimentArm) {
+	data, err := json.Marshal(arms)
+	if err == nil {
+		err = x.broker.Brain.Set(experimentKey(name), data)
+	}
+	if err != nil {
+		Logger.Error(`Experiments:: `, name, `: `, err)
+	}
+}
+
+func experimentArm(on bool) string {
+	if on {
+		return `on`
+	}
+	return `off`
+}
+
+// Report compares the two sides of the experiment: how many users saw each,
+// and how often they did each of the tracked things
+func (x *Experiments) Report(name string) *Result {
+	arms := x.Arms(name)
+	r := &Result{Title: `Experiment ` + name}
+	r.Field(`Allocation`, fmt.Sprintf("%d%%", x.Allocation(name)))
+	var metrics []string
+	seen := make(map[string]bool)
+	for _, arm := range arms {
+		for metric := range arm.Metrics {
+			if !seen[metric] {
+				seen[metric] = true
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+	sort.Strings(metrics)
+	r.Table = &ResultTable{Header: append([]string{`side`, `users`}, metrics...)}
+	for _, side := range []string{`on`, `off`} {
+		arm := arms[side]
+		row := []interface{}{side, arm.Exposed}
+		for _, metric := range metrics {
+			n := arm.Metrics[metric]
+			if arm.Exposed > 0 {
+				row = append(row, fmt.Sprintf("%d (%.2f per user)", n, float64(n)/float64(arm.Exposed)))
+			} else {
+				row = append(row, n)
+			}
+		}
+		r.Row(row...)
+	}
+	if arms[`on`].Exposed+arms[`off`].Exposed == 0 {
+		r.Text = `Nobody's been exposed to it yet`
+	}
+	return r
+}
@@ -0,0 +1,87 @@
+package lib
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// An ExpungeHook is run by ExpungeUser so modules that keep user data
+// somewhere other than a UserKey() can forget about the user too. It returns
+// a description of each thing it removed.
+type ExpungeHook func(userID string) ([]string, error)
+
+// ExpungeReport describes everything ExpungeUser removed
+type ExpungeReport struct {
+	UserID  string
+	Keys    []string // brain keys that were deleted
+	Removed []string // things removed by module expunge hooks
+	Errors  []error
+}
+
+// OnExpunge registers a hook to run whenever a user's data is expunged
+func (b *Broker) OnExpunge(hook ExpungeHook) {
+	b.expungeHooks = append(b.expungeHooks, hook)
+}
+
+// ExpungeUser deletes every brain key that belongs to the given user (any
+// key with the user's ID as one of its colon-separated segments, like the
+// ones made by UserKey), runs the registered expunge hooks, and reports what
+// was removed.
+func (b *Broker) ExpungeUser(userID string) (*ExpungeReport, error) {
+	if userID == `` {
+		return nil, fmt.Errorf("refusing to expunge an empty user ID")
+	}
+	report := &ExpungeReport{UserID: userID}
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !keyBelongsTo(key, userID) {
+			continue
+		}
+		if err := b.Brain.Delete(key); err != nil {
+			report.Errors = append(report.Errors, fmt.Errorf("%s: %v", key, err))
+			continue
+		}
+		report.Keys = append(report.Keys, key)
+	}
+	for _, hook := range b.expungeHooks {
+		removed, err := hook(userID)
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+		}
+		report.Removed = append(report.Removed, removed...)
+	}
+	Logger.Info(`expunged `, len(report.Keys), ` keys and `, len(report.Removed), ` other things for user `, userID)
+	return report, nil
+}
+
+func keyBelongsTo(key string, userID string) bool {
+	for _, segment := range strings.Split(key, `:`) {
+		if segment == userID {
+			return true
+		}
+	}
+	return false
+}
+
+// String renders the report for humans
+func (r *ExpungeReport) String() string {
+	out := fmt.Sprintf("Expunged data for %s:", r.UserID)
+	if len(r.Keys) == 0 && len(r.Removed) == 0 {
+		out = fmt.Sprintf("%s\nnothing found", out)
+	}
+	for _, key := range r.Keys {
+		out = fmt.Sprintf("%s\n  brain key: %s", out, key)
+	}
+	for _, thing := range r.Removed {
+		out = fmt.Sprintf("%s\n  %s", out, thing)
+	}
+	for _, err := range r.Errors {
+		out = fmt.Sprintf("%s\n  ERROR: %v", out, err)
+	}
+	return out
+}
@@ -0,0 +1,92 @@
+package lib
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// The fake broker's slack has one channel and one user (besides the bot)
+const (
+	FakeChannel = `C0GENERAL`
+	FakeUser    = `U0TESTER`
+	fakeBotUser = `U0LAZLO`
+)
+
+// fakeAdapter stands in for slack when a broker is used to test plugins
+type fakeAdapter struct {
+	sync.Mutex
+	said []Event
+	ts   int
+}
+
+// NewFakeBroker returns a broker that isn't connected to slack, for testing
+// plugins. Feed it messages with Hear; everything it would have said is kept
+// for Said instead. Its brain is always in memory.
+func NewFakeBroker() (*Broker, error) {
+	b := newBroker()
+	b.fake = new(fakeAdapter)
+	b.Config.RedisURL = ``
+	b.Redactors = newRedactors(b.Config)
+	b.History = newHistory(b.Config.HistorySize)
+	b.traces = newTraceBuffer(b.Config.TraceSize)
+	b.Fetcher = newFetcher(b.Config)
+	b.prompts = newPromptStore(b.Config.PromptDir)
+	b.SlackMeta = &ApiResponse{
+		Self:     Self{ID: fakeBotUser, Name: b.Config.Name},
+		Users:    []User{{ID: fakeBotUser, Name: b.Config.Name, IsBot: true}, {ID: FakeUser, Name: `tester`}},
+		Channels: []Channel{{ID: FakeChannel, Name: `general`, IsChannel: true, IsGeneral: true}},
+	}
+	var err error
+	if b.Brain, err = newRAMBrain(b); err != nil {
+		return nil, err
+	}
+	if err = b.Brain.Open(); err != nil {
+		return nil, err
+	}
+	b.Registry = newRegistry(b)
+	b.templates = newTemplateFuncs(b)
+	b.Ignore = newIgnoreList(b)
+	b.Jobs = newJobManager(b)
+	b.once = newOnceLog(b)
+	// there's no custom emoji to fetch
+	b.Emoji.fetched = time.Now()
+	return b, nil
+}
+
+// Hear hands a fake broker a message, as if user had said it in channel. It
+// returns once every callback the message matched has been given it, so
+// something has to be reading the callbacks' channels.
+func (b *Broker) Hear(user string, channel string, text string) {
+	b.fake.Lock()
+	b.fake.ts++
+	ts := fmt.Sprintf("%d.%06d", b.started.Unix(), b.fake.ts)
+	b.fake.Unlock()
+	b.handleMessage(map[string]interface{}{
+		`type`:    `message`,
+		`user`:    user,
+		`channel`: channel,
+		`text`:    text,
+		`ts`:      ts,
+	})
+}
+
+// Said returns everything a fake broker has said since the last time you
+// asked
+func (b *Broker) Said() []Event {
+	b.fake.Lock()
+	defer b.fake.Unlock()
+	said := b.fake.said
+	b.fake.said = nil
+	return said
+}
+
+// send keeps an outbound event instead of sending it. Nobody ever replies.
+func (f *fakeAdapter) send(e Event) chan map[string]interface{} {
+	f.Lock()
+	f.said = append(f.said, e)
+	f.Unlock()
+	reply := make(chan map[string]interface{})
+	close(reply)
+	return reply
+}
@@ -0,0 +1,202 @@
+package lib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// A Fetcher makes outbound GET requests on behalf of every module, so that
+// modules hitting the same API share one rate limit and one cache instead of
+// tripping the upstream's limits. Responses are cached for TTL and then
+// revalidated with their ETag (or Last-Modified) header. After BreakAfter
+// failures in a row, requests to that host fail fast (or get the last cached
+// copy) for Cooldown.
+type Fetcher struct {
+	lock       sync.Mutex
+	Client     *http.Client
+	TTL        time.Duration
+	PerMinute  int // per host
+	BreakAfter int
+	Cooldown   time.Duration
+	cache      map[string]*FetchResponse
+	hosts      map[string]*hostState
+}
+
+// A FetchResponse is a (possibly cached) response to a GET request
+type FetchResponse struct {
+	URL     string
+	Status  int
+	Header  http.Header
+	Body    []byte
+	Fetched time.Time
+	Cached  bool // true if this came out of the cache
+	Stale   bool // true if the host is down and this copy is past its TTL
+}
+
+// the number of cached responses after which we start throwing out stale ones
+const maxFetchCache = 1000
+
+// hostState tracks the rate limit and circuit breaker for one host
+type hostState struct {
+	next      time.Time // the earliest we can send the next request
+	failures  int
+	openUntil time.Time
+}
+
+func newFetcher(c *Config) *Fetcher {
+	return &Fetcher{
+		Client:     &http.Client{Timeout: 30 * time.Second},
+		TTL:        time.Duration(c.FetchTTL) * time.Second,
+		PerMinute:  c.FetchRate,
+		BreakAfter: c.FetchBreakAfter,
+		Cooldown:   time.Duration(c.FetchCooldown) * time.Second,
+		cache:      make(map[string]*FetchResponse),
+		hosts:      make(map[string]*hostState),
+	}
+}
+
+// Fetch GETs the given URL through the broker's shared Fetcher
+func (b *Broker) Fetch(rawurl string) (*FetchResponse, error) {
+	return b.Fetcher.Get(rawurl)
+}
+
+// Get returns the cached response for the URL if it's fresh, and otherwise
+// waits its turn and asks the host for it (revalidating our cached copy if
+// we have one)
+func (f *Fetcher) Get(rawurl string) (*FetchResponse, error) {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != `http` && u.Scheme != `https` {
+		return nil, fmt.Errorf("can't fetch %s: only http and https are supported", rawurl)
+	}
+
+	f.lock.Lock()
+	cached := f.cache[rawurl]
+	if cached != nil && time.Since(cached.Fetched) < f.TTL {
+		f.lock.Unlock()
+		return cached.copy(true, false), nil
+	}
+	host := f.hosts[u.Host]
+	if host == nil {
+		host = new(hostState)
+		f.hosts[u.Host] = host
+	}
+	if time.Now().Before(host.openUntil) {
+		f.lock.Unlock()
+		if cached != nil {
+			return cached.copy(true, true), nil
+		}
+		return nil, fmt.Errorf("not fetching %s: %s has failed %d times in a row, trying again after %s", rawurl, u.Host, host.failures, host.openUntil.Format(time.Kitchen))
+	}
+	// reserve the next slot for this host and sleep until it comes up
+	wait := host.next.Sub(time.Now())
+	if wait < 0 {
+		wait = 0
+	}
+	if f.PerMinute > 0 {
+		host.next = time.Now().Add(wait + time.Minute/time.Duration(f.PerMinute))
+	}
+	f.lock.Unlock()
+	if wait > 0 {
+		Logger.Debug(`Fetcher:: waiting `, wait, ` to fetch `, rawurl)
+		time.Sleep(wait)
+	}
+
+	resp, err := f.do(rawurl, cached)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if err != nil {
+		host.failures++
+		if f.BreakAfter > 0 && host.failures >= f.BreakAfter {
+			host.openUntil = time.Now().Add(f.Cooldown)
+			Logger.Error(`Fetcher:: `, u.Host, ` failed `, host.failures, ` times in a row, backing off until `, host.openUntil)
+		}
+		if cached != nil {
+			Logger.Debug(`Fetcher:: serving stale copy of `, rawurl, `: `, err)
+			return cached.copy(true, true), nil
+		}
+		return nil, err
+	}
+	host.failures = 0
+	if resp.Status == http.StatusOK {
+		if len(f.cache) >= maxFetchCache {
+			f.prune()
+		}
+		f.cache[rawurl] = resp
+	}
+	return resp.copy(resp.Cached, false), nil
+}
+
+// do makes the request. Network errors, 5xx's and 429's count as failures.
+func (f *Fetcher) do(rawurl string, cached *FetchResponse) (*FetchResponse, error) {
+	req, err := http.NewRequest(`GET`, rawurl, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if etag := cached.Header.Get(`ETag`); etag != `` {
+			req.Header.Set(`If-None-Match`, etag)
+		}
+		if modified := cached.Header.Get(`Last-Modified`); modified != `` {
+			req.Header.Set(`If-Modified-Since`, modified)
+		}
+	}
+	reply, err := f.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer reply.Body.Close()
+	if reply.StatusCode >= 500 || reply.StatusCode == 429 {
+		return nil, fmt.Errorf("fetching %s: %s", rawurl, reply.Status)
+	}
+	if reply.StatusCode == http.StatusNotModified && cached != nil {
+		Logger.Debug(`Fetcher:: `, rawurl, ` not modified`)
+		resp := cached.copy(true, false)
+		resp.Fetched = time.Now()
+		return resp, nil
+	}
+	body, err := ioutil.ReadAll(reply.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &FetchResponse{
+		URL:     rawurl,
+		Status:  reply.StatusCode,
+		Header:  reply.Header,
+		Body:    body,
+		Fetched: time.Now(),
+	}, nil
+}
+
+// copy returns a shallow copy of a response, so callers can't change the
+// cached copy's fields out from under us
+func (fr *FetchResponse) copy(cached bool, stale bool) *FetchResponse {
+	out := *fr
+	out.Cached = cached
+	out.Stale = stale
+	return &out
+}
+
+// prune drops cached responses that are past their TTL (we hang on to them
+// otherwise, so we have something to serve when a host goes down)
+func (f *Fetcher) prune() {
+	for key, resp := range f.cache {
+		if time.Since(resp.Fetched) >= f.TTL {
+			delete(f.cache, key)
+		}
+	}
+}
+
+// Forget drops the cached copy of the URL
+func (f *Fetcher) Forget(rawurl string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	delete(f.cache, rawurl)
+}
@@ -0,0 +1,320 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Modules write messages for slack: mrkdwn text, blocks and attachments.
+// Before a message goes out it's formatted for whatever lazlo is talking to
+// (LAZLO_ADAPTER): blocks and attachments are downgraded to markdown for
+// chat systems that only speak that, or all the way down to plain text, and
+// messages that are too long for one go are split into several (see
+// pages.go for what happens to them).
+
+// An AdapterFormat is what a chat system can show in one message
+type AdapterFormat struct {
+	Render    string // the richest it can show: RenderBlocks, RenderMarkdown or RenderText
+	MaxText   int    // the most characters of text
+	MaxBlocks int    // the most blocks (if it can show them)
+}
+
+// the chat systems lazlo knows how to format for
+var adapterFormats = map[string]AdapterFormat{
+	`slack`:      {Render: RenderBlocks, MaxText: 40000, MaxBlocks: 50},
+	`mattermost`: {Render: RenderMarkdown, MaxText: 16383},
+	`text`:       {Render: RenderText, MaxText: 4000},
+}
+
+// AdapterFormat returns the format of the chat system we're talking to
+func (b *Broker) AdapterFormat() AdapterFormat {
+	if b.fake != nil {
+		return adapterFormats[`text`]
+	}
+	if f, ok := adapterFormats[strings.ToLower(b.Config.Adapter)]; ok {
+		return f
+	}
+	return adapterFormats[`slack`]
+}
+
+// Format formats the event for the chat system we're talking to, splitting
+// it into as many events as it takes
+func (b *Broker) Format(e *Event) []*Event {
+	f := b.AdapterFormat()
+	switch f.Render {
+	case RenderMarkdown:
+		e.Text = downgrade(e, b.MrkdwnToMarkdown, b.blocksMarkdown, Attachment.markdown)
+		e.Blocks, e.Attachments = nil, nil
+	case RenderText:
+		e.Text = downgrade(e, b.MrkdwnToText, b.blocksText, b.attachmentText)
+		e.Blocks, e.Attachments = nil, nil
+	default:
+		e.Blocks = splitSections(e.Blocks)
+	}
+
+	if e.Blocks != nil && len(e.Text) > f.MaxText {
+		// it's only the fallback for notifications
+		e.Text = TruncateBytes(e.Text, f.MaxText)
+	}
+	var events []*Event
+	for i, text := range splitText(e.Text, f.MaxText) {
+		part := *e
+		part.Text = text
+		if i > 0 {
+			part.Blocks, part.Attachments = nil, nil
+		}
+		events = append(events, &part)
+	}
+	if f.MaxBlocks > 0 && len(e.Blocks) > f.MaxBlocks {
+		// the first message gets the first lot of blocks, and the rest go
+		// after the text
+		events[0].Blocks = e.Blocks[:f.MaxBlocks]
+		for i := f.MaxBlocks; i < len(e.Blocks); i += f.MaxBlocks {
+			part := *e
+			part.Blocks = e.Blocks[i:min(i+f.MaxBlocks, len(e.Blocks))]
+			part.Text = blocksMrkdwn(part.Blocks, ``)
+			part.Text = TruncateBytes(part.Text, f.MaxText)
+			part.Attachments = nil
+			events = append(events, &part)
+		}
+	}
+	return events
+}
+
+// downgrade renders the event's blocks and attachments as text. The event's
+// text is only a fallback when it has blocks, so it's dropped for them.
+func downgrade(e *Event, text func(string) string, blocks func([]Block) string, attachment func(Attachment) string) string {
+	var out []string
+	if e.Blocks != nil {
+		out = append(out, blocks(e.Blocks))
+	} else if e.Text != `` {
+		out = append(out, text(e.Text))
+	}
+	for _, a := range e.Attachments {
+		if s := attachment(a); s != `` {
+			out = append(out, s)
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// splitText breaks text into pieces of at most max characters, between
+// paragraphs or lines if it can, and closes (and reopens) code blocks that
+// get split
+func splitText(text string, max int) []string {
+	if max <= 0 || len(text) <= max {
+		return []string{text}
+	}
+	var parts []string
+	for len(text) > max {
+		limit := max - len("\n```")
+		cut := strings.LastIndex(text[:limit], "\n\n")
+		if cut < limit/2 {
+			cut = strings.LastIndex(text[:limit], "\n")
+		}
+		if cut < limit/2 {
+			cut = strings.LastIndex(text[:limit], ` `)
+		}
+		if cut < limit/2 {
+			cut = graphemeBoundary(text, limit)
+		}
+		part, rest := text[:cut], strings.TrimLeft(text[cut:], "\n ")
+		if strings.Count(part, "```")%2 == 1 {
+			part += "\n```"
+			rest = "```\n" + rest
+		}
+		parts = append(parts, part)
+		text = rest
+	}
+	return append(parts, text)
+}
+
+// slack mrkdwn, outside of code
+var (
+	mrkdwnBold   = regexp.MustCompile(`(^|[\s(])\*([^*\n]+)\*`)
+	mrkdwnItalic = regexp.MustCompile(`(^|[\s(])_([^_\n]+)_`)
+	mrkdwnStrike = regexp.MustCompile(`(^|[\s(])~([^~\n]+)~`)
+	mrkdwnLink   = regexp.MustCompile(`<((?:https?|mailto):[^|>]+)(?:\|([^>]+))?>`)
+	mrkdwnRef    = regexp.MustCompile(`<([@#!])([^|>]+)(?:\|([^>]+))?>`)
+)
+
+// outsideCode applies f to the parts of mrkdwn that aren't code
+func outsideCode(s string, f func(string) string) string {
+	parts := strings.Split(s, "```")
+	for i := range parts {
+		if i%2 == 1 {
+			continue
+		}
+		spans := strings.Split(parts[i], "`")
+		for j := range spans {
+			if j%2 == 0 {
+				spans[j] = f(spans[j])
+			}
+		}
+		parts[i] = strings.Join(spans, "`")
+	}
+	return strings.Join(parts, "```")
+}
+
+// MrkdwnToMarkdown converts slack's mrkdwn to (github flavored) markdown.
+// Mentions become @names and #channels.
+func (b *Broker) MrkdwnToMarkdown(s string) string {
+	return outsideCode(s, func(s string) string {
+		s = b.plainRefs(s)
+		s = mrkdwnBold.ReplaceAllString(s, `$1**$2**`)
+		s = mrkdwnItalic.ReplaceAllString(s, `$1*$2*`)
+		s = mrkdwnStrike.ReplaceAllString(s, `$1~~$2~~`)
+		s = mrkdwnLink.ReplaceAllStringFunc(s, func(link string) string {
+			m := mrkdwnLink.FindStringSubmatch(link)
+			if m[2] == `` {
+				return m[1]
+			}
+			return fmt.Sprintf("[%s](%s)", m[2], m[1])
+		})
+		return s
+	})
+}
+
+// MrkdwnToText strips the markup out of slack's mrkdwn. Mentions become
+// @names and #channels, and links become "text (url)".
+func (b *Broker) MrkdwnToText(s string) string {
+	s = outsideCode(s, func(s string) string {
+		s = mrkdwnBold.ReplaceAllString(s, `$1$2`)
+		s = mrkdwnItalic.ReplaceAllString(s, `$1$2`)
+		s = mrkdwnStrike.ReplaceAllString(s, `$1$2`)
+		s = mrkdwnLink.ReplaceAllStringFunc(s, func(link string) string {
+			m := mrkdwnLink.FindStringSubmatch(link)
+			if m[2] == `` || m[2] == m[1] {
+				return m[1]
+			}
+			return fmt.Sprintf("%s (%s)", m[2], m[1])
+		})
+		return b.plainRefs(s)
+	})
+	return strings.Replace(s, "```", ``, -1)
+}
+
+// plainRefs turns mentions, channel links and <!here>s into @names,
+// #channels and @heres
+func (b *Broker) plainRefs(s string) string {
+	return mrkdwnRef.ReplaceAllStringFunc(s, func(ref string) string {
+		m := mrkdwnRef.FindStringSubmatch(ref)
+		switch {
+		case m[3] != ``:
+			if m[1] == `!` {
+				return m[3]
+			}
+			return m[1] + m[3]
+		case m[1] == `@`:
+			if name := b.SlackMeta.GetUserName(m[2]); name != `` {
+				return `@` + name
+			}
+		case m[1] == `#`:
+			return b.channelName(m[2])
+		case m[1] == `!`:
+			return `@` + m[2]
+		}
+		return m[1] + m[2]
+	})
+}
+
+// blocksMrkdwn renders blocks as mrkdwn, with the given horizontal rule
+func blocksMrkdwn(blocks []Block, rule string) string {
+	var out []string
+	for _, block := range blocks {
+		switch block[`type`] {
+		case `header`:
+			out = append(out, `*`+blockText(block[`text`])+`*`)
+		case `section`:
+			if text := blockText(block[`text`]); text != `` {
+				out = append(out, text)
+			}
+			var fields []string
+			for _, field := range blockList(block[`fields`]) {
+				fields = append(fields, blockText(field))
+			}
+			if fields != nil {
+				out = append(out, strings.Join(fields, "\n"))
+			}
+		case `context`:
+			var texts []string
+			for _, element := range blockList(block[`elements`]) {
+				if text := blockText(element); text != `` {
+					texts = append(texts, text)
+				}
+			}
+			out = append(out, strings.Join(texts, ` `))
+		case `divider`:
+			if rule != `` {
+				out = append(out, rule)
+			}
+		case `image`:
+			alt, _ := block[`alt_text`].(string)
+			url, _ := block[`image_url`].(string)
+			out = append(out, fmt.Sprintf("<%s|%s>", url, alt))
+		case `actions`:
+			// buttons only work in slack, but links still do
+			for _, element := range blockList(block[`elements`]) {
+				if url, ok := element[`url`].(string); ok {
+					out = append(out, fmt.Sprintf("<%s|%s>", url, blockText(element[`text`])))
+				}
+			}
+		}
+	}
+	return strings.Join(out, "\n\n")
+}
+
+func (b *Broker) blocksMarkdown(blocks []Block) string {
+	return b.MrkdwnToMarkdown(blocksMrkdwn(blocks, `---`))
+}
+
+func (b *Broker) blocksText(blocks []Block) string {
+	return b.MrkdwnToText(blocksMrkdwn(blocks, ``))
+}
+
+func (b *Broker) attachmentText(a Attachment) string {
+	out := a.Title
+	if a.TitleLink != `` {
+		out = strings.TrimSpace(out + ` (` + a.TitleLink + `)`)
+	}
+	if a.Text != `` {
+		out = strings.TrimSpace(out + "\n" + b.MrkdwnToText(a.Text))
+	}
+	return out
+}
+
+// blockText is the text of a text object (or a plain string)
+func blockText(v interface{}) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case Block:
+		s, _ := t[`text`].(string)
+		return s
+	case map[string]interface{}:
+		s, _ := t[`text`].(string)
+		return s
+	}
+	return ``
+}
+
+// blockList is a list of blocks, however it was built
+func blockList(v interface{}) []Block {
+	switch l := v.(type) {
+	case []Block:
+		return l
+	case []interface{}:
+		var blocks []Block
+		for _, item := range l {
+			switch b := item.(type) {
+			case Block:
+				blocks = append(blocks, b)
+			case map[string]interface{}:
+				blocks = append(blocks, Block(b))
+			}
+		}
+		return blocks
+	}
+	return nil
+}
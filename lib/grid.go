@@ -0,0 +1,166 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// On an Enterprise Grid, lazlo can be installed across the whole org, and
+// channels can be shared between workspaces (or with other companies). That
+// means users turn up who weren't in the user list rtm.start gave us, two
+// users can have the same name in different workspaces, and some settings
+// (who the admins are, say) differ from workspace to workspace.
+//
+// Set LAZLO_TEAM_ID to the workspace an org-wide token should act in. Users
+// we don't know are looked up with users.info the first time they say
+// something. Per-workspace settings come from the registry: a "workspace"
+// entity named for the team ID, whose attrs are config keys:
+//
+//	{"workspace": {"T0SALES": {"LAZLO_ADMINS": "dinah,U0BOSS"}}}
+
+// EnterpriseUser is the org-wide side of a grid user
+type EnterpriseUser struct {
+	ID             string   `json:"id,omitempty"`
+	EnterpriseID   string   `json:"enterprise_id,omitempty"`
+	EnterpriseName string   `json:"enterprise_name,omitempty"`
+	IsAdmin        bool     `json:"is_admin,omitempty"`
+	IsOwner        bool     `json:"is_owner,omitempty"`
+	Teams          []string `json:"teams,omitempty"`
+}
+
+// userLock guards additions to SlackMeta.Users
+var userLock sync.Mutex
+
+// LookupUser returns the user with the given ID, asking slack about them if
+// they're not someone we know (like a user in a shared channel from another
+// workspace). It returns nil if slack doesn't know them either.
+func (b *Broker) LookupUser(ID string) *User {
+	userLock.Lock()
+	defer userLock.Unlock()
+	if u := b.SlackMeta.GetUser(ID); u != nil {
+		return u
+	}
+	if b.fake != nil {
+		return nil
+	}
+	req := ApiRequest{
+		URL:    `https://slack.com/api/users.info`,
+		Values: make(url.Values),
+		Broker: b,
+	}
+	req.Values.Set(`user`, ID)
+	reply, err := MakeAPIReq(req)
+	if err != nil || !reply.Ok {
+		Logger.Debug(`Grid:: couldn't look up user `, ID, `: `, err, reply.Error)
+		return nil
+	}
+	b.SlackMeta.Users = append(b.SlackMeta.Users, reply.User)
+	Logger.Debug(`Grid:: learned about `, reply.User.Name, ` (`, ID, `) from team `, reply.User.TeamID)
+	return &reply.User
+}
+
+// IsExternal returns true if the user is from outside our org: someone from
+// another company in a shared channel
+func (b *Broker) IsExternal(ID string) bool {
+	u := b.SlackMeta.GetUser(ID)
+	if u == nil {
+		return false
+	}
+	if u.IsStranger {
+		return true
+	}
+	team := b.SlackMeta.Team
+	switch {
+	case u.TeamID == `` || u.TeamID == team.ID:
+		return false
+	case team.EnterpriseID != `` && u.Enterprise != nil:
+		return u.Enterprise.EnterpriseID != team.EnterpriseID
+	}
+	return true
+}
+
+// GetUserInTeam returns the user with the given name in the given workspace
+func (meta *ApiResponse) GetUserInTeam(name string, team string) *User {
+	for _, user := range meta.Users {
+		if user.Name == name && user.TeamID == team {
+			return &user
+		}
+	}
+	return nil
+}
+
+// UserTeam returns the workspace the event's user belongs to
+func (event *Event) UserTeam() string {
+	if event.UserTeamID != `` {
+		return event.UserTeamID
+	}
+	return event.Team
+}
+
+// ConfigFor returns lazlo's config with the team's workspace overrides
+// from the registry applied
+func (b *Broker) ConfigFor(team string) *Config {
+	if team == `` || b.Registry == nil {
+		return b.Config
+	}
+	e := b.Registry.Get(`workspace`, team)
+	if e == nil {
+		return b.Config
+	}
+	config := *b.Config
+	if err := overrideConfig(&config, e.Attrs); err != nil {
+		Logger.Error(`Grid:: workspace `, team, `: `, err)
+	}
+	return &config
+}
+
+// Config returns lazlo's config for the workspace of the event's user
+func (event *Event) Config() *Config {
+	return event.Broker.ConfigFor(event.UserTeam())
+}
+
+// overrideConfig sets the config fields named in overrides (by their env
+// key, like LAZLO_ADMINS)
+func overrideConfig(config *Config, overrides map[string]string) error {
+	v := reflect.ValueOf(config).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		key := envKey(t.Field(i).Tag.Get(`env`))
+		value, ok := overrides[key]
+		if !ok || key == `` {
+			continue
+		}
+		field := v.Field(i)
+		switch field.Kind() {
+		case reflect.String:
+			field.SetString(value)
+		case reflect.Int:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("%s: %q isn't a number", key, value)
+			}
+			field.SetInt(int64(n))
+		case reflect.Bool:
+			bv, err := strconv.ParseBool(value)
+			if err != nil {
+				return fmt.Errorf("%s: %q isn't true or false", key, value)
+			}
+			field.SetBool(bv)
+		}
+	}
+	return nil
+}
+
+// envKey pulls the key out of a config field's env tag
+func envKey(tag string) string {
+	for _, part := range strings.Fields(tag) {
+		if strings.HasPrefix(part, `key=`) {
+			return strings.TrimPrefix(part, `key=`)
+		}
+	}
+	return ``
+}
@@ -0,0 +1,90 @@
+package lib
+
+import (
+	"fmt"
+	"sync"
+)
+
+// History remembers the last few messages said in each channel so modules
+// can look back at the conversation. Messages are redacted before they are
+// recorded, and nothing at all is kept for channels flagged as sensitive.
+type History struct {
+	sync.Mutex
+	size      int
+	channels  map[string][]Event
+	sensitive map[string]bool
+}
+
+func newHistory(size int) *History {
+	return &History{
+		size:      size,
+		channels:  make(map[string][]Event),
+		sensitive: make(map[string]bool),
+	}
+}
+
+// record adds a (redacted) message to its channel's history
+func (h *History) record(e Event) {
+	h.Lock()
+	defer h.Unlock()
+	if h.size <= 0 || h.sensitive[e.Channel] {
+		return
+	}
+	e.Broker = nil
+	msgs := append(h.channels[e.Channel], e)
+	if len(msgs) > h.size {
+		msgs = msgs[len(msgs)-h.size:]
+	}
+	h.channels[e.Channel] = msgs
+}
+
+// Get returns a copy of the remembered messages for the given channel ID,
+// oldest first
+func (h *History) Get(channel string) []Event {
+	h.Lock()
+	defer h.Unlock()
+	return append([]Event{}, h.channels[channel]...)
+}
+
+// SetSensitive flags (or un-flags) a channel as sensitive. Flagging a channel
+// also forgets everything we remembered about it.
+func (h *History) SetSensitive(channel string, sensitive bool) {
+	h.Lock()
+	defer h.Unlock()
+	if sensitive {
+		h.sensitive[channel] = true
+		delete(h.channels, channel)
+	} else {
+		delete(h.sensitive, channel)
+	}
+}
+
+// IsSensitive returns true if the given channel ID is flagged as sensitive
+func (h *History) IsSensitive(channel string) bool {
+	h.Lock()
+	defer h.Unlock()
+	return h.sensitive[channel]
+}
+
+// forget removes every remembered message said by the given user
+func (h *History) forget(userID string) ([]string, error) {
+	h.Lock()
+	defer h.Unlock()
+	var removed []string
+	for channel, msgs := range h.channels {
+		kept := msgs[:0]
+		count := 0
+		for _, msg := range msgs {
+			if msg.User == userID {
+				count++
+				continue
+			}
+			kept = append(kept, msg)
+		}
+		h.channels[channel] = kept
+		if count > 0 {
+			removed = append(removed, fmt.Sprintf("%d history messages in %s", count, channel))
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,123 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// Lazlo can fill in each user's App Home tab in slack. Modules contribute
+// sections to it with HomeSection (a module that knows about rotations shows
+// you your rotations, and so on), and the Home module publishes the tab
+// whenever someone opens it.
+
+// A Block is a slack block kit block, like the ones TextBlock returns
+type Block map[string]interface{}
+
+// slack won't take a view with more blocks than this
+const maxHomeBlocks = 100
+
+type homeSection struct {
+	name   string
+	order  int
+	render func(user string) []Block
+}
+
+type homeSections struct {
+	sync.Mutex
+	list []*homeSection
+}
+
+type byHomeOrder []*homeSection
+
+func (s byHomeOrder) Len() int      { return len(s) }
+func (s byHomeOrder) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byHomeOrder) Less(i, j int) bool {
+	if s[i].order != s[j].order {
+		return s[i].order < s[j].order
+	}
+	return s[i].name < s[j].name
+}
+
+// HomeSection adds a section to everyone's App Home tab. render is called
+// each time a user's tab is published, and returns the blocks to show them
+// under the section's name (or nothing, to leave the section out). Sections
+// are shown in order, lowest first.
+func (b *Broker) HomeSection(name string, order int, render func(user string) []Block) {
+	b.home.Lock()
+	defer b.home.Unlock()
+	b.home.list = append(b.home.list, &homeSection{name: name, order: order, render: render})
+	sort.Sort(byHomeOrder(b.home.list))
+}
+
+// HomeView returns the blocks of the user's App Home tab
+func (b *Broker) HomeView(user string) []Block {
+	b.home.Lock()
+	sections := append([]*homeSection{}, b.home.list...)
+	b.home.Unlock()
+	var blocks []Block
+	for _, section := range sections {
+		rendered := section.render(user)
+		if len(rendered) == 0 {
+			continue
+		}
+		if blocks != nil {
+			blocks = append(blocks, DividerBlock())
+		}
+		blocks = append(blocks, HeaderBlock(section.name))
+		blocks = append(blocks, rendered...)
+	}
+	if blocks == nil {
+		blocks = append(blocks, TextBlock(fmt.Sprintf("Nothing to show you yet. Say `%s help` to see what I can do.", b.Config.Name)))
+	}
+	if len(blocks) > maxHomeBlocks {
+		blocks = append(blocks[:maxHomeBlocks-1], TextBlock(`_(there's more, but that's all that fits)_`))
+	}
+	return blocks
+}
+
+// PublishHome renders the user's App Home tab and sends it to slack
+func (b *Broker) PublishHome(user string) error {
+	view, _ := json.Marshal(map[string]interface{}{
+		`type`:   `home`,
+		`blocks`: b.HomeView(user),
+	})
+	req := ApiRequest{
+		URL:    `https://slack.com/api/views.publish`,
+		Values: make(url.Values),
+		Broker: b,
+	}
+	req.Values.Set(`user_id`, user)
+	req.Values.Set(`view`, string(view))
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return err
+	}
+	if !reply.Ok {
+		return fmt.Errorf("views.publish failed: %s", reply.Error)
+	}
+	return nil
+}
+
+// TextBlock is a block of markdown text
+func TextBlock(markdown string) Block {
+	return Block{
+		`type`: `section`,
+		`text`: Block{`type`: `mrkdwn`, `text`: markdown},
+	}
+}
+
+// HeaderBlock is a heading
+func HeaderBlock(text string) Block {
+	return Block{
+		`type`: `header`,
+		`text`: Block{`type`: `plain_text`, `text`: text},
+	}
+}
+
+// DividerBlock is a horizontal line
+func DividerBlock() Block {
+	return Block{`type`: `divider`}
+}
@@ -0,0 +1,189 @@
+package lib
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io/ioutil"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An HTTPAuth verifies an inbound request to a LinkCallback. It's handed the
+// request and its (already read) body, and returns an error if the request
+// should be rejected. Hand them to Broker.LinkCallback() with the route, so
+// it's never published without them.
+type HTTPAuth func(req *http.Request, body []byte) error
+
+// authenticate runs the callback's authenticators against the request. It
+// buffers the body so the module can still read it afterward.
+func (l *LinkCallback) authenticate(req *http.Request) error {
+	if len(l.Auth) == 0 {
+		return nil
+	}
+	body, err := readBody(req)
+	if err != nil {
+		return err
+	}
+	for _, auth := range l.Auth {
+		if err := auth(req, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBody reads the request's body, leaving it there to be read again
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// SlackSignature verifies Slack's X-Slack-Signature request signing. If
+// secret is empty, LAZLO_SLACK_SIGNING_SECRET is used.
+func (b *Broker) SlackSignature(secret ...string) HTTPAuth {
+	key := b.Config.SlackSigningSecret
+	if secret != nil {
+		key = secret[0]
+	}
+	return func(req *http.Request, body []byte) error {
+		if key == `` {
+			return fmt.Errorf("no slack signing secret configured")
+		}
+		ts := req.Header.Get(`X-Slack-Request-Timestamp`)
+		sec, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			return fmt.Errorf("bad slack request timestamp")
+		}
+		if math.Abs(time.Since(time.Unix(sec, 0)).Minutes()) > 5 {
+			return fmt.Errorf("stale slack request timestamp")
+		}
+		mac := hmac.New(sha256.New, []byte(key))
+		fmt.Fprintf(mac, "v0:%s:%s", ts, body)
+		expected := `v0=` + hex.EncodeToString(mac.Sum(nil))
+		if !hmac.Equal([]byte(expected), []byte(req.Header.Get(`X-Slack-Signature`))) {
+			return fmt.Errorf("bad slack signature")
+		}
+		return nil
+	}
+}
+
+// BearerToken accepts requests with an "Authorization: Bearer <token>"
+// header matching one of the given tokens
+func BearerToken(tokens ...string) HTTPAuth {
+	return func(req *http.Request, body []byte) error {
+		auth := req.Header.Get(`Authorization`)
+		if !strings.HasPrefix(auth, `Bearer `) {
+			return fmt.Errorf("missing bearer token")
+		}
+		given := []byte(strings.TrimPrefix(auth, `Bearer `))
+		for _, token := range tokens {
+			if subtle.ConstantTimeCompare(given, []byte(token)) == 1 {
+				return nil
+			}
+		}
+		return fmt.Errorf("bad bearer token")
+	}
+}
+
+// HMACSignature verifies a hex encoded HMAC of the request body in the given
+// header, like github's X-Hub-Signature ("sha1=<hex>"). algo is sha1 or
+// sha256, and a leading "<algo>=" in the header is ignored.
+func HMACSignature(header string, algo string, secret string) HTTPAuth {
+	var h func() hash.Hash
+	switch algo {
+	case `sha1`:
+		h = sha1.New
+	default:
+		algo = `sha256`
+		h = sha256.New
+	}
+	return func(req *http.Request, body []byte) error {
+		given := strings.TrimPrefix(req.Header.Get(header), algo+`=`)
+		mac := hmac.New(h, []byte(secret))
+		mac.Write(body)
+		if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(strings.ToLower(given))) {
+			return fmt.Errorf("bad %s signature", header)
+		}
+		return nil
+	}
+}
+
+// ClientCert accepts requests that presented a verified TLS client
+// certificate. If any names are given, the certificate's CommonName or one of
+// its DNS names must match one of them. This only works when the http server
+// is running TLS and asks for client certs.
+func ClientCert(names ...string) HTTPAuth {
+	return func(req *http.Request, body []byte) error {
+		if req.TLS == nil || len(req.TLS.VerifiedChains) == 0 {
+			return fmt.Errorf("no verified client certificate")
+		}
+		if len(names) == 0 {
+			return nil
+		}
+		cert := req.TLS.VerifiedChains[0][0]
+		for _, name := range names {
+			if cert.Subject.CommonName == name {
+				return nil
+			}
+			for _, dnsName := range cert.DNSNames {
+				if dnsName == name {
+					return nil
+				}
+			}
+		}
+		return fmt.Errorf("client certificate %s not allowed", cert.Subject.CommonName)
+	}
+}
+
+// IPAllowlist accepts requests from the given IP addresses or CIDR ranges.
+// It looks at the connection's remote address, so if you run lazlo behind a
+// proxy, allow the proxy.
+func IPAllowlist(allowed ...string) HTTPAuth {
+	var nets []*net.IPNet
+	for _, a := range allowed {
+		if !strings.Contains(a, `/`) {
+			if strings.Contains(a, `:`) {
+				a += `/128`
+			} else {
+				a += `/32`
+			}
+		}
+		_, n, err := net.ParseCIDR(a)
+		if err != nil {
+			Logger.Error(`IPAllowlist:: ignoring bad address `, a, `: `, err)
+			continue
+		}
+		nets = append(nets, n)
+	}
+	return func(req *http.Request, body []byte) error {
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			host = req.RemoteAddr
+		}
+		ip := net.ParseIP(host)
+		for _, n := range nets {
+			if ip != nil && n.Contains(ip) {
+				return nil
+			}
+		}
+		return fmt.Errorf("address %s not allowed", host)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/bmizerany/pat"
 	"net/http"
+	"strings"
 )
 
 //This map is redundant with the brokers cbIndex but there's
@@ -17,19 +18,74 @@ type LinkCallback struct {
 	URL     string
 	Handler func(res http.ResponseWriter, req *http.Request)
 	Chan    chan *http.Request
+	Auth    []HTTPAuth // every one of these must pass before we handle a request
 }
 
 func (b *Broker) StartHttp() {
 	m := pat.New()
 	m.Get("/", http.HandlerFunc(metaHandler))
-	m.Get("/linkcb/:name", http.HandlerFunc(metaHandler))
-	http.Handle("/", m)
-	err := http.ListenAndServe(":"+b.Config.Port, nil)
-	if err != nil {
+	m.Get("/linkcb/:name", b.traced(metaHandler))
+	m.Post("/linkcb/:name", b.traced(metaHandler))
+	m.Get("/health", http.HandlerFunc(b.healthHandler))
+	if b.Config.DebugToken != `` {
+		m.Get("/debug/webhooks/:id", b.debugOnly(b.traceHandler))
+		m.Get("/debug/pprof/:name", b.debugOnly(b.pprofHandler))
+		m.Get("/debug/runtime", b.debugOnly(b.runtimeHandler))
+		m.Get("/debug/snapshot", b.debugOnly(b.snapshotHandler))
+		m.Get("/metrics", b.debugOnly(b.metricsHandler))
+	}
+	http.Handle("/", b.counted(m))
+	if b.Config.SlackSigningSecret != `` {
+		b.LinkCallback(`events`, b.eventsHandler, b.SlackSignature())
+		b.LinkCallback(`interactive`, b.interactiveHandler, b.SlackSignature())
+	}
+
+	if b.tlsEnabled() {
+		var err error
+		if b.certs, err = newCertStore(splitList(b.Config.TLSCerts)); err != nil {
+			Logger.Error(err)
+			return
+		}
+		if hosts := splitList(b.Config.TLSAutocert); hosts != nil {
+			Logger.Info(`getting tls certificates from Let's Encrypt for `, strings.Join(hosts, `, `))
+			b.certs.autocert = newAutocert(hosts, b.Config.TLSAutocertCache, b.Config.TLSAutocertEmail)
+		}
+		go func() {
+			if err := b.startHttps(nil); err != nil && !b.handingOver() {
+				Logger.Error(err)
+			}
+		}()
+		var handler http.Handler = http.DefaultServeMux
+		if b.Config.TLSRedirect {
+			handler = b.counted(http.HandlerFunc(b.httpsRedirect))
+		}
+		if b.certs.autocert != nil {
+			// answers Let's Encrypt's http-01 challenges, and passes the rest on
+			handler = b.certs.autocert.HTTPHandler(handler)
+		}
+		if err := b.serve(`http`, handler); err != nil {
+			Logger.Error(err)
+		}
+		return
+	}
+	if err := b.serve(`http`, nil); err != nil {
 		Logger.Error(err)
 	}
 }
 
+// serve serves plain http on PORT (on the listener the old lazlo handed us,
+// if we're an upgrade)
+func (b *Broker) serve(name string, handler http.Handler) error {
+	ln, err := b.listen(name, ":"+b.Config.Port)
+	if err != nil {
+		return err
+	}
+	if err := http.Serve(ln, handler); err != nil && !b.handingOver() {
+		return err
+	}
+	return nil
+}
+
 func metaHandler(res http.ResponseWriter, req *http.Request) {
 	Logger.Debug("entered metaHandler")
 	path := req.URL.Query().Get(":name")
@@ -38,11 +94,21 @@ func metaHandler(res http.ResponseWriter, req *http.Request) {
 		fmt.Fprintln(res, "Hi. I am a Lazlo bot")
 	} else if cb, ok := httpRoutes[path]; ok {
 		Logger.Debug("path is known")
+		if err := cb.authenticate(req); err != nil {
+			Logger.Info("rejected request for ", path, " from ", req.RemoteAddr, ": ", err)
+			http.Error(res, "unauthorized", http.StatusUnauthorized)
+			return
+		}
 		if cb.Handler == nil {
+			// the body's gone once we've answered, so keep it for the module
+			if _, err := readBody(req); err != nil {
+				http.Error(res, "couldn't read the request", http.StatusBadRequest)
+				return
+			}
 			go func(cb *LinkCallback) {
 				cb.Chan <- req
-				fmt.Fprintln(res, "Path: %s handled. Thanks!", path)
 			}(cb)
+			fmt.Fprintf(res, "Path: %s handled. Thanks!\n", path)
 		} else {
 			cb.Handler(res, req)
 		}
@@ -52,31 +118,40 @@ func metaHandler(res http.ResponseWriter, req *http.Request) {
 	}
 }
 
-func (b *Broker) LinkCallback(p string, f ...func(http.ResponseWriter, *http.Request)) *LinkCallback {
+// LinkCallback publishes a route at /linkcb/p. Requests go to f if it isn't
+// nil, and down the callback's Chan if it is, once every one of the
+// authenticators has passed them (see httpauth.go).
+func (b *Broker) LinkCallback(p string, f func(http.ResponseWriter, *http.Request), auth ...HTTPAuth) *LinkCallback {
 	path := fmt.Sprintf("linkcb/%s", p)
 	callback := &LinkCallback{
-		p:    p,
-		ID:   fmt.Sprintf("link:%d", len(b.cbIndex[L])),
-		Path: path,
-		URL:  fmt.Sprintf("%s:%s/%s", b.Config.URL, b.Config.Port, path),
-		Chan: make(chan *http.Request),
+		p:       p,
+		ID:      fmt.Sprintf("link:%d", len(b.cbIndex[L])),
+		Path:    path,
+		URL:     fmt.Sprintf("%s:%s/%s", b.Config.URL, b.linkPort(), path),
+		Handler: f,
+		Chan:    make(chan *http.Request),
+		Auth:    auth,
 	}
 
-	//user-provided http handler function
-	if f != nil {
-		callback.Handler = f[0]
-	}
-
-	//append the path to the list of routes used by metaHandler()
-	httpRoutes[p] = callback
-
 	if err := b.RegisterCallback(callback); err != nil {
 		Logger.Error("error registering callback ", callback.ID, ":: ", err)
 		return nil
 	}
+
+	//append the path to the list of routes used by metaHandler(), now it's
+	//ready to take requests
+	httpRoutes[p] = callback
 	return callback
 }
 
+// linkPort returns the port our links should point at
+func (b *Broker) linkPort() string {
+	if b.tlsEnabled() {
+		return b.Config.TLSPort
+	}
+	return b.Config.Port
+}
+
 func (l *LinkCallback) Delete() {
 	delete(httpRoutes, l.p)
 }
@@ -0,0 +1,246 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lazlo can be told to ignore messages from some users (usually other bots),
+// apps, or channels, and messages that match some patterns. Ignored messages
+// are dropped before anything else sees them: they don't make it into the
+// history, no callback hears them, and they can't delegate commands. Events
+// from ignored users and channels are dropped too.
+//
+// The list starts out with LAZLO_IGNORE_USERS, LAZLO_IGNORE_APPS,
+// LAZLO_IGNORE_CHANNELS and LAZLO_IGNORE_PATTERNS, and admins can add to it
+// and take things off it with the ignore command; what they change is kept
+// in the brain under ignore:list.
+//
+// Separately, to keep two bots from talking to each other forever, a bot
+// (lazlo included) that says more than LAZLO_BOT_LOOP_LIMIT things a minute
+// in a channel is ignored there until the minute's up.
+
+// The kinds of things that can be ignored
+const (
+	IgnoreUser    = `user`
+	IgnoreApp     = `app`
+	IgnoreChannel = `channel`
+	IgnorePattern = `pattern`
+)
+
+// IgnoreKinds are the kinds of things that can be ignored, in the order
+// they're listed
+var IgnoreKinds = []string{IgnoreUser, IgnoreApp, IgnoreChannel, IgnorePattern}
+
+const ignoreKey = `ignore:list`
+
+// ignoreState is what admins have changed, on top of the config
+type ignoreState struct {
+	Added   map[string][]string `json:"added"`
+	Removed map[string][]string `json:"removed"`
+}
+
+// IgnoreList decides which inbound messages and events lazlo ignores
+type IgnoreList struct {
+	lock     sync.Mutex
+	broker   *Broker
+	config   map[string][]string
+	state    *ignoreState
+	patterns []*regexp.Regexp
+	loops    map[string]*botLoop // by bot and channel
+}
+
+// a botLoop counts what a bot said in a channel this minute
+type botLoop struct {
+	start  time.Time
+	count  int
+	warned bool
+}
+
+func newIgnoreList(b *Broker) *IgnoreList {
+	il := &IgnoreList{
+		broker: b,
+		config: map[string][]string{
+			IgnoreUser:    splitList(b.Config.IgnoreUsers),
+			IgnoreApp:     splitList(b.Config.IgnoreApps),
+			IgnoreChannel: splitList(b.Config.IgnoreChannels),
+		},
+		loops: make(map[string]*botLoop),
+	}
+	if p := b.Config.IgnorePatterns; p != `` {
+		il.config[IgnorePattern] = []string{p}
+	}
+	il.state = &ignoreState{Added: make(map[string][]string), Removed: make(map[string][]string)}
+	if data, err := b.Brain.Get(ignoreKey); err == nil && data != nil {
+		if err := json.Unmarshal(data, il.state); err != nil {
+			Logger.Error(`Ignore:: `, err)
+		}
+	}
+	il.compile()
+	return il
+}
+
+// ignoreValue tidies up a user, app or channel for comparing
+func ignoreValue(kind string, value string) string {
+	value = strings.TrimSpace(value)
+	if kind != IgnorePattern {
+		value = strings.ToLower(strings.TrimLeft(value, `@#`))
+	}
+	return value
+}
+
+// List returns everything of the kind that's being ignored
+func (il *IgnoreList) List(kind string) []string {
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	return il.list(kind)
+}
+
+func (il *IgnoreList) list(kind string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, value := range append(append([]string{}, il.config[kind]...), il.state.Added[kind]...) {
+		value = ignoreValue(kind, value)
+		if seen[value] || contains(il.state.Removed[kind], value) {
+			continue
+		}
+		seen[value] = true
+		out = append(out, value)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Add starts ignoring the user, app, channel or pattern
+func (il *IgnoreList) Add(kind string, value string) error {
+	value = ignoreValue(kind, value)
+	if kind == IgnorePattern {
+		if _, err := regexp.Compile(value); err != nil {
+			return err
+		}
+	}
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	il.state.Removed[kind] = without(il.state.Removed[kind], value)
+	if !contains(il.state.Added[kind], value) {
+		il.state.Added[kind] = append(il.state.Added[kind], value)
+	}
+	return il.save()
+}
+
+// Remove stops ignoring the user, app, channel or pattern
+func (il *IgnoreList) Remove(kind string, value string) error {
+	value = ignoreValue(kind, value)
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	if !contains(il.list(kind), value) {
+		return fmt.Errorf("I'm not ignoring the %s %s", kind, value)
+	}
+	il.state.Added[kind] = without(il.state.Added[kind], value)
+	il.state.Removed[kind] = append(il.state.Removed[kind], value)
+	return il.save()
+}
+
+func (il *IgnoreList) save() error {
+	il.compile()
+	data, err := json.Marshal(il.state)
+	if err != nil {
+		return err
+	}
+	return il.broker.Brain.Set(ignoreKey, data)
+}
+
+func (il *IgnoreList) compile() {
+	il.patterns = nil
+	for _, p := range il.list(IgnorePattern) {
+		r, err := regexp.Compile(p)
+		if err != nil {
+			Logger.Error(`Ignore:: bad pattern `, p, `: `, err)
+			continue
+		}
+		il.patterns = append(il.patterns, r)
+	}
+}
+
+// Ignored says why the message (or event) should be ignored, or returns ""
+// if it shouldn't be
+func (il *IgnoreList) Ignored(thingy map[string]interface{}) string {
+	b := il.broker
+	user, _ := thingy[`user`].(string)
+	bot, _ := thingy[`bot_id`].(string)
+	app, _ := thingy[`app_id`].(string)
+	channel, _ := thingy[`channel`].(string)
+	text, _ := thingy[`text`].(string)
+	il.lock.Lock()
+	defer il.lock.Unlock()
+	for _, u := range il.list(IgnoreUser) {
+		if (user != `` && (u == strings.ToLower(user) || u == b.SlackMeta.GetUserName(user))) || (bot != `` && u == strings.ToLower(bot)) {
+			return `the user ` + u
+		}
+	}
+	if app != `` && contains(il.list(IgnoreApp), strings.ToLower(app)) {
+		return `the app ` + app
+	}
+	if channel != `` {
+		for _, c := range il.list(IgnoreChannel) {
+			if c == strings.ToLower(channel) || `#`+c == b.channelName(channel) {
+				return `the channel ` + c
+			}
+		}
+	}
+	if thingy[`type`] != `message` {
+		return ``
+	}
+	for _, r := range il.patterns {
+		if r.MatchString(text) {
+			return `the pattern ` + r.String()
+		}
+	}
+	isBot := bot != `` || thingy[`subtype`] == `bot_message` || (user != `` && user == b.SlackMeta.Self.ID)
+	if isBot && il.looping(bot+user, channel) {
+		return `a bot loop`
+	}
+	return ``
+}
+
+// looping counts a bot's message, and returns true if it's said too much in
+// the channel this minute
+func (il *IgnoreList) looping(bot string, channel string) bool {
+	limit := il.broker.Config.BotLoopLimit
+	if limit <= 0 {
+		return false
+	}
+	key := bot + `:` + channel
+	loop := il.loops[key]
+	if loop == nil || time.Since(loop.start) > time.Minute {
+		if len(il.loops) > 1000 {
+			il.loops = make(map[string]*botLoop)
+		}
+		loop = &botLoop{start: time.Now()}
+		il.loops[key] = loop
+	}
+	loop.count++
+	if loop.count <= limit {
+		return false
+	}
+	if !loop.warned {
+		loop.warned = true
+		Logger.Error(`Ignore:: `, bot, ` said more than `, limit, ` things in `, channel, ` this minute; ignoring it there for now`)
+	}
+	return true
+}
+
+func without(list []string, value string) []string {
+	var out []string
+	for _, item := range list {
+		if item != value {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,363 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Modules hand long tasks (a deploy, a backfill) to the job manager instead
+// of running them on their own goroutine:
+//
+//	job := b.Jobs.Submit(`backfill`, pm.Event, func(job *lazlo.Job) error {
+//		for i, batch := range batches {
+//			select {
+//			case <-job.Cancelled():
+//				return nil
+//			default:
+//			}
+//			job.Logf("batch %d: %d rows", i, load(batch))
+//			job.Progress("%d of %d batches", i+1, len(batches))
+//		}
+//		return nil
+//	})
+//
+// The job gets an ID and a status message in the channel that asked for it,
+// which is edited as it makes progress and when it's done, and "!jobs" lists
+// jobs and shows their status and logs, and cancels them. Cancelling is
+// cooperative: the job's function has to notice Cancelled and return.
+//
+// Jobs are kept in the brain under jobs:<id>, so they're still listed after a
+// restart; jobs that were running when lazlo stopped are marked interrupted,
+// since their functions are gone.
+
+// Job statuses
+const (
+	JobRunning     = `running`
+	JobCancelling  = `cancelling`
+	JobDone        = `done`
+	JobFailed      = `failed`
+	JobCancelled   = `cancelled`
+	JobInterrupted = `interrupted`
+)
+
+const (
+	maxJobLog      = 200             // log lines kept per job
+	maxJobs        = 50              // finished jobs kept
+	jobEditEvery   = 2 * time.Second // how often the status message is edited
+	jobsNextKey    = `jobs:next`
+	jobStatusEmoji = `:hourglass_flowing_sand:`
+)
+
+// A Job is a long task a module handed the job manager
+type Job struct {
+	lock       sync.Mutex
+	ID         int       `json:"id"`
+	Name       string    `json:"name"`
+	Module     string    `json:"module"`
+	User       string    `json:"user"`
+	Channel    string    `json:"channel"`
+	Status     string    `json:"status"`
+	Progressed string    `json:"progress"`
+	Err        string    `json:"error,omitempty"`
+	Started    time.Time `json:"started"`
+	Finished   time.Time `json:"finished,omitempty"`
+	MessageTs  string    `json:"message_ts,omitempty"` // the status message
+	Log        []string  `json:"log"`
+	edited     time.Time
+	cancel     chan struct{}
+	jobs       *JobManager
+}
+
+// JobManager runs jobs and keeps track of them
+type JobManager struct {
+	lock   sync.Mutex
+	broker *Broker
+	jobs   map[int]*Job
+}
+
+func jobKey(id int) string {
+	return fmt.Sprintf("jobs:%d", id)
+}
+
+// newJobManager loads the jobs in the brain, marking the ones that were
+// running as interrupted
+func newJobManager(b *Broker) *JobManager {
+	jm := &JobManager{broker: b, jobs: make(map[int]*Job)}
+	keys, _ := b.Brain.Keys()
+	for _, key := range keys {
+		if !strings.HasPrefix(key, `jobs:`) || key == jobsNextKey {
+			continue
+		}
+		data, err := b.Brain.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		job := new(Job)
+		if err := json.Unmarshal(data, job); err != nil {
+			Logger.Error(`Jobs:: `, key, `: `, err)
+			continue
+		}
+		job.jobs = jm
+		jm.jobs[job.ID] = job
+		if job.Status == JobRunning || job.Status == JobCancelling {
+			job.Status, job.Finished = JobInterrupted, time.Now()
+			job.Logf("lazlo restarted while this was running")
+			job.save()
+			go job.updateMessage(true)
+		}
+	}
+	return jm
+}
+
+// Submit starts running fn as a job, on behalf of whoever said e (which can
+// be nil, for jobs nobody asked for; their status goes to the default
+// channel). It returns the job right away.
+func (jm *JobManager) Submit(name string, e *Event, fn func(*Job) error) *Job {
+	b := jm.broker
+	job := &Job{
+		Name:    name,
+		Module:  b.callerModule(),
+		Channel: b.DefaultChannel(),
+		Status:  JobRunning,
+		Started: time.Now(),
+		cancel:  make(chan struct{}),
+		jobs:    jm,
+	}
+	if e != nil {
+		job.User, job.Channel = e.User, e.Channel
+	}
+	jm.lock.Lock()
+	job.ID = jm.nextID()
+	jm.jobs[job.ID] = job
+	jm.prune()
+	jm.lock.Unlock()
+	job.save()
+	Logger.Info(`Jobs:: started job `, job.ID, ` (`, name, `)`)
+
+	reply := b.Send(&Event{Type: `message`, Channel: job.Channel, Text: job.Summary()})
+	go func() {
+		if r, ok := <-reply; ok {
+			if ts, _ := r[`ts`].(string); ts != `` {
+				job.lock.Lock()
+				job.MessageTs = ts
+				job.lock.Unlock()
+				job.save()
+			}
+		}
+		job.run(fn)
+	}()
+	return job
+}
+
+// nextID hands out job IDs, which keep counting up across restarts
+func (jm *JobManager) nextID() int {
+	b := jm.broker
+	next := 1
+	if data, err := b.Brain.Get(jobsNextKey); err == nil && data != nil {
+		if n, err := strconv.Atoi(string(data)); err == nil {
+			next = n
+		}
+	}
+	for jm.jobs[next] != nil {
+		next++
+	}
+	b.Brain.Set(jobsNextKey, []byte(strconv.Itoa(next+1)))
+	return next
+}
+
+// prune forgets the oldest finished jobs, past maxJobs
+func (jm *JobManager) prune() {
+	var finished []*Job
+	for _, job := range jm.jobs {
+		if job.finished() {
+			finished = append(finished, job)
+		}
+	}
+	if len(finished) <= maxJobs {
+		return
+	}
+	sort.Sort(jobsByID(finished))
+	for _, job := range finished[:len(finished)-maxJobs] {
+		delete(jm.jobs, job.ID)
+		jm.broker.Brain.Delete(jobKey(job.ID))
+	}
+}
+
+// Get returns the job with the ID, or nil
+func (jm *JobManager) Get(id int) *Job {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	return jm.jobs[id]
+}
+
+// List returns every job we know about, newest first
+func (jm *JobManager) List() []*Job {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	var jobs []*Job
+	for _, job := range jm.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Sort(sort.Reverse(jobsByID(jobs)))
+	return jobs
+}
+
+// Cancel asks the job to stop
+func (jm *JobManager) Cancel(id int) error {
+	job := jm.Get(id)
+	if job == nil {
+		return fmt.Errorf("there's no job %d", id)
+	}
+	job.lock.Lock()
+	if job.Status != JobRunning {
+		job.lock.Unlock()
+		return fmt.Errorf("job %d is %s", id, job.Status)
+	}
+	job.Status = JobCancelling
+	close(job.cancel)
+	job.lock.Unlock()
+	job.Logf("asked to cancel")
+	job.save()
+	job.updateMessage(true)
+	return nil
+}
+
+type jobsByID []*Job
+
+func (l jobsByID) Len() int           { return len(l) }
+func (l jobsByID) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l jobsByID) Less(i, j int) bool { return l[i].ID < l[j].ID }
+
+// run runs the job's function, recovering from panics
+func (job *Job) run(fn func(*Job) error) {
+	var err error
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		err = fn(job)
+	}()
+	job.lock.Lock()
+	switch {
+	case err != nil:
+		job.Status, job.Err = JobFailed, err.Error()
+	case job.Status == JobCancelling:
+		job.Status = JobCancelled
+	default:
+		job.Status = JobDone
+	}
+	job.Finished = time.Now()
+	status := job.Status
+	job.lock.Unlock()
+	if err != nil {
+		job.Logf("failed: %s", err)
+	}
+	Logger.Info(`Jobs:: job `, job.ID, ` (`, job.Name, `) `, status)
+	job.save()
+	job.updateMessage(true)
+}
+
+// Cancelled is closed when someone cancels the job
+func (job *Job) Cancelled() <-chan struct{} {
+	return job.cancel
+}
+
+// Progress says how the job's getting on (the status message shows it)
+func (job *Job) Progress(format string, args ...interface{}) {
+	job.lock.Lock()
+	job.Progressed = fmt.Sprintf(format, args...)
+	job.lock.Unlock()
+	job.save()
+	job.updateMessage(false)
+}
+
+// Logf adds a line to the job's log
+func (job *Job) Logf(format string, args ...interface{}) {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	line := time.Now().Format(`15:04:05 `) + fmt.Sprintf(format, args...)
+	job.Log = append(job.Log, line)
+	if len(job.Log) > maxJobLog {
+		job.Log = job.Log[len(job.Log)-maxJobLog:]
+	}
+}
+
+// Lines returns the job's log
+func (job *Job) Lines() []string {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return append([]string(nil), job.Log...)
+}
+
+func (job *Job) finished() bool {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	return job.Status != JobRunning && job.Status != JobCancelling
+}
+
+func (job *Job) save() {
+	job.lock.Lock()
+	data, err := json.Marshal(job)
+	job.lock.Unlock()
+	if err == nil {
+		err = job.jobs.broker.Brain.Set(jobKey(job.ID), data)
+	}
+	if err != nil {
+		Logger.Error(`Jobs:: couldn't save job `, job.ID, `: `, err)
+	}
+}
+
+// Summary is the job's status, in a line
+func (job *Job) Summary() string {
+	job.lock.Lock()
+	defer job.lock.Unlock()
+	emoji := map[string]string{
+		JobDone:        `:white_check_mark:`,
+		JobFailed:      `:x:`,
+		JobCancelled:   `:no_entry_sign:`,
+		JobInterrupted: `:warning:`,
+	}[job.Status]
+	if emoji == `` {
+		emoji = jobStatusEmoji
+	}
+	out := fmt.Sprintf("%s job %d (%s): %s", emoji, job.ID, job.Name, job.Status)
+	if job.Progressed != `` {
+		out += ` - ` + job.Progressed
+	}
+	if job.Err != `` {
+		out += `: ` + job.Err
+	}
+	return out
+}
+
+// updateMessage edits the job's status message, at most every jobEditEvery
+// unless it's forced to. If there's no status message to edit, the job's
+// status is only posted when it's finished.
+func (job *Job) updateMessage(force bool) {
+	b := job.jobs.broker
+	job.lock.Lock()
+	ts, channel := job.MessageTs, job.Channel
+	if !force && time.Since(job.edited) < jobEditEvery {
+		job.lock.Unlock()
+		return
+	}
+	job.edited = time.Now()
+	job.lock.Unlock()
+	text := job.Summary()
+	if ts == `` {
+		if job.finished() {
+			b.Send(&Event{Type: `message`, Channel: channel, Text: text})
+		}
+		return
+	}
+	if err := b.updateMessage(ts, &Event{Channel: channel, Text: text}); err != nil {
+		Logger.Error(`Jobs:: couldn't update job `, job.ID, `'s status: `, err)
+	}
+}
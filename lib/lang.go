@@ -0,0 +1,154 @@
+package lib
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Lazlo guesses what language each message it hears is in, and tags it as
+// Event.Lang (an ISO 639-1 code like "en" or "ja", or "" when it can't
+// tell), so modules that answer people in their own language don't each have
+// to work it out. The built-in detector goes by the script a message is
+// written in and, for languages written with latin letters, by how many of
+// each language's commonest words it has; it's cheap, and wrong about short
+// messages, so it doesn't guess at fewer than minLangWords words. Modules can
+// swap in a better one by setting Broker.LangDetector.
+//
+// Channels that only ever speak one language can say so with a lang attr on
+// the channel in the registry (kind "channel"), and their messages are tagged
+// with it without being looked at.
+
+// A LanguageDetector guesses what language the text is in ("" if it can't)
+type LanguageDetector interface {
+	Detect(text string) string
+}
+
+// fewer words than this are too few to guess from (for latin scripts)
+const minLangWords = 3
+
+// A StopwordDetector guesses the language of latin-script text by counting
+// the commonest words of each language in it. Other scripts are told apart
+// by the script itself.
+type StopwordDetector map[string][]string
+
+// the script each language (or family) is written in, checked in order, so
+// kana wins over the han it's mixed with
+var langScripts = []struct {
+	lang   string
+	script *unicode.RangeTable
+}{
+	{`ja`, unicode.Hiragana},
+	{`ja`, unicode.Katakana},
+	{`ko`, unicode.Hangul},
+	{`zh`, unicode.Han},
+	{`ru`, unicode.Cyrillic},
+	{`ar`, unicode.Arabic},
+	{`he`, unicode.Hebrew},
+	{`el`, unicode.Greek},
+	{`hi`, unicode.Devanagari},
+	{`th`, unicode.Thai},
+}
+
+// slack markup (mentions, links, emoji) and code say nothing about the
+// language
+var langNoisePat = regexp.MustCompile("(?s)<[^>]*>|```.*?```|`[^`]*`|:[a-z0-9_+-]+:")
+
+func (sd StopwordDetector) Detect(text string) string {
+	text = langNoisePat.ReplaceAllString(text, ` `)
+	if lang := scriptLang(text); lang != `` {
+		return lang
+	}
+	words := wordPat.FindAllString(strings.ToLower(text), -1)
+	if len(words) < minLangWords {
+		return ``
+	}
+	counts := make(map[string]int)
+	for lang, stopwords := range sd {
+		common := make(map[string]bool, len(stopwords))
+		for _, w := range stopwords {
+			common[w] = true
+		}
+		for _, word := range words {
+			if common[word] {
+				counts[lang]++
+			}
+		}
+	}
+	// the winner has to have a couple of hits, and beat everyone else
+	best, bestCount, tied := ``, 1, false
+	for lang, count := range counts {
+		if count > bestCount {
+			best, bestCount, tied = lang, count, false
+		} else if count == bestCount {
+			tied = true
+		}
+	}
+	if tied {
+		return ``
+	}
+	return best
+}
+
+// scriptLang is the language the text's letters are mostly written in, if
+// it isn't latin
+func scriptLang(text string) string {
+	letters := 0
+	counts := make(map[string]int)
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range langScripts {
+			if unicode.Is(s.script, r) {
+				counts[s.lang]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ``
+	}
+	if counts[`ja`] > 0 {
+		// japanese is kana and kanji
+		counts[`ja`] += counts[`zh`]
+	}
+	if counts[`ru`] > 0 && strings.ContainsAny(text, `іїєґІЇЄҐ`) {
+		counts[`uk`], counts[`ru`] = counts[`ru`], 0
+	}
+	for lang, count := range counts {
+		if count*2 > letters {
+			return lang
+		}
+	}
+	return ``
+}
+
+// the built-in detector's word lists
+var defaultStopwords = StopwordDetector{
+	`en`: {`the`, `and`, `is`, `are`, `to`, `of`, `it`, `that`, `this`, `you`, `with`, `for`, `was`, `have`, `what`, `not`, `can`, `be`, `on`, `my`},
+	`es`: {`el`, `la`, `los`, `las`, `de`, `que`, `y`, `es`, `en`, `un`, `una`, `por`, `para`, `con`, `no`, `lo`, `está`, `pero`, `como`, `muy`},
+	`fr`: {`le`, `la`, `les`, `de`, `des`, `et`, `est`, `un`, `une`, `que`, `pas`, `pour`, `dans`, `je`, `vous`, `nous`, `ce`, `il`, `avec`, `sur`},
+	`de`: {`der`, `die`, `das`, `und`, `ist`, `nicht`, `ich`, `du`, `ein`, `eine`, `mit`, `auf`, `zu`, `den`, `es`, `wir`, `sie`, `auch`, `noch`, `für`},
+	`pt`: {`o`, `a`, `os`, `as`, `de`, `que`, `e`, `é`, `não`, `um`, `uma`, `para`, `com`, `em`, `do`, `da`, `isso`, `você`, `mas`, `está`},
+	`it`: {`il`, `la`, `di`, `che`, `e`, `è`, `non`, `un`, `una`, `per`, `con`, `sono`, `gli`, `del`, `della`, `questo`, `ma`, `anche`, `io`, `ho`},
+	`nl`: {`de`, `het`, `een`, `en`, `is`, `van`, `niet`, `ik`, `je`, `dat`, `op`, `met`, `voor`, `zijn`, `maar`, `ook`, `wat`, `er`, `nog`, `wel`},
+}
+
+// messageLang is the language the message is in: its channel's lang attr,
+// if it has one, or the detector's guess
+func (b *Broker) messageLang(message *Event) string {
+	if b.Registry != nil && b.SlackMeta != nil {
+		if c := b.SlackMeta.GetChannel(message.Channel); c != nil {
+			if entity := b.Registry.Get(`channel`, c.Name); entity != nil && entity.Attrs[`lang`] != `` {
+				return entity.Attrs[`lang`]
+			}
+		}
+	}
+	detector := b.LangDetector
+	if detector == nil {
+		detector = defaultStopwords
+	}
+	return detector.Detect(message.Text)
+}
@@ -0,0 +1,292 @@
+package lib
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lazlo formats the dates, times and numbers it shows people the way they're
+// used to seeing them. Whose way is worked out like this:
+//
+//	the user's locale and tz prefs
+//	the locale and tz attrs of the channel in the registry (kind "channel")
+//	the user's timezone in slack
+//	LAZLO_LOCALE and LAZLO_TZ
+//
+// Messages can use {{date}}, {{time}}, {{datetime}}, {{ago}}, {{duration}}
+// and {{number}} in templates, and they're formatted for the channel
+// they're sent to (or the user, in a DM). Words (like "3 minutes ago") are
+// english whatever the locale.
+
+// A Locale knows how to format things for someone
+type Locale struct {
+	Name       string
+	Thousands  string // the thousands separator
+	Decimal    string // the decimal point
+	DateLayout string // a date, with the year
+	DayLayout  string // a date in the next or last few months
+	TimeLayout string
+	Location   *time.Location
+}
+
+// the locales lazlo knows how to format for
+var locales = map[string]Locale{
+	`en-US`: {Thousands: `,`, Decimal: `.`, DateLayout: `Jan 2, 2006`, DayLayout: `Mon Jan 2`, TimeLayout: `3:04 PM`},
+	`en-GB`: {Thousands: `,`, Decimal: `.`, DateLayout: `2 Jan 2006`, DayLayout: `Mon 2 Jan`, TimeLayout: `15:04`},
+	`en-AU`: {Thousands: `,`, Decimal: `.`, DateLayout: `2 Jan 2006`, DayLayout: `Mon 2 Jan`, TimeLayout: `3:04 pm`},
+	`de-DE`: {Thousands: `.`, Decimal: `,`, DateLayout: `2.1.2006`, DayLayout: `2.1.`, TimeLayout: `15:04`},
+	`fr-FR`: {Thousands: " ", Decimal: `,`, DateLayout: `02/01/2006`, DayLayout: `02/01`, TimeLayout: `15:04`},
+	`es-ES`: {Thousands: `.`, Decimal: `,`, DateLayout: `2/1/2006`, DayLayout: `2/1`, TimeLayout: `15:04`},
+	`pt-BR`: {Thousands: `.`, Decimal: `,`, DateLayout: `02/01/2006`, DayLayout: `02/01`, TimeLayout: `15:04`},
+	`nl-NL`: {Thousands: `.`, Decimal: `,`, DateLayout: `2-1-2006`, DayLayout: `2-1`, TimeLayout: `15:04`},
+	`ja-JP`: {Thousands: `,`, Decimal: `.`, DateLayout: `2006/01/02`, DayLayout: `1/2`, TimeLayout: `15:04`},
+	`iso`:   {Thousands: ``, Decimal: `.`, DateLayout: `2006-01-02`, DayLayout: `2006-01-02`, TimeLayout: `15:04`},
+}
+
+// findLocale looks up a locale by name (en_GB, en-gb and en-GB are all fine,
+// and a bare language picks the first locale for it)
+func findLocale(name string) (Locale, bool) {
+	name = strings.Replace(name, `_`, `-`, -1)
+	for n, l := range locales {
+		if strings.EqualFold(n, name) {
+			l.Name = n
+			return l, true
+		}
+	}
+	for _, n := range []string{`en-US`, `en-GB`, `de-DE`, `fr-FR`, `es-ES`, `pt-BR`, `nl-NL`, `ja-JP`} {
+		if strings.HasPrefix(strings.ToLower(n), strings.ToLower(name)+`-`) {
+			l := locales[n]
+			l.Name = n
+			return l, true
+		}
+	}
+	return Locale{}, false
+}
+
+// Locale returns the locale to format things in for the user, in the
+// channel. Either can be empty; a DM channel means its user.
+func (b *Broker) Locale(user string, channel string) *Locale {
+	if user == `` && channel != `` {
+		user = b.dmUser(channel)
+	}
+	var entity *Entity
+	if c := b.SlackMeta.GetChannel(channel); c != nil && b.Registry != nil {
+		entity = b.Registry.Get(`channel`, c.Name)
+	}
+
+	var names, zones []string
+	if user != `` {
+		names = append(names, b.GetPref(user, `locale`))
+		zones = append(zones, b.GetPref(user, `tz`))
+	}
+	if entity != nil {
+		names = append(names, entity.Attrs[`locale`])
+		zones = append(zones, entity.Attrs[`tz`])
+	}
+	if u := b.SlackMeta.GetUser(user); u != nil {
+		zones = append(zones, u.Tz)
+	}
+	names = append(names, b.Config.Locale)
+	zones = append(zones, b.Config.TZ)
+
+	l, _ := findLocale(`en-US`)
+	for _, name := range names {
+		if found, ok := findLocale(name); ok && name != `` {
+			l = found
+			break
+		}
+	}
+	l.Location = time.Local
+	for _, zone := range zones {
+		if zone == `` {
+			continue
+		}
+		if loc, err := time.LoadLocation(zone); err == nil {
+			l.Location = loc
+			break
+		}
+	}
+	return &l
+}
+
+// dmUser returns the user on the other end of a DM channel, or ""
+func (b *Broker) dmUser(channel string) string {
+	if b.fake != nil && strings.HasPrefix(channel, `D`) {
+		return strings.TrimPrefix(channel, `D`)
+	}
+	for _, im := range b.SlackMeta.IMs {
+		if im.ID == channel {
+			return im.User
+		}
+	}
+	return ``
+}
+
+// Date formats the date, with the year
+func (l *Locale) Date(t time.Time) string {
+	return t.In(l.Location).Format(l.DateLayout)
+}
+
+// Day formats the date, leaving the year out if it's this year's
+func (l *Locale) Day(t time.Time) string {
+	t = t.In(l.Location)
+	if t.Year() != time.Now().In(l.Location).Year() {
+		return t.Format(l.DateLayout)
+	}
+	return t.Format(l.DayLayout)
+}
+
+// Time formats the time of day
+func (l *Locale) Time(t time.Time) string {
+	return t.In(l.Location).Format(l.TimeLayout)
+}
+
+// DateTime formats the date and time
+func (l *Locale) DateTime(t time.Time) string {
+	return l.Day(t) + ` ` + l.Time(t)
+}
+
+// Number formats the number with the given number of decimal places
+func (l *Locale) Number(n float64, decimals int) string {
+	s := strconv.FormatFloat(math.Abs(n), 'f', decimals, 64)
+	whole, frac := s, ``
+	if i := strings.Index(s, `.`); i >= 0 {
+		whole, frac = s[:i], s[i+1:]
+	}
+	var groups []string
+	for len(whole) > 3 {
+		groups = append([]string{whole[len(whole)-3:]}, groups...)
+		whole = whole[:len(whole)-3]
+	}
+	out := strings.Join(append([]string{whole}, groups...), l.Thousands)
+	if frac != `` {
+		out += l.Decimal + frac
+	}
+	if n < 0 {
+		out = `-` + out
+	}
+	return out
+}
+
+// the units durations are described in, biggest first
+var durationUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{`year`, 365 * 24 * time.Hour},
+	{`month`, 30 * 24 * time.Hour},
+	{`week`, 7 * 24 * time.Hour},
+	{`day`, 24 * time.Hour},
+	{`hour`, time.Hour},
+	{`minute`, time.Minute},
+	{`second`, time.Second},
+}
+
+func plural(n int64, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// Duration describes the duration in the two biggest units it has, like "2
+// hours 5 minutes"
+func (l *Locale) Duration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	var parts []string
+	for _, unit := range durationUnits {
+		if n := int64(d / unit.size); n > 0 {
+			parts = append(parts, plural(n, unit.name))
+			d -= time.Duration(n) * unit.size
+		} else if parts != nil {
+			break
+		}
+		if len(parts) == 2 {
+			break
+		}
+	}
+	if parts == nil {
+		return `0 seconds`
+	}
+	return strings.Join(parts, ` `)
+}
+
+// Ago describes when t was relative to now, like "3 minutes ago" or "in 2
+// days"
+func (l *Locale) Ago(t time.Time) string {
+	d := time.Since(t)
+	if d < time.Minute && d > -time.Minute {
+		return `just now`
+	}
+	var when string
+	for _, unit := range durationUnits {
+		if n := int64(d / unit.size); n >= 1 || n <= -1 {
+			if n < 0 {
+				n = -n
+			}
+			when = plural(n, unit.name)
+			break
+		}
+	}
+	if d < 0 {
+		return `in ` + when
+	}
+	return when + ` ago`
+}
+
+// parseWhen reads a time in a template: RFC3339, a date (2006-01-02), or
+// unix seconds (slack timestamps too)
+func parseWhen(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(`2006-01-02`, s, time.Local); err == nil {
+		return t, nil
+	}
+	if t := tsTime(s); !t.IsZero() {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q isn't a time", s)
+}
+
+// localeFuncs are the template functions that format things for l
+func localeFuncs(l *Locale) map[string]interface{} {
+	when := func(format func(time.Time) string) func(string) (string, error) {
+		return func(s string) (string, error) {
+			t, err := parseWhen(s)
+			if err != nil {
+				return ``, err
+			}
+			return format(t), nil
+		}
+	}
+	return map[string]interface{}{
+		`date`:     when(l.Date),
+		`time`:     when(l.Time),
+		`datetime`: when(l.DateTime),
+		`ago`:      when(l.Ago),
+		`duration`: func(s string) (string, error) {
+			d, err := time.ParseDuration(s)
+			if err != nil {
+				return ``, err
+			}
+			return l.Duration(d), nil
+		},
+		`number`: func(s string) (string, error) {
+			n, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return ``, err
+			}
+			decimals := 0
+			if i := strings.Index(s, `.`); i >= 0 {
+				decimals = len(s) - i - 1
+			}
+			return l.Number(n, decimals), nil
+		},
+	}
+}
@@ -0,0 +1,166 @@
+package lib
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Modules shouldn't have to know how to mention someone in whatever lazlo's
+// talking to, or who to tell about a service. Mention and friends build
+// mentions the way the adapter wants them (slack's <@U123> markup, or
+// @names elsewhere), GetGroupDM opens one conversation with several people,
+// and NotifyOwners tells the owners of something in the registry.
+
+// the most people (besides lazlo) slack lets into a group DM
+const maxGroupDM = 8
+
+// slackMarkup says whether mentions should be slack's markup
+func (b *Broker) slackMarkup() bool {
+	return b.fake == nil && strings.ToLower(b.Config.Adapter) == `slack`
+}
+
+// Mention mentions the user (by ID)
+func (b *Broker) Mention(user string) string {
+	if b.slackMarkup() {
+		return `<@` + user + `>`
+	}
+	if name := b.SlackMeta.GetUserName(user); name != `` {
+		return `@` + name
+	}
+	return `@` + user
+}
+
+// Mentions mentions each of the users, separated by commas
+func (b *Broker) Mentions(users []string) string {
+	mentions := make([]string, len(users))
+	for i, user := range users {
+		mentions[i] = b.Mention(user)
+	}
+	return strings.Join(mentions, `, `)
+}
+
+// MentionChannel links to the channel (by ID)
+func (b *Broker) MentionChannel(channel string) string {
+	if b.slackMarkup() {
+		return `<#` + channel + `>`
+	}
+	return b.channelName(channel)
+}
+
+// MentionGroup mentions a usergroup (by ID), with its handle for adapters
+// that don't know slack's usergroups
+func (b *Broker) MentionGroup(group string, handle string) string {
+	if b.slackMarkup() {
+		return `<!subteam^` + group + `>`
+	}
+	return `@` + strings.TrimPrefix(handle, `@`)
+}
+
+// GetGroupDM opens a conversation with all of the users (by ID) and lazlo,
+// returning its ID. For one user, it's their DM.
+func (b *Broker) GetGroupDM(users []string) (string, error) {
+	switch {
+	case len(users) == 0:
+		return ``, fmt.Errorf("there's nobody to talk to")
+	case len(users) == 1:
+		if dm := b.GetDM(users[0]); dm != `` {
+			return dm, nil
+		}
+		return ``, fmt.Errorf("couldn't open a DM with %s", b.SlackMeta.GetUserName(users[0]))
+	case len(users) > maxGroupDM:
+		return ``, fmt.Errorf("a group DM can only have %d people in it, not %d", maxGroupDM, len(users))
+	}
+	if b.fake != nil {
+		return `G` + strings.Join(users, ``), nil
+	}
+	req := ApiRequest{
+		URL:    `https://slack.com/api/conversations.open`,
+		Values: make(url.Values),
+		Broker: b,
+	}
+	req.Values.Set(`users`, strings.Join(users, `,`))
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return ``, err
+	}
+	if !reply.Ok {
+		return ``, fmt.Errorf("couldn't open a group DM: %s", reply.Error)
+	}
+	return reply.Channel.ID, nil
+}
+
+// Owners returns the users (by ID) who own the entity in the registry: the
+// people in its owners attr, or else its members attr, or else the owners of
+// its team. Owners and members are lists of user IDs, names, mentions,
+// #channels and @usergroups, as for ResolveUsers.
+func (b *Broker) Owners(kind string, name string) ([]string, error) {
+	what := kind + ` ` + name
+	seen := make(map[string]bool)
+	for {
+		e := b.Registry.Get(kind, name)
+		if e == nil {
+			return nil, fmt.Errorf("I don't know the %s %s", kind, name)
+		}
+		for _, attr := range []string{`owners`, `members`} {
+			if refs := splitList(e.Attrs[attr]); refs != nil {
+				return b.resolveMembers(refs)
+			}
+		}
+		seen[registryKey(kind, name)] = true
+		kind, name = `team`, e.Attrs[`team`]
+		if name == `` || seen[registryKey(kind, name)] {
+			return nil, fmt.Errorf("nobody owns the %s", what)
+		}
+	}
+}
+
+// resolveMembers is ResolveUsers, taking plain user IDs too, and leaving
+// lazlo out
+func (b *Broker) resolveMembers(refs []string) ([]string, error) {
+	for i, ref := range refs {
+		if b.SlackMeta.GetUser(ref) != nil {
+			refs[i] = `<@` + ref + `>`
+		}
+	}
+	ids, err := b.ResolveUsers(refs)
+	if err != nil {
+		return nil, err
+	}
+	var users []string
+	for _, id := range ids {
+		if id != b.SlackMeta.Self.ID {
+			users = append(users, id)
+		}
+	}
+	return users, nil
+}
+
+// NotifyOwners tells the owners of the entity in the registry about
+// something: in a group DM if there are few enough of them, or else one by
+// one. It returns who it told.
+func (b *Broker) NotifyOwners(kind string, name string, text string) ([]string, error) {
+	owners, err := b.Owners(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(owners) <= maxGroupDM {
+		dm, err := b.GetGroupDM(owners)
+		if err != nil {
+			return nil, err
+		}
+		b.Say(text, dm)
+		return owners, nil
+	}
+	var told []string
+	for _, owner := range owners {
+		if dm := b.GetDM(owner); dm != `` {
+			b.Say(text, dm)
+			told = append(told, owner)
+		}
+	}
+	if told == nil {
+		return nil, fmt.Errorf("couldn't DM any of the owners of the %s %s", kind, name)
+	}
+	return told, nil
+}
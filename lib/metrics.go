@@ -0,0 +1,211 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lazlo counts the messages it handles, and how many times each module's
+// callbacks are called. The counts start from zero with every lazlo, unless
+// LAZLO_METRICS_INTERVAL is set: then they're added to lifetime totals in the
+// brain that often (and when lazlo stops), so they survive restarts. Counts
+// since the last save are lost if lazlo dies without stopping. Both are in
+// "debug stats", and at /metrics for Prometheus.
+
+const metricsKey = `metrics:lifetime`
+
+// the counter for messages handled
+const messagesCounter = `messages`
+
+// Metrics are lazlo's counters
+type Metrics struct {
+	lock   sync.Mutex
+	broker *Broker
+	since  map[string]int64 // counted by this lazlo
+	before map[string]int64 // counted by the lazlos before it
+}
+
+// A Counter is one of lazlo's counts: since this lazlo started, and over its
+// lifetime
+type Counter struct {
+	Name       string `json:"name"`
+	Module     string `json:"module,omitempty"`
+	SinceStart int64  `json:"since_start"`
+	Lifetime   int64  `json:"lifetime"`
+}
+
+type byCounter []Counter
+
+func (c byCounter) Len() int      { return len(c) }
+func (c byCounter) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c byCounter) Less(i, j int) bool {
+	if c[i].Name != c[j].Name {
+		return c[i].Name < c[j].Name
+	}
+	return c[i].Module < c[j].Module
+}
+
+func newMetrics(b *Broker) *Metrics {
+	return &Metrics{
+		broker: b,
+		since:  make(map[string]int64),
+		before: make(map[string]int64),
+	}
+}
+
+// counterKey is how a counter is kept: its name, or name:module for a
+// module's
+func counterKey(name string, module string) string {
+	if module == `` {
+		return name
+	}
+	return name + `:` + module
+}
+
+// Add adds n to the named counter (for module, if it isn't "")
+func (m *Metrics) Add(name string, module string, n int64) {
+	m.lock.Lock()
+	m.since[counterKey(name, module)] += n
+	m.lock.Unlock()
+}
+
+// Counters returns every counter, by name and module
+func (m *Metrics) Counters() []Counter {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	var counters []Counter
+	add := func(key string) {
+		c := Counter{Name: key, SinceStart: m.since[key], Lifetime: m.before[key] + m.since[key]}
+		if i := strings.Index(key, `:`); i >= 0 {
+			c.Name, c.Module = key[:i], key[i+1:]
+		}
+		counters = append(counters, c)
+	}
+	for key := range m.since {
+		add(key)
+	}
+	for key := range m.before {
+		if _, ok := m.since[key]; !ok {
+			add(key)
+		}
+	}
+	sort.Sort(byCounter(counters))
+	return counters
+}
+
+// load reads the lifetime totals from the brain
+func (m *Metrics) load() {
+	data, err := m.broker.Brain.Get(metricsKey)
+	if err != nil || len(data) == 0 {
+		return
+	}
+	before := make(map[string]int64)
+	if err := json.Unmarshal(data, &before); err != nil {
+		Logger.Error(`Metrics:: couldn't read the lifetime totals: `, err)
+		return
+	}
+	m.lock.Lock()
+	m.before = before
+	m.lock.Unlock()
+}
+
+// save writes the lifetime totals to the brain
+func (m *Metrics) save() error {
+	m.lock.Lock()
+	lifetime := make(map[string]int64)
+	for key, n := range m.before {
+		lifetime[key] = n
+	}
+	for key, n := range m.since {
+		lifetime[key] += n
+	}
+	m.lock.Unlock()
+	data, err := json.Marshal(lifetime)
+	if err != nil {
+		return err
+	}
+	return m.broker.Brain.Set(metricsKey, data)
+}
+
+// persisting is true if the counters are kept in the brain
+func (m *Metrics) persisting() bool {
+	return m.broker.Config.MetricsInterval > 0
+}
+
+// run loads the lifetime totals, and saves them every interval, until lazlo
+// hands over to another one
+func (m *Metrics) run() {
+	if !m.persisting() {
+		return
+	}
+	m.load()
+	for range time.Tick(time.Duration(m.broker.Config.MetricsInterval) * time.Minute) {
+		if m.broker.handingOver() {
+			return
+		}
+		if err := m.save(); err != nil {
+			Logger.Error(`Metrics:: couldn't save the lifetime totals: `, err)
+		}
+	}
+}
+
+// stop saves the lifetime totals one last time
+func (m *Metrics) stop() {
+	if !m.persisting() {
+		return
+	}
+	if err := m.save(); err != nil {
+		Logger.Error(`Metrics:: couldn't save the lifetime totals: `, err)
+	}
+}
+
+// String is a line for each counter
+func (m *Metrics) String() string {
+	var lines []string
+	for _, c := range m.Counters() {
+		name := c.Name
+		if c.Module != `` {
+			name += ` (` + c.Module + `)`
+		}
+		if m.persisting() {
+			lines = append(lines, fmt.Sprintf("%s: %d since I started, %d all told", name, c.SinceStart, c.Lifetime))
+		} else {
+			lines = append(lines, fmt.Sprintf("%s: %d since I started", name, c.SinceStart))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// metricsHandler serves the counters in Prometheus' text format, as
+// lazlo_<name>_total (since this lazlo started, so Prometheus sees the
+// restarts) and lazlo_<name>_lifetime_total, with a module label for a
+// module's counters
+func (b *Broker) metricsHandler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set(`Content-Type`, `text/plain; version=0.0.4`)
+	counters := b.Metrics.Counters()
+	for _, suffix := range []string{`_total`, `_lifetime_total`} {
+		last := ``
+		for _, c := range counters {
+			name := `lazlo_` + c.Name + suffix
+			if name != last {
+				fmt.Fprintf(res, "# TYPE %s counter\n", name)
+				last = name
+			}
+			n := c.SinceStart
+			if suffix == `_lifetime_total` {
+				n = c.Lifetime
+			}
+			if c.Module != `` {
+				fmt.Fprintf(res, "%s{module=%q} %d\n", name, c.Module, n)
+			} else {
+				fmt.Fprintf(res, "%s %d\n", name, n)
+			}
+		}
+	}
+	fmt.Fprintf(res, "# TYPE lazlo_uptime_seconds gauge\nlazlo_uptime_seconds %d\n", int64(time.Since(b.started)/time.Second))
+}
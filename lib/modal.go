@@ -0,0 +1,337 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Modals are forms slack pops up over the chat. Slack only lets an app open
+// one in response to something the user clicked (it needs the click's
+// trigger ID), so the usual way in is a button: post ModalCallback.Button in
+// a message, and lazlo opens the modal when it's clicked. Clicks and
+// submissions come in through slack's interactivity requests, which lazlo
+// takes at linkcb/interactive if LAZLO_SLACK_SIGNING_SECRET is set.
+
+// A Modal is a form with a few inputs
+type Modal struct {
+	Title  string // at most 24 characters
+	Submit string // the submit button's label (Submit if empty)
+	Inputs []ModalInput
+}
+
+// A ModalInput is one field in a modal. It's a select if it has Options, a
+// text box otherwise. Submissions are checked against Optional and Pattern
+// before the module sees them.
+type ModalInput struct {
+	Name        string // what the value's called in the submission
+	Label       string
+	Placeholder string
+	Initial     string
+	Multiline   bool
+	Optional    bool
+	Options     []string
+	Pattern     string // a regular expression the value has to match
+	Hint        string // shown under the input, and when the value doesn't match Pattern
+}
+
+// A ModalSubmission is what someone filled in
+type ModalSubmission struct {
+	Name   string
+	User   string
+	Values map[string]string
+	Meta   string // whatever the modal was opened with
+}
+
+// An Action is someone clicking a button
+type Action struct {
+	ID        string // the button's action ID
+	Value     string
+	User      string
+	Channel   string
+	MessageTs string
+	TriggerID string // opens a modal in response, for a few seconds
+}
+
+// A Button is a button in a message (see ButtonsBlock)
+type Button struct {
+	Text   string
+	Action string
+	Value  string
+	Style  string // primary, danger, or empty
+}
+
+// modal buttons have action IDs like modal:<name>
+const modalActionPrefix = `modal:`
+
+// ButtonsBlock is a row of buttons
+func ButtonsBlock(buttons ...Button) Block {
+	var elements []Block
+	for _, button := range buttons {
+		element := Block{
+			`type`:      `button`,
+			`text`:      Block{`type`: `plain_text`, `text`: button.Text},
+			`action_id`: button.Action,
+		}
+		if button.Value != `` {
+			element[`value`] = button.Value
+		}
+		if button.Style != `` {
+			element[`style`] = button.Style
+		}
+		elements = append(elements, element)
+	}
+	return Block{`type`: `actions`, `elements`: elements}
+}
+
+// Button is a button that opens the modal. meta is handed back with the
+// submission.
+func (v *ModalCallback) Button(text string, meta string) Button {
+	return Button{Text: text, Action: modalActionPrefix + v.Name, Value: meta}
+}
+
+// Open opens the modal for whoever's click trigger came from. initial
+// overrides the inputs' initial values.
+func (v *ModalCallback) Open(trigger string, meta string, initial map[string]string) error {
+	view, _ := json.Marshal(v.view(meta, initial))
+	req := ApiRequest{
+		URL:    `https://slack.com/api/views.open`,
+		Values: make(url.Values),
+		Broker: v.broker,
+	}
+	req.Values.Set(`trigger_id`, trigger)
+	req.Values.Set(`view`, string(view))
+	reply, err := MakeAPIReq(req)
+	if err != nil {
+		return err
+	}
+	if !reply.Ok {
+		return fmt.Errorf("views.open failed: %s", reply.Error)
+	}
+	return nil
+}
+
+// view renders the modal as a slack view
+func (v *ModalCallback) view(meta string, initial map[string]string) Block {
+	submit := v.Modal.Submit
+	if submit == `` {
+		submit = `Submit`
+	}
+	var blocks []Block
+	for _, input := range v.Modal.Inputs {
+		value := input.Initial
+		if i, ok := initial[input.Name]; ok {
+			value = i
+		}
+		element := Block{`action_id`: input.Name}
+		if input.Options != nil {
+			element[`type`] = `static_select`
+			var options []Block
+			for _, option := range input.Options {
+				o := Block{`text`: Block{`type`: `plain_text`, `text`: option}, `value`: option}
+				options = append(options, o)
+				if option == value {
+					element[`initial_option`] = o
+				}
+			}
+			element[`options`] = options
+		} else {
+			element[`type`] = `plain_text_input`
+			element[`multiline`] = input.Multiline
+			if value != `` {
+				element[`initial_value`] = value
+			}
+		}
+		if input.Placeholder != `` {
+			element[`placeholder`] = Block{`type`: `plain_text`, `text`: input.Placeholder}
+		}
+		label := input.Label
+		if label == `` {
+			label = input.Name
+		}
+		block := Block{
+			`type`:     `input`,
+			`block_id`: input.Name,
+			`label`:    Block{`type`: `plain_text`, `text`: label},
+			`element`:  element,
+			`optional`: input.Optional,
+		}
+		if input.Hint != `` {
+			block[`hint`] = Block{`type`: `plain_text`, `text`: input.Hint}
+		}
+		blocks = append(blocks, block)
+	}
+	return Block{
+		`type`:             `modal`,
+		`callback_id`:      v.Name,
+		`private_metadata`: meta,
+		`title`:            Block{`type`: `plain_text`, `text`: v.Modal.Title},
+		`submit`:           Block{`type`: `plain_text`, `text`: submit},
+		`close`:            Block{`type`: `plain_text`, `text`: `Cancel`},
+		`blocks`:           blocks,
+	}
+}
+
+// Validate checks the values against the modal's inputs, and returns what's
+// wrong with each bad one (by input name)
+func (m *Modal) Validate(values map[string]string) map[string]string {
+	problems := make(map[string]string)
+	for _, input := range m.Inputs {
+		value := strings.TrimSpace(values[input.Name])
+		switch {
+		case value == ``:
+			if !input.Optional {
+				problems[input.Name] = `this can't be empty`
+			}
+		case input.Options != nil && !contains(input.Options, value):
+			problems[input.Name] = `pick one of the options`
+		case input.Pattern != ``:
+			if ok, err := regexp.MatchString(input.Pattern, value); err != nil || !ok {
+				problems[input.Name] = input.Hint
+				if problems[input.Name] == `` {
+					problems[input.Name] = `that doesn't look right`
+				}
+			}
+		}
+	}
+	return problems
+}
+
+// interactivePayload is the bits of slack's interactivity requests we use
+type interactivePayload struct {
+	Type      string `json:"type"`
+	TriggerID string `json:"trigger_id"`
+	User      struct {
+		ID string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		ID string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		Ts string `json:"ts"`
+	} `json:"message"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+	View struct {
+		CallbackID      string `json:"callback_id"`
+		PrivateMetadata string `json:"private_metadata"`
+		State           struct {
+			Values map[string]map[string]struct {
+				Value          string `json:"value"`
+				SelectedOption struct {
+					Value string `json:"value"`
+				} `json:"selected_option"`
+			} `json:"values"`
+		} `json:"state"`
+	} `json:"view"`
+}
+
+// interactiveHandler takes button clicks and modal submissions from slack
+func (b *Broker) interactiveHandler(res http.ResponseWriter, req *http.Request) {
+	payload := new(interactivePayload)
+	if err := json.Unmarshal([]byte(req.FormValue(`payload`)), payload); err != nil {
+		http.Error(res, "bad payload", http.StatusBadRequest)
+		return
+	}
+	switch payload.Type {
+	case `block_actions`:
+		for _, a := range payload.Actions {
+			action := Action{
+				ID:        a.ActionID,
+				Value:     a.Value,
+				User:      payload.User.ID,
+				Channel:   payload.Channel.ID,
+				MessageTs: payload.Message.Ts,
+				TriggerID: payload.TriggerID,
+			}
+			b.handleAction(action)
+		}
+	case `view_submission`:
+		b.handleSubmission(res, payload)
+	}
+}
+
+// handleAction opens the modal a modal button is for, turns the page for
+// page buttons, and hands any other click to its action callbacks
+func (b *Broker) handleAction(action Action) {
+	if strings.HasPrefix(action.ID, modalActionPrefix) {
+		name := strings.TrimPrefix(action.ID, modalActionPrefix)
+		for _, cbInterface := range b.cbIndex[V] {
+			if v := cbInterface.(*ModalCallback); v.Name == name {
+				if err := v.Open(action.TriggerID, action.Value, nil); err != nil {
+					Logger.Error(`Modals:: couldn't open `, name, `: `, err)
+				}
+				return
+			}
+		}
+		Logger.Debug(`Modals:: nobody's handling modal `, name)
+		return
+	}
+	if strings.HasPrefix(action.ID, pagerActionPrefix) {
+		b.turnPage(action)
+		return
+	}
+	for _, cbInterface := range b.cbIndex[A] {
+		callback := cbInterface.(*ActionCallback)
+		if callback.Action == action.ID {
+			Logger.Debug(`Broker:: firing callback: `, callback.ID)
+			go func(c chan Action) { c <- action }(callback.Chan)
+		}
+	}
+}
+
+// handleSubmission validates a modal submission. If something's wrong, slack
+// shows the user what; otherwise the submission goes to the module and the
+// modal closes.
+func (b *Broker) handleSubmission(res http.ResponseWriter, payload *interactivePayload) {
+	var callback *ModalCallback
+	for _, cbInterface := range b.cbIndex[V] {
+		if v := cbInterface.(*ModalCallback); v.Name == payload.View.CallbackID {
+			callback = v
+		}
+	}
+	if callback == nil {
+		Logger.Debug(`Modals:: nobody's handling modal `, payload.View.CallbackID)
+		return
+	}
+	submission := ModalSubmission{
+		Name:   callback.Name,
+		User:   payload.User.ID,
+		Values: make(map[string]string),
+		Meta:   payload.View.PrivateMetadata,
+	}
+	for block, actions := range payload.View.State.Values {
+		for _, value := range actions {
+			submission.Values[block] = value.Value
+			if value.SelectedOption.Value != `` {
+				submission.Values[block] = value.SelectedOption.Value
+			}
+		}
+	}
+	if problems := callback.Modal.Validate(submission.Values); len(problems) > 0 {
+		res.Header().Set(`Content-Type`, `application/json`)
+		json.NewEncoder(res).Encode(map[string]interface{}{
+			`response_action`: `errors`,
+			`errors`:          problems,
+		})
+		return
+	}
+	Logger.Debug(`Broker:: firing callback: `, callback.ID)
+	go func() { callback.Chan <- submission }()
+}
+
+// RespondBlocks responds to the event with block kit blocks. text is what
+// notifications (and clients that can't show blocks) get.
+func (event *Event) RespondBlocks(text string, blocks ...Block) chan map[string]interface{} {
+	return event.send(&Event{
+		Type:    `message`,
+		Channel: event.Channel,
+		Text:    text,
+		Blocks:  blocks,
+	})
+}
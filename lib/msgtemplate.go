@@ -0,0 +1,149 @@
+package lib
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Outbound messages can have placeholders in them that are filled in when
+// the message is sent, so announcements that are set up once (scheduled
+// reminders, lua scripts, canned answers) stay current:
+//
+//	{{user "dinah"}}                   a mention of the user named dinah
+//	{{entity "service.api.owner"}}     the owner attribute of the api service in the registry
+//	{{owners "service.api"}}           mentions of the api service's owners (see Owners)
+//	{{oncall "payments"}}              whoever's up in the payments rotation
+//	{{ago "2026-10-15T09:00:00Z"}}     how long ago that was (see Locale for the rest)
+//
+// Modules add their own with TemplateFunc. A message is only treated as a
+// template if every {{ }} in it calls one of these functions, so messages
+// that just happen to have braces in them are sent as they are. Templates
+// expanded with a Context can use its fields, and text/template's if, range
+// and friends, too.
+
+// templateFuncs are the functions message templates can call
+type templateFuncs struct {
+	sync.RWMutex
+	funcs template.FuncMap
+}
+
+func newTemplateFuncs(b *Broker) *templateFuncs {
+	tf := &templateFuncs{funcs: template.FuncMap{
+		`user`:   b.templateUser,
+		`entity`: b.templateEntity,
+		`owners`: b.templateOwners,
+	}}
+	// these are swapped for the recipient's locale when a message is expanded
+	l, _ := findLocale(`en-US`)
+	l.Location = time.Local
+	for name, fn := range localeFuncs(&l) {
+		tf.funcs[name] = fn
+	}
+	return tf
+}
+
+// TemplateFunc makes a function available to message templates. It takes
+// string arguments and returns a string (and optionally an error), like
+// func(rotation string) string.
+func (b *Broker) TemplateFunc(name string, fn interface{}) {
+	b.templates.Lock()
+	defer b.templates.Unlock()
+	b.templates.funcs[name] = fn
+}
+
+// the name of the function each {{ }} calls
+var templateActionPat = regexp.MustCompile(`{{-?\s*(\w*)`)
+
+// ExpandTemplate fills in the placeholders in a message, formatting dates
+// and numbers for the channel it's going to, if there is one. If the message
+// isn't a template, or it doesn't render, it's returned unchanged.
+func (b *Broker) ExpandTemplate(text string, channel ...string) string {
+	return b.expandTemplate(text, nil, channel...)
+}
+
+// templateBuiltins are what text/template has built in, which templates can
+// use when they have data to use them on (see Context.Expand)
+var templateBuiltins = map[string]bool{
+	``: true, `if`: true, `else`: true, `end`: true, `range`: true, `with`: true,
+	`and`: true, `or`: true, `not`: true, `eq`: true, `ne`: true, `lt`: true,
+	`le`: true, `gt`: true, `ge`: true, `len`: true, `index`: true,
+	`print`: true, `printf`: true,
+}
+
+// expandTemplate is ExpandTemplate, executing the template with data
+func (b *Broker) expandTemplate(text string, data interface{}, channel ...string) string {
+	if !strings.Contains(text, `{{`) {
+		return text
+	}
+	b.templates.RLock()
+	defer b.templates.RUnlock()
+	for _, action := range templateActionPat.FindAllStringSubmatch(text, -1) {
+		if _, ok := b.templates.funcs[action[1]]; !ok && (data == nil || !templateBuiltins[action[1]]) {
+			return text
+		}
+	}
+	funcs := b.templates.funcs
+	if channel != nil && channel[0] != `` {
+		funcs = make(template.FuncMap)
+		for name, fn := range b.templates.funcs {
+			funcs[name] = fn
+		}
+		for name, fn := range localeFuncs(b.Locale(``, channel[0])) {
+			funcs[name] = fn
+		}
+	}
+	tmpl, err := template.New(`message`).Funcs(funcs).Parse(text)
+	if err != nil {
+		Logger.Debug(`Templates:: not expanding a message: `, err)
+		return text
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&out, data); err != nil {
+		Logger.Error(`Templates:: couldn't expand a message: `, err)
+		return text
+	}
+	return out.String()
+}
+
+func (b *Broker) templateUser(name string) string {
+	if u := b.SlackMeta.GetUserByName(strings.TrimPrefix(name, `@`)); u != nil {
+		return b.Mention(u.ID)
+	}
+	return `@` + name
+}
+
+// templateEntity looks up kind.name.attr in the registry
+func (b *Broker) templateEntity(path string) string {
+	parts := strings.SplitN(path, `.`, 3)
+	if len(parts) < 2 {
+		return `(` + path + `?)`
+	}
+	e := b.Registry.Get(parts[0], parts[1])
+	if e == nil {
+		return `(` + path + `?)`
+	}
+	if len(parts) == 2 {
+		return e.Name
+	}
+	if value, ok := e.Attrs[parts[2]]; ok {
+		return value
+	}
+	return `(` + path + `?)`
+}
+
+// templateOwners mentions the owners of kind.name in the registry
+func (b *Broker) templateOwners(path string) string {
+	parts := strings.SplitN(path, `.`, 2)
+	if len(parts) < 2 {
+		return `(` + path + `?)`
+	}
+	owners, err := b.Owners(parts[0], parts[1])
+	if err != nil {
+		return `(` + path + `?)`
+	}
+	return b.Mentions(owners)
+}
@@ -0,0 +1,236 @@
+package lib
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Some commands mustn't run twice: a deploy, a failover, paging someone.
+// But slack delivers the same message again after a reconnect, and people
+// hit enter twice. Set Once on a command's MessageCallback:
+//
+//	cb := b.MessageCallback(`(?i)deploy (\S+)`, true)
+//	cb.Once = true
+//
+// and each time it runs, lazlo records the command (with an idempotency key)
+// in the brain before handing it to the module, and marks it complete when
+// the module calls pm.Done(), along with what the module said. The same
+// message delivered again, or the same person asking for the same command
+// in the same channel within LAZLO_ONCE_WINDOW minutes, isn't run again:
+// lazlo answers with what the module said the first time, or says it's
+// still running. If lazlo restarts while a command runs, it's marked
+// interrupted, and redeliveries of that message aren't run either.
+
+const (
+	onceKeep    = 24 * time.Hour   // how long a command is remembered
+	onceTimeout = 30 * time.Minute // after which a command that never called Done counts as done
+)
+
+// the states of a command
+const (
+	OnceRunning     = `running`
+	OnceDone        = `done`
+	OnceInterrupted = `interrupted`
+)
+
+// A OnceRecord is a run of an exactly-once command
+type OnceRecord struct {
+	Key      string    `json:"key"`
+	Module   string    `json:"module"`
+	Command  string    `json:"command"` // the normalized command
+	User     string    `json:"user"`
+	Channel  string    `json:"channel"`
+	Ts       string    `json:"ts"` // of the message that ran it
+	Status   string    `json:"status"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitempty"`
+	Said     []Event   `json:"said,omitempty"` // the module's answer
+}
+
+// onceLog keeps track of exactly-once commands
+type onceLog struct {
+	lock      sync.Mutex
+	broker    *Broker
+	records   map[string]*OnceRecord // by key
+	byMessage map[string]string      // channel+ts -> key
+}
+
+// a onceRun is a command running, recording what the module says
+type onceRun struct {
+	log    *onceLog
+	entry  *OnceRecord
+	finish sync.Once
+}
+
+func onceBrainKey(key string) string {
+	return `once:` + key
+}
+
+func onceMessage(channel string, ts string) string {
+	return channel + "\x00" + ts
+}
+
+// newOnceLog loads the commands in the brain, marking the ones that were
+// running as interrupted
+func newOnceLog(b *Broker) *onceLog {
+	ol := &onceLog{broker: b, records: make(map[string]*OnceRecord), byMessage: make(map[string]string)}
+	keys, _ := b.Brain.Keys()
+	for _, key := range keys {
+		if !strings.HasPrefix(key, `once:`) {
+			continue
+		}
+		data, err := b.Brain.Get(key)
+		if err != nil || len(data) == 0 {
+			continue
+		}
+		record := new(OnceRecord)
+		if err := json.Unmarshal(data, record); err != nil {
+			Logger.Error(`Once:: `, key, `: `, err)
+			continue
+		}
+		if record.Status == OnceRunning {
+			record.Status, record.Finished = OnceInterrupted, time.Now()
+			ol.save(record)
+		}
+		ol.records[record.Key] = record
+		ol.byMessage[onceMessage(record.Channel, record.Ts)] = record.Key
+	}
+	ol.prune()
+	return ol
+}
+
+// onceKey is the idempotency key for the callback's command from the user in
+// the channel
+func onceKey(callback *MessageCallback, command string, message *Event) string {
+	sum := sha1.Sum([]byte(strings.Join([]string{callback.module, callback.Pattern, message.User, message.Channel, command}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// claim checks the message isn't a duplicate (a delivery of a message
+// that's been run, or the same command from the same person within the
+// window) and records that its command is running, all under one hold of the
+// lock, so two quick deliveries can't both run. It returns the run the
+// message duplicates, or, if it isn't one, the run it's starting.
+func (ol *onceLog) claim(callback *MessageCallback, match []string, message *Event) (*OnceRecord, *onceRun) {
+	window := time.Duration(ol.broker.Config.OnceWindow) * time.Minute
+	command := cacheCommand(match)
+	key := onceKey(callback, command, message)
+	ol.lock.Lock()
+	if seen, ok := ol.byMessage[onceMessage(message.Channel, message.Ts)]; ok {
+		if record := ol.records[seen]; record != nil {
+			copied := *record
+			ol.lock.Unlock()
+			return &copied, nil
+		}
+	}
+	if record := ol.records[key]; record != nil && record.Status != OnceInterrupted && time.Since(record.Started) <= window {
+		copied := *record
+		ol.lock.Unlock()
+		return &copied, nil
+	}
+	record := &OnceRecord{
+		Key:     key,
+		Module:  callback.module,
+		Command: command,
+		User:    message.User,
+		Channel: message.Channel,
+		Ts:      message.Ts,
+		Status:  OnceRunning,
+		Started: time.Now(),
+	}
+	ol.prune()
+	ol.records[record.Key] = record
+	ol.byMessage[onceMessage(record.Channel, record.Ts)] = record.Key
+	ol.lock.Unlock()
+	ol.save(record)
+	run := &onceRun{log: ol, entry: record}
+	time.AfterFunc(onceTimeout, run.done)
+	return nil, run
+}
+
+// save writes the record to the brain, with the command and what the module
+// said redacted (so a duplicate after a restart gets the redacted answer)
+func (ol *onceLog) save(record *OnceRecord) {
+	ol.lock.Lock()
+	stored := *record
+	stored.Command = ol.broker.Redact(record.Command)
+	stored.Said = make([]Event, len(record.Said))
+	for i, e := range record.Said {
+		e.Text = ol.broker.Redact(e.Text)
+		stored.Said[i] = e
+	}
+	ol.lock.Unlock()
+	data, err := json.Marshal(stored)
+	if err == nil {
+		err = ol.broker.Brain.Set(onceBrainKey(record.Key), data)
+	}
+	if err != nil {
+		Logger.Error(`Once:: couldn't save `, record.Module, ` `, record.Command, `: `, err)
+	}
+}
+
+// prune forgets commands older than onceKeep. Call it holding the lock.
+func (ol *onceLog) prune() {
+	for key, record := range ol.records {
+		if time.Since(record.Started) > onceKeep {
+			delete(ol.records, key)
+			delete(ol.byMessage, onceMessage(record.Channel, record.Ts))
+			ol.broker.Brain.Delete(onceBrainKey(key))
+		}
+	}
+}
+
+// record adds something the module said to the command's answer
+func (run *onceRun) record(e Event) {
+	e.Broker = nil
+	run.log.lock.Lock()
+	if run.entry.Status == OnceRunning {
+		run.entry.Said = append(run.entry.Said, e)
+	}
+	run.log.lock.Unlock()
+}
+
+// done marks the command complete
+func (run *onceRun) done() {
+	run.finish.Do(func() {
+		run.log.lock.Lock()
+		run.entry.Status, run.entry.Finished = OnceDone, time.Now()
+		run.log.lock.Unlock()
+		run.log.save(run.entry)
+	})
+}
+
+// answer answers a duplicate of the command with what the module said the
+// first time, or what's become of it
+func (ol *onceLog) answer(record *OnceRecord, message *Event) {
+	b := ol.broker
+	when := record.Started.Format(time.Kitchen)
+	switch record.Status {
+	case OnceRunning:
+		message.Reply(fmt.Sprintf("that's already running (since %s), so I'm not running it again", when))
+		return
+	case OnceInterrupted:
+		message.Reply(fmt.Sprintf("that was interrupted when I restarted (it started at %s), so I'm not running it again; check on it before you ask again", when))
+		return
+	}
+	message.Reply(fmt.Sprintf("that already ran (at %s), so I'm not running it again. Here's what I said then:", when))
+	was, now := b.SlackMeta.GetUserName(record.User)+`: `, b.SlackMeta.GetUserName(message.User)+`: `
+	for _, e := range record.Said {
+		e.Channel = message.Channel
+		if e.ThreadTs != `` {
+			e.ThreadTs = message.ThreadTs
+			if e.ThreadTs == `` {
+				e.ThreadTs = message.Ts
+			}
+		}
+		if strings.HasPrefix(e.Text, was) {
+			e.Text = now + strings.TrimPrefix(e.Text, was)
+		}
+		b.Send(&e)
+	}
+}
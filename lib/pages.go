@@ -0,0 +1,219 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// When a message has to be split up (see Format), LAZLO_LONG_MESSAGES says
+// what to do with the rest of it:
+//
+//	thread  the first part is posted, and the rest follow in its thread
+//	pages   one message, with buttons to page through it (slack only)
+//	split   the parts are posted one after another
+//
+// Pages are kept in the brain under pages:<id>, so old messages can still be
+// paged after a restart (give them a TTL with LAZLO_BRAIN_TTLS).
+
+// Long message modes
+const (
+	LongThread = `thread`
+	LongPages  = `pages`
+	LongSplit  = `split`
+)
+
+// page buttons have action IDs like page:next
+const pagerActionPrefix = `page:`
+
+// slack won't take more than this in a section's text
+const maxSectionText = 3000
+
+// a pagerPage is one page of a paged message
+type pagerPage struct {
+	Text   string  `json:"text,omitempty"`
+	Blocks []Block `json:"blocks,omitempty"`
+}
+
+// sendLong sends the parts of a message that had to be split up
+func (b *Broker) sendLong(parts []*Event) chan map[string]interface{} {
+	mode := strings.ToLower(b.Config.LongMessages)
+	if mode == LongPages && b.AdapterFormat().Render != RenderBlocks {
+		mode = LongThread
+	}
+	switch {
+	case b.fake != nil || mode == LongSplit:
+	case mode == LongPages:
+		return b.sendPages(parts)
+	default:
+		return b.sendThreaded(parts)
+	}
+	var reply chan map[string]interface{}
+	for _, part := range parts {
+		if r := b.send(part); reply == nil {
+			reply = r
+		}
+	}
+	return reply
+}
+
+// sendThreaded posts the first part, and the rest in its thread (or the
+// thread it's in). The reply is the first part's.
+func (b *Broker) sendThreaded(parts []*Event) chan map[string]interface{} {
+	first := b.send(parts[0])
+	reply := make(chan map[string]interface{}, 1)
+	go func() {
+		r := <-first
+		reply <- r
+		close(reply)
+		thread := parts[0].ThreadTs
+		if thread == `` {
+			thread, _ = r[`ts`].(string)
+		}
+		if thread == `` {
+			Logger.Error(`Broker:: couldn't post the start of a long message, so I'm not posting the rest`)
+			return
+		}
+		for _, part := range parts[1:] {
+			part.ThreadTs = thread
+			// wait for each, so they stay in order
+			<-b.send(part)
+		}
+	}()
+	return reply
+}
+
+// sendPages posts the first page, with buttons for the rest
+func (b *Broker) sendPages(parts []*Event) chan map[string]interface{} {
+	var pages []pagerPage
+	var blocks []Block
+	for _, part := range parts {
+		blocks = append(blocks, part.Blocks...)
+	}
+	if blocks != nil {
+		// leave room for the page number and the buttons
+		size := b.AdapterFormat().MaxBlocks - 2
+		for i := 0; i < len(blocks); i += size {
+			pages = append(pages, pagerPage{Blocks: blocks[i:min(i+size, len(blocks))]})
+		}
+	} else {
+		for _, part := range parts {
+			pages = append(pages, pagerPage{Text: part.Text})
+		}
+	}
+	id := strconv.FormatInt(time.Now().UnixNano(), 36)
+	data, err := json.Marshal(pages)
+	if err == nil {
+		err = b.Brain.Set(`pages:`+id, data)
+	}
+	if err != nil {
+		Logger.Error(`Broker:: couldn't keep the pages of a long message, so it's going in a thread instead: `, err)
+		return b.sendThreaded(parts)
+	}
+	e := parts[0]
+	e.Text, e.Blocks = pagerText(pages, 0, parts[0].Text), pagerBlocks(id, pages, 0)
+	return b.send(e)
+}
+
+// pagerText is the notification text for a page
+func pagerText(pages []pagerPage, n int, fallback string) string {
+	if pages[n].Text != `` {
+		return pages[n].Text
+	}
+	return TruncateBytes(fallback, maxSectionText)
+}
+
+// pagerBlocks renders a page, with its number and the buttons
+func pagerBlocks(id string, pages []pagerPage, n int) []Block {
+	page := pages[n]
+	blocks := page.Blocks
+	if blocks == nil {
+		blocks = TextBlocks(page.Text)
+	}
+	blocks = append(blocks, Block{
+		`type`:     `context`,
+		`elements`: []Block{{`type`: `mrkdwn`, `text`: fmt.Sprintf("page %d of %d", n+1, len(pages))}},
+	})
+	var buttons []Button
+	if n > 0 {
+		buttons = append(buttons, Button{Text: `previous`, Action: pagerActionPrefix + `prev`, Value: fmt.Sprintf("%s %d", id, n-1)})
+	}
+	if n < len(pages)-1 {
+		buttons = append(buttons, Button{Text: `next`, Action: pagerActionPrefix + `next`, Value: fmt.Sprintf("%s %d", id, n+1)})
+	}
+	return append(blocks, ButtonsBlock(buttons...))
+}
+
+// TextBlocks is as many text blocks as it takes to hold the markdown
+func TextBlocks(markdown string) []Block {
+	var blocks []Block
+	for _, text := range splitText(markdown, maxSectionText) {
+		blocks = append(blocks, TextBlock(text))
+	}
+	return blocks
+}
+
+// splitSections splits sections with more text than slack takes into
+// several
+func splitSections(blocks []Block) []Block {
+	var out []Block
+	for _, block := range blocks {
+		text := blockText(block[`text`])
+		if block[`type`] != `section` || len(text) <= maxSectionText || block[`fields`] != nil || block[`accessory`] != nil {
+			out = append(out, block)
+			continue
+		}
+		out = append(out, TextBlocks(text)...)
+	}
+	return out
+}
+
+// turnPage shows the page a page button asked for
+func (b *Broker) turnPage(action Action) {
+	parts := strings.Fields(action.Value)
+	if len(parts) != 2 {
+		return
+	}
+	n, err := strconv.Atoi(parts[1])
+	data, gerr := b.Brain.Get(`pages:` + parts[0])
+	var pages []pagerPage
+	if err != nil || gerr != nil || json.Unmarshal(data, &pages) != nil || n < 0 || n >= len(pages) {
+		Logger.Debug(`Broker:: those pages are gone: `, action.Value)
+		return
+	}
+	e := &Event{Channel: action.Channel, Text: pagerText(pages, n, ``), Blocks: pagerBlocks(parts[0], pages, n)}
+	if err := b.updateMessage(action.MessageTs, e); err != nil {
+		Logger.Error(`Broker:: couldn't turn the page: `, err)
+	}
+}
+
+// updateMessage replaces the message at ts with the event's text and blocks
+func (b *Broker) updateMessage(ts string, e *Event) error {
+	values := make(url.Values)
+	values.Set(`token`, b.Config.Token)
+	values.Set(`channel`, e.Channel)
+	values.Set(`ts`, ts)
+	values.Set(`text`, e.Text)
+	if e.Blocks != nil {
+		blocks, _ := json.Marshal(e.Blocks)
+		values.Set(`blocks`, string(blocks))
+	}
+	// like chat.postMessage, this answers with the channel as a string
+	reply, err := http.PostForm(`https://slack.com/api/chat.update`, values)
+	if err != nil {
+		return err
+	}
+	defer reply.Body.Close()
+	resp := make(map[string]interface{})
+	if err := json.NewDecoder(reply.Body).Decode(&resp); err != nil {
+		return err
+	}
+	if ok, _ := resp[`ok`].(bool); !ok {
+		return fmt.Errorf("%v", resp[`error`])
+	}
+	return nil
+}
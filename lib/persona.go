@@ -0,0 +1,114 @@
+package lib
+
+import (
+	"net/url"
+	"strings"
+)
+
+// A Persona is a name and icon Lazlo posts under instead of its own.
+// Personas are registry entities of kind persona, so they're configured in one
+// place (LAZLO_REGISTRY_FILE) rather than in every module:
+//
+//	"persona": {
+//	  "alertbot": {"username": "AlertBot", "icon_emoji": ":rotating_light:", "modules": "Triage", "channels": "#alerts"}
+//	}
+//
+// Messages a module sends with SayAs or RespondAs (naming itself) use the
+// persona that lists the module, and anything said in one of a persona's
+// channels uses that persona. Everything else is posted as the bot.
+type Persona struct {
+	Name      string
+	Username  string
+	IconEmoji string
+	IconURL   string
+}
+
+// Persona picks the persona for a message from who (a persona or a module
+// name, or empty) to channel, or returns nil if it should be posted as the
+// bot
+func (b *Broker) Persona(who string, channel string) *Persona {
+	if b.Registry == nil {
+		return nil
+	}
+	if who != `` {
+		if e := b.Registry.Get(`persona`, who); e != nil {
+			return newPersona(e)
+		}
+	}
+	channelName := ``
+	if c := b.SlackMeta.GetChannel(channel); c != nil {
+		channelName = `#` + c.Name
+	}
+	var forChannel *Entity
+	for _, e := range b.Registry.List(`persona`) {
+		if who != `` && listHas(e.Attrs[`modules`], who) {
+			return newPersona(e)
+		}
+		if forChannel == nil && channel != `` && (listHas(e.Attrs[`channels`], channel) || listHas(e.Attrs[`channels`], channelName)) {
+			forChannel = e
+		}
+	}
+	if forChannel != nil {
+		return newPersona(forChannel)
+	}
+	return nil
+}
+
+func newPersona(e *Entity) *Persona {
+	p := &Persona{
+		Name:      e.Name,
+		Username:  e.Attrs[`username`],
+		IconEmoji: e.Attrs[`icon_emoji`],
+		IconURL:   e.Attrs[`icon_url`],
+	}
+	if p.Username == `` {
+		p.Username = e.Name
+	}
+	return p
+}
+
+// listHas is true if the comma-separated list has item in it (ignoring case)
+func listHas(list string, item string) bool {
+	for _, i := range splitList(list) {
+		if strings.EqualFold(i, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// set adds the persona to a chat.postMessage request
+func (p *Persona) set(values url.Values) {
+	values.Set(`as_user`, `false`)
+	values.Set(`username`, p.Username)
+	if p.IconEmoji != `` {
+		values.Set(`icon_emoji`, p.IconEmoji)
+	} else if p.IconURL != `` {
+		values.Set(`icon_url`, p.IconURL)
+	}
+}
+
+// SayAs is Say, from who: a module (so the persona configured for the module
+// is used) or a persona by name
+func (b *Broker) SayAs(who string, s string, channel ...string) chan map[string]interface{} {
+	c := b.DefaultChannel()
+	if channel != nil {
+		c = channel[0]
+	}
+	return b.Send(&Event{
+		Type:    `message`,
+		Channel: c,
+		Text:    s,
+		Persona: who,
+	})
+}
+
+// RespondAs is Respond, from who (see SayAs)
+func (event *Event) RespondAs(who string, s string) chan map[string]interface{} {
+	return event.send(&Event{
+		Type:    event.Type,
+		Channel: event.Channel,
+		Text:    s,
+		Persona: who,
+	})
+}
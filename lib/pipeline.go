@@ -0,0 +1,99 @@
+package lib
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// how long the broker waits for a blocking callback to call PatternMatch.Done()
+// before it gives up and moves on to the next callback
+const pipelineTimeout = 5 * time.Second
+
+// annotations hold the metadata handlers attach to a message for the
+// handlers that run after them
+type annotations struct {
+	sync.RWMutex
+	data         map[string]interface{}
+	conversation *Conversation // see Event.Conversation
+}
+
+// Annotate attaches a bit of metadata to the event, for message callbacks
+// that run after this one to read with Annotation()
+func (event *Event) Annotate(key string, value interface{}) {
+	if event.annotations == nil {
+		event.annotations = &annotations{data: make(map[string]interface{})}
+	}
+	event.annotations.Lock()
+	defer event.annotations.Unlock()
+	event.annotations.data[key] = value
+}
+
+// Annotation returns the metadata an earlier callback attached to the event
+// under key (or nil)
+func (event *Event) Annotation(key string) interface{} {
+	if event.annotations == nil {
+		return nil
+	}
+	event.annotations.RLock()
+	defer event.annotations.RUnlock()
+	return event.annotations.data[key]
+}
+
+// Done tells the broker a blocking callback is finished with the message, so
+// it can hand the message to the next callback in line. It's harmless to call
+// this on a PatternMatch from a non-blocking callback.
+func (pm PatternMatch) Done() {
+	if pm.Event != nil && pm.Event.once != nil {
+		pm.Event.once.done()
+	}
+	if pm.done == nil {
+		return
+	}
+	select {
+	case pm.done <- struct{}{}:
+	default:
+	}
+}
+
+// PipelineCallback registers a blocking message callback: every message it
+// matches is held until the module calls PatternMatch.Done(), so callbacks
+// with a higher priority number see whatever it annotated. Callbacks run in
+// priority order (lowest first); plain MessageCallbacks have priority 0.
+func (b *Broker) PipelineCallback(pattern string, respond bool, priority int, channel ...string) *MessageCallback {
+	callback := b.MessageCallback(pattern, respond, channel...)
+	if callback != nil {
+		callback.Priority = priority
+		callback.Blocking = true
+	}
+	return callback
+}
+
+// nextSeq numbers callbacks in the order they were registered so callbacks
+// with the same priority always run in the same order
+func (b *Broker) nextSeq() int64 {
+	return atomic.AddInt64(&b.cbSeq, 1)
+}
+
+// sortedMessageCallbacks returns the message callbacks in the order they
+// should see a message
+func (b *Broker) sortedMessageCallbacks() []*MessageCallback {
+	var callbacks byPriority
+	for _, cbInterface := range b.cbIndex[M] {
+		callbacks = append(callbacks, cbInterface.(*MessageCallback))
+	}
+	sort.Sort(callbacks)
+	return callbacks
+}
+
+type byPriority []*MessageCallback
+
+func (p byPriority) Len() int      { return len(p) }
+func (p byPriority) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p byPriority) Less(i, j int) bool {
+	if p[i].Priority != p[j].Priority {
+		return p[i].Priority < p[j].Priority
+	}
+	return p[i].seq < p[j].seq
+}
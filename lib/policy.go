@@ -0,0 +1,121 @@
+package lib
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// People from other companies can turn up in shared channels (see grid.go),
+// and most modules were written assuming everyone talking to them works
+// here. So messages and events from external users only go to modules that
+// say they're safe for it (Module.ExternalSafe), and those modules should
+// read the brain through Event.Brain, which only shows external users the
+// keys in LAZLO_EXTERNAL_BRAIN. LAZLO_EXTERNAL_POLICY can also be "ignore",
+// to ignore external users altogether, or "allow", to treat them like
+// everyone else.
+
+// External policies
+const (
+	ExternalRestrict = `restrict`
+	ExternalIgnore   = `ignore`
+	ExternalAllow    = `allow`
+)
+
+// callerModule returns the name of the module whose Run function we were
+// called from (so callbacks know who they belong to), or "" if it wasn't
+// one of them
+func (b *Broker) callerModule() string {
+	runs := make(map[string]string)
+	for name, m := range b.Modules {
+		if m.Run != nil {
+			runs[runtime.FuncForPC(reflect.ValueOf(m.Run).Pointer()).Name()] = name
+		}
+	}
+	pcs := make([]uintptr, 64)
+	n := runtime.Callers(2, pcs)
+	for _, pc := range pcs[:n] {
+		if f := runtime.FuncForPC(pc); f != nil {
+			if name, ok := runs[f.Name()]; ok {
+				return name
+			}
+		}
+	}
+	return ``
+}
+
+// externalAllowed says whether something from an external user can go to a
+// callback belonging to the named module
+func (b *Broker) externalAllowed(module string) bool {
+	switch b.Config.ExternalPolicy {
+	case ExternalAllow:
+		return true
+	case ExternalIgnore:
+		return false
+	}
+	m, ok := b.Modules[module]
+	return ok && m.ExternalSafe
+}
+
+// eventExternal says whether an event (as it came off the wire) is from an
+// external user
+func (b *Broker) eventExternal(thingy map[string]interface{}) bool {
+	user, _ := thingy[`user`].(string)
+	if user == `` || b.Config.ExternalPolicy == ExternalAllow {
+		return false
+	}
+	return b.IsExternal(user)
+}
+
+// Brain returns the brain, or if the event is from an external user, a
+// brain that only has the keys in LAZLO_EXTERNAL_BRAIN in it (and can't be
+// written to)
+func (event *Event) Brain() Brain {
+	b := event.Broker
+	if !event.External {
+		return b.Brain
+	}
+	return &externalBrain{Brain: b.Brain, prefixes: splitList(b.Config.ExternalBrain)}
+}
+
+// externalBrain is the brain as external users see it
+type externalBrain struct {
+	Brain
+	prefixes []string
+}
+
+func (eb *externalBrain) visible(key string) bool {
+	for _, prefix := range eb.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (eb *externalBrain) Get(key string) ([]byte, error) {
+	if !eb.visible(key) {
+		return nil, fmt.Errorf("%s isn't shared with external users", key)
+	}
+	return eb.Brain.Get(key)
+}
+
+func (eb *externalBrain) Set(key string, data []byte) error {
+	return fmt.Errorf("external users can't change %s", key)
+}
+
+func (eb *externalBrain) Delete(key string) error {
+	return fmt.Errorf("external users can't delete %s", key)
+}
+
+func (eb *externalBrain) Keys() ([]string, error) {
+	keys, err := eb.Brain.Keys()
+	var visible []string
+	for _, key := range keys {
+		if eb.visible(key) {
+			visible = append(visible, key)
+		}
+	}
+	return visible, err
+}
@@ -0,0 +1,60 @@
+package lib
+
+import (
+	"strings"
+	"time"
+)
+
+// Prefs are small per-user settings that any module can read and write, like
+// when a user's away until (which rotations and the like use to skip them).
+// They're kept in the brain under prefs:<user>:<name>, so they're expunged
+// along with the user.
+
+// GetPref returns the user's value for the named pref, or "" if it isn't set
+func (b *Broker) GetPref(user string, name string) string {
+	data, err := b.Brain.Get(UserKey(`prefs`, user, name))
+	if err != nil || data == nil {
+		return ``
+	}
+	return string(data)
+}
+
+// SetPref sets the user's value for the named pref. Setting it to "" deletes
+// it.
+func (b *Broker) SetPref(user string, name string, value string) error {
+	if value == `` {
+		if b.GetPref(user, name) == `` {
+			return nil
+		}
+		return b.Brain.Delete(UserKey(`prefs`, user, name))
+	}
+	return b.Brain.Set(UserKey(`prefs`, user, name), []byte(value))
+}
+
+// IsAway returns true if today falls within the user's away period: on or
+// before their away_until pref (a YYYY-MM-DD date), and on or after their
+// away_from pref if they have one
+func (b *Broker) IsAway(user string) bool {
+	today := time.Now().Format(`2006-01-02`)
+	until := b.GetPref(user, `away_until`)
+	if until == `` || today > until {
+		return false
+	}
+	from := b.GetPref(user, `away_from`)
+	return from == `` || today >= from
+}
+
+// Prefs returns all of the user's prefs
+func (b *Broker) Prefs(user string) map[string]string {
+	prefs := make(map[string]string)
+	prefix := UserKey(`prefs`, user) + `:`
+	keys, _ := b.Brain.Keys()
+	for _, key := range keys {
+		if strings.HasPrefix(key, prefix) {
+			if value := b.GetPref(user, strings.TrimPrefix(key, prefix)); value != `` {
+				prefs[strings.TrimPrefix(key, prefix)] = value
+			}
+		}
+	}
+	return prefs
+}
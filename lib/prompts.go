@@ -0,0 +1,282 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// A Prompt is a named, versioned template for talking to a language model,
+// along with the model parameters to use it with. Modules register a default
+// version of each prompt they use, and operators can tune them (or try out
+// several versions side by side) by dropping new versions in
+// LAZLO_PROMPT_DIR:
+//
+//	prompts/<name>/<version>.tmpl   the template (text/template)
+//	prompts/<name>/<version>.json   optional parameters (any Prompt field)
+//
+// If there are versions of a prompt on disk, the registered default is
+// ignored, and each render picks one of the versions on disk by Weight.
+type Prompt struct {
+	Name        string  `json:"-"`
+	Version     string  `json:"-"`
+	Template    string  `json:"-"`
+	Budget      int     `json:"budget"` // max tokens in the rendered prompt (0 means no limit)
+	Model       string  `json:"model"`
+	MaxTokens   int     `json:"max_tokens"` // max tokens in the model's response
+	Temperature float64 `json:"temperature"`
+	DailyQuota  int     `json:"daily_quota"` // max tokens (prompt and response) per day (0 means no limit)
+	Weight      int     `json:"weight"`      // share of renders this version gets (defaults to 1)
+	tmpl        *template.Template
+}
+
+// A RenderedPrompt is a prompt that's ready to send to a model
+type RenderedPrompt struct {
+	Name        string
+	Version     string
+	Text        string
+	Tokens      int // estimated
+	Model       string
+	MaxTokens   int
+	Temperature float64
+}
+
+// promptStore holds the registered and on-disk versions of every prompt
+type promptStore struct {
+	sync.RWMutex
+	dir      string
+	defaults map[string]*Prompt
+	versions map[string][]*Prompt // loaded from disk, sorted by version
+}
+
+func newPromptStore(dir string) *promptStore {
+	ps := &promptStore{
+		dir:      dir,
+		defaults: make(map[string]*Prompt),
+		versions: make(map[string][]*Prompt),
+	}
+	if err := ps.load(); err != nil {
+		Logger.Error(`Prompts:: `, err)
+	}
+	return ps
+}
+
+// load (re)reads every prompt version from disk. If anything's wrong with
+// any of them, we keep the versions we already had.
+func (ps *promptStore) load() error {
+	names, err := ioutil.ReadDir(ps.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	versions := make(map[string][]*Prompt)
+	for _, name := range names {
+		if !name.IsDir() {
+			continue
+		}
+		files, err := filepath.Glob(filepath.Join(ps.dir, name.Name(), `*.tmpl`))
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			p, err := loadPrompt(name.Name(), file)
+			if err != nil {
+				return err
+			}
+			versions[p.Name] = append(versions[p.Name], p)
+		}
+		sort.Sort(byPromptVersion(versions[name.Name()]))
+	}
+	ps.Lock()
+	ps.versions = versions
+	ps.Unlock()
+	Logger.Debug(`Prompts:: loaded `, len(versions), ` prompts from `, ps.dir)
+	return nil
+}
+
+// loadPrompt reads one version of a prompt (and its parameters, if any)
+func loadPrompt(name string, file string) (*Prompt, error) {
+	text, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	p := &Prompt{
+		Name:     name,
+		Version:  strings.TrimSuffix(filepath.Base(file), `.tmpl`),
+		Template: string(text),
+	}
+	params, err := ioutil.ReadFile(strings.TrimSuffix(file, `.tmpl`) + `.json`)
+	if err == nil {
+		if err := json.Unmarshal(params, p); err != nil {
+			return nil, fmt.Errorf("prompt %s version %s: %v", name, p.Version, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err := p.compile(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *Prompt) compile() error {
+	tmpl, err := template.New(p.Name).Option(`missingkey=error`).Parse(p.Template)
+	if err != nil {
+		return fmt.Errorf("prompt %s version %s: %v", p.Name, p.Version, err)
+	}
+	p.tmpl = tmpl
+	return nil
+}
+
+// RegisterPrompt registers the default version of a prompt. It's used unless
+// there are versions of the prompt in LAZLO_PROMPT_DIR.
+func (b *Broker) RegisterPrompt(p *Prompt) error {
+	if p.Version == `` {
+		p.Version = `default`
+	}
+	if err := p.compile(); err != nil {
+		return err
+	}
+	b.prompts.Lock()
+	defer b.prompts.Unlock()
+	b.prompts.defaults[p.Name] = p
+	return nil
+}
+
+// ReloadPrompts re-reads the prompts in LAZLO_PROMPT_DIR. Lazlo calls it when
+// it gets a SIGHUP.
+func (b *Broker) ReloadPrompts() {
+	if err := b.prompts.load(); err != nil {
+		Logger.Error(`Prompts:: couldn't reload prompts: `, err)
+	}
+}
+
+// PromptVersions returns every version of the named prompt that's in use
+func (b *Broker) PromptVersions(name string) []*Prompt {
+	b.prompts.RLock()
+	defer b.prompts.RUnlock()
+	if versions := b.prompts.versions[name]; versions != nil {
+		return versions
+	}
+	if p, ok := b.prompts.defaults[name]; ok {
+		return []*Prompt{p}
+	}
+	return nil
+}
+
+// RenderPrompt picks a version of the named prompt and renders it with vars.
+// The version is picked by weight, but always the same way for the same key
+// (a user or channel ID, say), so people don't flip between versions of an
+// experiment. It fails if the rendered prompt is over its token budget, or
+// if sending it could put the prompt over its daily quota.
+func (b *Broker) RenderPrompt(name string, key string, vars interface{}) (*RenderedPrompt, error) {
+	versions := b.PromptVersions(name)
+	if versions == nil {
+		return nil, fmt.Errorf("no such prompt: %s", name)
+	}
+	p := pickPrompt(versions, key)
+
+	var text bytes.Buffer
+	if err := p.tmpl.Execute(&text, vars); err != nil {
+		return nil, fmt.Errorf("prompt %s version %s: %v", p.Name, p.Version, err)
+	}
+	rp := &RenderedPrompt{
+		Name:        p.Name,
+		Version:     p.Version,
+		Text:        text.String(),
+		Tokens:      EstimateTokens(text.String()),
+		Model:       p.Model,
+		MaxTokens:   p.MaxTokens,
+		Temperature: p.Temperature,
+	}
+	if p.Budget > 0 && rp.Tokens > p.Budget {
+		return nil, fmt.Errorf("prompt %s version %s is about %d tokens, over its budget of %d", p.Name, p.Version, rp.Tokens, p.Budget)
+	}
+	if p.DailyQuota > 0 {
+		if used := b.PromptUsage(p.Name, time.Now()); used+rp.Tokens+rp.MaxTokens > p.DailyQuota {
+			return nil, fmt.Errorf("prompt %s has used %d of its %d tokens for today", p.Name, used, p.DailyQuota)
+		}
+	}
+	return rp, nil
+}
+
+// pickPrompt picks one of the versions by weight, consistently for the key
+func pickPrompt(versions []*Prompt, key string) *Prompt {
+	if len(versions) == 1 {
+		return versions[0]
+	}
+	total := 0
+	for _, p := range versions {
+		total += p.weight()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	n := int(h.Sum32() % uint32(total))
+	for _, p := range versions {
+		if n -= p.weight(); n < 0 {
+			return p
+		}
+	}
+	return versions[len(versions)-1]
+}
+
+func (p *Prompt) weight() int {
+	if p.Weight > 0 {
+		return p.Weight
+	}
+	return 1
+}
+
+// EstimateTokens guesses how many tokens a model will see in the text (about
+// four characters per token for english)
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+func promptUsageKey(name string, day time.Time) string {
+	return fmt.Sprintf("prompts:usage:%s:%s", name, day.Format(`2006-01-02`))
+}
+
+// RecordPromptUsage counts the tokens a rendered prompt actually used (prompt
+// and response) against its daily quota
+func (b *Broker) RecordPromptUsage(rp *RenderedPrompt, tokens int) {
+	b.prompts.Lock()
+	defer b.prompts.Unlock()
+	key := promptUsageKey(rp.Name, time.Now())
+	used := 0
+	if data, err := b.Brain.Get(key); err == nil {
+		used, _ = strconv.Atoi(string(data))
+	}
+	if err := b.Brain.Set(key, []byte(strconv.Itoa(used+tokens))); err != nil {
+		Logger.Error(`Prompts:: couldn't record usage for `, rp.Name, `: `, err)
+	}
+}
+
+// PromptUsage returns the number of tokens the named prompt used on the given
+// day
+func (b *Broker) PromptUsage(name string, day time.Time) int {
+	data, err := b.Brain.Get(promptUsageKey(name, day))
+	if err != nil {
+		return 0
+	}
+	used, _ := strconv.Atoi(string(data))
+	return used
+}
+
+type byPromptVersion []*Prompt
+
+func (s byPromptVersion) Len() int           { return len(s) }
+func (s byPromptVersion) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byPromptVersion) Less(i, j int) bool { return s[i].Version < s[j].Version }
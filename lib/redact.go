@@ -0,0 +1,101 @@
+package lib
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A Redactor scrubs sensitive information out of message text before it is
+// logged or remembered. Register your own with broker.Register() to add
+// detectors for things the built-in redactors don't know about.
+type Redactor struct {
+	Name  string
+	Usage string
+	Run   func(text string) string
+}
+
+// PatternRedactor returns a Redactor that replaces everything matching the
+// given regex with [REDACTED <name>]
+func PatternRedactor(name string, pattern string) *Redactor {
+	r := regexp.MustCompile(pattern)
+	replacement := `[REDACTED ` + name + `]`
+	return &Redactor{
+		Name:  name,
+		Usage: `redacts text matching ` + pattern,
+		Run: func(text string) string {
+			return r.ReplaceAllString(text, replacement)
+		},
+	}
+}
+
+var cardPat = regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`)
+
+// builtinRedactors are enabled unless LAZLO_REDACT=false
+func builtinRedactors() []*Redactor {
+	return []*Redactor{
+		PatternRedactor(`email`, `(?i)[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}`),
+		PatternRedactor(`token`, `\bxox[abposr]-[0-9A-Za-z-]+`),
+		PatternRedactor(`token`, `(?i)\b(?:bearer|token|password|passwd|secret)[:=]\s*\S+`),
+		&Redactor{
+			Name:  `card`,
+			Usage: `redacts credit card numbers`,
+			Run: func(text string) string {
+				return cardPat.ReplaceAllStringFunc(text, func(s string) string {
+					if luhn(s) {
+						return `[REDACTED card]`
+					}
+					return s
+				})
+			},
+		},
+	}
+}
+
+// luhn validates a credit card number (ignoring spaces and dashes) so we
+// don't redact every long number anyone says in chat
+func luhn(number string) bool {
+	sum := 0
+	double := false
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// newRedactors returns the redactors configured in the environment
+func newRedactors(c *Config) []*Redactor {
+	var redactors []*Redactor
+	if c.Redact {
+		redactors = builtinRedactors()
+	}
+	for _, pattern := range strings.Fields(c.RedactPatterns) {
+		if _, err := regexp.Compile(pattern); err != nil {
+			Logger.Error(`ignoring invalid redact pattern `, pattern, `: `, err)
+			continue
+		}
+		redactors = append(redactors, PatternRedactor(`pattern`, pattern))
+	}
+	return redactors
+}
+
+// Redact runs the given text through every registered redactor. Use this
+// before you log or store anything a user said.
+func (b *Broker) Redact(text string) string {
+	for _, r := range b.Redactors {
+		text = r.Run(text)
+	}
+	return text
+}
@@ -0,0 +1,242 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// An Entity is something several modules need to agree on, like a service,
+// an environment, or a team, along with whatever they need to know about it
+// (which team owns a service, which channel a team hangs out in, etc)
+type Entity struct {
+	Kind   string            `json:"kind"`
+	Name   string            `json:"name"`
+	Attrs  map[string]string `json:"attrs"`
+	Source string            `json:"source"` // "config", or the module that declared it
+}
+
+// The Registry is the one list of services, environments, teams and the like
+// that every module shares, so the deploy, alerting and incident modules
+// don't each keep their own. Entities come from LAZLO_REGISTRY_FILE, which
+// looks like this:
+//
+//	{
+//	  "service": {"web": {"team": "platform", "repo": "acme/web"}},
+//	  "environment": {"prod": {"channel": "#ops"}},
+//	  "team": {"platform": {"channel": "#platform"}}
+//	}
+//
+// and from modules, which can declare entities of their own. Everything's
+// cached in the brain, so lazlo still knows about it if the file goes away.
+type Registry struct {
+	sync.RWMutex
+	broker   *Broker
+	file     string
+	entities map[string]map[string]*Entity // kind -> name -> entity
+}
+
+func newRegistry(b *Broker) *Registry {
+	r := &Registry{
+		broker:   b,
+		file:     b.SyncPath(`registry.json`, b.Config.RegistryFile),
+		entities: make(map[string]map[string]*Entity),
+	}
+	if err := r.loadCache(); err != nil {
+		Logger.Error(`Registry:: couldn't read the cached registry: `, err)
+	}
+	if err := r.load(); err != nil {
+		Logger.Error(`Registry:: `, err)
+	}
+	return r
+}
+
+func registryKey(kind string, name string) string {
+	return fmt.Sprintf("registry:%s:%s", strings.ToLower(kind), strings.ToLower(name))
+}
+
+// loadCache reads back whatever we had in the brain
+func (r *Registry) loadCache() error {
+	keys, err := r.broker.Brain.Keys()
+	if err != nil {
+		return err
+	}
+	r.Lock()
+	defer r.Unlock()
+	for _, key := range keys {
+		if !strings.HasPrefix(key, `registry:`) {
+			continue
+		}
+		data, err := r.broker.Brain.Get(key)
+		if err != nil {
+			return err
+		}
+		e := new(Entity)
+		if err := json.Unmarshal(data, e); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		r.put(e)
+	}
+	return nil
+}
+
+// load (re)reads LAZLO_REGISTRY_FILE, replacing everything that came from
+// the last version of it. If the file's missing or broken, we keep what we
+// had.
+func (r *Registry) load() error {
+	data, err := ioutil.ReadFile(r.file)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var file map[string]map[string]map[string]string
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("couldn't parse %s: %v", r.file, err)
+	}
+
+	r.Lock()
+	defer r.Unlock()
+	for _, names := range r.entities {
+		for _, e := range names {
+			if e.Source == `config` {
+				r.remove(e)
+			}
+		}
+	}
+	count := 0
+	for kind, names := range file {
+		for name, attrs := range names {
+			if attrs == nil {
+				attrs = make(map[string]string)
+			}
+			r.put(&Entity{Kind: kind, Name: name, Attrs: attrs, Source: `config`})
+			count++
+		}
+	}
+	for _, names := range r.entities {
+		for _, e := range names {
+			r.save(e)
+		}
+	}
+	Logger.Debug(`Registry:: loaded `, count, ` entities from `, r.file)
+	return nil
+}
+
+// put, remove and save expect the caller to hold the lock
+func (r *Registry) put(e *Entity) {
+	kind := strings.ToLower(e.Kind)
+	if r.entities[kind] == nil {
+		r.entities[kind] = make(map[string]*Entity)
+	}
+	r.entities[kind][strings.ToLower(e.Name)] = e
+}
+
+func (r *Registry) remove(e *Entity) {
+	kind := strings.ToLower(e.Kind)
+	delete(r.entities[kind], strings.ToLower(e.Name))
+	if len(r.entities[kind]) == 0 {
+		delete(r.entities, kind)
+	}
+	r.broker.Brain.Delete(registryKey(e.Kind, e.Name))
+}
+
+func (r *Registry) save(e *Entity) {
+	data, _ := json.Marshal(e)
+	if err := r.broker.Brain.Set(registryKey(e.Kind, e.Name), data); err != nil {
+		Logger.Error(`Registry:: couldn't cache `, e.Kind, ` `, e.Name, `: `, err)
+	}
+}
+
+// Declare adds an entity on behalf of a module. If the entity's already
+// there (from the registry file, say), the attributes it doesn't have yet are
+// added to it, and the rest are left alone.
+func (r *Registry) Declare(module string, kind string, name string, attrs map[string]string) {
+	r.Lock()
+	defer r.Unlock()
+	e, ok := r.entities[strings.ToLower(kind)][strings.ToLower(name)]
+	if !ok {
+		e = &Entity{Kind: kind, Name: name, Attrs: make(map[string]string), Source: module}
+		r.put(e)
+	}
+	for k, v := range attrs {
+		if _, exists := e.Attrs[k]; !exists {
+			e.Attrs[k] = v
+		}
+	}
+	r.save(e)
+}
+
+// Get returns a copy of the named entity, or nil if there's no such thing
+func (r *Registry) Get(kind string, name string) *Entity {
+	r.RLock()
+	defer r.RUnlock()
+	if e, ok := r.entities[strings.ToLower(kind)][strings.ToLower(name)]; ok {
+		return e.copy()
+	}
+	return nil
+}
+
+// List returns copies of every entity of the given kind, sorted by name
+func (r *Registry) List(kind string) []*Entity {
+	r.RLock()
+	defer r.RUnlock()
+	var list []*Entity
+	for _, e := range r.entities[strings.ToLower(kind)] {
+		list = append(list, e.copy())
+	}
+	sort.Sort(byEntityName(list))
+	return list
+}
+
+// Find returns every entity of the given kind with the given attribute
+// (every service owned by a team, say)
+func (r *Registry) Find(kind string, attr string, value string) []*Entity {
+	var found []*Entity
+	for _, e := range r.List(kind) {
+		if e.Attrs[attr] == value {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// Kinds returns every kind of entity in the registry
+func (r *Registry) Kinds() []string {
+	r.RLock()
+	defer r.RUnlock()
+	var kinds []string
+	for kind := range r.entities {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (e *Entity) copy() *Entity {
+	c := *e
+	c.Attrs = make(map[string]string)
+	for k, v := range e.Attrs {
+		c.Attrs[k] = v
+	}
+	return &c
+}
+
+// ReloadRegistry re-reads LAZLO_REGISTRY_FILE. Lazlo calls it when it gets a
+// SIGHUP.
+func (b *Broker) ReloadRegistry() {
+	if err := b.Registry.load(); err != nil {
+		Logger.Error(`Registry:: couldn't reload the registry: `, err)
+	}
+}
+
+type byEntityName []*Entity
+
+func (s byEntityName) Len() int           { return len(s) }
+func (s byEntityName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byEntityName) Less(i, j int) bool { return s[i].Name < s[j].Name }
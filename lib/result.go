@@ -0,0 +1,274 @@
+package lib
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A Result is a command's answer, described rather than formatted, so it can
+// be rendered for wherever it's going: block kit for slack, markdown for
+// chat systems that speak it, and plain text for the fake adapter and the
+// console. Hand one to Event.RespondResult (or Broker.SayResult) instead of
+// formatting the text yourself.
+type Result struct {
+	Title       string
+	Status      string // ResultOK, ResultWarning, ResultError, or empty
+	Text        string // slack-style markup is fine
+	Fields      []ResultField
+	Table       *ResultTable
+	Attachments []Attachment // links, mostly: Title, TitleLink and Text are rendered everywhere
+}
+
+// Result statuses
+const (
+	ResultOK      = `ok`
+	ResultWarning = `warning`
+	ResultError   = `error`
+)
+
+// A ResultField is a name and a value, shown side by side where there's room
+type ResultField struct {
+	Name  string
+	Value string
+}
+
+// A ResultTable is a table. Rows shorter than the header are padded.
+type ResultTable struct {
+	Header []string
+	Rows   [][]string
+}
+
+// Result render targets
+const (
+	RenderBlocks   = `blocks`
+	RenderMarkdown = `markdown`
+	RenderText     = `text`
+)
+
+// slack takes at most this many fields in a section
+const maxSectionFields = 10
+
+var resultEmoji = map[string]string{
+	ResultOK:      `:white_check_mark:`,
+	ResultWarning: `:warning:`,
+	ResultError:   `:x:`,
+}
+
+// Field adds a field to the result
+func (r *Result) Field(name string, value interface{}) *Result {
+	r.Fields = append(r.Fields, ResultField{Name: name, Value: fmt.Sprint(value)})
+	return r
+}
+
+// Row adds a row to the result's table
+func (r *Result) Row(cells ...interface{}) *Result {
+	if r.Table == nil {
+		r.Table = new(ResultTable)
+	}
+	var row []string
+	for _, cell := range cells {
+		row = append(row, fmt.Sprint(cell))
+	}
+	r.Table.Rows = append(r.Table.Rows, row)
+	return r
+}
+
+// renderTarget is how results should be rendered for the adapter we're
+// talking to
+func (b *Broker) renderTarget() string {
+	return b.AdapterFormat().Render
+}
+
+// ResultEvent renders the result into a message for the channel
+func (b *Broker) ResultEvent(r *Result, channel string) *Event {
+	e := &Event{Type: `message`, Channel: channel}
+	switch b.renderTarget() {
+	case RenderBlocks:
+		e.Text = r.PlainText() // for notifications
+		e.Blocks = r.Blocks()
+		e.Attachments = r.Attachments
+	case RenderMarkdown:
+		e.Text = r.Markdown()
+	default:
+		e.Text = r.PlainText()
+	}
+	return e
+}
+
+// SayResult says the result in the channel
+func (b *Broker) SayResult(r *Result, channel string) chan map[string]interface{} {
+	return b.Send(b.ResultEvent(r, channel))
+}
+
+// RespondResult responds to the event with the result
+func (event *Event) RespondResult(r *Result) chan map[string]interface{} {
+	return event.send(event.Broker.ResultEvent(r, event.Channel))
+}
+
+func (r *Result) title() string {
+	if emoji, ok := resultEmoji[r.Status]; ok && r.Title != `` {
+		return emoji + ` ` + r.Title
+	}
+	return r.Title
+}
+
+// Blocks renders the result as slack block kit blocks (the attachments go
+// alongside, as message attachments)
+func (r *Result) Blocks() []Block {
+	var blocks []Block
+	if r.Title != `` {
+		blocks = append(blocks, TextBlock(`*`+r.title()+`*`))
+	}
+	if r.Text != `` {
+		blocks = append(blocks, TextBlock(r.Text))
+	}
+	for i := 0; i < len(r.Fields); i += maxSectionFields {
+		var fields []Block
+		for _, f := range r.Fields[i:min(i+maxSectionFields, len(r.Fields))] {
+			fields = append(fields, Block{`type`: `mrkdwn`, `text`: fmt.Sprintf("*%s*\n%s", f.Name, f.Value)})
+		}
+		blocks = append(blocks, Block{`type`: `section`, `fields`: fields})
+	}
+	if r.Table != nil {
+		blocks = append(blocks, TextBlock("```\n"+r.Table.text()+"```"))
+	}
+	return blocks
+}
+
+// Markdown renders the result as (github flavored) markdown
+func (r *Result) Markdown() string {
+	var out []string
+	if r.Title != `` {
+		out = append(out, `**`+r.title()+`**`)
+	}
+	if r.Text != `` {
+		out = append(out, r.Text)
+	}
+	if r.Fields != nil {
+		var fields []string
+		for _, f := range r.Fields {
+			fields = append(fields, fmt.Sprintf("- **%s**: %s", f.Name, f.Value))
+		}
+		out = append(out, strings.Join(fields, "\n"))
+	}
+	if t := r.Table; t != nil {
+		var rows []string
+		columns := len(t.Header)
+		for _, row := range t.Rows {
+			columns = max(columns, len(row))
+		}
+		escape := func(row []string) string {
+			var cells []string
+			for i := 0; i < columns; i++ {
+				cell := ``
+				if i < len(row) {
+					cell = strings.Replace(row[i], `|`, `\|`, -1)
+				}
+				cells = append(cells, cell)
+			}
+			return `| ` + strings.Join(cells, ` | `) + ` |`
+		}
+		rows = append(rows, escape(t.Header))
+		rows = append(rows, `|`+strings.Repeat(` --- |`, columns))
+		for _, row := range t.Rows {
+			rows = append(rows, escape(row))
+		}
+		out = append(out, strings.Join(rows, "\n"))
+	}
+	for _, a := range r.Attachments {
+		out = append(out, a.markdown())
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// PlainText renders the result with no markup at all
+func (r *Result) PlainText() string {
+	var out []string
+	if r.Title != `` {
+		title := r.Title
+		if r.Status != `` {
+			title = fmt.Sprintf("%s [%s]", title, r.Status)
+		}
+		out = append(out, title)
+	}
+	if r.Text != `` {
+		out = append(out, r.Text)
+	}
+	if r.Fields != nil {
+		var fields []string
+		for _, f := range r.Fields {
+			fields = append(fields, fmt.Sprintf("%s: %s", f.Name, f.Value))
+		}
+		out = append(out, strings.Join(fields, "\n"))
+	}
+	if r.Table != nil {
+		out = append(out, strings.TrimSuffix(r.Table.text(), "\n"))
+	}
+	for _, a := range r.Attachments {
+		line := a.Title
+		if a.TitleLink != `` {
+			line = strings.TrimSpace(line + ` ` + a.TitleLink)
+		}
+		if a.Text != `` {
+			line = strings.TrimSpace(line + "\n" + a.Text)
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n\n")
+}
+
+// text lines the table's columns up, by how wide they look rather than how
+// many bytes they are, so emoji and CJK don't push them out of line
+func (t *ResultTable) text() string {
+	rows := t.Rows
+	if t.Header != nil {
+		rows = append([][]string{t.Header}, rows...)
+	}
+	var widths []int
+	for _, row := range rows {
+		// like tabwriter, the last cell in a row doesn't set the width
+		for i := 0; i < len(row)-1; i++ {
+			if i == len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], TextWidth(row[i]))
+		}
+	}
+	var buf bytes.Buffer
+	for _, row := range rows {
+		for i, cell := range row {
+			if i < len(row)-1 {
+				cell = PadRight(cell, widths[i]+2)
+			}
+			buf.WriteString(cell)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func (a Attachment) markdown() string {
+	out := a.Title
+	if a.TitleLink != `` {
+		out = fmt.Sprintf("[%s](%s)", a.Title, a.TitleLink)
+	}
+	if a.Text != `` {
+		out = strings.TrimSpace(out + "\n" + a.Text)
+	}
+	return out
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,35 @@
+package lib
+
+import (
+	"strings"
+)
+
+// Roles say who's allowed to do the things modules guard with them (like
+// running commands on a host). A role is a "role" entity in the registry
+// whose members attr lists its members, by user ID or name:
+//
+//	"role": {"oncall": {"members": "U024BE7LH, alice"}}
+//
+// Admins have every role.
+
+// HasRole returns true if the user has the role
+func (b *Broker) HasRole(user string, role string) bool {
+	if role == `` || b.IsAdmin(user) {
+		return true
+	}
+	if b.Registry == nil {
+		return false
+	}
+	e := b.Registry.Get(`role`, role)
+	if e == nil {
+		return false
+	}
+	name := b.SlackMeta.GetUserName(user)
+	for _, member := range splitList(e.Attrs[`members`]) {
+		member = strings.TrimPrefix(member, `@`)
+		if member == user || (name != `` && strings.EqualFold(member, name)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package lib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Lazlo reads its own secrets (for now, the keys it encrypts the brain with)
+// from a secrets provider rather than its config, so they don't show up in
+// snapshots or the lua config, and can live somewhere other than the
+// environment. LAZLO_SECRETS picks the provider:
+//
+//	env          an environment variable named after the secret (the default)
+//	file:<dir>   a file named after the secret in dir, the way docker and
+//	             kubernetes mount secrets (e.g. file:/run/secrets)
+
+// A SecretProvider looks up a secret by name ("" if there's no such secret)
+type SecretProvider interface {
+	Secret(name string) (string, error)
+}
+
+// envSecrets reads secrets from the environment
+type envSecrets struct{}
+
+func (envSecrets) Secret(name string) (string, error) {
+	return os.Getenv(name), nil
+}
+
+// fileSecrets reads secrets from files in a directory
+type fileSecrets string
+
+func (dir fileSecrets) Secret(name string) (string, error) {
+	data, err := ioutil.ReadFile(filepath.Join(string(dir), name))
+	if os.IsNotExist(err) {
+		return ``, nil
+	}
+	if err != nil {
+		return ``, fmt.Errorf("couldn't read secret %s: %v", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// newSecretProvider returns the provider LAZLO_SECRETS asks for
+func newSecretProvider(c *Config) (SecretProvider, error) {
+	switch spec := strings.TrimSpace(c.Secrets); {
+	case spec == ``, spec == `env`:
+		return envSecrets{}, nil
+	case strings.HasPrefix(spec, `file:`):
+		return fileSecrets(strings.TrimPrefix(spec, `file:`)), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q (try env or file:<dir>)", spec)
+	}
+}
+
+// Secret looks up a secret with the configured secrets provider
+func (b *Broker) Secret(name string) (string, error) {
+	provider, err := newSecretProvider(b.Config)
+	if err != nil {
+		return ``, err
+	}
+	return provider.Secret(name)
+}
@@ -0,0 +1,51 @@
+package lib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSecretProviders(t *testing.T) {
+	dir, err := ioutil.TempDir(``, `secrets`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, `LAZLO_TEST_SECRET`), []byte("from a file\n"), 0600)
+	os.Mkdir(filepath.Join(dir, `LAZLO_TEST_DIR`), 0700)
+	os.Setenv(`LAZLO_TEST_SECRET`, `from the environment`)
+	defer os.Unsetenv(`LAZLO_TEST_SECRET`)
+
+	tests := []struct {
+		name   string
+		spec   string
+		secret string
+		want   string
+		err    string
+	}{
+		{`default`, ``, `LAZLO_TEST_SECRET`, `from the environment`, ``},
+		{`env`, `env`, `LAZLO_TEST_SECRET`, `from the environment`, ``},
+		{`env, unset`, `env`, `LAZLO_TEST_UNSET`, ``, ``},
+		{`file`, `file:` + dir, `LAZLO_TEST_SECRET`, `from a file`, ``},
+		{`file, missing`, `file:` + dir, `LAZLO_TEST_UNSET`, ``, ``},
+		{`file, unreadable`, `file:` + dir, `LAZLO_TEST_DIR`, ``, `couldn't read secret LAZLO_TEST_DIR`},
+		{`unknown provider`, `vault`, `LAZLO_TEST_SECRET`, ``, `unknown secrets provider "vault"`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			b := &Broker{Config: &Config{Secrets: test.spec}}
+			got, err := b.Secret(test.secret)
+			switch {
+			case test.err == `` && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case test.err != `` && (err == nil || !strings.Contains(err.Error(), test.err)):
+				t.Errorf("got %q, %v, want an error about %q", got, err, test.err)
+			case got != test.want:
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
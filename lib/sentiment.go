@@ -0,0 +1,106 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A SentimentScorer rates how positive (1) or negative (-1) a bit of text is
+type SentimentScorer interface {
+	Score(text string) (float64, error)
+}
+
+// NewSentimentScorer returns the scorer lazlo is configured to use: an
+// HTTPScorer if LAZLO_SENTIMENT_URL is set, and the built-in lexicon
+// otherwise
+func (b *Broker) NewSentimentScorer() SentimentScorer {
+	if b.Config.SentimentURL != `` {
+		return &HTTPScorer{URL: b.Config.SentimentURL}
+	}
+	return LexiconScorer(defaultLexicon)
+}
+
+// A LexiconScorer scores text by looking its words up in a word list (scored
+// from -3 to 3) and averaging them. A word right after a negation ("not
+// good") counts the other way.
+type LexiconScorer map[string]float64
+
+var wordPat = regexp.MustCompile(`[\pL\pM']+`)
+
+var negations = map[string]bool{
+	`not`: true, `no`: true, `never`: true, `isn't`: true, `don't`: true,
+	`doesn't`: true, `wasn't`: true, `didn't`: true, `can't`: true, `won't`: true,
+}
+
+func (ls LexiconScorer) Score(text string) (float64, error) {
+	var total float64
+	matched := 0
+	negated := false
+	// phones like to send curly apostrophes
+	text = strings.Replace(Fold(text), `’`, `'`, -1)
+	for _, word := range wordPat.FindAllString(text, -1) {
+		if negations[word] {
+			negated = true
+			continue
+		}
+		if score, ok := ls[word]; ok {
+			if negated {
+				score = -score
+			}
+			total += score
+			matched++
+		}
+		negated = false
+	}
+	if matched == 0 {
+		return 0, nil
+	}
+	return total / float64(matched) / 3, nil
+}
+
+// An HTTPScorer asks a sentiment model behind an http endpoint. It POSTs
+// {"text": "..."} and expects {"score": <-1 to 1>} back.
+type HTTPScorer struct {
+	URL    string
+	Client *http.Client
+}
+
+func (hs *HTTPScorer) Score(text string) (float64, error) {
+	client := hs.Client
+	if client == nil {
+		client = &http.Client{Timeout: 2 * time.Second}
+	}
+	body, _ := json.Marshal(map[string]string{`text`: text})
+	reply, err := client.Post(hs.URL, `application/json`, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	defer reply.Body.Close()
+	if reply.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("sentiment scorer said %s", reply.Status)
+	}
+	resp := struct {
+		Score float64 `json:"score"`
+	}{}
+	if err := json.NewDecoder(reply.Body).Decode(&resp); err != nil {
+		return 0, fmt.Errorf("Couldn't decode json. ERR: %v", err)
+	}
+	return resp.Score, nil
+}
+
+// a small AFINN-style word list; good enough for a rough read on a channel
+var defaultLexicon = map[string]float64{
+	`amazing`: 3, `awesome`: 3, `excellent`: 3, `fantastic`: 3, `love`: 3, `perfect`: 3, `thrilled`: 3,
+	`great`: 2, `happy`: 2, `nice`: 2, `glad`: 2, `thanks`: 2, `thank`: 2, `cool`: 2, `fun`: 2,
+	`excited`: 2, `win`: 2, `wonderful`: 2, `yay`: 2, `congrats`: 2, `good`: 2, `helpful`: 2,
+	`fixed`: 1, `works`: 1, `ok`: 1, `okay`: 1, `like`: 1, `better`: 1, `easy`: 1, `solved`: 1, `shipped`: 1,
+	`slow`: -1, `confused`: -1, `blocked`: -1, `stuck`: -1, `issue`: -1, `bug`: -1, `meh`: -1, `tired`: -1,
+	`bad`: -2, `broken`: -2, `fail`: -2, `failed`: -2, `failing`: -2, `sad`: -2, `annoying`: -2,
+	`annoyed`: -2, `problem`: -2, `sucks`: -2, `ugh`: -2, `worried`: -2, `wrong`: -2, `down`: -2, `outage`: -2,
+	`angry`: -3, `awful`: -3, `hate`: -3, `horrible`: -3, `terrible`: -3, `furious`: -3, `disaster`: -3,
+}
@@ -1,25 +1,30 @@
 package lib
 
 type ApiResponse struct {
-	Bots          []Bot     `json:"bots,omitempty"`
-	CacheVersion  string    `json:"cache_version,omitempty"`
-	Channels      []Channel `json:"channels,omitempty"`
-	Channel       Channel   `json:"channel,omitempty"`
-	Groups        []Group   `json:"groups,omitempty"`
-	Group         Group     `json:"group,omitempty"`
-	IMs           []IM      `json:"ims,omitempty"`
-	LatestEventTs string    `json:"latest_event_ts,omitempty"`
-	Latest        string    `json:"latest,omitempty"`
-	Ok            bool      `json:"ok,omitempty"`
-	ReplyTo       int32     `json:"reply_to,omitempty"`
-	Error         string    `json:"error,omitempty"`
-	HasMore       bool      `json:"has_more,omitempty"`
-	Self          Self      `json:"self,omitempty"`
-	Team          Team      `json:"team,omitempty"`
-	URL           string    `json:"url,omitempty"`
-	Users         []User    `json:"users,omitempty"`
-	User          User      `json:"user,omitempty"`
-	Messages      []Event   `json:"messages,omitempty"`
+	Bots          []Bot             `json:"bots,omitempty"`
+	CacheVersion  string            `json:"cache_version,omitempty"`
+	Channels      []Channel         `json:"channels,omitempty"`
+	Channel       Channel           `json:"channel,omitempty"`
+	Groups        []Group           `json:"groups,omitempty"`
+	Group         Group             `json:"group,omitempty"`
+	IMs           []IM              `json:"ims,omitempty"`
+	LatestEventTs string            `json:"latest_event_ts,omitempty"`
+	Latest        string            `json:"latest,omitempty"`
+	Ok            bool              `json:"ok,omitempty"`
+	ReplyTo       int32             `json:"reply_to,omitempty"`
+	Error         string            `json:"error,omitempty"`
+	HasMore       bool              `json:"has_more,omitempty"`
+	Self          Self              `json:"self,omitempty"`
+	Team          Team              `json:"team,omitempty"`
+	URL           string            `json:"url,omitempty"`
+	Users         []User            `json:"users,omitempty"`
+	User          User              `json:"user,omitempty"`
+	Messages      []Event           `json:"messages,omitempty"`
+	Emoji         map[string]string `json:"emoji,omitempty"`
+	// cursor for the next page of paginated methods
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor,omitempty"`
+	} `json:"response_metadata,omitempty"`
 }
 
 type Event struct {
@@ -28,14 +33,27 @@ type Event struct {
 	Channel      string       `json:"channel,omitempty"`
 	Text         string       `json:"text,omitempty"`
 	Attachments  []Attachment `json:"attachments,omitempty"`
+	Blocks       []Block      `json:"blocks,omitempty"`
 	User         string       `json:"user,omitempty"`
 	UserName     string       `json:"username,omitempty"`
 	BotID        string       `json:"bot_id,omitempty"`
 	Subtype      string       `json:"subtype,omitempty"`
 	Ts           string       `json:"ts,omitempty"`
+	ThreadTs     string       `json:"thread_ts,omitempty"`
 	Broker       *Broker
 	CallBackCode string `json:"callbackcode,omitempty"`
 	Extra        map[string]interface{}
+	Entities     MessageEntities `json:"-"`
+	Persona      string          `json:"-"` // who's talking, for outbound messages (see Broker.Persona)
+	Team         string          `json:"team,omitempty"`
+	UserTeamID   string          `json:"user_team,omitempty"`   // the user's workspace, in shared channels
+	SourceTeam   string          `json:"source_team,omitempty"` // the workspace the message was sent from
+	External     bool            `json:"-"`                     // from someone in another org (see policy.go)
+	Files        []File          `json:"files,omitempty"`       // files shared with the message
+	Lang         string          `json:"-"`                     // the language it's in, for inbound messages (see lang.go)
+	annotations  *annotations
+	cache        *cacheRun // records what's said in answer, for cached callbacks
+	once         *onceRun  // records what's said in answer, for exactly-once callbacks
 }
 
 type Attachment struct {
@@ -54,6 +72,16 @@ type Attachment struct {
 	MarkdownIn []string          `json:"mrkdwn_in,omitempty"`
 }
 
+// A File is a file someone shared in a message (see Broker.Download)
+type File struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Title      string `json:"title,omitempty"`
+	Filetype   string `json:"filetype,omitempty"`
+	Size       int    `json:"size,omitempty"`
+	URLPrivate string `json:"url_private,omitempty"`
+}
+
 type AttachmentField struct {
 	Title string `json:"title"`
 	Value string `json:"value"`
@@ -87,31 +115,49 @@ type User struct {
 		RealName           string `json:"real_name,omitempty"`
 		RealNameNormalized string `json:"real_name_normalized,omitempty"`
 	} `json:"profile,omitempty"`
-	RealName string      `json:"real_name,omitempty"`
-	Skype    string      `json:"skype,omitempty"`
-	Status   interface{} `json:"status,omitempty"`
-	Tz       string      `json:"tz,omitempty"`
-	TzLabel  string      `json:"tz_label,omitempty"`
-	TzOffset float64     `json:"tz_offset,omitempty"`
-	Extra    map[string]interface{}
+	RealName   string          `json:"real_name,omitempty"`
+	Skype      string          `json:"skype,omitempty"`
+	Status     interface{}     `json:"status,omitempty"`
+	Tz         string          `json:"tz,omitempty"`
+	TzLabel    string          `json:"tz_label,omitempty"`
+	TzOffset   float64         `json:"tz_offset,omitempty"`
+	TeamID     string          `json:"team_id,omitempty"`
+	IsStranger bool            `json:"is_stranger,omitempty"` // from another org, in a shared channel
+	Enterprise *EnterpriseUser `json:"enterprise_user,omitempty"`
+	Extra      map[string]interface{}
 }
 
 type Channel struct {
-	Created     float64  `json:"created,omitempty"`
-	Creator     string   `json:"creator,omitempty"`
-	ID          string   `json:"id,omitempty"`
-	IsArchived  bool     `json:"is_archived,omitempty"`
-	IsChannel   bool     `json:"is_channel,omitempty"`
-	IsGeneral   bool     `json:"is_general,omitempty"`
-	IsMember    bool     `json:"is_member,omitempty"`
-	LastRead    string   `json:"last_read,omitempty"`
-	Latest      Event    `json:"latest,omitempty"`
-	Members     []string `json:"members,omitempty"`
-	Name        string   `json:"name,omitempty"`
-	Purpose     Topic    `json:"purpose,omitempty"`
-	Topic       Topic    `json:"topic,omitempty"`
-	UnreadCount float64  `json:"unread_count,omitempty"`
-	Extra       map[string]interface{}
+	Created       float64  `json:"created,omitempty"`
+	Creator       string   `json:"creator,omitempty"`
+	ID            string   `json:"id,omitempty"`
+	IsArchived    bool     `json:"is_archived,omitempty"`
+	IsChannel     bool     `json:"is_channel,omitempty"`
+	IsGeneral     bool     `json:"is_general,omitempty"`
+	IsMember      bool     `json:"is_member,omitempty"`
+	LastRead      string   `json:"last_read,omitempty"`
+	Latest        Event    `json:"latest,omitempty"`
+	Members       []string `json:"members,omitempty"`
+	Name          string   `json:"name,omitempty"`
+	Purpose       Topic    `json:"purpose,omitempty"`
+	Topic         Topic    `json:"topic,omitempty"`
+	UnreadCount   float64  `json:"unread_count,omitempty"`
+	IsShared      bool     `json:"is_shared,omitempty"`
+	IsExtShared   bool     `json:"is_ext_shared,omitempty"` // shared with another org
+	IsOrgShared   bool     `json:"is_org_shared,omitempty"` // shared across the grid
+	SharedTeamIDs []string `json:"shared_team_ids,omitempty"`
+	ContextTeamID string   `json:"context_team_id,omitempty"`
+	Extra         map[string]interface{}
+}
+
+// String describes the user, like "@name (U123)", for logs and lua's print
+func (u User) String() string {
+	return `@` + u.Name + ` (` + u.ID + `)`
+}
+
+// String describes the channel, like "#name (C123)"
+func (c Channel) String() string {
+	return `#` + c.Name + ` (` + c.ID + `)`
 }
 
 type Group struct {
@@ -156,7 +202,7 @@ type Bot struct {
 }
 
 type Icon struct {
-	Image192     string `json:"image_132,omitempty"`
+	Image192     string `json:"image_192,omitempty"`
 	Image132     string `json:"image_132,omitempty"`
 	Image102     string `json:"image_102,omitempty"`
 	Image88      string `json:"image_88,omitempty"`
@@ -277,6 +323,8 @@ type Team struct {
 	Icon              Icon    `json:"icon,omitempty"`
 	ID                string  `json:"id,omitempty"`
 	MsgEditWindowMins float64 `json:"msg_edit_window_mins,omitempty"`
+	EnterpriseID      string  `json:"enterprise_id,omitempty"`
+	EnterpriseName    string  `json:"enterprise_name,omitempty"`
 	Name              string  `json:"name,omitempty"`
 	OverStorageLimit  bool    `json:"over_storage_limit,omitempty"`
 	Prefs             struct {
@@ -0,0 +1,271 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A bug report is a lot easier to act on with a picture of what lazlo was
+// doing at the time: whether it was connected, what was backed up, what was
+// registered to hear what, what was scheduled, and which hosts the fetcher
+// had given up on. A Snapshot is that picture, with nothing in it that
+// shouldn't be attached to a bug report: secrets in the config are masked,
+// and text from chat goes through the redactors.
+
+// config fields whose values never go in a snapshot
+var secretField = regexp.MustCompile(`(?i)token|secret|key|pw|password|webhook`)
+
+// Snapshot is the broker's state at a moment, for debugging
+type Snapshot struct {
+	Taken      time.Time         `json:"taken"`
+	Build      *BuildInfo        `json:"build"`
+	Runtime    *RuntimeStats     `json:"runtime"`
+	Connection SnapshotConn      `json:"connection"`
+	Queues     SnapshotQueues    `json:"queues"`
+	Handlers   []SnapshotHandler `json:"handlers"`
+	Timers     []SnapshotTimer   `json:"timers"`
+	Jobs       []SnapshotJob     `json:"jobs"`
+	Breakers   []SnapshotBreaker `json:"breakers"`
+	Deprecated []Deprecation     `json:"deprecations,omitempty"`
+	Config     map[string]string `json:"config"`
+}
+
+// SnapshotConn is how lazlo is talking to slack
+type SnapshotConn struct {
+	Adapter   string            `json:"adapter"`
+	Team      string            `json:"team,omitempty"`
+	Self      string            `json:"self,omitempty"`
+	Socket    bool              `json:"socket"` // RTM websocket open
+	LastEvent string            `json:"last_event,omitempty"`
+	Quiet     string            `json:"quiet,omitempty"` // since we last heard from slack
+	HandOver  bool              `json:"handing_over"`
+	Listeners map[string]string `json:"listeners,omitempty"` // name -> address
+}
+
+// SnapshotQueues is what's waiting to be done
+type SnapshotQueues struct {
+	Writes       int `json:"writes"`        // messages waiting to be sent
+	APIResponses int `json:"api_responses"` // RTM replies we're waiting on
+	Questions    int `json:"questions"`     // users with questions queued
+	Handlers     int `json:"handlers"`      // events waiting in handlers' channels
+}
+
+// SnapshotHandler is a registered callback
+type SnapshotHandler struct {
+	Type     string `json:"type"`
+	ID       string `json:"id"`
+	Module   string `json:"module,omitempty"`
+	Match    string `json:"match,omitempty"` // pattern, event key, path, topic...
+	Respond  bool   `json:"respond,omitempty"`
+	Channel  string `json:"channel,omitempty"`
+	Priority int    `json:"priority,omitempty"`
+	Queued   int    `json:"queued"`
+}
+
+// SnapshotTimer is a scheduled timer callback
+type SnapshotTimer struct {
+	ID       string    `json:"id"`
+	Schedule string    `json:"schedule"`
+	State    string    `json:"state"`
+	Next     time.Time `json:"next"`
+}
+
+// SnapshotJob is a job the job manager knows about
+type SnapshotJob struct {
+	ID       int       `json:"id"`
+	Name     string    `json:"name"`
+	Module   string    `json:"module"`
+	Status   string    `json:"status"`
+	Progress string    `json:"progress,omitempty"`
+	Err      string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitempty"`
+}
+
+// SnapshotBreaker is the fetcher's circuit breaker for one host
+type SnapshotBreaker struct {
+	Host      string    `json:"host"`
+	Open      bool      `json:"open"`
+	Failures  int       `json:"failures"`
+	OpenUntil time.Time `json:"open_until,omitempty"`
+}
+
+type byHandler []SnapshotHandler
+
+func (h byHandler) Len() int      { return len(h) }
+func (h byHandler) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h byHandler) Less(i, j int) bool {
+	if h[i].Type != h[j].Type {
+		return h[i].Type < h[j].Type
+	}
+	return h[i].ID < h[j].ID
+}
+
+type byTimerID []SnapshotTimer
+
+func (t byTimerID) Len() int           { return len(t) }
+func (t byTimerID) Swap(i, j int)      { t[i], t[j] = t[j], t[i] }
+func (t byTimerID) Less(i, j int) bool { return t[i].ID < t[j].ID }
+
+type byHost []SnapshotBreaker
+
+func (h byHost) Len() int           { return len(h) }
+func (h byHost) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h byHost) Less(i, j int) bool { return h[i].Host < h[j].Host }
+
+// Snapshot takes a (redacted) snapshot of the broker's state
+func (b *Broker) Snapshot() *Snapshot {
+	s := &Snapshot{
+		Taken:      time.Now(),
+		Build:      b.BuildInfo(),
+		Runtime:    b.RuntimeStats(),
+		Connection: b.snapshotConn(),
+		Deprecated: b.Deprecations(),
+		Config:     snapshotConfig(b.Config),
+	}
+	s.Queues.Writes = len(b.WriteThread.Chan)
+	s.Queues.APIResponses = len(b.ApiResponses)
+	if b.QuestionThread != nil {
+		s.Queues.Questions = len(b.QuestionThread.userdex)
+	}
+	for kind, callbacks := range b.cbIndex {
+		for _, cbInterface := range callbacks {
+			h := snapshotHandler(kind, cbInterface)
+			s.Queues.Handlers += h.Queued
+			s.Handlers = append(s.Handlers, h)
+			if t, ok := cbInterface.(*TimerCallback); ok {
+				s.Timers = append(s.Timers, SnapshotTimer{ID: t.ID, Schedule: t.Schedule, State: t.State, Next: t.Next})
+			}
+		}
+	}
+	sort.Sort(byHandler(s.Handlers))
+	sort.Sort(byTimerID(s.Timers))
+	if b.Jobs != nil {
+		for _, job := range b.Jobs.List() {
+			job.lock.Lock()
+			s.Jobs = append(s.Jobs, SnapshotJob{
+				ID:       job.ID,
+				Name:     job.Name,
+				Module:   job.Module,
+				Status:   job.Status,
+				Progress: b.Redact(job.Progressed),
+				Err:      b.Redact(job.Err),
+				Started:  job.Started,
+				Finished: job.Finished,
+			})
+			job.lock.Unlock()
+		}
+	}
+	if b.Fetcher != nil {
+		s.Breakers = b.Fetcher.breakers()
+	}
+	return s
+}
+
+func (b *Broker) snapshotHandler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set(`Content-Type`, `application/json`)
+	json.NewEncoder(res).Encode(b.Snapshot())
+}
+
+func (b *Broker) snapshotConn() SnapshotConn {
+	conn := SnapshotConn{
+		Adapter:   b.Config.Adapter,
+		Socket:    b.socket() != nil,
+		LastEvent: b.lastTs,
+		HandOver:  b.handingOver(),
+	}
+	if b.SlackMeta != nil {
+		conn.Team = b.SlackMeta.Team.Name
+		conn.Self = b.SlackMeta.Self.Name
+	}
+	if b.watchdog != nil {
+		conn.Quiet = (b.watchdog.quiet() / time.Second * time.Second).String()
+	}
+	b.listenLock.Lock()
+	for name, ln := range b.listeners {
+		if conn.Listeners == nil {
+			conn.Listeners = make(map[string]string)
+		}
+		conn.Listeners[name] = ln.Addr().String()
+	}
+	b.listenLock.Unlock()
+	return conn
+}
+
+// snapshotHandler describes a callback of the given kind (M, E, T...)
+func snapshotHandler(kind string, cbInterface interface{}) SnapshotHandler {
+	h := SnapshotHandler{Type: kind}
+	switch cb := cbInterface.(type) {
+	case *MessageCallback:
+		h.ID, h.Module, h.Match, h.Queued = cb.ID, cb.module, cb.Pattern, len(cb.Chan)
+		h.Respond, h.Channel, h.Priority = cb.Respond, cb.SlackChan, cb.Priority
+	case *EventCallback:
+		h.ID, h.Module, h.Match, h.Queued = cb.ID, cb.module, cb.Key+`=`+cb.Val, len(cb.Chan)
+	case *TimerCallback:
+		h.ID, h.Match, h.Queued = cb.ID, cb.Schedule, len(cb.Chan)
+	case *LinkCallback:
+		h.ID, h.Match, h.Queued = cb.ID, cb.Path, len(cb.Chan)
+	case *QuestionCallback:
+		// the question (and who it's for) is chat, and stays out of it
+		h.ID = cb.ID
+	case *TopicCallback:
+		h.ID, h.Match, h.Queued = cb.ID, cb.Topic, len(cb.Chan)
+	case *ActionCallback:
+		h.ID, h.Match, h.Queued = cb.ID, cb.Action, len(cb.Chan)
+	case *ModalCallback:
+		h.ID, h.Match, h.Queued = cb.ID, cb.Name, len(cb.Chan)
+	default:
+		h.ID = fmt.Sprintf("%T", cbInterface)
+	}
+	return h
+}
+
+// snapshotConfig is the config by environment variable, with anything that
+// looks like a secret (or a URL with a password in it) masked
+func snapshotConfig(c *Config) map[string]string {
+	config := make(map[string]string)
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Name
+		for _, part := range strings.Fields(field.Tag.Get(`env`)) {
+			if strings.HasPrefix(part, `key=`) {
+				key = strings.TrimPrefix(part, `key=`)
+			}
+		}
+		value := fmt.Sprint(v.Field(i).Interface())
+		switch {
+		case value == ``:
+		case secretField.MatchString(field.Name):
+			value = `[REDACTED]`
+		case strings.Contains(value, `@`) && strings.Contains(value, `://`):
+			value = `[REDACTED url]`
+		}
+		config[key] = value
+	}
+	return config
+}
+
+// breakers is the state of the circuit breaker for each host we've fetched
+// from
+func (f *Fetcher) breakers() []SnapshotBreaker {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var list []SnapshotBreaker
+	for name, host := range f.hosts {
+		breaker := SnapshotBreaker{Host: name, Failures: host.failures}
+		if time.Now().Before(host.openUntil) {
+			breaker.Open, breaker.OpenUntil = true, host.openUntil
+		}
+		list = append(list, breaker)
+	}
+	sort.Sort(byHost(list))
+	return list
+}
@@ -0,0 +1,112 @@
+package lib
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// When lazlo starts, every module starts at once, and slack starts sending
+// events straight away. A message that comes in before the module it's for
+// has registered its callbacks is dropped, or handled by a module that's only
+// half set up (the lua module, still loading its scripts, say). So events are
+// held until every module has called b.Ready() from its Run, and then handed
+// on in the order they came in. Replies to what lazlo sent aren't held, so a
+// module can talk to slack while it gets ready.
+//
+// Lazlo stops waiting after LAZLO_STARTUP_TIMEOUT seconds, or once
+// LAZLO_STARTUP_QUEUE events are being held, and logs the modules it gave up
+// on.
+
+// startupGate holds events until the modules are ready
+type startupGate struct {
+	lock    sync.Mutex
+	broker  *Broker
+	waiting map[string]bool // modules that haven't said they're ready
+	held    []map[string]interface{}
+	open    bool
+	started time.Time
+}
+
+func newStartupGate(b *Broker) *startupGate {
+	g := &startupGate{broker: b, waiting: make(map[string]bool), started: time.Now()}
+	for name := range b.Modules {
+		g.waiting[name] = true
+	}
+	return g
+}
+
+// hold keeps the event back if the modules aren't ready, returning false if
+// it should be handled now
+func (g *startupGate) hold(thingy map[string]interface{}) bool {
+	if g == nil {
+		return false
+	}
+	if _, isReply := thingy[`reply_to`]; isReply {
+		return false
+	}
+	g.lock.Lock()
+	if g.open {
+		g.lock.Unlock()
+		return false
+	}
+	g.held = append(g.held, thingy)
+	full := len(g.held) >= g.broker.Config.StartupQueue
+	g.lock.Unlock()
+	if full {
+		g.release(`there are too many events waiting`)
+	}
+	return true
+}
+
+// ready marks the module ready, and lets the events through if it was the
+// last one
+func (g *startupGate) ready(module string) {
+	g.lock.Lock()
+	delete(g.waiting, module)
+	done := len(g.waiting) == 0
+	g.lock.Unlock()
+	if done {
+		g.release(``)
+	}
+}
+
+// release hands on the events being held, and stops holding them. why is
+// why lazlo stopped waiting before every module was ready.
+func (g *startupGate) release(why string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.open {
+		return
+	}
+	g.open = true
+	if why != `` && len(g.waiting) > 0 {
+		var waiting []string
+		for name := range g.waiting {
+			waiting = append(waiting, name)
+		}
+		sort.Strings(waiting)
+		Logger.Warning(`Startup:: `, why, `, so I'm not waiting for `, strings.Join(waiting, `, `), ` to be ready`)
+	}
+	Logger.Info(`Startup:: ready after `, time.Since(g.started)/time.Millisecond*time.Millisecond, `; handing on `, len(g.held), ` events`)
+	// (holding the lock, so nothing newer gets in first)
+	for _, thingy := range g.held {
+		g.broker.dispatch(thingy)
+	}
+	g.held = nil
+}
+
+// Ready tells lazlo the calling module is ready for events: it's registered
+// its callbacks and loaded what it needs. Call it from the module's Run.
+func (b *Broker) Ready() {
+	module := b.callerModule()
+	if module == `` {
+		Logger.Warning(`Startup:: Ready was called from outside a module's Run`)
+		return
+	}
+	Logger.Debug(`Startup:: `, module, ` is ready`)
+	if b.startup != nil {
+		b.startup.ready(module)
+	}
+}
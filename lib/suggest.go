@@ -0,0 +1,212 @@
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strings"
+)
+
+// When someone says something to lazlo and nothing answers, it's usually a
+// typo. Lazlo compares what they said with the commands it knows (from the
+// modules' usage, and the patterns of every respond callback, so lua plugins
+// count too) and offers the closest: "did you mean ...?".
+//
+// LAZLO_SUGGEST_DISTANCE is how many typos it forgives (0 turns suggestions
+// off), and a suggestion has to be closer than a third of the command's
+// length, so short commands don't match everything. Suggestions can be
+// turned off in a channel (see SetSuggestions).
+
+// the most suggestions lazlo offers at once
+const maxSuggestions = 3
+
+// a command in a module's usage, like "%BOTNAME% triage claim <n>"
+var usageCommand = regexp.MustCompile(`"%BOTNAME%\s+([^"]+)"`)
+
+// a Command is something lazlo can be asked to do
+type Command struct {
+	Words  string // what has to be typed, folded ("triage claim")
+	Usage  string // how it's shown ("triage claim <n>")
+	Module string
+}
+
+// Commands lists the commands lazlo answers to: the ones in the modules'
+// usage, and the literal start of each respond callback's pattern
+func (b *Broker) Commands() []Command {
+	seen := make(map[string]bool)
+	var commands []Command
+	add := func(c Command) {
+		if c.Words == `` || seen[c.Words] {
+			return
+		}
+		seen[c.Words] = true
+		commands = append(commands, c)
+	}
+	for name, m := range b.Modules {
+		for _, match := range usageCommand.FindAllStringSubmatch(m.Usage, -1) {
+			usage := strings.TrimSpace(match[1])
+			add(Command{Words: commandWords(usage), Usage: usage, Module: name})
+		}
+	}
+	for _, callback := range b.sortedMessageCallbacks() {
+		if callback.Respond {
+			prefix := strings.TrimSpace(patternPrefix(callback.Pattern))
+			add(Command{Words: commandWords(prefix), Usage: prefix, Module: callback.module})
+		}
+	}
+	return commands
+}
+
+// commandWords is the part of a usage that's typed as-is: the words before
+// the first <argument> or [option]
+func commandWords(usage string) string {
+	var words []string
+	for _, word := range strings.Fields(usage) {
+		if strings.ContainsAny(word[:1], `<[(|"`) {
+			break
+		}
+		words = append(words, Fold(word))
+	}
+	return strings.Join(words, ` `)
+}
+
+// patternPrefix is the literal text a pattern starts with
+func patternPrefix(pattern string) string {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return ``
+	}
+	subs := []*syntax.Regexp{re}
+	if re.Op == syntax.OpConcat {
+		subs = re.Sub
+	}
+	var prefix []rune
+	for _, sub := range subs {
+		if sub.Op == syntax.OpBeginLine || sub.Op == syntax.OpBeginText {
+			continue
+		}
+		if sub.Op != syntax.OpLiteral {
+			break
+		}
+		if sub.Flags&syntax.FoldCase != 0 {
+			// (?i) literals come out in upper case
+			prefix = append(prefix, []rune(strings.ToLower(string(sub.Rune)))...)
+			continue
+		}
+		prefix = append(prefix, sub.Rune...)
+	}
+	return string(prefix)
+}
+
+// Suggest returns the usage of the commands closest to text, best first
+func (b *Broker) Suggest(text string, external bool) []string {
+	words := strings.Fields(Fold(text))
+	var found []suggestion
+	for _, c := range b.Commands() {
+		if external && !b.externalAllowed(c.Module) {
+			continue
+		}
+		want := strings.Fields(c.Words)
+		if len(words) < len(want) {
+			continue
+		}
+		// compare as many words as the command has
+		said := strings.Join(words[:len(want)], ` `)
+		distance := editDistance(said, c.Words)
+		if distance <= b.Config.SuggestDistance && distance*3 < len([]rune(c.Words)) {
+			found = append(found, suggestion{c.Usage, distance})
+		}
+	}
+	sort.Sort(byDistance(found))
+	var usages []string
+	for i := 0; i < len(found) && i < maxSuggestions; i++ {
+		usages = append(usages, found[i].usage)
+	}
+	return usages
+}
+
+type suggestion struct {
+	usage    string
+	distance int
+}
+
+type byDistance []suggestion
+
+func (s byDistance) Len() int      { return len(s) }
+func (s byDistance) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byDistance) Less(i, j int) bool {
+	if s[i].distance != s[j].distance {
+		return s[i].distance < s[j].distance
+	}
+	return s[i].usage < s[j].usage
+}
+
+// editDistance is the Damerau-Levenshtein distance between a and b: how many
+// characters have to be added, removed, changed or swapped to get from one to
+// the other
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(min(rows[i-1][j]+1, rows[i][j-1]+1), rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
+
+// suggest answers a message addressed to lazlo that nothing else answered
+func (b *Broker) suggest(message *Event, text string) {
+	if b.Config.SuggestDistance <= 0 || message.User == `` || message.User == b.SlackMeta.Self.ID || !b.SuggestionsOn(message.Channel) {
+		return
+	}
+	usages := b.Suggest(text, message.External)
+	if usages == nil {
+		return
+	}
+	for i, usage := range usages {
+		usages[i] = "`" + b.Config.Name + ` ` + usage + "`"
+	}
+	last := len(usages) - 1
+	did := usages[last]
+	if last > 0 {
+		did = strings.Join(usages[:last], `, `) + ` or ` + did
+	}
+	message.Reply(fmt.Sprintf("I don't know that one. Did you mean %s?", did))
+}
+
+func suggestOffKey(channel string) string {
+	return `suggest:off:` + channel
+}
+
+// SuggestionsOn says whether lazlo suggests commands in the channel
+func (b *Broker) SuggestionsOn(channel string) bool {
+	off, _ := b.Brain.Get(suggestOffKey(channel))
+	return len(off) == 0
+}
+
+// SetSuggestions turns suggestions on or off in the channel
+func (b *Broker) SetSuggestions(channel string, on bool) error {
+	if on == b.SuggestionsOn(channel) {
+		return nil
+	}
+	if on {
+		return b.Brain.Delete(suggestOffKey(channel))
+	}
+	return b.Brain.Set(suggestOffKey(channel), []byte(`1`))
+}
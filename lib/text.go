@@ -0,0 +1,265 @@
+package lib
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// People post emoji, accents and CJK, and cutting or lining those up by the
+// byte (or even by the rune) mangles them: half a flag, an accent on its own,
+// a table column knocked out of line by a wide character. These work on
+// graphemes (what a reader would call a character) and display columns
+// instead. They only approximate Unicode's rules (UAX #29 and #11), without
+// the tables, but they get emoji sequences, combining marks and wide
+// characters right.
+
+// NextGrapheme returns the length in bytes of the grapheme s starts with
+func NextGrapheme(s string) int {
+	if s == `` {
+		return 0
+	}
+	r, n := utf8.DecodeRuneInString(s)
+	if r == '\r' && strings.HasPrefix(s[n:], "\n") {
+		return n + 1
+	}
+	if r < unicode.MaxASCII && (len(s) == n || s[n] < utf8.RuneSelf) {
+		return n // the usual case
+	}
+	regional := isRegional(r)
+	joined := false
+	for n < len(s) {
+		next, size := utf8.DecodeRuneInString(s[n:])
+		switch {
+		case isExtend(next):
+		case joined && isPictographic(next):
+		case regional && isRegional(next):
+			regional = false // flags are pairs
+		default:
+			return n
+		}
+		joined = next == '\u200d'
+		n += size
+	}
+	return n
+}
+
+// Graphemes splits s into its graphemes
+func Graphemes(s string) []string {
+	var graphemes []string
+	for s != `` {
+		n := NextGrapheme(s)
+		graphemes = append(graphemes, s[:n])
+		s = s[n:]
+	}
+	return graphemes
+}
+
+// TextWidth is how many columns s takes up in a monospaced font: two for
+// wide (CJK) characters and emoji, none for control characters, one for the
+// rest
+func TextWidth(s string) int {
+	width := 0
+	for s != `` {
+		n := NextGrapheme(s)
+		width += graphemeWidth(s[:n])
+		s = s[n:]
+	}
+	return width
+}
+
+func graphemeWidth(g string) int {
+	if IsEmoji(g) {
+		return 2
+	}
+	r, _ := utf8.DecodeRuneInString(g)
+	switch {
+	case unicode.IsControl(r) || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case unicode.Is(wideChars, r):
+		return 2
+	}
+	return 1
+}
+
+// TruncateWidth cuts s down to at most width columns, ending it with tail
+// (like "...") if anything had to go
+func TruncateWidth(s string, width int, tail string) string {
+	if TextWidth(s) <= width {
+		return s
+	}
+	width -= TextWidth(tail)
+	used, cut := 0, 0
+	for cut < len(s) {
+		n := NextGrapheme(s[cut:])
+		w := graphemeWidth(s[cut : cut+n])
+		if used+w > width {
+			break
+		}
+		used += w
+		cut += n
+	}
+	return s[:cut] + tail
+}
+
+// TruncateBytes cuts s down to at most max bytes without splitting a
+// grapheme, for limits that are counted in bytes
+func TruncateBytes(s string, max int) string {
+	return s[:graphemeBoundary(s, max)]
+}
+
+// graphemeBoundary is the last grapheme boundary in s at or before i
+func graphemeBoundary(s string, i int) int {
+	if i >= len(s) {
+		return len(s)
+	}
+	cut := 0
+	for cut < i {
+		n := NextGrapheme(s[cut:])
+		if cut+n > i {
+			break
+		}
+		cut += n
+	}
+	return cut
+}
+
+// PadRight pads s with spaces out to width columns
+func PadRight(s string, width int) string {
+	if pad := width - TextWidth(s); pad > 0 {
+		return s + strings.Repeat(` `, pad)
+	}
+	return s
+}
+
+// PadLeft pads s with spaces on the left out to width columns, for lining up
+// numbers
+func PadLeft(s string, width int) string {
+	if pad := width - TextWidth(s); pad > 0 {
+		return strings.Repeat(` `, pad) + s
+	}
+	return s
+}
+
+// slack sends the emoji people post as :shortcodes: (with a skin tone,
+// maybe), but bots and other clients send the characters
+var emojiShortcode = regexp.MustCompile(`:[a-z0-9_+'-]*[a-z][a-z0-9_+'-]*:(?::skin-tone-[2-6]:)?`)
+
+// IsEmoji says whether the grapheme is an emoji
+func IsEmoji(g string) bool {
+	for _, r := range g {
+		if r == '\ufe0f' || r == '\u20e3' || isRegional(r) || unicode.Is(emojiPresentation, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// CountEmoji counts the emoji in s, shortcodes and characters both
+func CountEmoji(s string) int {
+	count := 0
+	s = emojiShortcode.ReplaceAllStringFunc(s, func(string) string {
+		count++
+		return ``
+	})
+	for s != `` {
+		n := NextGrapheme(s)
+		if IsEmoji(s[:n]) {
+			count++
+		}
+		s = s[n:]
+	}
+	return count
+}
+
+// StripEmoji takes the emoji out of s, shortcodes and characters both
+func StripEmoji(s string) string {
+	s = emojiShortcode.ReplaceAllString(s, ``)
+	var out []byte
+	for s != `` {
+		n := NextGrapheme(s)
+		if !IsEmoji(s[:n]) {
+			out = append(out, s[:n]...)
+		}
+		s = s[n:]
+	}
+	return string(out)
+}
+
+// Fold case-folds s for matching, so text that differs only in case (by
+// Unicode's rules, not just ASCII's: "STRASSE" and "straße", "ΣΑΣ" and
+// "σας") folds to the same thing
+func Fold(s string) string {
+	var out []rune
+	for _, r := range s {
+		if r == 'ß' || r == 'ẞ' {
+			out = append(out, 's', 's')
+			continue
+		}
+		// the smallest rune that folds to r stands in for all of them
+		folded := r
+		for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+			if f < folded {
+				folded = f
+			}
+		}
+		out = append(out, unicode.ToLower(folded))
+	}
+	return string(out)
+}
+
+// isExtend says whether r belongs to the grapheme before it: combining
+// marks, joiners, variation selectors, skin tones and flag tags
+func isExtend(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc) ||
+		r == '\u200d' || r == '\u200c' ||
+		(r >= '\ufe00' && r <= '\ufe0f') ||
+		(r >= 0x1f3fb && r <= 0x1f3ff) ||
+		(r >= 0xe0020 && r <= 0xe007f)
+}
+
+func isRegional(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// isPictographic says whether r can follow a zero width joiner in an emoji
+// sequence
+func isPictographic(r rune) bool {
+	return unicode.Is(emojiPresentation, r) || (r >= 0x2600 && r <= 0x27bf) || (r >= 0x2190 && r <= 0x21ff)
+}
+
+// emojiPresentation are the characters that show as emoji without a
+// variation selector
+var emojiPresentation = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x231a, 0x231b, 1}, {0x23e9, 0x23ec, 1}, {0x23f0, 0x23f3, 3},
+		{0x25fd, 0x25fe, 1}, {0x2614, 0x2615, 1}, {0x2648, 0x2653, 1},
+		{0x267f, 0x2693, 20}, {0x26a1, 0x26aa, 9}, {0x26ab, 0x26bd, 18},
+		{0x26be, 0x26c4, 6}, {0x26c5, 0x26ce, 9}, {0x26d4, 0x26ea, 22},
+		{0x26f2, 0x26f3, 1}, {0x26f5, 0x26fa, 5}, {0x26fd, 0x2705, 8},
+		{0x270a, 0x270b, 1}, {0x2728, 0x274c, 36}, {0x274e, 0x2753, 5},
+		{0x2754, 0x2755, 1}, {0x2757, 0x2795, 62}, {0x2796, 0x2797, 1},
+		{0x27b0, 0x27bf, 15}, {0x2b1b, 0x2b1c, 1}, {0x2b50, 0x2b55, 5},
+	},
+	R32: []unicode.Range32{
+		{0x1f004, 0x1f0cf, 203}, {0x1f18e, 0x1f191, 3}, {0x1f192, 0x1f19a, 1},
+		{0x1f201, 0x1f21a, 25}, {0x1f22f, 0x1f232, 3}, {0x1f233, 0x1f236, 1},
+		{0x1f238, 0x1f23a, 1}, {0x1f250, 0x1f251, 1}, {0x1f300, 0x1f64f, 1},
+		{0x1f680, 0x1f6ff, 1}, {0x1f7e0, 0x1f7f0, 1}, {0x1f900, 0x1faff, 1},
+	},
+}
+
+// wideChars are the east asian wide and fullwidth characters
+var wideChars = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1}, {0x2e80, 0x303e, 1}, {0x3041, 0x33ff, 1},
+		{0x3400, 0x4dbf, 1}, {0x4e00, 0x9fff, 1}, {0xa000, 0xa4cf, 1},
+		{0xa960, 0xa97f, 1}, {0xac00, 0xd7a3, 1}, {0xf900, 0xfaff, 1},
+		{0xfe10, 0xfe19, 1}, {0xfe30, 0xfe6f, 1}, {0xff00, 0xff60, 1},
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x20000, 0x2fffd, 1}, {0x30000, 0x3fffd, 1},
+	},
+}
@@ -8,36 +8,43 @@ import (
 
 // verify the schedule and start the timer
 func (t *TimerCallback) Start() error {
-	expr := cronexpr.MustParse(t.Schedule)
-	if expr.Next(time.Now()).IsZero() {
+	expr, err := cronexpr.Parse(t.Schedule)
+	if err != nil || expr.Next(time.Now()).IsZero() {
 		Logger.Debug("invalid schedule", t.Schedule)
 		t.State = fmt.Sprintf("NOT Scheduled (invalid Schedule: %s)", t.Schedule)
-		return fmt.Errorf("invalid schedule", t.Schedule)
+		return fmt.Errorf("invalid schedule: %s", t.Schedule)
 	}
-	t.Next = expr.Next(time.Now())
-	dur := t.Next.Sub(time.Now())
-	if dur > 0 {
-		go t.Run(dur)
+	if t.stop == nil {
+		t.stop = make(chan bool, 1)
 	}
+	t.Next = expr.Next(time.Now())
+	go t.Run(expr)
 	return nil
 }
 
 // wait for the timer to expire, callback to the module, and reschedule
-func (t *TimerCallback) Run(dur time.Duration) {
-	Logger.Debug(`scheduling timer `, t.ID, ` for: `, t.Next)
-	timer := time.NewTimer(dur)
-	stop := false
-	for !stop {
+func (t *TimerCallback) Run(expr *cronexpr.Expression) {
+	for !t.Next.IsZero() {
+		Logger.Debug(`scheduling timer `, t.ID, ` for: `, t.Next)
+		timer := time.NewTimer(t.Next.Sub(time.Now()))
 		select {
 		case alarm := <-timer.C:
-			t.Chan <- alarm //signal the module
-			t.Start()       // (potentially) reschedule
-		case stop = <-t.stop:
-			stop = true
+			select {
+			case t.Chan <- alarm: //signal the module
+			case <-t.stop:
+				return
+			}
+			t.Next = expr.Next(time.Now()) // (potentially) reschedule
+		case <-t.stop:
+			timer.Stop()
+			return
 		}
 	}
 }
 
 func (t *TimerCallback) Stop() {
-	t.stop <- true
+	select {
+	case t.stop <- true:
+	default:
+	}
 }
@@ -0,0 +1,173 @@
+package lib
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// certStore holds the TLS certificates we serve, indexed by hostname so we
+// can pick the right one for each client (SNI). It can be re-loaded from disk
+// at runtime, so renewed certificates (e.g. from certbot) get picked up
+// without a restart. Hostnames listed in LAZLO_TLS_AUTOCERT that none of
+// those certificates cover get theirs from Let's Encrypt instead.
+type certStore struct {
+	sync.RWMutex
+	pairs    []string // the configured cert:key file pairs
+	byName   map[string]*tls.Certificate
+	fallback *tls.Certificate
+	autocert *autocert.Manager // nil unless LAZLO_TLS_AUTOCERT is set
+}
+
+func newCertStore(pairs []string) (*certStore, error) {
+	cs := &certStore{pairs: pairs}
+	return cs, cs.load()
+}
+
+// newAutocert returns a manager that gets (and renews) certificates from
+// Let's Encrypt for the given hosts, and no others, keeping them in cacheDir
+func newAutocert(hosts []string, cacheDir string, email string) *autocert.Manager {
+	return &autocert.Manager{
+		Prompt:     autocert.AcceptTOS,
+		HostPolicy: autocert.HostWhitelist(hosts...),
+		Cache:      autocert.DirCache(cacheDir),
+		Email:      email,
+	}
+}
+
+// load (re)reads every configured certificate from disk
+func (cs *certStore) load() error {
+	byName := make(map[string]*tls.Certificate)
+	var fallback *tls.Certificate
+	for _, pair := range cs.pairs {
+		files := strings.SplitN(pair, `:`, 2)
+		if len(files) != 2 {
+			return fmt.Errorf("tls cert %s should look like cert.pem:key.pem", pair)
+		}
+		cert, err := tls.LoadX509KeyPair(files[0], files[1])
+		if err != nil {
+			return fmt.Errorf("couldn't load tls cert %s: %v", pair, err)
+		}
+		leaf, err := x509.ParseCertificate(cert.Certificate[0])
+		if err != nil {
+			return err
+		}
+		if fallback == nil {
+			fallback = &cert
+		}
+		names := append([]string{leaf.Subject.CommonName}, leaf.DNSNames...)
+		for _, name := range names {
+			if name != `` {
+				byName[strings.ToLower(name)] = &cert
+			}
+		}
+	}
+	cs.Lock()
+	cs.byName = byName
+	cs.fallback = fallback
+	cs.Unlock()
+	Logger.Info(`loaded `, len(cs.pairs), ` tls certificate(s)`)
+	return nil
+}
+
+// getCertificate picks a certificate for the requested server name,
+// including wildcard certificates, then asks Let's Encrypt (if autocert's on)
+func (cs *certStore) getCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cs.RLock()
+	defer cs.RUnlock()
+	name := strings.ToLower(hello.ServerName)
+	if cert, ok := cs.byName[name]; ok {
+		return cert, nil
+	}
+	if i := strings.Index(name, `.`); i > 0 {
+		if cert, ok := cs.byName[`*`+name[i:]]; ok {
+			return cert, nil
+		}
+	}
+	if cs.autocert != nil {
+		// (it turns down hosts that aren't in LAZLO_TLS_AUTOCERT)
+		if cert, err := cs.autocert.GetCertificate(hello); err == nil || cs.fallback == nil {
+			return cert, err
+		}
+	}
+	if cs.fallback == nil {
+		return nil, fmt.Errorf("no tls certificates loaded")
+	}
+	return cs.fallback, nil
+}
+
+// tlsConfig builds the server's tls config, asking for (but not requiring)
+// client certificates if a client CA is configured so the ClientCert()
+// authenticator has something to check.
+func (b *Broker) tlsConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		GetCertificate: b.certs.getCertificate,
+		MinVersion:     tls.VersionTLS12,
+	}
+	if b.certs.autocert != nil {
+		// so Let's Encrypt can check we're who we say with tls-alpn-01
+		config.NextProtos = []string{`http/1.1`, acme.ALPNProto}
+	}
+	if b.Config.TLSClientCA != `` {
+		pem, err := ioutil.ReadFile(b.Config.TLSClientCA)
+		if err != nil {
+			return nil, err
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", b.Config.TLSClientCA)
+		}
+		config.ClientCAs = pool
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return config, nil
+}
+
+// startHttps serves the given handler over TLS on LAZLO_TLS_PORT
+func (b *Broker) startHttps(handler http.Handler) error {
+	config, err := b.tlsConfig()
+	if err != nil {
+		return err
+	}
+	listener, err := b.listen(`https`, `:`+b.Config.TLSPort)
+	if err != nil {
+		return err
+	}
+	Logger.Info(`serving https on port `, b.Config.TLSPort)
+	return http.Serve(tls.NewListener(listener, config), handler)
+}
+
+// httpsRedirect sends plain http requests to the same URL on our https port
+func (b *Broker) httpsRedirect(res http.ResponseWriter, req *http.Request) {
+	host := req.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if b.Config.TLSPort != `443` {
+		host = net.JoinHostPort(host, b.Config.TLSPort)
+	}
+	http.Redirect(res, req, `https://`+host+req.URL.RequestURI(), http.StatusMovedPermanently)
+}
+
+// tlsEnabled says whether we serve https
+func (b *Broker) tlsEnabled() bool {
+	return b.Config.TLSCerts != `` || b.Config.TLSAutocert != ``
+}
+
+// ReloadCerts re-reads the TLS certificates from disk. main() calls this when
+// lazlo gets a SIGHUP.
+func (b *Broker) ReloadCerts() {
+	if b.certs == nil {
+		return
+	}
+	if err := b.certs.load(); err != nil {
+		Logger.Error(`keeping the old tls certificates: `, err)
+	}
+}
@@ -0,0 +1,195 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// headers we never keep a copy of
+var secretHeaders = []string{
+	`Authorization`,
+	`Cookie`,
+	`X-Slack-Signature`,
+	`X-Hub-Signature`,
+	`X-Hub-Signature-256`,
+}
+
+// A Trace is a (redacted) copy of an inbound webhook request and the response
+// we sent back
+type Trace struct {
+	ID       int
+	Time     time.Time
+	Method   string
+	Path     string
+	Query    string // (redacted, like the body)
+	Remote   string
+	Header   http.Header
+	Body     string
+	Status   int
+	Response string
+}
+
+// Summary is a one-line description of the trace
+func (t *Trace) Summary() string {
+	return fmt.Sprintf("#%d %s %s %s -> %d (from %s)", t.ID, t.Time.Format(time.RFC3339), t.Method, t.URI(), t.Status, t.Remote)
+}
+
+// URI is the path the request was for, with its query
+func (t *Trace) URI() string {
+	if t.Query == `` {
+		return t.Path
+	}
+	return t.Path + `?` + t.Query
+}
+
+// traceQuery is the request's query, without the :name parameters pat adds
+// to it for the route
+func traceQuery(req *http.Request) string {
+	query := req.URL.Query()
+	for key := range query {
+		if strings.HasPrefix(key, `:`) {
+			delete(query, key)
+		}
+	}
+	return query.Encode()
+}
+
+// traceBuffer is a fixed-size ring of the most recent webhook traces
+type traceBuffer struct {
+	sync.Mutex
+	size   int
+	nextID int
+	traces []*Trace
+}
+
+func newTraceBuffer(size int) *traceBuffer {
+	return &traceBuffer{size: size, nextID: 1}
+}
+
+func (tb *traceBuffer) add(t *Trace) {
+	tb.Lock()
+	defer tb.Unlock()
+	t.ID = tb.nextID
+	tb.nextID++
+	tb.traces = append(tb.traces, t)
+	if len(tb.traces) > tb.size {
+		tb.traces = tb.traces[len(tb.traces)-tb.size:]
+	}
+}
+
+// Traces returns up to the last n webhook traces, newest first
+func (b *Broker) Traces(n int) []*Trace {
+	b.traces.Lock()
+	defer b.traces.Unlock()
+	var out []*Trace
+	for i := len(b.traces.traces) - 1; i >= 0 && len(out) < n; i-- {
+		out = append(out, b.traces.traces[i])
+	}
+	return out
+}
+
+// GetTrace returns the webhook trace with the given ID, or nil if it's
+// fallen out of the buffer
+func (b *Broker) GetTrace(id int) *Trace {
+	b.traces.Lock()
+	defer b.traces.Unlock()
+	for _, t := range b.traces.traces {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// traceRecorder captures the status and body of our response
+type traceRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (tr *traceRecorder) WriteHeader(status int) {
+	tr.status = status
+	tr.ResponseWriter.WriteHeader(status)
+}
+
+func (tr *traceRecorder) Write(p []byte) (int, error) {
+	if tr.body.Len() < 4096 {
+		tr.body.Write(p)
+	}
+	return tr.ResponseWriter.Write(p)
+}
+
+// traced records every request to the wrapped handler in the trace buffer
+func (b *Broker) traced(handler http.HandlerFunc) http.HandlerFunc {
+	if b.Config.TraceSize <= 0 {
+		return handler
+	}
+	return func(res http.ResponseWriter, req *http.Request) {
+		var body []byte
+		if req.Body != nil {
+			body, _ = ioutil.ReadAll(req.Body)
+			req.Body.Close()
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+		header := make(http.Header)
+		for key, vals := range req.Header {
+			header[key] = vals
+		}
+		for _, key := range secretHeaders {
+			if header.Get(key) != `` {
+				header.Set(key, `[REDACTED]`)
+			}
+		}
+		rec := &traceRecorder{ResponseWriter: res, status: http.StatusOK}
+		handler(rec, req)
+		b.traces.add(&Trace{
+			Time:     time.Now(),
+			Method:   req.Method,
+			Path:     req.URL.Path,
+			Query:    b.Redact(traceQuery(req)),
+			Remote:   req.RemoteAddr,
+			Header:   header,
+			Body:     b.Redact(string(body)),
+			Status:   rec.status,
+			Response: b.Redact(rec.body.String()),
+		})
+	}
+}
+
+// traceHandler serves captured traces as json so `lazlo replay` can fetch
+// them. It needs the debug token, like the other /debug endpoints.
+func (b *Broker) traceHandler(res http.ResponseWriter, req *http.Request) {
+	var id int
+	fmt.Sscanf(req.URL.Query().Get(":id"), "%d", &id)
+	t := b.GetTrace(id)
+	if t == nil {
+		http.NotFound(res, req)
+		return
+	}
+	res.Header().Set(`Content-Type`, `application/json`)
+	json.NewEncoder(res).Encode(t)
+}
+
+// ReplayTrace re-sends a captured request to the lazlo at baseURL. Headers
+// we redacted are dropped, so routes that check signatures will reject it.
+func ReplayTrace(t *Trace, baseURL string) (*http.Response, error) {
+	req, err := http.NewRequest(t.Method, strings.TrimSuffix(baseURL, `/`)+t.URI(), strings.NewReader(t.Body))
+	if err != nil {
+		return nil, err
+	}
+	for key, vals := range t.Header {
+		if len(vals) > 0 && vals[0] == `[REDACTED]` {
+			continue
+		}
+		req.Header[key] = vals
+	}
+	req.Header.Set(`X-Lazlo-Replay`, fmt.Sprintf("%d", t.ID))
+	return http.DefaultClient.Do(req)
+}
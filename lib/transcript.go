@@ -0,0 +1,160 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Transcript is a redacted copy of a conversation (a thread, or what lazlo
+// remembers of a channel), for attaching to postmortems and the like
+type Transcript struct {
+	Channel     string              `json:"channel"`
+	ChannelName string              `json:"channel_name"`
+	Thread      string              `json:"thread,omitempty"`
+	Exported    time.Time           `json:"exported"`
+	Messages    []TranscriptMessage `json:"messages"`
+}
+
+type TranscriptMessage struct {
+	Ts       string    `json:"ts"`
+	Time     time.Time `json:"time"`
+	User     string    `json:"user"`
+	UserName string    `json:"user_name"`
+	Text     string    `json:"text"`
+}
+
+// the most messages we'll put in one transcript
+const maxTranscript = 5000
+
+// ThreadTranscript fetches every message in a thread from slack. The
+// messages are redacted, and threads in sensitive channels can't be
+// exported.
+func (b *Broker) ThreadTranscript(channel string, ts string) (*Transcript, error) {
+	if b.History.IsSensitive(channel) {
+		return nil, fmt.Errorf("%s is flagged as sensitive", b.channelName(channel))
+	}
+	var msgs []Event
+	cursor := ``
+	for len(msgs) < maxTranscript {
+		req := ApiRequest{
+			URL:    `https://slack.com/api/conversations.replies`,
+			Values: make(url.Values),
+			Broker: b,
+		}
+		req.Values.Set(`channel`, channel)
+		req.Values.Set(`ts`, ts)
+		req.Values.Set(`limit`, `200`)
+		if cursor != `` {
+			req.Values.Set(`cursor`, cursor)
+		}
+		reply, err := MakeAPIReq(req)
+		if err != nil {
+			return nil, err
+		}
+		if !reply.Ok {
+			return nil, fmt.Errorf("couldn't fetch the thread: %s", reply.Error)
+		}
+		msgs = append(msgs, reply.Messages...)
+		if cursor = reply.ResponseMetadata.NextCursor; cursor == `` {
+			break
+		}
+	}
+	t := b.newTranscript(channel, msgs)
+	t.Thread = ts
+	return t, nil
+}
+
+// HistoryTranscript returns what lazlo remembers of the channel (see
+// History), which is already redacted
+func (b *Broker) HistoryTranscript(channel string) (*Transcript, error) {
+	if b.History.IsSensitive(channel) {
+		return nil, fmt.Errorf("%s is flagged as sensitive", b.channelName(channel))
+	}
+	return b.newTranscript(channel, b.History.Get(channel)), nil
+}
+
+func (b *Broker) newTranscript(channel string, msgs []Event) *Transcript {
+	t := &Transcript{
+		Channel:     channel,
+		ChannelName: b.channelName(channel),
+		Exported:    time.Now(),
+	}
+	for _, m := range msgs {
+		if m.Type != `` && m.Type != `message` {
+			continue
+		}
+		t.Messages = append(t.Messages, TranscriptMessage{
+			Ts:       m.Ts,
+			Time:     tsTime(m.Ts),
+			User:     m.User,
+			UserName: b.SlackMeta.GetUserName(m.User),
+			Text:     b.Redact(m.Text),
+		})
+	}
+	return t
+}
+
+func (b *Broker) channelName(channel string) string {
+	if c := b.SlackMeta.GetChannel(channel); c != nil {
+		return `#` + c.Name
+	}
+	return channel
+}
+
+// tsTime turns a slack timestamp into a time
+func tsTime(ts string) time.Time {
+	secs, err := strconv.ParseFloat(ts, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(int64(secs), 0)
+}
+
+// mentions and channel links, like <@U123> and <#C123|general>
+var slackRefPat = regexp.MustCompile(`<([@#])(\w+)(?:\|([^>]*))?>`)
+
+// Markdown renders the transcript as a markdown document
+func (t *Transcript) Markdown(b *Broker) string {
+	var md bytes.Buffer
+	what := `Conversation in ` + t.ChannelName
+	if t.Thread != `` {
+		what = `Thread in ` + t.ChannelName
+	}
+	fmt.Fprintf(&md, "# %s\n\n", what)
+	fmt.Fprintf(&md, "_Exported %s, %d messages. Sensitive values have been redacted._\n\n", t.Exported.UTC().Format(time.RFC1123), len(t.Messages))
+	for _, m := range t.Messages {
+		text := slackRefPat.ReplaceAllStringFunc(m.Text, func(ref string) string {
+			parts := slackRefPat.FindStringSubmatch(ref)
+			switch {
+			case parts[3] != ``:
+				return parts[1] + parts[3]
+			case parts[1] == `@`:
+				return `@` + b.SlackMeta.GetUserName(parts[2])
+			default:
+				return b.channelName(parts[2])
+			}
+		})
+		name := m.UserName
+		if name == `` {
+			name = `(unknown)`
+		}
+		fmt.Fprintf(&md, "**%s** %s\n\n", name, m.Time.UTC().Format(`2006-01-02 15:04:05 MST`))
+		for _, line := range strings.Split(text, "\n") {
+			fmt.Fprintf(&md, "> %s\n", line)
+		}
+		md.WriteString("\n")
+	}
+	return md.String()
+}
+
+// JSON renders the transcript as indented json
+func (t *Transcript) JSON() []byte {
+	data, _ := json.MarshalIndent(t, ``, `  `)
+	return data
+}
@@ -0,0 +1,180 @@
+package lib
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Some commands destroy things: dropping a stash, deleting a rotation. A
+// module makes one of them undoable by giving lazlo a way to put it back
+// (a compensating action) once it's done it:
+//
+//	b.Undoable(pm.Event, `stash drop`, name, 0, func() error {
+//		return stashSave(b, entry)
+//	})
+//
+// Until the ttl runs out (undoTTL, if it's 0), the person who ran the
+// command can say "!undo" in the same channel, and once they've confirmed,
+// lazlo runs the compensation. Each undo takes back the latest thing they did
+// there, so undoing again goes another step back. The command and its undo
+// both go in the audit log, the undo pointing back at the command's ID.
+// Undos are kept in memory, so a restart forgets them.
+
+const (
+	undoTTL  = 15 * time.Minute // how long a command can be undone, unless the module says otherwise
+	undoKeep = 20               // how many steps back someone can go, per channel
+)
+
+// An UndoAction is a destructive command that can be taken back
+type UndoAction struct {
+	ID      string
+	Module  string
+	User    string
+	Channel string
+	Action  string // what was done, like "stash drop"
+	Target  string // what it was done to
+	Done    time.Time
+	Expires time.Time
+	undo    func() error
+}
+
+// String describes the action, like "stash drop notes"
+func (u *UndoAction) String() string {
+	if u.Target == `` {
+		return u.Action
+	}
+	return u.Action + ` ` + u.Target
+}
+
+// undoStack keeps what people can undo
+type undoStack struct {
+	lock    sync.Mutex
+	actions map[string][]*UndoAction // by channel and user, oldest first
+}
+
+func newUndoStack() *undoStack {
+	return &undoStack{actions: make(map[string][]*UndoAction)}
+}
+
+func undoScope(user string, channel string) string {
+	return channel + "\x00" + user
+}
+
+// live is the actions in the scope that haven't expired. Call it holding the
+// lock.
+func (s *undoStack) live(scope string) []*UndoAction {
+	var live []*UndoAction
+	for _, u := range s.actions[scope] {
+		if time.Now().Before(u.Expires) {
+			live = append(live, u)
+		}
+	}
+	if live == nil {
+		delete(s.actions, scope)
+	} else {
+		s.actions[scope] = live
+	}
+	return live
+}
+
+func (s *undoStack) push(u *UndoAction) {
+	scope := undoScope(u.User, u.Channel)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	actions := append(s.live(scope), u)
+	if len(actions) > undoKeep {
+		actions = actions[len(actions)-undoKeep:]
+	}
+	s.actions[scope] = actions
+}
+
+// list is what the user can undo in the channel, latest first
+func (s *undoStack) list(user string, channel string) []*UndoAction {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	live := s.live(undoScope(user, channel))
+	list := make([]*UndoAction, len(live))
+	for i, u := range live {
+		list[len(live)-1-i] = u
+	}
+	return list
+}
+
+// take removes the action, returning false if it's expired or already gone
+func (s *undoStack) take(u *UndoAction) bool {
+	scope := undoScope(u.User, u.Channel)
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	actions := s.live(scope)
+	for i, a := range actions {
+		if a == u {
+			s.actions[scope] = append(actions[:i:i], actions[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Undoable records that the person who sent e did something that undo takes
+// back, for ttl (or undoTTL, if it's 0), and logs it in the audit log. It
+// returns the action's ID.
+func (b *Broker) Undoable(e *Event, action string, target string, ttl time.Duration, undo func() error) string {
+	if ttl <= 0 {
+		ttl = undoTTL
+	}
+	now := time.Now()
+	u := &UndoAction{
+		ID:      strconv.FormatInt(now.UnixNano(), 36),
+		Module:  b.callerModule(),
+		User:    e.User,
+		Channel: e.Channel,
+		Action:  action,
+		Target:  target,
+		Done:    now,
+		Expires: now.Add(ttl),
+		undo:    undo,
+	}
+	b.undo.push(u)
+	b.audit(AuditEntry{
+		Time:    now,
+		User:    u.User,
+		Module:  u.Module,
+		Action:  action,
+		Target:  target,
+		Detail:  `undoable until ` + u.Expires.Format(time.Kitchen),
+		Allowed: true,
+		ID:      u.ID,
+	})
+	return u.ID
+}
+
+// Undos returns what the user can undo in the channel, latest first
+func (b *Broker) Undos(user string, channel string) []*UndoAction {
+	return b.undo.list(user, channel)
+}
+
+// Undo runs the action's compensation, and logs it in the audit log as
+// undoing the action. An action can only be undone once (even if undoing it
+// fails), and only until it expires.
+func (b *Broker) Undo(u *UndoAction) error {
+	if !b.undo.take(u) {
+		return fmt.Errorf("it's too late to undo %s", u)
+	}
+	err := u.undo()
+	entry := AuditEntry{
+		Time:    time.Now(),
+		User:    u.User,
+		Module:  u.Module,
+		Action:  `undo ` + u.Action,
+		Target:  u.Target,
+		Allowed: true,
+		Undoes:  u.ID,
+	}
+	if err != nil {
+		entry.Detail = err.Error()
+	}
+	b.audit(entry)
+	return err
+}
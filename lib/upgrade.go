@@ -0,0 +1,333 @@
+package lib
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Lazlo can replace itself with a new binary without dropping requests or
+// events (send it a SIGUSR2 after you've put the new binary in place). The
+// old lazlo starts the new one, handing it the http listeners (so nobody sees
+// a refused connection). Once the new lazlo has connected to slack and
+// started its modules, the old one stops reading events, tells the new one
+// the last event it handled (so it isn't handled twice), finishes whatever it
+// was in the middle of, sends the new one the brain if it's in memory, and
+// exits. The new lazlo starts reading events once it has the brain. If the
+// new binary doesn't get going within LAZLO_UPGRADE_GRACE seconds, the old
+// one carries on as if nothing happened.
+
+// upgradeEnv tells the new lazlo which inherited file descriptors are which,
+// like "ready:3,proceed:4,http:5"
+const upgradeEnv = `LAZLO_UPGRADE_FDS`
+
+// upgrade states
+const (
+	notUpgrading int32 = iota
+	upgrading
+	handingOver
+)
+
+// a handover is what a new lazlo inherits from the old one
+type handover struct {
+	ready     *os.File // we say when we're ready on this
+	proceed   *os.File // the old lazlo sends the ts of its last event on this, then the brain
+	listeners map[string]net.Listener
+	cutoff    string
+	rest      *bufio.Reader // what's on proceed after the cutoff
+}
+
+// inheritHandover picks up whatever the old lazlo left us, if we're an
+// upgrade
+func inheritHandover() *handover {
+	spec := os.Getenv(upgradeEnv)
+	if spec == `` {
+		return nil
+	}
+	os.Unsetenv(upgradeEnv)
+	h := &handover{listeners: make(map[string]net.Listener)}
+	for _, item := range splitList(spec) {
+		parts := strings.SplitN(item, `:`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		fd, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		f := os.NewFile(uintptr(fd), parts[0])
+		switch parts[0] {
+		case `ready`:
+			h.ready = f
+		case `proceed`:
+			h.proceed = f
+		default:
+			ln, err := net.FileListener(f)
+			f.Close()
+			if err != nil {
+				Logger.Error(`Upgrade:: couldn't inherit the `, parts[0], ` listener: `, err)
+				continue
+			}
+			h.listeners[parts[0]] = ln
+		}
+	}
+	Logger.Info(`Upgrade:: taking over from the old lazlo`)
+	return h
+}
+
+// takeOver tells the old lazlo we're ready, and waits for it to stop reading
+// events
+func (h *handover) takeOver(grace time.Duration) {
+	if h.ready == nil || h.proceed == nil {
+		return
+	}
+	fmt.Fprintln(h.ready, `ready`)
+	h.ready.Close()
+	cutoff := make(chan string, 1)
+	go func() {
+		rest := bufio.NewReader(h.proceed)
+		line, _ := rest.ReadString('\n')
+		h.rest = rest
+		cutoff <- strings.TrimSpace(line)
+	}()
+	select {
+	case h.cutoff = <-cutoff:
+		Logger.Info(`Upgrade:: the old lazlo let go, we're on`)
+	case <-time.After(grace):
+		Logger.Error(`Upgrade:: the old lazlo didn't let go in time, carrying on anyway`)
+		h.proceed.Close()
+	}
+}
+
+// restoreBrain loads the old lazlo's in-memory brain into ours. The old lazlo
+// sends it once its handlers have finished, which can take it another grace
+// period after it let go.
+func (h *handover) restoreBrain(brain Brain, grace time.Duration) error {
+	if h.cutoff == `` {
+		// (it didn't let go, so we're not getting it)
+		return nil
+	}
+	defer h.proceed.Close()
+	dump := make(map[string][]byte)
+	decoded := make(chan error, 1)
+	go func() { decoded <- json.NewDecoder(h.rest).Decode(&dump) }()
+	select {
+	case err := <-decoded:
+		if err != nil {
+			return err
+		}
+	case <-time.After(2 * grace):
+		return fmt.Errorf("the old lazlo didn't send it in time")
+	}
+	for key, value := range dump {
+		if err := brain.Set(key, value); err != nil {
+			return err
+		}
+	}
+	Logger.Info(`Upgrade:: restored `, len(dump), ` brain keys`)
+	return nil
+}
+
+// seen tells us if the old lazlo already handled the event. Slack's
+// timestamps are fixed width, so comparing them as strings works.
+func (h *handover) seen(thingy map[string]interface{}) bool {
+	if h == nil || h.cutoff == `` {
+		return false
+	}
+	ts := eventTs(thingy)
+	return ts != `` && ts <= h.cutoff
+}
+
+func eventTs(thingy map[string]interface{}) string {
+	if ts, ok := thingy[`event_ts`].(string); ok {
+		return ts
+	}
+	ts, _ := thingy[`ts`].(string)
+	return ts
+}
+
+// listen returns the listener we inherited for name, or a new one on addr
+func (b *Broker) listen(name string, addr string) (net.Listener, error) {
+	b.listenLock.Lock()
+	defer b.listenLock.Unlock()
+	var ln net.Listener
+	if b.handover != nil {
+		ln = b.handover.listeners[name]
+		delete(b.handover.listeners, name)
+	}
+	if ln == nil {
+		var err error
+		if ln, err = net.Listen(`tcp`, addr); err != nil {
+			return nil, err
+		}
+	}
+	b.listeners[name] = ln
+	return ln, nil
+}
+
+// counted keeps track of requests in flight, so an upgrade can wait for them
+func (b *Broker) counted(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+		b.inflight.Add(1)
+		defer b.inflight.Done()
+		if atomic.LoadInt32(&b.upgrading) != notUpgrading {
+			// don't let keep-alives hold on to the old lazlo
+			res.Header().Set(`Connection`, `close`)
+		}
+		handler.ServeHTTP(res, req)
+	})
+}
+
+// dumpBrain writes every key in the brain to w (decrypted, if the brain's
+// encrypted, which is why it goes over a pipe and never touches the disk)
+func (b *Broker) dumpBrain(w io.Writer) error {
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		return err
+	}
+	dump := make(map[string][]byte)
+	for _, key := range keys {
+		if value, err := b.Brain.Get(key); err == nil {
+			dump[key] = value
+		}
+	}
+	return json.NewEncoder(w).Encode(dump)
+}
+
+// Upgrade starts a new copy of lazlo's binary and hands everything over to
+// it. If it returns nil, the new lazlo is running, and this one's done
+// everything it was in the middle of and should exit. Otherwise this one's
+// still in charge.
+func (b *Broker) Upgrade() error {
+	if !atomic.CompareAndSwapInt32(&b.upgrading, notUpgrading, upgrading) {
+		return fmt.Errorf("already upgrading")
+	}
+	grace := time.Duration(b.Config.UpgradeGrace) * time.Second
+	abort := func(err error) error {
+		atomic.StoreInt32(&b.upgrading, notUpgrading)
+		return fmt.Errorf("upgrade failed: %v", err)
+	}
+	path, err := exec.LookPath(os.Args[0])
+	if err != nil {
+		return abort(err)
+	}
+
+	readyR, readyW, err := os.Pipe()
+	if err != nil {
+		return abort(err)
+	}
+	defer readyR.Close()
+	proceedR, proceedW, err := os.Pipe()
+	if err != nil {
+		readyW.Close()
+		return abort(err)
+	}
+	defer proceedW.Close()
+	files := []*os.File{readyW, proceedR}
+	spec := []string{`ready:3`, `proceed:4`}
+	closeFiles := func() {
+		for _, f := range files {
+			f.Close()
+		}
+	}
+	b.Metrics.stop() // so the new lazlo starts from our totals
+	b.listenLock.Lock()
+	for name, ln := range b.listeners {
+		tcp, ok := ln.(*net.TCPListener)
+		if !ok {
+			continue
+		}
+		f, err := tcp.File()
+		if err != nil {
+			b.listenLock.Unlock()
+			closeFiles()
+			return abort(err)
+		}
+		files = append(files, f)
+		spec = append(spec, fmt.Sprintf("%s:%d", name, len(files)+2))
+	}
+	b.listenLock.Unlock()
+
+	Logger.Info(`Upgrade:: starting `, path)
+	cmd := exec.Command(path, os.Args[1:]...)
+	cmd.Env = append(os.Environ(), upgradeEnv+`=`+strings.Join(spec, `,`))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.ExtraFiles = files
+	err = cmd.Start()
+	// the new lazlo has its own copies of these (and we need to close ours to
+	// notice if it dies)
+	closeFiles()
+	if err != nil {
+		return abort(err)
+	}
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	ready := make(chan bool, 1)
+	go func() {
+		buf := make([]byte, 16)
+		n, _ := readyR.Read(buf)
+		ready <- n > 0
+	}()
+	select {
+	case ok := <-ready:
+		if !ok {
+			return abort(fmt.Errorf("the new lazlo quit before it was ready"))
+		}
+	case err := <-exited:
+		return abort(fmt.Errorf("the new lazlo quit before it was ready: %v", err))
+	case <-time.After(grace):
+		cmd.Process.Kill()
+		return abort(fmt.Errorf("the new lazlo wasn't ready after %s", grace))
+	}
+
+	// stop reading events and taking requests, and tell the new lazlo where
+	// we left off
+	atomic.StoreInt32(&b.upgrading, handingOver)
+	b.socket().SetReadDeadline(time.Now())
+	select {
+	case <-b.readDone:
+	case <-time.After(grace):
+		Logger.Error(`Upgrade:: the read loop didn't stop`)
+	}
+	b.listenLock.Lock()
+	for _, ln := range b.listeners {
+		ln.Close()
+	}
+	b.listenLock.Unlock()
+	fmt.Fprintln(proceedW, b.lastTs)
+	Logger.Info(`Upgrade:: handed over to pid `, cmd.Process.Pid, `, finishing up`)
+
+	drained := make(chan bool)
+	go func() {
+		b.inflight.Wait()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+	case <-time.After(grace):
+		Logger.Error(`Upgrade:: gave up waiting for handlers to finish`)
+	}
+	// now nothing else is going to write to it, the brain can go
+	if b.Config.RedisURL == `` {
+		if err := b.dumpBrain(proceedW); err != nil {
+			Logger.Error(`Upgrade:: couldn't hand the brain over: `, err)
+		}
+	}
+	proceedW.Close()
+	return nil
+}
+
+// handingOver is true once we've stopped reading events for an upgrade
+func (b *Broker) handingOver() bool {
+	return atomic.LoadInt32(&b.upgrading) == handingOver
+}
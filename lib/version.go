@@ -0,0 +1,140 @@
+package lib
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+// These describe the build, and are set at build time with something like:
+//
+//	go build -ldflags "-X github.com/djosephsen/hustlebot/lib.Version=1.2.0 \
+//	  -X github.com/djosephsen/hustlebot/lib.BuildCommit=$(git rev-parse HEAD) \
+//	  -X github.com/djosephsen/hustlebot/lib.BuildDate=$(date -u +%Y-%m-%d)"
+var (
+	Version     = `dev`
+	BuildCommit = ``
+	BuildDate   = ``
+)
+
+// BuildInfo is what we know about the running lazlo: how it was built, what
+// it's running, and where its config came from
+type BuildInfo struct {
+	Version      string            `json:"version"`
+	Commit       string            `json:"commit,omitempty"`
+	BuildDate    string            `json:"build_date,omitempty"`
+	GoVersion    string            `json:"go_version"`
+	Started      time.Time         `json:"started"`
+	Uptime       string            `json:"uptime"`
+	Workspace    string            `json:"workspace"`
+	Modules      map[string]string `json:"modules"` // name -> version ("" if it doesn't say)
+	Plugins      map[string]string `json:"plugins"`
+	Brain        string            `json:"brain"`
+	ConfigSource string            `json:"config_source"`
+	Deprecations []Deprecation     `json:"deprecations,omitempty"`
+}
+
+// SetPluginVersion records the version of a plugin (a lua script, say) for
+// BuildInfo to report
+func (b *Broker) SetPluginVersion(name string, version string) {
+	b.pluginLock.Lock()
+	b.plugins[name] = version
+	b.pluginLock.Unlock()
+}
+
+// BuildInfo gathers up the build and runtime details
+func (b *Broker) BuildInfo() *BuildInfo {
+	bi := &BuildInfo{
+		Version:   Version,
+		Commit:    BuildCommit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+		Started:   b.started,
+		Uptime:    (time.Since(b.started) / time.Second * time.Second).String(),
+		Modules:   make(map[string]string),
+		Plugins:   make(map[string]string),
+		Brain:     `memory`,
+	}
+	if b.SlackMeta != nil {
+		bi.Workspace = fmt.Sprintf("%s (%s.slack.com)", b.SlackMeta.Team.Name, b.SlackMeta.Team.Domain)
+	}
+	for name, m := range b.Modules {
+		bi.Modules[name] = m.Version
+	}
+	b.pluginLock.Lock()
+	for name, version := range b.plugins {
+		bi.Plugins[name] = version
+	}
+	b.pluginLock.Unlock()
+	bi.Deprecations = b.Deprecations()
+	if b.Config.RedisURL != `` {
+		bi.Brain = `redis`
+	}
+	if _, ok := b.Brain.(*cryptBrain); ok {
+		bi.Brain += ` (encrypted)`
+	}
+	bi.ConfigSource = `environment`
+	if b.ConfigSync != nil {
+		bi.ConfigSource = fmt.Sprintf("git %s@%s", redactURL(b.Config.SyncRepo), short(b.ConfigCommit()))
+	}
+	return bi
+}
+
+// redactURL drops any credentials from a URL
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	u.User = nil
+	return u.String()
+}
+
+// String formats the build info for chat
+func (bi *BuildInfo) String() string {
+	version := bi.Version
+	if bi.Commit != `` {
+		version += ` (` + short(bi.Commit) + `)`
+	}
+	if bi.BuildDate != `` {
+		version += `, built ` + bi.BuildDate
+	}
+	lines := []string{
+		`lazlo ` + version + ` on ` + bi.GoVersion,
+		`up ` + bi.Uptime + ` (since ` + bi.Started.Format(time.RFC1123) + `)`,
+		`workspace: ` + bi.Workspace,
+		`brain: ` + bi.Brain,
+		`config: ` + bi.ConfigSource,
+		`modules: ` + listVersions(bi.Modules),
+	}
+	if len(bi.Plugins) > 0 {
+		lines = append(lines, `plugins: `+listVersions(bi.Plugins))
+	}
+	if len(bi.Deprecations) > 0 {
+		lines = append(lines, fmt.Sprintf("deprecated APIs: %d in use (see !modules)", len(bi.Deprecations)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func listVersions(m map[string]string) string {
+	var list []string
+	for name, version := range m {
+		if version != `` {
+			name += ` ` + version
+		}
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return strings.Join(list, `, `)
+}
+
+// healthHandler serves the build info as json
+func (b *Broker) healthHandler(res http.ResponseWriter, req *http.Request) {
+	res.Header().Set(`Content-Type`, `application/json`)
+	json.NewEncoder(res).Encode(b.BuildInfo())
+}
@@ -0,0 +1,145 @@
+package lib
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// The RTM websocket can die without telling us: slack stops sending, our
+// reads don't fail, and lazlo sits there looking fine while nobody hears
+// from it. The watchdog notices when nothing has come in (from the websocket
+// or the Events API) for LAZLO_WATCHDOG_MINUTES, and pings slack to see if
+// it's just quiet. If the ping goes unanswered, it reconnects, and tells
+// LAZLO_WATCHDOG_WEBHOOK (anything that takes a JSON {"text": "..."} post,
+// like a slack incoming webhook in another workspace, or an SMS gateway)
+// what happened, since the chat lazlo lives in isn't much use for that.
+// When the websocket does fail a read, the read loop doesn't wait for the
+// watchdog: it reconnects the same way, straight away.
+
+// how long to wait for slack to answer a ping
+const watchdogPingTimeout = 30 * time.Second
+
+// how long the read loop waits to try again when it couldn't reconnect
+const watchdogRetry = 10 * time.Second
+
+// the watchdog keeps track of when we last heard from slack
+type watchdog struct {
+	broker  *Broker
+	last    int64 // when we last heard from slack, in unix nanoseconds
+	lock    sync.Mutex
+	failing bool // we've already said we couldn't reconnect
+}
+
+func newWatchdog(b *Broker) *watchdog {
+	return &watchdog{broker: b, last: time.Now().UnixNano()}
+}
+
+// saw notes that we heard from slack
+func (w *watchdog) saw() {
+	atomic.StoreInt64(&w.last, time.Now().UnixNano())
+}
+
+// quiet is how long it's been since we heard from slack
+func (w *watchdog) quiet() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&w.last)))
+}
+
+// run checks on the connection every so often, until lazlo stops
+func (w *watchdog) run() {
+	window := time.Duration(w.broker.Config.WatchdogMinutes) * time.Minute
+	if window <= 0 {
+		return
+	}
+	Logger.Debug(`Watchdog:: reconnecting if slack's quiet for `, window)
+	for range time.Tick(window / 4) {
+		if w.broker.handingOver() {
+			return
+		}
+		if w.quiet() < window {
+			continue
+		}
+		if w.ping() {
+			continue
+		}
+		w.reconnect(w.broker.socket(), fmt.Sprintf("heard nothing from slack for %s, and it didn't answer a ping", w.quiet()/time.Second*time.Second))
+	}
+}
+
+// ping returns true if slack answers a ping
+func (w *watchdog) ping() bool {
+	reply := w.broker.Send(&Event{Type: `ping`})
+	select {
+	case <-reply:
+		return true
+	case <-time.After(watchdogPingTimeout):
+		return false
+	}
+}
+
+// reconnect swaps the websocket for a new one, unless someone already has
+// (the watchdog and the read loop can both notice old's dead), and says if
+// we're connected. The read loop is waiting on the old one, so closing it
+// sends the loop on to the new one. The slack metadata we started with is
+// kept (everything reads it without a lock, and who we are doesn't change);
+// users we haven't heard of are looked up as they turn up, same as ever.
+func (w *watchdog) reconnect(old *websocket.Conn, why string) bool {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+	b := w.broker
+	if b.socket() != old {
+		return true
+	}
+	Logger.Error(`Watchdog:: `, why, `; reconnecting`)
+	socket, _, err := b.getASocket()
+	if err != nil {
+		Logger.Error(`Watchdog:: couldn't reconnect: `, err)
+		if !w.failing {
+			w.failing = true
+			w.notify(fmt.Sprintf("%s, and I couldn't reconnect: %s. I'll keep trying.", why, err))
+		}
+		return false
+	}
+	b.socketLock.Lock()
+	b.Socket = socket
+	b.socketLock.Unlock()
+	old.Close()
+	w.saw()
+	w.failing = false
+	Logger.Info(`Watchdog:: reconnected`)
+	w.notify(fmt.Sprintf("%s, so I reconnected.", why))
+	return true
+}
+
+// notify posts the message to LAZLO_WATCHDOG_WEBHOOK
+func (w *watchdog) notify(text string) {
+	url := w.broker.Config.WatchdogWebhook
+	if url == `` {
+		return
+	}
+	if w.broker.SlackMeta != nil && w.broker.SlackMeta.Self.Name != `` {
+		text = w.broker.SlackMeta.Self.Name + `: ` + text
+	}
+	body, _ := json.Marshal(map[string]string{`text`: text})
+	reply, err := http.Post(url, `application/json`, bytes.NewReader(body))
+	if err != nil {
+		Logger.Error(`Watchdog:: couldn't notify the webhook: `, err)
+		return
+	}
+	reply.Body.Close()
+	if reply.StatusCode >= 300 {
+		Logger.Error(`Watchdog:: the webhook said `, reply.Status)
+	}
+}
+
+// socket is the websocket we're connected to slack with
+func (b *Broker) socket() *websocket.Conn {
+	b.socketLock.Lock()
+	defer b.socketLock.Unlock()
+	return b.Socket
+}
@@ -0,0 +1,341 @@
+package lib
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// A Workflow is a finite state machine for long-running processes (like an
+// access request that needs a manager's approval). Each time a workflow is
+// started, a WorkflowInstance is created and persisted in the brain, so
+// in-flight instances pick up where they left off when lazlo restarts.
+type Workflow struct {
+	Name   string
+	Start  string // the name of the first state
+	States map[string]*WorkflowState
+}
+
+// A WorkflowState is one state of a Workflow. OnEnter runs every time an
+// instance enters the state. An instance that enters a Final state is done
+// and removed from the brain.
+type WorkflowState struct {
+	OnEnter     func(b *Broker, wi *WorkflowInstance)
+	Transitions []*Transition
+	Final       bool
+}
+
+// A Transition moves an instance to another state. It fires on a message in
+// the instance's channel matching Message, a Reaction added to the
+// instance's MessageTs, or After the instance has been in the state for that
+// long (whichever is set). If Users is set, only those users can trigger it,
+// and if Guard is set it must return true. Action runs before the instance
+// enters the new state.
+type Transition struct {
+	To       string
+	Message  string
+	Reaction string
+	After    time.Duration
+	Users    []string
+	Guard    func(wi *WorkflowInstance, t *WorkflowTrigger) bool
+	Action   func(b *Broker, wi *WorkflowInstance, t *WorkflowTrigger)
+	msgRegex *regexp.Regexp
+}
+
+// WorkflowTrigger describes whatever made a transition fire
+type WorkflowTrigger struct {
+	Kind     string // message, reaction or timer
+	User     string
+	Message  *Event
+	Match    []string
+	Reaction string
+}
+
+// A WorkflowInstance is one run of a workflow. Everything in it is persisted,
+// so keep anything you need to remember across restarts in Data.
+type WorkflowInstance struct {
+	ID        string
+	Workflow  string
+	State     string
+	Channel   string
+	User      string // whoever started the instance
+	MessageTs string // the message whose reactions we're watching
+	Data      map[string]string
+	Entered   time.Time
+	timer     *time.Timer
+}
+
+// A WorkflowEngine runs every instance of one workflow
+type WorkflowEngine struct {
+	broker    *Broker
+	workflow  *Workflow
+	instances map[string]*WorkflowInstance
+	start     chan *WorkflowInstance
+	timers    chan string
+}
+
+// Workflow validates the workflow, resumes any instances that were in flight
+// when lazlo last stopped, and starts processing triggers
+func (b *Broker) Workflow(wf *Workflow) (*WorkflowEngine, error) {
+	if _, ok := wf.States[wf.Start]; !ok {
+		return nil, fmt.Errorf("workflow %s: no such start state %s", wf.Name, wf.Start)
+	}
+	for name, state := range wf.States {
+		for _, t := range state.Transitions {
+			if _, ok := wf.States[t.To]; !ok {
+				return nil, fmt.Errorf("workflow %s: state %s has a transition to unknown state %s", wf.Name, name, t.To)
+			}
+			if t.Message != `` {
+				r, err := regexp.Compile(t.Message)
+				if err != nil {
+					return nil, fmt.Errorf("workflow %s: %v", wf.Name, err)
+				}
+				t.msgRegex = r
+			}
+		}
+	}
+	we := &WorkflowEngine{
+		broker:    b,
+		workflow:  wf,
+		instances: make(map[string]*WorkflowInstance),
+		start:     make(chan *WorkflowInstance),
+		timers:    make(chan string),
+	}
+	if err := we.resume(); err != nil {
+		return nil, err
+	}
+	b.HomeSection(wf.Name+` waiting on you`, 10, we.homeSection)
+	go we.run()
+	return we, nil
+}
+
+// Start creates a new instance of the workflow in the given channel on behalf
+// of the given user, and enters the start state
+func (we *WorkflowEngine) Start(channel string, user string, data map[string]string) *WorkflowInstance {
+	id := make([]byte, 8)
+	rand.Read(id)
+	if data == nil {
+		data = make(map[string]string)
+	}
+	wi := &WorkflowInstance{
+		ID:       hex.EncodeToString(id),
+		Workflow: we.workflow.Name,
+		Channel:  channel,
+		User:     user,
+		Data:     data,
+	}
+	we.start <- wi
+	return wi
+}
+
+func (we *WorkflowEngine) key(id string) string {
+	return fmt.Sprintf("workflow:%s:%s", we.workflow.Name, id)
+}
+
+// resume loads every persisted instance of this workflow from the brain
+func (we *WorkflowEngine) resume() error {
+	keys, err := we.broker.Brain.Keys()
+	if err != nil {
+		return err
+	}
+	prefix := we.key(``)
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		data, err := we.broker.Brain.Get(key)
+		if err != nil {
+			return err
+		}
+		wi := new(WorkflowInstance)
+		if err := json.Unmarshal(data, wi); err != nil {
+			Logger.Error(`Workflow:: dropping corrupt instance `, key, `: `, err)
+			we.broker.Brain.Delete(key)
+			continue
+		}
+		if _, ok := we.workflow.States[wi.State]; !ok {
+			Logger.Error(`Workflow:: dropping instance `, key, ` in unknown state `, wi.State)
+			we.broker.Brain.Delete(key)
+			continue
+		}
+		we.instances[wi.ID] = wi
+		we.schedule(wi)
+		Logger.Info(`Workflow:: resumed `, key, ` in state `, wi.State)
+	}
+	return nil
+}
+
+// run serializes every trigger for every instance of the workflow
+func (we *WorkflowEngine) run() {
+	messages := we.broker.MessageCallback(`.*`, false)
+	reactions := we.broker.EventCallback(`type`, `^reaction_added$`)
+	for {
+		select {
+		case wi := <-we.start:
+			we.instances[wi.ID] = wi
+			we.enter(wi, we.workflow.Start)
+		case pm := <-messages.Chan:
+			for _, wi := range we.instances {
+				if wi.Channel == pm.Event.Channel {
+					we.fire(wi, &WorkflowTrigger{Kind: `message`, User: pm.Event.User, Message: pm.Event})
+				}
+			}
+		case event := <-reactions.Chan:
+			item, _ := event[`item`].(map[string]interface{})
+			ts, _ := item[`ts`].(string)
+			user, _ := event[`user`].(string)
+			reaction, _ := event[`reaction`].(string)
+			for _, wi := range we.instances {
+				if ts != `` && wi.MessageTs == ts {
+					we.fire(wi, &WorkflowTrigger{Kind: `reaction`, User: user, Reaction: reaction})
+				}
+			}
+		case id := <-we.timers:
+			if wi, ok := we.instances[id]; ok {
+				we.fire(wi, &WorkflowTrigger{Kind: `timer`})
+			}
+		}
+	}
+}
+
+// fire looks for a transition out of the instance's current state that
+// matches the trigger, and follows the first one it finds
+func (we *WorkflowEngine) fire(wi *WorkflowInstance, trigger *WorkflowTrigger) {
+	state := we.workflow.States[wi.State]
+	for _, t := range state.Transitions {
+		switch trigger.Kind {
+		case `message`:
+			if t.msgRegex == nil || !t.msgRegex.MatchString(trigger.Message.Text) {
+				continue
+			}
+			trigger.Match = t.msgRegex.FindStringSubmatch(trigger.Message.Text)
+		case `reaction`:
+			if t.Reaction == `` || t.Reaction != trigger.Reaction {
+				continue
+			}
+		case `timer`:
+			if t.After == 0 || time.Since(wi.Entered) < t.After {
+				continue
+			}
+		}
+		if t.Users != nil && !contains(t.Users, trigger.User) {
+			continue
+		}
+		if t.Guard != nil && !t.Guard(wi, trigger) {
+			continue
+		}
+		Logger.Debug(`Workflow:: `, wi.Workflow, `:`, wi.ID, ` `, wi.State, ` -> `, t.To, ` (`, trigger.Kind, `)`)
+		if t.Action != nil {
+			t.Action(we.broker, wi, trigger)
+		}
+		we.enter(wi, t.To)
+		return
+	}
+}
+
+// enter moves the instance into the named state and persists it
+func (we *WorkflowEngine) enter(wi *WorkflowInstance, name string) {
+	if wi.timer != nil {
+		wi.timer.Stop()
+	}
+	wi.State = name
+	wi.Entered = time.Now()
+	state := we.workflow.States[name]
+	if state.OnEnter != nil {
+		state.OnEnter(we.broker, wi)
+	}
+	if state.Final {
+		delete(we.instances, wi.ID)
+		we.broker.Brain.Delete(we.key(wi.ID))
+		Logger.Debug(`Workflow:: `, wi.Workflow, `:`, wi.ID, ` finished in state `, name)
+		return
+	}
+	we.persist(wi)
+	we.schedule(wi)
+}
+
+func (we *WorkflowEngine) persist(wi *WorkflowInstance) {
+	data, err := json.Marshal(wi)
+	if err == nil {
+		err = we.broker.Brain.Set(we.key(wi.ID), data)
+	}
+	if err != nil {
+		Logger.Error(`Workflow:: couldn't save `, wi.Workflow, `:`, wi.ID, `: `, err)
+	}
+}
+
+// schedule sets a timer for the soonest timed transition out of the
+// instance's state. Timers that ran out while lazlo was down fire right away.
+func (we *WorkflowEngine) schedule(wi *WorkflowInstance) {
+	var soonest time.Duration = -1
+	for _, t := range we.workflow.States[wi.State].Transitions {
+		if t.After > 0 && (soonest < 0 || t.After < soonest) {
+			soonest = t.After
+		}
+	}
+	if soonest < 0 {
+		return
+	}
+	id := wi.ID
+	wi.timer = time.AfterFunc(wi.Entered.Add(soonest).Sub(time.Now()), func() {
+		we.timers <- id
+	})
+}
+
+// Instances returns the IDs and states of every in-flight instance
+func (we *WorkflowEngine) Instances() map[string]string {
+	out := make(map[string]string)
+	for id, wi := range we.instances {
+		out[id] = wi.State
+	}
+	return out
+}
+
+// homeSection lists the instances that are waiting for the user to move
+// them along, for their App Home tab. It reads the instances from the brain,
+// since the engine's goroutine owns the in-memory ones.
+func (we *WorkflowEngine) homeSection(user string) []Block {
+	keys, _ := we.broker.Brain.Keys()
+	var lines []string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, we.key(``)) {
+			continue
+		}
+		data, err := we.broker.Brain.Get(key)
+		wi := new(WorkflowInstance)
+		if err != nil || json.Unmarshal(data, wi) != nil {
+			continue
+		}
+		state, ok := we.workflow.States[wi.State]
+		if !ok {
+			continue
+		}
+		for _, t := range state.Transitions {
+			if contains(t.Users, user) {
+				where := fmt.Sprintf("<#%s>", wi.Channel)
+				if wi.MessageTs != `` {
+					where = fmt.Sprintf("<%s|%s>", we.broker.Permalink(wi.Channel, wi.MessageTs), where)
+				}
+				lines = append(lines, fmt.Sprintf("*%s* in %s (started by <@%s>, waiting since %s)", wi.State, where, wi.User, we.broker.Locale(user, ``).Day(wi.Entered)))
+				break
+			}
+		}
+	}
+	if lines == nil {
+		return nil
+	}
+	return []Block{TextBlock(strings.Join(lines, "\n"))}
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
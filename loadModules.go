@@ -12,6 +12,37 @@ func initModules(b *lazlo.Broker) error {
 	b.Register(modules.BrainTest)
 	b.Register(modules.Help)
 	b.Register(modules.LuaMod)
+	b.Register(modules.LuaGrants)
 	b.Register(modules.QuestionTest)
+	b.Register(modules.Expunge)
+	b.Register(modules.Webhooks)
+	b.Register(modules.BulkDM)
+	b.Register(modules.Triage)
+	b.Register(modules.Status)
+	b.Register(modules.Rotation)
+	b.Register(modules.Mood)
+	b.Register(modules.Threads)
+	b.Register(modules.Vacation)
+	b.Register(modules.ConfigSync)
+	b.Register(modules.Version)
+	b.Register(modules.Debug)
+	b.Register(modules.Transcripts)
+	b.Register(modules.Home)
+	b.Register(modules.Emoji)
+	b.Register(modules.Cleanup)
+	b.Register(modules.Costs)
+	b.Register(modules.Experiments)
+	b.Register(modules.Stash)
+	b.Register(modules.Ignore)
+	b.Register(modules.Delegate)
+	b.Register(modules.Cache)
+	b.Register(modules.Jobs)
+	b.Register(modules.SSH)
+	b.Register(modules.Plans)
+	b.Register(modules.Cloud)
+	b.Register(modules.Certs)
+	b.Register(modules.Uptime)
+	b.Register(modules.Suggestions)
+	b.Register(modules.Undo)
 	return nil
 }
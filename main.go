@@ -2,11 +2,40 @@ package main
 
 import (
 	lazlo "github.com/djosephsen/hustlebot/lib"
+	"os"
 	"os/signal"
 	"syscall"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == `replay` {
+		if err := replay(os.Args[2:]); err != nil {
+			lazlo.Logger.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == `plugin` {
+		if err := plugin(os.Args[2:]); err != nil {
+			lazlo.Logger.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == `brain` {
+		if err := brain(os.Args[2:]); err != nil {
+			lazlo.Logger.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == `doctor` {
+		if err := doctor(os.Args[2:]); err != nil {
+			lazlo.Logger.Error(err)
+			os.Exit(1)
+		}
+		return
+	}
 
 	lazlo.Logger.Debug(`creating broker`)
 	//make a broker
@@ -30,7 +59,7 @@ func main() {
 	//start the broker
 	go broker.Start()
 	// Loop
-	signal.Notify(broker.SigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	signal.Notify(broker.SigChan, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGUSR2)
 	stop := false
 	for !stop {
 		select {
@@ -38,6 +67,17 @@ func main() {
 			switch sig {
 			case syscall.SIGINT, syscall.SIGTERM:
 				stop = true
+			case syscall.SIGHUP:
+				broker.ReloadCerts()
+				broker.ReloadPrompts()
+				broker.ReloadRegistry()
+			case syscall.SIGUSR2:
+				// hand over to a new binary, and stop if that worked
+				if err := broker.Upgrade(); err != nil {
+					lazlo.Logger.Error(err)
+				} else {
+					stop = true
+				}
 			}
 		}
 	}
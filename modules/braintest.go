@@ -11,6 +11,7 @@ var BrainTest = &lazlo.Module{
 	Usage: `"%BOTNAME% brain [set|get] <key> <value>": tests lazlo's persistent storage (aka the brain)`,
 	Run: func(b *lazlo.Broker) {
 		callback := b.MessageCallback(`(?i:brain) ((?i)set|get) (\w+) *(\w*)$`, true)
+		b.Ready()
 		for {
 			msg := <-callback.Chan
 			brain := b.Brain
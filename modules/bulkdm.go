@@ -0,0 +1,124 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+)
+
+var BulkDM = &lazlo.Module{
+	Name: `BulkDM`,
+	Usage: `"%BOTNAME% bulkdm [dryrun] <@users #channels @groups>" followed by a template on the next lines : (admins only) DMs everyone a personalized copy of the template
+"%BOTNAME% bulkdm [dryrun] csv" followed by a template, with a csv file uploaded : same thing, with per-user fields from the csv (first column is the user)`,
+	Run: bulkDMRun,
+}
+
+// the biggest csv we'll read
+const bulkDMMaxCSV = 1 << 20
+
+func bulkDMRun(b *lazlo.Broker) {
+	cb := b.MessageCallback(`(?is)bulk *dm( dry *run)? +([^\n]+)\n(.+)`, true)
+	b.Ready()
+	for {
+		pm := <-cb.Chan
+		go bulkDM(b, pm)
+	}
+}
+
+func bulkDM(b *lazlo.Broker, pm lazlo.PatternMatch) {
+	if !b.IsAdmin(pm.Event.User) {
+		pm.Event.Reply(`Sorry, only admins can send bulk DMs`)
+		return
+	}
+	dryRun := pm.Match[1] != ``
+	bd, err := parseBulkDM(b, strings.TrimSpace(pm.Match[2]), pm.Match[3], pm.Event.Files)
+	if err != nil {
+		pm.Event.Reply(fmt.Sprintf("Sorry, %s", err))
+		return
+	}
+	msgs, err := bd.Render(b)
+	if err != nil {
+		pm.Event.Reply(fmt.Sprintf("Sorry, %s", err))
+		return
+	}
+	if len(msgs) == 0 {
+		pm.Event.Reply(`Sorry, I couldn't find anyone to send that to`)
+		return
+	}
+
+	dm := b.GetDM(pm.Event.User)
+	if dryRun {
+		preview := fmt.Sprintf("Dry run: I'd send %d messages.", len(msgs))
+		for i, msg := range msgs {
+			if i == 10 {
+				preview = fmt.Sprintf("%s\n...and %d more", preview, len(msgs)-i)
+				break
+			}
+			preview = fmt.Sprintf("%s\n*to %s:*\n%s", preview, b.SlackMeta.GetUserName(msg.User), msg.Text)
+		}
+		b.Say(preview, dm)
+		return
+	}
+
+	b.Say(fmt.Sprintf("Here's what %s will get:\n%s", b.SlackMeta.GetUserName(msgs[0].User), msgs[0].Text), dm)
+	if !b.Confirm(pm.Event.User, fmt.Sprintf("Send that to %d people?", len(msgs))) {
+		b.Say(`Ok, I won't send anything`, dm)
+		return
+	}
+	b.Say(fmt.Sprintf("Ok, sending %d messages. I'll let you know when I'm done.", len(msgs)), dm)
+	sent, err := bd.Send(b)
+	if err != nil {
+		b.Say(fmt.Sprintf("Sorry, something went wrong: %s", err), dm)
+		return
+	}
+	b.Say(fmt.Sprintf("Done. I sent %d of %d messages.", sent, len(msgs)), dm)
+}
+
+// parseBulkDM builds a BulkDM from the recipient line and the rest of the
+// message (which is the template), reading the recipients from the csv
+// uploaded with it if the recipient line is "csv"
+func parseBulkDM(b *lazlo.Broker, recipients string, rest string, files []lazlo.File) (*lazlo.BulkDM, error) {
+	bd := &lazlo.BulkDM{
+		Template: strings.TrimSpace(rest),
+		Fields:   make(map[string]map[string]string),
+	}
+	if strings.ToLower(recipients) != `csv` {
+		refs := strings.Fields(strings.Replace(recipients, `,`, ` `, -1))
+		ids, err := b.ResolveUsers(refs)
+		bd.Recipients = ids
+		return bd, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("I expected a csv file uploaded with that")
+	}
+	data, err := b.Download(files[0], bulkDMMaxCSV)
+	if err != nil {
+		return nil, fmt.Errorf("I couldn't download %s: %s", files[0].Name, err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("I couldn't read that csv: %s", err)
+	}
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("the csv needs a header row and at least one user")
+	}
+	header := rows[0]
+	for _, row := range rows[1:] {
+		ids, err := b.ResolveUsers([]string{strings.TrimSpace(row[0])})
+		if err != nil {
+			return nil, err
+		}
+		fields := make(map[string]string)
+		for i := 1; i < len(header) && i < len(row); i++ {
+			fields[strings.TrimSpace(header[i])] = strings.TrimSpace(row[i])
+		}
+		for _, id := range ids {
+			bd.Recipients = append(bd.Recipients, id)
+			bd.Fields[id] = fields
+		}
+	}
+	return bd, nil
+}
@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+)
+
+// Cache shows and busts the answers cached for expensive commands (see
+// lazlo.ResponseCache)
+var Cache = &lazlo.Module{
+	Name: `Cache`,
+	Usage: `"%BOTNAME% cache list" : lists the answers I'm remembering instead of asking again
+"%BOTNAME% cache bust [module or command]" : forgets them (all of them, or a module's, or the ones for commands starting with that)`,
+	Run: cacheRun,
+}
+
+func cacheRun(b *lazlo.Broker) {
+	list := b.MessageCallback(`(?i)cache list$`, true)
+	bust := b.MessageCallback(`(?i)cache bust\s*(.*)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-list.Chan:
+			entries := b.ResponseCache.Entries()
+			if entries == nil {
+				pm.Event.Respond(`I'm not remembering any answers`)
+				continue
+			}
+			l := b.Locale(pm.Event.User, pm.Event.Channel)
+			var lines []string
+			for _, entry := range entries {
+				command := entry.Command
+				if command == `` {
+					command = `(no arguments)`
+				}
+				lines = append(lines, fmt.Sprintf("%s: %s, from %s (for %s)", entry.Module, command, l.Ago(entry.Stored), entry.TTL))
+			}
+			pm.Event.Respond(strings.Join(lines, "\n"))
+		case pm := <-bust.Chan:
+			n := b.ResponseCache.Bust(pm.Match[1])
+			pm.Event.Reply(fmt.Sprintf("OK, I forgot %d answers", n))
+		}
+	}
+}
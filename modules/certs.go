@@ -0,0 +1,451 @@
+package modules
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"github.com/gorhill/cronexpr"
+	"io"
+	"io/ioutil"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Certs watches the TLS certificates of the hosts in LAZLO_CERT_DOMAINS, and
+// the registrations of their domains (with whois), and says so in
+// LAZLO_CERT_CHANNEL when one's about to run out. It checks on the
+// LAZLO_CERT_SCHEDULE schedule, and alerts once per threshold in
+// LAZLO_CERT_THRESHOLDS (days), remembering what it's said in the brain so
+// it doesn't nag.
+var Certs = &lazlo.Module{
+	Name: `Certs`,
+	Usage: `"!cert status" : shows when the certificates and domains I'm watching expire
+"!cert status <host[:port]>" : checks a host's certificate and domain right now`,
+	Run: certsRun,
+}
+
+// the default schedule: every day at 9am
+const certsScheduleDefault = `0 0 9 * * * *`
+
+// how long a TLS or whois server gets to answer
+const certsTimeout = 10 * time.Second
+
+// what's being checked
+const (
+	certKindTLS    = `certificate`
+	certKindDomain = `domain`
+)
+
+// A CertCheck is when a certificate or domain registration expires, as of
+// the last check
+type CertCheck struct {
+	Kind    string
+	Name    string // host[:port] for certificates, the domain for domains
+	Expires time.Time
+	Issuer  string // who issued the certificate, or the whois server
+	Problem string // why it isn't valid, even though it hasn't expired
+	Err     string // why we couldn't check
+	Checked time.Time
+	Alerted int // the threshold we last alerted for, in days (0 for none)
+}
+
+func certsRun(b *lazlo.Broker) {
+	schedule := b.Config.CertSchedule
+	if schedule == `` {
+		schedule = certsScheduleDefault
+	}
+	if _, err := cronexpr.Parse(schedule); err != nil {
+		lazlo.Logger.Error(`Certs:: bad LAZLO_CERT_SCHEDULE `, schedule, `: `, err)
+		return
+	}
+	thresholds := certsThresholds(b.Config.CertThresholds)
+	channel := strings.TrimPrefix(b.Config.CertChannel, `#`)
+	if channel == `` {
+		channel = b.DefaultChannel()
+	} else if c := b.SlackMeta.GetChannelByName(channel); c != nil {
+		channel = c.ID
+	}
+
+	status := b.MessageCallback(`(?i)^!certs? status$`, false)
+	check := b.MessageCallback(`(?i)^!certs? status <?(?:https?://)?([\w.-]+(?::\d+)?)(?:\|[^>]*)?>?/?$`, false)
+	timer := b.TimerCallback(schedule)
+	checked := make(chan []*CertCheck)
+	checking := false
+	b.Ready()
+	for {
+		select {
+		case <-timer.Chan:
+			if checking || b.Config.CertDomains == `` {
+				continue
+			}
+			checking = true
+			go func() { checked <- certsCheckAll(b) }()
+		case checks := <-checked:
+			checking = false
+			certsAlert(b, channel, thresholds, checks)
+		case pm := <-status.Chan:
+			checks := certsWatched(b)
+			if len(checks) == 0 {
+				pm.Event.Reply(`I'm not watching any certificates (see LAZLO_CERT_DOMAINS)`)
+				continue
+			}
+			pm.Event.RespondResult(certsResult(b, pm.Event, `Certificates and domains`, checks))
+		case pm := <-check.Chan:
+			go func(pm lazlo.PatternMatch) {
+				host := strings.ToLower(pm.Match[1])
+				checks := []*CertCheck{certCheckTLS(host)}
+				if domain := certRegisteredDomain(certHostname(host)); domain != `` {
+					checks = append(checks, certCheckDomain(domain))
+				}
+				pm.Event.RespondResult(certsResult(b, pm.Event, host, checks))
+			}(pm)
+		}
+	}
+}
+
+// certsThresholds parses LAZLO_CERT_THRESHOLDS, biggest first
+func certsThresholds(s string) []int {
+	var thresholds []int
+	for _, item := range strings.Split(s, `,`) {
+		if n, err := strconv.Atoi(strings.TrimSpace(item)); err == nil && n > 0 {
+			thresholds = append(thresholds, n)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(thresholds)))
+	return thresholds
+}
+
+// certsTargets returns the hosts in LAZLO_CERT_DOMAINS, and their domains
+func certsTargets(b *lazlo.Broker) (hosts []string, domains []string) {
+	seen := make(map[string]bool)
+	for _, item := range strings.Split(b.Config.CertDomains, `,`) {
+		host := strings.ToLower(strings.TrimSpace(item))
+		if host == `` {
+			continue
+		}
+		hosts = append(hosts, host)
+		if domain := certRegisteredDomain(certHostname(host)); domain != `` && !seen[domain] {
+			seen[domain] = true
+			domains = append(domains, domain)
+		}
+	}
+	return hosts, domains
+}
+
+// certsCheckAll checks everything we're watching, keeping what it finds
+// (and what we've already alerted about) in the brain
+func certsCheckAll(b *lazlo.Broker) []*CertCheck {
+	hosts, domains := certsTargets(b)
+	var checks []*CertCheck
+	for _, host := range hosts {
+		checks = append(checks, certCheckTLS(host))
+	}
+	for _, domain := range domains {
+		checks = append(checks, certCheckDomain(domain))
+	}
+	for _, check := range checks {
+		if old := certsLoad(b, check.Kind, check.Name); old != nil && old.Expires.Equal(check.Expires) {
+			check.Alerted = old.Alerted
+		}
+	}
+	return checks
+}
+
+// certsWatched returns what the last check found
+func certsWatched(b *lazlo.Broker) []*CertCheck {
+	hosts, domains := certsTargets(b)
+	var checks []*CertCheck
+	for _, host := range hosts {
+		check := certsLoad(b, certKindTLS, host)
+		if check == nil {
+			check = &CertCheck{Kind: certKindTLS, Name: host, Err: `not checked yet`}
+		}
+		checks = append(checks, check)
+	}
+	for _, domain := range domains {
+		check := certsLoad(b, certKindDomain, domain)
+		if check == nil {
+			check = &CertCheck{Kind: certKindDomain, Name: domain, Err: `not checked yet`}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func certsKey(kind string, name string) string {
+	return fmt.Sprintf("certs:%s:%s", kind, name)
+}
+
+func certsLoad(b *lazlo.Broker, kind string, name string) *CertCheck {
+	data, err := b.Brain.Get(certsKey(kind, name))
+	if err != nil || data == nil {
+		return nil
+	}
+	check := new(CertCheck)
+	if err := json.Unmarshal(data, check); err != nil {
+		return nil
+	}
+	return check
+}
+
+func certsSave(b *lazlo.Broker, check *CertCheck) {
+	data, _ := json.Marshal(check)
+	if err := b.Brain.Set(certsKey(check.Kind, check.Name), data); err != nil {
+		lazlo.Logger.Error(`Certs:: couldn't save `, check.Name, `: `, err)
+	}
+}
+
+// certsAlert says which certificates and domains have crossed a threshold
+// since we last said anything about them, and saves the checks
+func certsAlert(b *lazlo.Broker, channel string, thresholds []int, checks []*CertCheck) {
+	l := b.Locale(``, channel)
+	for _, check := range checks {
+		old := certsLoad(b, check.Kind, check.Name)
+		if check.Err != `` {
+			lazlo.Logger.Error(`Certs:: couldn't check the `, check.Kind, ` `, check.Name, `: `, check.Err)
+			// certificates we can't check are worth a mention (once); whois
+			// servers are flaky, so domains aren't
+			if check.Kind == certKindTLS && (old == nil || old.Err == ``) {
+				b.Say(fmt.Sprintf(":warning: I couldn't check the certificate for %s: %s", check.Name, check.Err), channel)
+			}
+			if old != nil {
+				// keep what we knew about it
+				old.Err, old.Checked = check.Err, check.Checked
+				check = old
+			}
+			certsSave(b, check)
+			continue
+		}
+		left := int(check.Expires.Sub(time.Now()).Hours() / 24)
+		crossed := 0
+		for _, threshold := range thresholds {
+			if left <= threshold {
+				crossed = threshold
+			}
+		}
+		if crossed > 0 && (check.Alerted == 0 || crossed < check.Alerted) {
+			check.Alerted = crossed
+			when := `expires in ` + l.Duration(check.Expires.Sub(time.Now()))
+			if left < 0 {
+				when = `expired ` + l.Ago(check.Expires)
+			}
+			what := `The certificate for ` + check.Name
+			if check.Kind == certKindDomain {
+				what = `The registration for ` + check.Name
+			}
+			b.Say(fmt.Sprintf(":warning: %s %s (%s)", what, when, l.Date(check.Expires)), channel)
+		}
+		certsSave(b, check)
+	}
+}
+
+// certsResult lists the checks
+func certsResult(b *lazlo.Broker, e *lazlo.Event, title string, checks []*CertCheck) *lazlo.Result {
+	l := b.Locale(e.User, e.Channel)
+	r := &lazlo.Result{Title: title, Status: lazlo.ResultOK}
+	r.Table = &lazlo.ResultTable{Header: []string{``, `Name`, `Expires`, `Left`, `Notes`}}
+	thresholds := certsThresholds(b.Config.CertThresholds)
+	warn := 0
+	if len(thresholds) > 0 {
+		warn = thresholds[0]
+	}
+	for _, check := range checks {
+		if check.Expires.IsZero() {
+			r.Status = lazlo.ResultWarning
+			r.Row(check.Kind, check.Name, ``, ``, check.Err)
+			continue
+		}
+		notes := check.Issuer
+		if check.Problem != `` {
+			notes = check.Problem
+			r.Status = lazlo.ResultWarning
+		}
+		if check.Err != `` {
+			notes = `last check failed: ` + check.Err
+		}
+		left := check.Expires.Sub(time.Now())
+		if left < time.Duration(warn)*24*time.Hour {
+			r.Status = lazlo.ResultWarning
+		}
+		if left < 0 {
+			r.Status = lazlo.ResultError
+		}
+		r.Row(check.Kind, check.Name, l.Date(check.Expires), l.Ago(check.Expires), notes)
+	}
+	return r
+}
+
+// certHostname is the host, without the port
+func certHostname(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		return h
+	}
+	return host
+}
+
+// certCheckTLS connects to the host (on 443, unless it says) and looks at
+// its certificate
+func certCheckTLS(host string) *CertCheck {
+	check := &CertCheck{Kind: certKindTLS, Name: host, Checked: time.Now()}
+	addr := host
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		addr = net.JoinHostPort(host, `443`)
+	}
+	// we want to know when it expires even if it isn't valid, so verify it
+	// ourselves
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: certsTimeout}, `tcp`, addr, &tls.Config{
+		ServerName:         certHostname(host),
+		InsecureSkipVerify: true,
+	})
+	if err != nil {
+		check.Err = err.Error()
+		return check
+	}
+	defer conn.Close()
+	state := conn.ConnectionState()
+	if len(state.PeerCertificates) == 0 {
+		check.Err = `no certificate`
+		return check
+	}
+	cert := state.PeerCertificates[0]
+	check.Expires = cert.NotAfter
+	check.Issuer = cert.Issuer.CommonName
+	if check.Issuer == `` && len(cert.Issuer.Organization) > 0 {
+		check.Issuer = cert.Issuer.Organization[0]
+	}
+	intermediates := x509.NewCertPool()
+	for _, c := range state.PeerCertificates[1:] {
+		intermediates.AddCert(c)
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{DNSName: certHostname(host), Intermediates: intermediates}); err != nil {
+		check.Problem = err.Error()
+	}
+	return check
+}
+
+// certRegisteredDomain guesses the domain that was registered for the host:
+// the last two labels, or three for the likes of example.co.uk
+func certRegisteredDomain(host string) string {
+	if net.ParseIP(host) != nil {
+		return ``
+	}
+	labels := strings.Split(strings.TrimSuffix(host, `.`), `.`)
+	if len(labels) < 2 {
+		return ``
+	}
+	n := 2
+	if len(labels) > 2 && len(labels[len(labels)-1]) == 2 && len(labels[len(labels)-2]) <= 3 {
+		n = 3
+	}
+	if len(labels) < n {
+		n = len(labels)
+	}
+	return strings.Join(labels[len(labels)-n:], `.`)
+}
+
+// whois servers, by TLD
+var (
+	whoisLock    sync.Mutex
+	whoisServers = make(map[string]string)
+)
+
+// the whois lines that say when a domain expires
+var whoisExpiry = regexp.MustCompile(`(?im)^\s*(?:registry expiry date|registrar registration expiration date|expiration date|expiry date|expire date|expires(?: on)?|paid-till|renewal date)\s*:\s*(.+?)\s*$`)
+
+// the formats whois servers give dates in
+var whoisLayouts = []string{
+	time.RFC3339,
+	`2006-01-02T15:04:05`,
+	`2006-01-02 15:04:05`,
+	`2006-01-02 15:04:05 MST`,
+	`2006-01-02`,
+	`2006.01.02`,
+	`2006/01/02`,
+	`02-Jan-2006`,
+	`02.01.2006`,
+	`January 2 2006`,
+}
+
+// certCheckDomain asks whois when the domain's registration expires
+func certCheckDomain(domain string) *CertCheck {
+	check := &CertCheck{Kind: certKindDomain, Name: domain, Checked: time.Now()}
+	tld := domain[strings.LastIndex(domain, `.`)+1:]
+	server, err := whoisServer(tld)
+	if err != nil {
+		check.Err = err.Error()
+		return check
+	}
+	check.Issuer = server
+	answer, err := whoisQuery(server, domain)
+	if err != nil {
+		check.Err = err.Error()
+		return check
+	}
+	for _, m := range whoisExpiry.FindAllStringSubmatch(answer, -1) {
+		if t, ok := whoisDate(m[1]); ok {
+			check.Expires = t
+			return check
+		}
+	}
+	check.Err = fmt.Sprintf("%s didn't say when it expires", server)
+	return check
+}
+
+func whoisDate(s string) (time.Time, bool) {
+	for _, layout := range whoisLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	// some servers put other things after the date
+	if fields := strings.Fields(s); len(fields) > 1 {
+		return whoisDate(fields[0])
+	}
+	return time.Time{}, false
+}
+
+// whoisServer asks IANA for the TLD's whois server
+func whoisServer(tld string) (string, error) {
+	whoisLock.Lock()
+	server, ok := whoisServers[tld]
+	whoisLock.Unlock()
+	if ok {
+		return server, nil
+	}
+	answer, err := whoisQuery(`whois.iana.org`, tld)
+	if err != nil {
+		return ``, err
+	}
+	m := regexp.MustCompile(`(?im)^(?:refer|whois):\s*(\S+)`).FindStringSubmatch(answer)
+	if m == nil {
+		return ``, fmt.Errorf("there's no whois server for .%s", tld)
+	}
+	whoisLock.Lock()
+	whoisServers[tld] = m[1]
+	whoisLock.Unlock()
+	return m[1], nil
+}
+
+func whoisQuery(server string, query string) (string, error) {
+	conn, err := net.DialTimeout(`tcp`, net.JoinHostPort(server, `43`), certsTimeout)
+	if err != nil {
+		return ``, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(certsTimeout))
+	if _, err := fmt.Fprintf(conn, "%s\r\n", query); err != nil {
+		return ``, err
+	}
+	answer, err := ioutil.ReadAll(io.LimitReader(conn, 1<<20))
+	if err != nil {
+		return ``, err
+	}
+	return string(answer), nil
+}
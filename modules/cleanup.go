@@ -0,0 +1,153 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cleanup finds channels nobody's using and, once an admin approves,
+// archives them
+var Cleanup = &lazlo.Module{
+	Name:  `Cleanup`,
+	Usage: `"%BOTNAME% channel cleanup [days]" : (admins only) lists the channels nobody's talked in for days (LAZLO_CLEANUP_DAYS by default), and offers to archive them`,
+	Run:   cleanupRun,
+}
+
+// a proposal lists at most this many channels
+const cleanupMax = 50
+
+// proposals nobody approves are dropped after this long
+const cleanupExpiry = 72 * time.Hour
+
+// a cleanupScan is a finished look for inactive channels
+type cleanupScan struct {
+	channel  string
+	user     string
+	days     int
+	inactive []lazlo.InactiveChannel
+	err      error
+}
+
+func cleanupRun(b *lazlo.Broker) {
+	admin := func(wi *lazlo.WorkflowInstance, t *lazlo.WorkflowTrigger) bool {
+		return b.IsAdmin(t.User)
+	}
+	engine, err := b.Approval(&lazlo.Approval{
+		Name:       `cleanup`,
+		Propose:    cleanupPropose,
+		CanApprove: admin,
+		CanReject:  admin,
+		Expiry:     cleanupExpiry,
+		Approved:   cleanupArchive,
+		Rejected: func(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+			b.Say(`OK, I'll leave those channels alone`, wi.Channel)
+		},
+		Expired: func(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+			b.Say(`Nobody approved archiving those channels, so I'm leaving them alone`, wi.Channel)
+		},
+	})
+	if err != nil {
+		lazlo.Logger.Error(`Cleanup:: `, err)
+		return
+	}
+
+	command := b.MessageCallback(`(?i)channel cleanup\s*(\d*)$`, true)
+	scans := make(chan cleanupScan)
+	scanning := false
+	b.Ready()
+	for {
+		select {
+		case pm := <-command.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can clean up channels`)
+				continue
+			}
+			if scanning {
+				pm.Event.Reply(`I'm already looking, hang on`)
+				continue
+			}
+			days := b.Config.CleanupDays
+			if pm.Match[1] != `` {
+				days, _ = strconv.Atoi(pm.Match[1])
+			}
+			if days < 1 {
+				pm.Event.Reply(`that's not much of a cleanup, try a number of days`)
+				continue
+			}
+			scanning = true
+			pm.Event.Reply(fmt.Sprintf("I'll look for channels nobody's talked in for %d days. That means reading every channel, so give me a few minutes", days))
+			go func(channel string, user string, days int) {
+				inactive, err := b.InactiveChannels(days, strings.Split(b.Config.CleanupExclude, `,`))
+				scans <- cleanupScan{channel, user, days, inactive, err}
+			}(pm.Event.Channel, pm.Event.User, days)
+		case scan := <-scans:
+			scanning = false
+			if scan.err != nil {
+				b.Say(fmt.Sprintf("I couldn't finish looking: %s", scan.err), scan.channel)
+				continue
+			}
+			if len(scan.inactive) == 0 {
+				b.Say(fmt.Sprintf("Every channel's had someone in it in the last %d days", scan.days), scan.channel)
+				continue
+			}
+			if len(scan.inactive) > cleanupMax {
+				b.Say(fmt.Sprintf("%d channels are quiet. Here are the quietest %d; ask again once they're sorted out for the rest", len(scan.inactive), cleanupMax), scan.channel)
+				scan.inactive = scan.inactive[:cleanupMax]
+			}
+			var ids, lines []string
+			l := b.Locale(scan.user, scan.channel)
+			for _, c := range scan.inactive {
+				ids = append(ids, c.ID)
+				lines = append(lines, fmt.Sprintf("<#%s> (%d members, quiet since %s)", c.ID, c.Members, l.Date(c.Last)))
+			}
+			engine.Start(scan.channel, scan.user, map[string]string{
+				`channels`: strings.Join(ids, `,`),
+				`list`:     strings.Join(lines, "\n"),
+				`days`:     strconv.Itoa(scan.days),
+			})
+		}
+	}
+}
+
+// cleanupPropose posts the proposal the admins approve or cancel by reacting
+func cleanupPropose(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+	reply := <-b.Say(fmt.Sprintf("Nobody's talked in these channels for %s days:\n%s\nAn admin can react with :%s: to archive them, or :%s: to leave them be. I'll forget about it in %d hours.",
+		wi.Data[`days`], wi.Data[`list`], lazlo.ApproveReaction, lazlo.RejectReaction, int(cleanupExpiry.Hours())), wi.Channel)
+	wi.MessageTs, _ = reply[`ts`].(string)
+	if wi.MessageTs == `` {
+		lazlo.Logger.Error(`Cleanup:: couldn't post the proposal in `, wi.Channel)
+	}
+}
+
+// cleanupArchive archives the proposed channels, except any that have woken
+// up since
+func cleanupArchive(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+	days, _ := strconv.Atoi(wi.Data[`days`])
+	cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+	var archived, skipped, failed []string
+	for _, id := range strings.Split(wi.Data[`channels`], `,`) {
+		if last, err := b.LastActivity(id); err == nil && last.After(cutoff) {
+			skipped = append(skipped, fmt.Sprintf("<#%s>", id))
+			continue
+		}
+		if err := b.ArchiveChannel(id); err != nil {
+			lazlo.Logger.Error(`Cleanup:: `, err)
+			failed = append(failed, fmt.Sprintf("<#%s>", id))
+			continue
+		}
+		lazlo.Logger.Info(`Cleanup:: archived `, id, ` for `, wi.User)
+		archived = append(archived, fmt.Sprintf("<#%s>", id))
+		time.Sleep(time.Second)
+	}
+	text := fmt.Sprintf("Archived %d channels.", len(archived))
+	if skipped != nil {
+		text += fmt.Sprintf(" Someone's talked in %s since, so I left them alone.", strings.Join(skipped, `, `))
+	}
+	if failed != nil {
+		text += fmt.Sprintf(" I couldn't archive %s (is the bot allowed to?)", strings.Join(failed, `, `))
+	}
+	b.Say(text, wi.Channel)
+}
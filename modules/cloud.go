@@ -0,0 +1,369 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cloud answers read-only questions about cloud accounts from chat: what's
+// running, how big the autoscaling groups are, what's been deployed lately,
+// and what it's costing. Accounts come from LAZLO_CLOUD_ACCOUNTS, and from
+// "account" entities in the registry:
+//
+//	"account": {"prod": {"provider": "aws", "region": "us-east-1", "assume": "arn:aws:iam::123456789012:role/lazlo-readonly", "role": "oncall"}}
+//
+// You need the account's role (or LAZLO_CLOUD_ROLE, or to be an admin) to
+// ask about it. Answers are cached for LAZLO_CLOUD_CACHE minutes, per
+// account and question; say "fresh" at the end to skip the cache.
+var Cloud = &lazlo.Module{
+	Name: `Cloud`,
+	Usage: `"%BOTNAME% cloud accounts" : lists the cloud accounts I can look at
+"%BOTNAME% cloud <account> instances [filter]" : lists the account's instances (whose name or ID contains filter)
+"%BOTNAME% cloud <account> groups [filter]" : shows the account's autoscaling group sizes
+"%BOTNAME% cloud <account> deploys [hours]" : lists the deploys in the last 24 (or so many) hours
+"%BOTNAME% cloud <account> costs" : shows this month's costs so far, by service`,
+	Run: cloudRun,
+}
+
+// A CloudProvider answers questions about an account on one cloud. Add one
+// to CloudProviders to teach the Cloud module about another cloud.
+type CloudProvider interface {
+	Instances(account *CloudAccount) ([]CloudInstance, error)
+	Groups(account *CloudAccount) ([]CloudGroup, error)
+	Deploys(account *CloudAccount, since time.Time) ([]CloudDeploy, error)
+	Costs(account *CloudAccount, from time.Time, to time.Time) ([]CloudCost, error)
+}
+
+// CloudProviders are the clouds the Cloud module knows, by name
+var CloudProviders = map[string]CloudProvider{
+	`aws`: newAWSProvider(),
+}
+
+// A CloudAccount is an account on a cloud
+type CloudAccount struct {
+	Name     string
+	Provider string
+	Region   string
+	Assume   string // who to act as in the account (an IAM role, for aws)
+	Role     string // the role you need to ask about it
+}
+
+// A CloudInstance is a server
+type CloudInstance struct {
+	ID       string
+	Name     string
+	Type     string
+	State    string
+	Address  string
+	Launched time.Time
+}
+
+// A CloudGroup is an autoscaling group
+type CloudGroup struct {
+	Name    string
+	Min     int
+	Max     int
+	Desired int
+	Healthy int // instances that are in service and healthy
+}
+
+// A CloudDeploy is a deploy
+type CloudDeploy struct {
+	ID          string
+	Application string
+	Group       string
+	Status      string
+	Creator     string
+	Started     time.Time
+	Finished    time.Time
+}
+
+// A CloudCost is what one service cost
+type CloudCost struct {
+	Service string
+	Amount  float64
+	Unit    string
+}
+
+// the most rows an answer lists
+const cloudMaxRows = 50
+
+// a cloudAnswer is what a provider said, and when
+type cloudAnswer struct {
+	data interface{}
+	err  error
+	at   time.Time
+}
+
+// cloudCache remembers what providers said, by account and question
+type cloudCache struct {
+	lock    sync.Mutex
+	answers map[string]cloudAnswer
+}
+
+func cloudRun(b *lazlo.Broker) {
+	cache := &cloudCache{answers: make(map[string]cloudAnswer)}
+	accounts := b.MessageCallback(`(?i)cloud accounts$`, true)
+	query := b.MessageCallback(`(?i)cloud ([\w.-]+) (instances|groups|deploys|costs)\s*(.*?)\s*$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-accounts.Chan:
+			pm.Event.Respond(cloudAccountList(b))
+		case pm := <-query.Chan:
+			go cloudQuery(b, cache, pm)
+		}
+	}
+}
+
+// cloudAccounts returns the accounts in LAZLO_CLOUD_ACCOUNTS and the registry
+// (which wins)
+func cloudAccounts(b *lazlo.Broker) map[string]*CloudAccount {
+	accounts := make(map[string]*CloudAccount)
+	for _, item := range strings.Split(b.Config.CloudAccounts, `,`) {
+		parts := strings.SplitN(strings.TrimSpace(item), `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		account := &CloudAccount{Name: strings.ToLower(parts[0]), Provider: `aws`, Region: parts[1], Role: b.Config.CloudRole}
+		if i := strings.LastIndex(account.Region, `@`); i >= 0 {
+			account.Assume, account.Region = account.Region[:i], account.Region[i+1:]
+		}
+		accounts[account.Name] = account
+	}
+	if b.Registry != nil {
+		for _, e := range b.Registry.List(`account`) {
+			account := &CloudAccount{
+				Name:     strings.ToLower(e.Name),
+				Provider: e.Attrs[`provider`],
+				Region:   e.Attrs[`region`],
+				Assume:   e.Attrs[`assume`],
+				Role:     e.Attrs[`role`],
+			}
+			if account.Provider == `` {
+				account.Provider = `aws`
+			}
+			if account.Role == `` {
+				account.Role = b.Config.CloudRole
+			}
+			accounts[account.Name] = account
+		}
+	}
+	return accounts
+}
+
+func cloudAccountList(b *lazlo.Broker) string {
+	accounts := cloudAccounts(b)
+	if len(accounts) == 0 {
+		return `I don't know about any cloud accounts (see LAZLO_CLOUD_ACCOUNTS)`
+	}
+	var lines []string
+	for _, account := range accounts {
+		role := `admins only`
+		if account.Role != `` {
+			role = `needs the ` + account.Role + ` role`
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s %s, %s", account.Name, account.Provider, account.Region, role))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// cloudAllowed returns true if the user can ask about the account
+func cloudAllowed(b *lazlo.Broker, account *CloudAccount, user string) bool {
+	if account.Role == `` {
+		return b.IsAdmin(user)
+	}
+	return b.HasRole(user, account.Role)
+}
+
+// ask returns what the provider said about the question, from the cache if
+// it's fresh enough (and fresh isn't set)
+func (c *cloudCache) ask(b *lazlo.Broker, key string, fresh bool, fn func() (interface{}, error)) cloudAnswer {
+	ttl := time.Duration(b.Config.CloudCache) * time.Minute
+	c.lock.Lock()
+	answer, ok := c.answers[key]
+	c.lock.Unlock()
+	if ok && !fresh && time.Since(answer.at) < ttl {
+		return answer
+	}
+	data, err := fn()
+	answer = cloudAnswer{data: data, err: err, at: time.Now()}
+	if err == nil && ttl > 0 {
+		c.lock.Lock()
+		for k, old := range c.answers {
+			if time.Since(old.at) >= ttl {
+				delete(c.answers, k)
+			}
+		}
+		c.answers[key] = answer
+		c.lock.Unlock()
+	}
+	return answer
+}
+
+func cloudQuery(b *lazlo.Broker, cache *cloudCache, pm lazlo.PatternMatch) {
+	e := pm.Event
+	name, question, args := strings.ToLower(pm.Match[1]), strings.ToLower(pm.Match[2]), strings.Fields(pm.Match[3])
+	account := cloudAccounts(b)[name]
+	if account == nil {
+		e.Reply(fmt.Sprintf("I don't know about an account called %s", name))
+		return
+	}
+	if !cloudAllowed(b, account, e.User) {
+		if account.Role == `` {
+			e.Reply(fmt.Sprintf("Sorry, only admins can ask about %s", name))
+		} else {
+			e.Reply(fmt.Sprintf("Sorry, you need the %s role to ask about %s", account.Role, name))
+		}
+		return
+	}
+	provider := CloudProviders[account.Provider]
+	if provider == nil {
+		e.Reply(fmt.Sprintf("I don't know how to talk to %s", account.Provider))
+		return
+	}
+	fresh := false
+	if len(args) > 0 && strings.EqualFold(args[len(args)-1], `fresh`) {
+		fresh, args = true, args[:len(args)-1]
+	}
+	filter := strings.ToLower(strings.Join(args, ` `))
+	l := b.Locale(e.User, e.Channel)
+	r := &lazlo.Result{}
+
+	switch question {
+	case `instances`:
+		answer := cache.ask(b, name+`:instances`, fresh, func() (interface{}, error) {
+			return provider.Instances(account)
+		})
+		if answer.err != nil {
+			e.Reply(fmt.Sprintf("I couldn't list %s's instances: %s", name, answer.err))
+			return
+		}
+		r.Title = fmt.Sprintf("Instances in %s", name)
+		r.Table = &lazlo.ResultTable{Header: []string{`Name`, `ID`, `Type`, `State`, `Address`, `Launched`}}
+		states := make(map[string]int)
+		for _, i := range answer.data.([]CloudInstance) {
+			if filter != `` && !strings.Contains(strings.ToLower(i.Name+` `+i.ID), filter) {
+				continue
+			}
+			states[i.State]++
+			launched := ``
+			if !i.Launched.IsZero() {
+				launched = l.Ago(i.Launched)
+			}
+			r.Row(i.Name, i.ID, i.Type, i.State, i.Address, launched)
+		}
+		for state, n := range states {
+			r.Field(state, n)
+		}
+		sort.Sort(byResultField(r.Fields))
+		cloudFinish(r, answer, l)
+	case `groups`:
+		answer := cache.ask(b, name+`:groups`, fresh, func() (interface{}, error) {
+			return provider.Groups(account)
+		})
+		if answer.err != nil {
+			e.Reply(fmt.Sprintf("I couldn't list %s's autoscaling groups: %s", name, answer.err))
+			return
+		}
+		r.Title = fmt.Sprintf("Autoscaling groups in %s", name)
+		r.Status = lazlo.ResultOK
+		r.Table = &lazlo.ResultTable{Header: []string{`Group`, `Healthy`, `Desired`, `Min`, `Max`}}
+		for _, g := range answer.data.([]CloudGroup) {
+			if filter != `` && !strings.Contains(strings.ToLower(g.Name), filter) {
+				continue
+			}
+			if g.Healthy < g.Desired {
+				r.Status = lazlo.ResultWarning
+			}
+			r.Row(g.Name, g.Healthy, g.Desired, g.Min, g.Max)
+		}
+		cloudFinish(r, answer, l)
+	case `deploys`:
+		hours := 24
+		if filter != `` {
+			n, err := strconv.Atoi(filter)
+			if err != nil || n < 1 {
+				e.Reply(`That's not a number of hours`)
+				return
+			}
+			hours = n
+		}
+		answer := cache.ask(b, fmt.Sprintf("%s:deploys:%d", name, hours), fresh, func() (interface{}, error) {
+			return provider.Deploys(account, time.Now().Add(-time.Duration(hours)*time.Hour))
+		})
+		if answer.err != nil {
+			e.Reply(fmt.Sprintf("I couldn't list %s's deploys: %s", name, answer.err))
+			return
+		}
+		r.Title = fmt.Sprintf("Deploys in %s in the last %s", name, l.Duration(time.Duration(hours)*time.Hour))
+		r.Status = lazlo.ResultOK
+		r.Table = &lazlo.ResultTable{Header: []string{`Started`, `Application`, `Group`, `Status`, `By`, `Took`}}
+		for _, d := range answer.data.([]CloudDeploy) {
+			took := ``
+			if !d.Finished.IsZero() {
+				took = l.Duration(d.Finished.Sub(d.Started))
+			}
+			if strings.EqualFold(d.Status, `failed`) {
+				r.Status = lazlo.ResultWarning
+			}
+			r.Row(l.DateTime(d.Started), d.Application, d.Group, d.Status, d.Creator, took)
+		}
+		cloudFinish(r, answer, l)
+	case `costs`:
+		to := time.Now().UTC()
+		from := time.Date(to.Year(), to.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if to.Day() == 1 {
+			// there's nothing for today yet, so show last month
+			from = from.AddDate(0, -1, 0)
+		}
+		answer := cache.ask(b, fmt.Sprintf("%s:costs:%s", name, from.Format(`2006-01`)), fresh, func() (interface{}, error) {
+			return provider.Costs(account, from, to)
+		})
+		if answer.err != nil {
+			e.Reply(fmt.Sprintf("I couldn't get %s's costs: %s", name, answer.err))
+			return
+		}
+		r.Title = fmt.Sprintf("Costs in %s since %s", name, l.Date(from))
+		r.Table = &lazlo.ResultTable{Header: []string{`Service`, `Cost`}}
+		costs := answer.data.([]CloudCost)
+		total, unit := 0.0, ``
+		for _, c := range costs {
+			total, unit = total+c.Amount, c.Unit
+		}
+		for _, c := range costs {
+			if c.Amount >= 0.01 {
+				r.Row(c.Service, l.Number(c.Amount, 2)+` `+c.Unit)
+			}
+		}
+		r.Field(`Total`, l.Number(total, 2)+` `+unit)
+		cloudFinish(r, answer, l)
+	}
+	e.RespondResult(r)
+}
+
+// cloudFinish trims the result's table, and says how old the answer is
+func cloudFinish(r *lazlo.Result, answer cloudAnswer, l *lazlo.Locale) {
+	if len(r.Table.Rows) == 0 {
+		r.Table = nil
+		r.Text = `Nothing to show.`
+	} else if len(r.Table.Rows) > cloudMaxRows {
+		r.Text = fmt.Sprintf("The first %d of %d.", cloudMaxRows, len(r.Table.Rows))
+		r.Table.Rows = r.Table.Rows[:cloudMaxRows]
+	}
+	if time.Since(answer.at) > time.Minute {
+		r.Text = strings.TrimSpace(r.Text + ` As of ` + l.Ago(answer.at) + ` (say "fresh" for an update).`)
+	}
+}
+
+type byResultField []lazlo.ResultField
+
+func (l byResultField) Len() int           { return len(l) }
+func (l byResultField) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l byResultField) Less(i, j int) bool { return l[i].Name < l[j].Name }
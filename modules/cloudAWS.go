@@ -0,0 +1,315 @@
+package modules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// awsProvider answers questions about aws accounts with the aws cli (so it
+// uses whatever credentials the cli finds), assuming the account's IAM role
+// if it has one
+type awsProvider struct {
+	lock  sync.Mutex
+	creds map[string]*awsCreds // by role ARN
+}
+
+// awsCreds are temporary credentials for an assumed role
+type awsCreds struct {
+	AccessKeyID     string    `json:"AccessKeyId"`
+	SecretAccessKey string    `json:"SecretAccessKey"`
+	SessionToken    string    `json:"SessionToken"`
+	Expiration      time.Time `json:"Expiration"`
+}
+
+// how long the aws cli gets to answer
+const awsTimeout = 30 * time.Second
+
+// the most deploys we look up
+const awsMaxDeploys = 100
+
+func newAWSProvider() *awsProvider {
+	return &awsProvider{creds: make(map[string]*awsCreds)}
+}
+
+// assume returns credentials for the account's role, assuming it again if
+// the ones we have are about to run out
+func (p *awsProvider) assume(account *CloudAccount) (*awsCreds, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if creds := p.creds[account.Assume]; creds != nil && creds.Expiration.Sub(time.Now()) > 5*time.Minute {
+		return creds, nil
+	}
+	var out struct {
+		Credentials awsCreds `json:"Credentials"`
+	}
+	if err := awsCommand(nil, account.Region, &out, `sts`, `assume-role`, `--role-arn`, account.Assume, `--role-session-name`, `lazlo`); err != nil {
+		return nil, err
+	}
+	p.creds[account.Assume] = &out.Credentials
+	return &out.Credentials, nil
+}
+
+// run runs an aws cli command in the account, decoding its JSON output into
+// out
+func (p *awsProvider) run(account *CloudAccount, out interface{}, args ...string) error {
+	var creds *awsCreds
+	if account.Assume != `` {
+		var err error
+		if creds, err = p.assume(account); err != nil {
+			return fmt.Errorf("couldn't assume %s: %s", account.Assume, err)
+		}
+	}
+	return awsCommand(creds, account.Region, out, args...)
+}
+
+func awsCommand(creds *awsCreds, region string, out interface{}, args ...string) error {
+	args = append(args, `--output`, `json`)
+	if region != `` {
+		args = append(args, `--region`, region)
+	}
+	cmd := exec.Command(`aws`, args...)
+	cmd.Env = os.Environ()
+	if creds != nil {
+		cmd.Env = append(cmd.Env,
+			`AWS_ACCESS_KEY_ID=`+creds.AccessKeyID,
+			`AWS_SECRET_ACCESS_KEY=`+creds.SecretAccessKey,
+			`AWS_SESSION_TOKEN=`+creds.SessionToken,
+		)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			if msg := strings.TrimSpace(stderr.String()); msg != `` {
+				return fmt.Errorf("%s", msg)
+			}
+			return err
+		}
+	case <-time.After(awsTimeout):
+		cmd.Process.Kill()
+		<-done
+		return fmt.Errorf("aws %s %s timed out", args[0], args[1])
+	}
+	return json.Unmarshal(stdout.Bytes(), out)
+}
+
+func (p *awsProvider) Instances(account *CloudAccount) ([]CloudInstance, error) {
+	var out struct {
+		Reservations []struct {
+			Instances []struct {
+				InstanceID       string    `json:"InstanceId"`
+				InstanceType     string    `json:"InstanceType"`
+				PrivateIPAddress string    `json:"PrivateIpAddress"`
+				LaunchTime       time.Time `json:"LaunchTime"`
+				State            struct {
+					Name string `json:"Name"`
+				} `json:"State"`
+				Tags []struct {
+					Key   string `json:"Key"`
+					Value string `json:"Value"`
+				} `json:"Tags"`
+			} `json:"Instances"`
+		} `json:"Reservations"`
+	}
+	if err := p.run(account, &out, `ec2`, `describe-instances`); err != nil {
+		return nil, err
+	}
+	var instances []CloudInstance
+	for _, r := range out.Reservations {
+		for _, i := range r.Instances {
+			instance := CloudInstance{
+				ID:       i.InstanceID,
+				Type:     i.InstanceType,
+				State:    i.State.Name,
+				Address:  i.PrivateIPAddress,
+				Launched: i.LaunchTime,
+			}
+			for _, tag := range i.Tags {
+				if tag.Key == `Name` {
+					instance.Name = tag.Value
+				}
+			}
+			instances = append(instances, instance)
+		}
+	}
+	sort.Sort(instancesByName(instances))
+	return instances, nil
+}
+
+func (p *awsProvider) Groups(account *CloudAccount) ([]CloudGroup, error) {
+	var out struct {
+		AutoScalingGroups []struct {
+			Name      string `json:"AutoScalingGroupName"`
+			Min       int    `json:"MinSize"`
+			Max       int    `json:"MaxSize"`
+			Desired   int    `json:"DesiredCapacity"`
+			Instances []struct {
+				HealthStatus   string `json:"HealthStatus"`
+				LifecycleState string `json:"LifecycleState"`
+			} `json:"Instances"`
+		} `json:"AutoScalingGroups"`
+	}
+	if err := p.run(account, &out, `autoscaling`, `describe-auto-scaling-groups`); err != nil {
+		return nil, err
+	}
+	var groups []CloudGroup
+	for _, g := range out.AutoScalingGroups {
+		group := CloudGroup{Name: g.Name, Min: g.Min, Max: g.Max, Desired: g.Desired}
+		for _, i := range g.Instances {
+			if i.LifecycleState == `InService` && i.HealthStatus == `Healthy` {
+				group.Healthy++
+			}
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// Deploys lists CodeDeploy deployments
+func (p *awsProvider) Deploys(account *CloudAccount, since time.Time) ([]CloudDeploy, error) {
+	var list struct {
+		Deployments []string `json:"deployments"`
+	}
+	if err := p.run(account, &list, `deploy`, `list-deployments`, `--create-time-range`, `start=`+strconv.FormatInt(since.Unix(), 10)); err != nil {
+		return nil, err
+	}
+	ids := list.Deployments
+	if len(ids) > awsMaxDeploys {
+		ids = ids[:awsMaxDeploys]
+	}
+	var deploys []CloudDeploy
+	// batch-get-deployments takes 25 at a time
+	for len(ids) > 0 {
+		batch := ids
+		if len(batch) > 25 {
+			batch = batch[:25]
+		}
+		ids = ids[len(batch):]
+		var out struct {
+			DeploymentsInfo []struct {
+				ID           string  `json:"deploymentId"`
+				Application  string  `json:"applicationName"`
+				Group        string  `json:"deploymentGroupName"`
+				Status       string  `json:"status"`
+				Creator      string  `json:"creator"`
+				CreateTime   awsTime `json:"createTime"`
+				CompleteTime awsTime `json:"completeTime"`
+			} `json:"deploymentsInfo"`
+		}
+		args := append([]string{`deploy`, `batch-get-deployments`, `--deployment-ids`}, batch...)
+		if err := p.run(account, &out, args...); err != nil {
+			return nil, err
+		}
+		for _, d := range out.DeploymentsInfo {
+			deploy := CloudDeploy{
+				ID:          d.ID,
+				Application: d.Application,
+				Group:       d.Group,
+				Status:      d.Status,
+				Creator:     d.Creator,
+				Started:     time.Time(d.CreateTime),
+				Finished:    time.Time(d.CompleteTime),
+			}
+			deploys = append(deploys, deploy)
+		}
+	}
+	sort.Sort(sort.Reverse(deploysByStart(deploys)))
+	return deploys, nil
+}
+
+// Costs asks Cost Explorer what each service cost
+func (p *awsProvider) Costs(account *CloudAccount, from time.Time, to time.Time) ([]CloudCost, error) {
+	var out struct {
+		ResultsByTime []struct {
+			Groups []struct {
+				Keys    []string `json:"Keys"`
+				Metrics struct {
+					UnblendedCost struct {
+						Amount string `json:"Amount"`
+						Unit   string `json:"Unit"`
+					} `json:"UnblendedCost"`
+				} `json:"Metrics"`
+			} `json:"Groups"`
+		} `json:"ResultsByTime"`
+	}
+	period := fmt.Sprintf("Start=%s,End=%s", from.Format(`2006-01-02`), to.Format(`2006-01-02`))
+	if err := p.run(account, &out, `ce`, `get-cost-and-usage`, `--time-period`, period, `--granularity`, `MONTHLY`,
+		`--metrics`, `UnblendedCost`, `--group-by`, `Type=DIMENSION,Key=SERVICE`); err != nil {
+		return nil, err
+	}
+	byService := make(map[string]*CloudCost)
+	for _, r := range out.ResultsByTime {
+		for _, g := range r.Groups {
+			if len(g.Keys) == 0 {
+				continue
+			}
+			amount, _ := strconv.ParseFloat(g.Metrics.UnblendedCost.Amount, 64)
+			cost := byService[g.Keys[0]]
+			if cost == nil {
+				cost = &CloudCost{Service: g.Keys[0], Unit: g.Metrics.UnblendedCost.Unit}
+				byService[g.Keys[0]] = cost
+			}
+			cost.Amount += amount
+		}
+	}
+	var costs []CloudCost
+	for _, cost := range byService {
+		costs = append(costs, *cost)
+	}
+	sort.Sort(sort.Reverse(costsByAmount(costs)))
+	return costs, nil
+}
+
+// an awsTime is a time in the cli's output, which is a fractional unix time
+// or an ISO 8601 one, depending on the cli's version and settings
+type awsTime time.Time
+
+func (t *awsTime) UnmarshalJSON(data []byte) error {
+	if f, err := strconv.ParseFloat(string(data), 64); err == nil {
+		*t = awsTime(time.Unix(int64(f), int64((f-float64(int64(f)))*1e9)))
+		return nil
+	}
+	var parsed time.Time
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return err
+	}
+	*t = awsTime(parsed)
+	return nil
+}
+
+type instancesByName []CloudInstance
+
+func (l instancesByName) Len() int      { return len(l) }
+func (l instancesByName) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l instancesByName) Less(i, j int) bool {
+	if l[i].Name != l[j].Name {
+		return l[i].Name < l[j].Name
+	}
+	return l[i].ID < l[j].ID
+}
+
+type deploysByStart []CloudDeploy
+
+func (l deploysByStart) Len() int           { return len(l) }
+func (l deploysByStart) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l deploysByStart) Less(i, j int) bool { return l[i].Started.Before(l[j].Started) }
+
+type costsByAmount []CloudCost
+
+func (l costsByAmount) Len() int           { return len(l) }
+func (l costsByAmount) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l costsByAmount) Less(i, j int) bool { return l[i].Amount < l[j].Amount }
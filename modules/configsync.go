@@ -0,0 +1,71 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"time"
+)
+
+// ConfigSync pulls lazlo's config repo (LAZLO_SYNC_REPO) every
+// LAZLO_SYNC_INTERVAL minutes, whenever the repo's push webhook fires, and
+// when an admin asks
+var ConfigSync = &lazlo.Module{
+	Name:  `ConfigSync`,
+	Usage: `"%BOTNAME% sync config" : (admins only) pulls the config repo right now, and tells you which commit I'm running with`,
+	Run:   configSyncRun,
+}
+
+func configSyncRun(b *lazlo.Broker) {
+	if b.ConfigSync == nil {
+		lazlo.Logger.Debug(`ConfigSync:: LAZLO_SYNC_REPO isn't set, not syncing`)
+		return
+	}
+	interval := time.Duration(b.Config.SyncInterval) * time.Minute
+	if interval <= 0 {
+		interval = 5 * time.Minute
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// github, gitlab and friends can ping us when someone pushes, as long as
+	// they sign it
+	webhook := make(chan bool)
+	if b.Config.SyncSecret != `` {
+		cb := b.LinkCallback(`configsync`, nil, lazlo.HMACSignature(`X-Hub-Signature-256`, `sha256`, b.Config.SyncSecret))
+		if cb != nil {
+			go func() {
+				for range cb.Chan {
+					webhook <- true
+				}
+			}()
+		}
+	}
+
+	command := b.MessageCallback(`(?i)sync config$`, true)
+	b.Ready()
+	for {
+		select {
+		case <-ticker.C:
+			configSync(b)
+		case <-webhook:
+			configSync(b)
+		case pm := <-command.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can sync my config`)
+				continue
+			}
+			sha, err := b.ConfigSync.Sync()
+			if err != nil {
+				pm.Event.Reply(fmt.Sprintf("that didn't work (%s), so I'm sticking with commit %s", err, sha))
+				continue
+			}
+			pm.Event.Reply(`I'm running with config from commit ` + sha)
+		}
+	}
+}
+
+func configSync(b *lazlo.Broker) {
+	if _, err := b.ConfigSync.Sync(); err != nil {
+		lazlo.Logger.Error(`ConfigSync:: `, err)
+	}
+}
@@ -0,0 +1,73 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"sort"
+	"time"
+)
+
+// Costs reports what lazlo's paid integrations are costing (see
+// lazlo.Broker.Spend)
+var Costs = &lazlo.Module{
+	Name: `Costs`,
+	Usage: `"%BOTNAME% costs [2006-01]" : what you've spent on paid integrations this month (or that one). Admins get everyone's.
+"!costs" : same thing, for this month`,
+	Run: costsRun,
+}
+
+func costsRun(b *lazlo.Broker) {
+	respond := b.MessageCallback(`(?i)costs\s*(\d{4}-\d{2})?$`, true)
+	bang := b.MessageCallback(`^!costs$`, false)
+	b.Ready()
+	for {
+		select {
+		case pm := <-respond.Chan:
+			month := pm.Match[1]
+			if month == `` {
+				month = time.Now().Format(`2006-01`)
+			}
+			costsReport(b, pm.Event, month)
+		case pm := <-bang.Chan:
+			costsReport(b, pm.Event, time.Now().Format(`2006-01`))
+		}
+	}
+}
+
+// costsReport responds with the whole month's report for admins, and the
+// user's own spending for everyone else
+func costsReport(b *lazlo.Broker, e *lazlo.Event, month string) {
+	if b.IsAdmin(e.User) {
+		r, err := b.CostReport(month)
+		if err != nil {
+			e.Reply(fmt.Sprintf("I couldn't add it up: %s", err))
+			return
+		}
+		e.RespondResult(r.Result())
+		return
+	}
+	e.Respond(costsOwn(b, e.User, month))
+}
+
+// costsOwn is what the user spent in the month
+func costsOwn(b *lazlo.Broker, user string, month string) string {
+	l := b.Ledger(month, user)
+	if len(l.Units) == 0 {
+		return fmt.Sprintf("You haven't spent anything in %s", month)
+	}
+	out := fmt.Sprintf("You've spent %.2f in %s", l.Spent, month)
+	if budget, ok := b.CostBudgets()[user]; ok {
+		out += fmt.Sprintf(" of your %.2f budget", budget)
+	} else if budget, ok := b.CostBudgets()[`user`]; ok {
+		out += fmt.Sprintf(" of your %.2f budget", budget)
+	}
+	var units []string
+	for unit := range l.Units {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		out += fmt.Sprintf("\n  %s: %g", unit, l.Units[unit])
+	}
+	return out
+}
@@ -0,0 +1,72 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+	"time"
+)
+
+var Debug = &lazlo.Module{
+	Name: `Debug`,
+	Usage: `"%BOTNAME% debug stats" : (admins only) goroutines, memory and GC numbers
+"%BOTNAME% debug goroutines" : (admins only) DMs you a dump of every goroutine's stack
+"%BOTNAME% debug heap" : (admins only) DMs you a heap profile (for go tool pprof)
+"%BOTNAME% debug dump" : (admins only) DMs you a redacted JSON snapshot of my state, for bug reports`,
+	Run: debugRun,
+}
+
+func debugRun(b *lazlo.Broker) {
+	cb := b.MessageCallback(`(?i)debug (stats|goroutines|heap|dump)$`, true)
+	b.Ready()
+	for {
+		pm := <-cb.Chan
+		if !b.IsAdmin(pm.Event.User) {
+			pm.Event.Reply(`Sorry, only admins can look at my insides`)
+			continue
+		}
+		stats := b.RuntimeStats()
+		what := strings.ToLower(pm.Match[1])
+		if what == `stats` {
+			text := stats.String()
+			if counters := b.Metrics.String(); counters != `` {
+				text += "\n" + counters
+			}
+			pm.Event.Respond(text)
+			continue
+		}
+
+		// goroutine stacks, heaps and snapshots can have all sorts in them,
+		// so they go to the admin rather than the channel
+		stamp := time.Now().Format(`20060102-150405`)
+		var data []byte
+		var filename string
+		var err error
+		kind := what + ` profile`
+		switch what {
+		case `goroutines`:
+			data, err = lazlo.Profile(`goroutine`, 2)
+			filename = fmt.Sprintf("goroutines-%s.txt", stamp)
+		case `dump`:
+			data, err = json.MarshalIndent(b.Snapshot(), ``, `  `)
+			filename = fmt.Sprintf("lazlo-%s.json", stamp)
+			kind = `snapshot`
+		default:
+			data, err = lazlo.Profile(`heap`, 0)
+			filename = fmt.Sprintf("heap-%s.pprof", stamp)
+		}
+		if err != nil {
+			pm.Event.Reply(fmt.Sprintf("I couldn't take a %s: %s", kind, err))
+			continue
+		}
+		dm := b.GetDM(pm.Event.User)
+		if err := b.Upload(dm, filename, data, stats.String()); err != nil {
+			pm.Event.Reply(fmt.Sprintf("I couldn't upload the %s: %s", kind, err))
+			continue
+		}
+		if dm != pm.Event.Channel {
+			pm.Event.Reply(`I DM'd it to you`)
+		}
+	}
+}
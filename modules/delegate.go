@@ -0,0 +1,37 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+)
+
+// Delegate asks another lazlo to run a command (see lazlo.Broker.Delegate)
+var Delegate = &lazlo.Module{
+	Name:  `Delegate`,
+	Usage: `"%BOTNAME% ask <bot> to <command>" : has another lazlo run the command, and says what it said`,
+	Run:   delegateRun,
+}
+
+func delegateRun(b *lazlo.Broker) {
+	ask := b.MessageCallback(`(?i)ask @?([\w.-]+) to (.+)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-ask.Chan:
+			go func(pm lazlo.PatternMatch) {
+				peer, command := pm.Match[1], strings.TrimSpace(pm.Match[2])
+				said, err := b.Delegate(peer, command)
+				if err != nil {
+					pm.Event.Reply(fmt.Sprintf("I couldn't get %s to %s: %s", peer, command, err))
+					return
+				}
+				if said == nil {
+					pm.Event.Respond(fmt.Sprintf("%s ran it, but didn't say anything", peer))
+					return
+				}
+				pm.Event.Respond(fmt.Sprintf("%s says:\n%s", peer, strings.Join(said, "\n")))
+			}(pm)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+	"time"
+)
+
+// Emoji keeps the broker's custom emoji catalog up to date, and lets admins
+// add emoji (if LAZLO_EMOJI_TOKEN is allowed to)
+var Emoji = &lazlo.Module{
+	Name: `Emoji`,
+	Usage: `"%BOTNAME% emoji <name>" : tells you about the custom emoji called name
+"%BOTNAME% emoji list [text]" : lists the custom emoji (with text in their names)
+"%BOTNAME% emoji add <name> <image url>" : (admins only) adds a custom emoji
+"%BOTNAME% emoji alias <name> <existing>" : (admins only) adds another name for an emoji
+"%BOTNAME% emoji sync" : (admins only) fetches the custom emoji from slack again`,
+	Run: emojiRun,
+}
+
+// the catalog is fetched this often, in case we missed an emoji_changed event
+const emojiSyncInterval = 24 * time.Hour
+
+// emoji lists longer than this are cut short
+const emojiListMax = 50
+
+func emojiRun(b *lazlo.Broker) {
+	if err := b.Emoji.Sync(); err != nil {
+		lazlo.Logger.Error(`Emoji:: couldn't fetch the custom emoji: `, err)
+	}
+	ticker := time.NewTicker(emojiSyncInterval)
+	defer ticker.Stop()
+
+	changed := b.EventCallback(`type`, `^emoji_changed$`)
+	lookup := b.MessageCallback(`(?i)emoji :?([\w+'-]+):?$`, true)
+	list := b.MessageCallback(`(?i)emoji list\s*(\S*)$`, true)
+	add := b.MessageCallback(`(?i)emoji add :?([\w+'-]+):? <?(https?://[^\s>|]+)[^\s]*$`, true)
+	alias := b.MessageCallback(`(?i)emoji alias :?([\w+'-]+):? :?([\w+'-]+):?$`, true)
+	sync := b.MessageCallback(`(?i)emoji sync$`, true)
+	b.Ready()
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.Emoji.Sync(); err != nil {
+				lazlo.Logger.Error(`Emoji:: couldn't fetch the custom emoji: `, err)
+			}
+		case event := <-changed.Chan:
+			emojiChanged(b, event)
+		case pm := <-lookup.Chan:
+			// the other commands match this too
+			switch strings.ToLower(pm.Match[1]) {
+			case `list`, `sync`:
+				continue
+			}
+			pm.Event.Reply(emojiDescribe(b, pm.Match[1]))
+		case pm := <-list.Chan:
+			names := b.Emoji.Names(pm.Match[1])
+			if len(names) == 0 {
+				pm.Event.Reply(`I don't know any custom emoji like that`)
+				continue
+			}
+			more := ``
+			if len(names) > emojiListMax {
+				more = fmt.Sprintf(" (and %d more)", len(names)-emojiListMax)
+				names = names[:emojiListMax]
+			}
+			pm.Event.Respond(`:` + strings.Join(names, `: :`) + `:` + more)
+		case pm := <-add.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can add emoji`)
+				continue
+			}
+			if _, ok := b.Emoji.Custom(pm.Match[1]); ok {
+				pm.Event.Reply(fmt.Sprintf("there's already an emoji called %s", pm.Match[1]))
+				continue
+			}
+			if err := b.Emoji.Add(pm.Match[1], pm.Match[2]); err != nil {
+				pm.Event.Reply(fmt.Sprintf("that didn't work: %s", err))
+				continue
+			}
+			pm.Event.Reply(fmt.Sprintf("added :%s:", pm.Match[1]))
+		case pm := <-alias.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can add emoji`)
+				continue
+			}
+			if err := b.Emoji.Alias(pm.Match[1], pm.Match[2]); err != nil {
+				pm.Event.Reply(fmt.Sprintf("that didn't work: %s", err))
+				continue
+			}
+			pm.Event.Reply(fmt.Sprintf(":%s: is now also :%s:", pm.Match[2], pm.Match[1]))
+		case pm := <-sync.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can sync the emoji`)
+				continue
+			}
+			if err := b.Emoji.Sync(); err != nil {
+				pm.Event.Reply(fmt.Sprintf("that didn't work: %s", err))
+				continue
+			}
+			pm.Event.Reply(fmt.Sprintf("I know %d custom emoji now", len(b.Emoji.Names(``))))
+		}
+	}
+}
+
+// emojiChanged applies an emoji_changed event to the catalog
+func emojiChanged(b *lazlo.Broker, event map[string]interface{}) {
+	subtype, _ := event[`subtype`].(string)
+	switch subtype {
+	case `add`:
+		name, _ := event[`name`].(string)
+		value, _ := event[`value`].(string)
+		b.Emoji.Set(name, value)
+	case `remove`:
+		names, _ := event[`names`].([]interface{})
+		for _, name := range names {
+			if n, ok := name.(string); ok {
+				b.Emoji.Set(n, ``)
+			}
+		}
+	default:
+		// renames and whatever else slack comes up with
+		if err := b.Emoji.Sync(); err != nil {
+			lazlo.Logger.Error(`Emoji:: couldn't fetch the custom emoji: `, err)
+		}
+	}
+}
+
+// emojiDescribe says what we know about the named emoji
+func emojiDescribe(b *lazlo.Broker, name string) string {
+	image, ok := b.Emoji.Custom(name)
+	if !ok {
+		return fmt.Sprintf("%s isn't a custom emoji (it might be one of slack's)", name)
+	}
+	if existing := b.Emoji.AliasOf(name); existing != `` {
+		return fmt.Sprintf(":%s: is another name for :%s: (%s)", name, existing, image)
+	}
+	return fmt.Sprintf(":%s: is %s", name, image)
+}
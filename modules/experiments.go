@@ -0,0 +1,45 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+)
+
+// Experiments shows admins how experiments are going (see
+// lazlo.Experiments)
+var Experiments = &lazlo.Module{
+	Name:  `Experiments`,
+	Usage: `"%BOTNAME% experiments [name]" : (admins only) lists the experiments, or compares the two sides of one`,
+	Run:   experimentsRun,
+}
+
+func experimentsRun(b *lazlo.Broker) {
+	command := b.MessageCallback(`(?i)experiments?\s*(\S*)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-command.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can see how experiments are going`)
+				continue
+			}
+			if name := pm.Match[1]; name != `` {
+				pm.Event.RespondResult(b.Experiments.Report(name))
+				continue
+			}
+			names := b.Experiments.Names()
+			if names == nil {
+				pm.Event.Respond(`There aren't any experiments running`)
+				continue
+			}
+			var lines []string
+			for _, name := range names {
+				arms := b.Experiments.Arms(name)
+				lines = append(lines, fmt.Sprintf("%s: on for %d%% (%d users have seen it on, %d off)",
+					name, b.Experiments.Allocation(name), arms[`on`].Exposed, arms[`off`].Exposed))
+			}
+			pm.Event.Respond(strings.Join(lines, "\n"))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"regexp"
+)
+
+var Expunge = &lazlo.Module{
+	Name:  `Expunge`,
+	Usage: `"%BOTNAME% expunge <user>" : (admins only) deletes everything lazlo knows about <user> and DMs you a report`,
+	Run:   expungeRun,
+}
+
+// matches slack's <@U1234> and <@U1234|name> user mentions
+var mentionPat = regexp.MustCompile(`^<@(\w+)(?:\|[^>]*)?>$`)
+
+func expungeRun(b *lazlo.Broker) {
+	cb := b.MessageCallback(`(?i)expunge (\S+)$`, true)
+	b.Ready()
+	for {
+		pm := <-cb.Chan
+		go expungeUser(b, pm)
+	}
+}
+
+func expungeUser(b *lazlo.Broker, pm lazlo.PatternMatch) {
+	if !b.IsAdmin(pm.Event.User) {
+		pm.Event.Reply(`Sorry, only admins can expunge user data`)
+		return
+	}
+	userID := pm.Match[1]
+	if m := mentionPat.FindStringSubmatch(userID); m != nil {
+		userID = m[1]
+	} else if user := b.SlackMeta.GetUserByName(userID); user != nil {
+		userID = user.ID
+	} else {
+		pm.Event.Reply(fmt.Sprintf("Sorry, I don't know who %s is", userID))
+		return
+	}
+	report, err := b.ExpungeUser(userID)
+	if err != nil {
+		lazlo.Logger.Error(err)
+		pm.Event.Reply(fmt.Sprintf("Sorry, something went wrong: %s", err))
+		return
+	}
+	pm.Event.Reply(`done. I sent you the details.`)
+	b.Say(report.String(), b.GetDM(pm.Event.User))
+}
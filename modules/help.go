@@ -7,13 +7,15 @@ import (
 )
 
 var Help = &lazlo.Module{
-	Name:  `Help`,
-	Usage: `"%BOTNAME% help": prints the usage information of every registered plugin`,
-	Run:   helpRun,
+	Name:         `Help`,
+	Usage:        `"%BOTNAME% help": prints the usage information of every registered plugin`,
+	Run:          helpRun,
+	ExternalSafe: true,
 }
 
 func helpRun(b *lazlo.Broker) {
 	cb := b.MessageCallback(`(?i)help`, true)
+	b.Ready()
 	for {
 		pm := <-cb.Chan
 		go getHelp(b, &pm)
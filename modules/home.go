@@ -0,0 +1,49 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"sort"
+	"strings"
+)
+
+var Home = &lazlo.Module{
+	Name:  `Home`,
+	Usage: `%HIDDEN% publishes everyone's App Home tab (see lazlo.Broker.HomeSection)`,
+	Run:   homeRun,
+}
+
+func homeRun(b *lazlo.Broker) {
+	b.HomeSection(`Your prefs`, 100, func(user string) []lazlo.Block {
+		return homePrefs(b, user)
+	})
+	opened := b.EventCallback(`type`, `^app_home_opened$`)
+	b.Ready()
+	for {
+		event := <-opened.Chan
+		if tab, _ := event[`tab`].(string); tab != `` && tab != `home` {
+			continue
+		}
+		user, _ := event[`user`].(string)
+		if err := b.PublishHome(user); err != nil {
+			lazlo.Logger.Error(`Home:: couldn't publish `, user, `'s home tab: `, err)
+		}
+	}
+}
+
+func homePrefs(b *lazlo.Broker, user string) []lazlo.Block {
+	prefs := b.Prefs(user)
+	if len(prefs) == 0 {
+		return nil
+	}
+	var names []string
+	for name := range prefs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("*%s*: %s", name, prefs[name]))
+	}
+	return []lazlo.Block{lazlo.TextBlock(strings.Join(lines, "\n"))}
+}
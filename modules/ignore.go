@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"regexp"
+	"strings"
+)
+
+// Ignore lets admins change what lazlo ignores (see lazlo.IgnoreList)
+var Ignore = &lazlo.Module{
+	Name: `Ignore`,
+	Usage: `"%BOTNAME% ignore <user|app|channel|pattern> <what>" : (admins only) stops listening to a user (or bot), app, channel, or messages matching a regex
+"%BOTNAME% unignore <user|app|channel|pattern> <what>" : (admins only) starts listening again
+"%BOTNAME% ignore list" : lists what's being ignored`,
+	Run: ignoreRun,
+}
+
+// mentions and channel links, which get ignored by ID
+var ignoreRef = regexp.MustCompile(`^<[@#]([^|>]+)(?:\|[^>]*)?>$`)
+
+func ignoreRun(b *lazlo.Broker) {
+	list := b.MessageCallback(`(?i)ignore list$`, true)
+	change := b.MessageCallback(`(?i)(un)?ignore (user|app|channel|pattern) (.+)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-list.Chan:
+			var lines []string
+			for _, kind := range lazlo.IgnoreKinds {
+				if ignored := b.Ignore.List(kind); ignored != nil {
+					lines = append(lines, fmt.Sprintf("%ss: %s", kind, strings.Join(ignored, `, `)))
+				}
+			}
+			if lines == nil {
+				pm.Event.Respond(`I'm not ignoring anything`)
+				continue
+			}
+			pm.Event.Respond(strings.Join(lines, "\n"))
+		case pm := <-change.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can change what I ignore`)
+				continue
+			}
+			kind, what := strings.ToLower(pm.Match[2]), strings.TrimSpace(pm.Match[3])
+			if m := ignoreRef.FindStringSubmatch(what); m != nil && kind != lazlo.IgnorePattern {
+				what = m[1]
+			}
+			if pm.Match[1] != `` {
+				if err := b.Ignore.Remove(kind, what); err != nil {
+					pm.Event.Reply(err.Error())
+					continue
+				}
+				pm.Event.Reply(fmt.Sprintf("OK, I'm listening to the %s %s again", kind, what))
+				continue
+			}
+			if err := b.Ignore.Add(kind, what); err != nil {
+				pm.Event.Reply(fmt.Sprintf("I can't ignore that: %s", err))
+				continue
+			}
+			pm.Event.Reply(fmt.Sprintf("OK, I'm ignoring the %s %s", kind, what))
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Jobs lists, shows and cancels the long-running jobs modules started (see
+// lazlo.JobManager)
+var Jobs = &lazlo.Module{
+	Name: `Jobs`,
+	Usage: `"!jobs list" : lists the jobs that are running, and the last few that finished
+"!jobs status <id>" : shows how a job's getting on
+"!jobs logs <id>" : shows what a job logged
+"!jobs cancel <id>" : asks a job to stop (only whoever started it, or an admin, can)`,
+	Run: jobsRun,
+}
+
+// the most log lines !jobs logs shows
+const jobsLogLines = 50
+
+func jobsRun(b *lazlo.Broker) {
+	list := b.MessageCallback(`(?i)^!jobs?(?: list)?$`, false)
+	command := b.MessageCallback(`(?i)^!jobs? (status|logs|cancel) #?(\d+)$`, false)
+	b.Ready()
+	for {
+		select {
+		case pm := <-list.Chan:
+			pm.Event.Respond(jobsList(b, pm.Event))
+		case pm := <-command.Chan:
+			id, _ := strconv.Atoi(pm.Match[2])
+			job := b.Jobs.Get(id)
+			if job == nil {
+				pm.Event.Reply(fmt.Sprintf("there's no job %d", id))
+				continue
+			}
+			switch strings.ToLower(pm.Match[1]) {
+			case `status`:
+				pm.Event.RespondResult(jobsStatus(b, pm.Event, job))
+			case `logs`:
+				pm.Event.Respond(jobsLogs(job))
+			case `cancel`:
+				if job.User != pm.Event.User && !b.IsAdmin(pm.Event.User) {
+					pm.Event.Reply(fmt.Sprintf("Sorry, only <@%s> (or an admin) can cancel job %d", job.User, id))
+					continue
+				}
+				if err := b.Jobs.Cancel(id); err != nil {
+					pm.Event.Reply(err.Error())
+					continue
+				}
+				pm.Event.Reply(fmt.Sprintf("OK, I've asked job %d to stop", id))
+			}
+		}
+	}
+}
+
+func jobsList(b *lazlo.Broker, e *lazlo.Event) string {
+	jobs := b.Jobs.List()
+	if jobs == nil {
+		return `there aren't any jobs`
+	}
+	l := b.Locale(e.User, e.Channel)
+	var lines []string
+	for i, job := range jobs {
+		if i >= 20 {
+			lines = append(lines, fmt.Sprintf("... and %d more", len(jobs)-i))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("%s, started %s", job.Summary(), l.Ago(job.Started)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func jobsStatus(b *lazlo.Broker, e *lazlo.Event, job *lazlo.Job) *lazlo.Result {
+	l := b.Locale(e.User, e.Channel)
+	r := &lazlo.Result{Title: fmt.Sprintf("Job %d: %s", job.ID, job.Name), Text: job.Summary()}
+	r.Field(`Module`, job.Module)
+	if job.User != `` {
+		r.Field(`Started by`, `<@`+job.User+`>`)
+	}
+	r.Field(`Started`, l.DateTime(job.Started))
+	if job.Finished.IsZero() {
+		r.Field(`Running for`, l.Duration(time.Since(job.Started)))
+	} else {
+		r.Field(`Took`, l.Duration(job.Finished.Sub(job.Started)))
+	}
+	return r
+}
+
+func jobsLogs(job *lazlo.Job) string {
+	log := job.Lines()
+	if log == nil {
+		return fmt.Sprintf("job %d hasn't logged anything", job.ID)
+	}
+	if len(log) > jobsLogLines {
+		log = log[len(log)-jobsLogLines:]
+	}
+	return fmt.Sprintf("job %d's log:\n```\n%s\n```", job.ID, strings.Join(log, "\n"))
+}
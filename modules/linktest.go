@@ -14,6 +14,7 @@ var LinkTest = &lazlo.Module{
 		command_cb := b.MessageCallback(`(?i)(link *me) (.*)`, true)
 		command_cb1 := b.MessageCallback(`(?i)(link *test)`, true)
 		command_cb2 := b.MessageCallback(`(?i)(link *choice)`, true)
+		b.Ready()
 		for {
 			select {
 			case msg := <-command_cb.Chan:
@@ -36,7 +37,7 @@ var LinkTest = &lazlo.Module{
 }
 
 func newLink(b *lazlo.Broker, path string, clickChan chan string) string {
-	link_cb := b.LinkCallback(path)
+	link_cb := b.LinkCallback(path, nil)
 	go func(link_cb *lazlo.LinkCallback, clickChan chan string) {
 		for {
 			<-link_cb.Chan
@@ -47,8 +48,8 @@ func newLink(b *lazlo.Broker, path string, clickChan chan string) string {
 }
 
 func newChoice(b *lazlo.Broker, clickChan chan string) string {
-	opt1 := b.LinkCallback(`option1`)
-	opt2 := b.LinkCallback(`option2`)
+	opt1 := b.LinkCallback(`option1`, nil)
+	opt2 := b.LinkCallback(`option2`, nil)
 	go func(opt1 *lazlo.LinkCallback, opt2 *lazlo.LinkCallback, clickChan chan string) {
 		for {
 			select {
@@ -0,0 +1,378 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"os"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Lua plugins can't do anything risky until an admin says they can. A plugin
+//asks for what it needs in a comment at the top of the script:
+//
+//	-- capabilities: http, brain-write
+//
+//and an admin grants it with "lua grant <plugin>". Grants are kept in the
+//brain, so they survive restarts and reinstalls; a plugin that calls
+//something it hasn't been granted gets a lua error.
+
+//the capabilities a plugin can ask for
+const (
+	capHTTP         = `http`                // robot:Fetch
+	capBrainWrite   = `brain-write`         // robot:Remember and robot:Forget
+	capSendAnywhere = `send-to-any-channel` // robot:Say, and job:Say to a channel
+	capExec         = `exec`                // robot:Exec
+)
+
+var luaCapabilities = []string{capHTTP, capBrainWrite, capSendAnywhere, capExec}
+
+//commands run by robot:Exec are killed after this long
+const luaExecTimeout = time.Minute
+
+var capsHeader = regexp.MustCompile(`(?i)^--\s*capabilities:(.*)$`)
+
+//LuaCaps are the capabilities a plugin asked for, and the ones it's been
+//granted
+type LuaCaps struct {
+	lock      sync.Mutex
+	Plugin    string
+	Requested []string
+	granted   map[string]bool
+}
+
+//luaPlugins are the running plugins' capabilities, by plugin name
+var luaPlugins = make(map[string]*LuaCaps)
+var luaPluginsLock sync.Mutex
+
+//readCaps reads the capabilities header of a plugin
+func readCaps(file string) (*LuaCaps, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	caps := &LuaCaps{granted: make(map[string]bool)}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == `` {
+			continue
+		}
+		if !strings.HasPrefix(line, `--`) {
+			break
+		}
+		match := capsHeader.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		for _, capability := range strings.Split(match[1], `,`) {
+			capability = strings.ToLower(strings.TrimSpace(capability))
+			switch {
+			case capability == ``:
+			case !knownCap(capability):
+				lazlo.Logger.Error(`luaMod:: `, file, ` asks for `, capability, `, which isn't a capability`)
+			default:
+				caps.Requested = append(caps.Requested, capability)
+			}
+		}
+	}
+	return caps, scanner.Err()
+}
+
+func knownCap(capability string) bool {
+	for _, c := range luaCapabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+func grantsKey(plugin string) string {
+	return `lua:grants:` + plugin
+}
+
+//loadGrants reads the capabilities an admin has granted the plugin
+func (c *LuaCaps) loadGrants(b *lazlo.Broker) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	data, err := b.Brain.Get(grantsKey(c.Plugin))
+	if err != nil || len(data) == 0 {
+		return
+	}
+	for _, capability := range strings.Split(string(data), `,`) {
+		c.granted[capability] = true
+	}
+}
+
+//grantAll grants everything the plugin asked for, without saving it (specs
+//run like this)
+func (c *LuaCaps) grantAll() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	for _, capability := range c.Requested {
+		c.granted[capability] = true
+	}
+}
+
+//Allowed returns true if the plugin asked for the capability and was
+//granted it
+func (c *LuaCaps) Allowed(capability string) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.granted[capability] && c.requested(capability)
+}
+
+func (c *LuaCaps) requested(capability string) bool {
+	for _, r := range c.Requested {
+		if r == capability {
+			return true
+		}
+	}
+	return false
+}
+
+//Pending are the capabilities the plugin asked for that it hasn't been
+//granted
+func (c *LuaCaps) Pending() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var pending []string
+	for _, capability := range c.Requested {
+		if !c.granted[capability] {
+			pending = append(pending, capability)
+		}
+	}
+	return pending
+}
+
+//Granted are the capabilities the plugin can use
+func (c *LuaCaps) Granted() []string {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	var granted []string
+	for _, capability := range c.Requested {
+		if c.granted[capability] {
+			granted = append(granted, capability)
+		}
+	}
+	return granted
+}
+
+//set grants (or revokes) the capabilities (every one the plugin asked for,
+//if there aren't any) and saves the grants
+func (c *LuaCaps) set(b *lazlo.Broker, grant bool, capabilities []string) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if len(capabilities) == 0 {
+		capabilities = c.Requested
+	}
+	for _, capability := range capabilities {
+		if grant && !c.requested(capability) {
+			return fmt.Errorf("%s didn't ask for %s", c.Plugin, capability)
+		}
+	}
+	for _, capability := range capabilities {
+		if grant {
+			c.granted[capability] = true
+		} else {
+			delete(c.granted, capability)
+		}
+	}
+	var granted []string
+	for capability := range c.granted {
+		granted = append(granted, capability)
+	}
+	sort.Strings(granted)
+	return b.Brain.Set(grantsKey(c.Plugin), []byte(strings.Join(granted, `,`)))
+}
+
+//need raises a lua error unless the script has been granted the capability
+func (r Robot) need(capability string) {
+	script := LuaScripts[r.id]
+	if !script.Caps.Allowed(capability) {
+		script.State.RaiseError("%s hasn't been granted %s (an admin can run \"lua grant %s %s\")", script.Caps.Plugin, capability, script.Caps.Plugin, capability)
+	}
+}
+
+//functions exported to the lua runtime below here
+
+//lua function to save value under key in the brain. Keys belong to the
+//plugin, so plugins can't see (or clobber) each other's.
+func (r Robot) Remember(key string, value string) string {
+	r.need(capBrainWrite)
+	plugin := LuaScripts[r.id].Caps.Plugin
+	old, err := broker.Brain.Get(r.brainKey(key))
+	added := 0
+	if err != nil || old == nil {
+		added = 1
+	}
+	if err := makeRoom(plugin, len(value)-len(old)); err != nil {
+		return err.Error()
+	}
+	if err := broker.Brain.Set(r.brainKey(key), []byte(value)); err != nil {
+		return err.Error()
+	}
+	adjustLuaStorage(plugin, added, len(value)-len(old))
+	return ``
+}
+
+//lua function to get what was saved under key ("" if nothing was)
+func (r Robot) Recall(key string) string {
+	data, _ := broker.Brain.Get(r.brainKey(key))
+	return string(data)
+}
+
+//lua function to delete what was saved under key
+func (r Robot) Forget(key string) string {
+	r.need(capBrainWrite)
+	old, err := broker.Brain.Get(r.brainKey(key))
+	if err := broker.Brain.Delete(r.brainKey(key)); err != nil {
+		return err.Error()
+	}
+	if err == nil && old != nil {
+		adjustLuaStorage(LuaScripts[r.id].Caps.Plugin, -1, -len(old))
+	}
+	return ``
+}
+
+func (r Robot) brainKey(key string) string {
+	return fmt.Sprintf("lua:%s:%s", LuaScripts[r.id].Caps.Plugin, key)
+}
+
+//lua function to say something in any channel
+func (r Robot) Say(text string, channel string) {
+	r.need(capSendAnywhere)
+	broker.Say(text, channel)
+}
+
+//lua function to run a command (not through a shell). Returns its output,
+//and an error string if it failed.
+func (r Robot) Exec(command string, args ...string) (string, string) {
+	r.need(capExec)
+	var out bytes.Buffer
+	cmd := exec.Command(command, args...)
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Start(); err != nil {
+		return ``, err.Error()
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case err := <-done:
+		if err != nil {
+			return out.String(), err.Error()
+		}
+		return out.String(), ``
+	case <-time.After(luaExecTimeout):
+		cmd.Process.Kill()
+		<-done
+		return out.String(), fmt.Sprintf("%s took longer than %s", command, luaExecTimeout)
+	}
+}
+
+//LuaGrants lets admins see what lua plugins want to do, and let them
+var LuaGrants = &lazlo.Module{
+	Name: `LuaGrants`,
+	Usage: `"%BOTNAME% lua plugins" : lists the lua plugins, and the capabilities they've asked for and been granted
+"%BOTNAME% lua grant <plugin> [capability...]" : (admins only) lets the plugin use the capabilities (all the ones it asked for, if you don't name any)
+"%BOTNAME% lua revoke <plugin> [capability...]" : (admins only) takes them away again
+"!lua stats" : (admins only) shows how much each lua plugin's state is holding on to
+"!lua storage" : (admins only) ranks the lua plugins by how much they've stored in the brain`,
+	Run: luaGrantsRun,
+}
+
+func luaGrantsRun(b *lazlo.Broker) {
+	list := b.MessageCallback(`(?i)lua plugins$`, true)
+	change := b.MessageCallback(`(?i)lua (grant|revoke) (\S+)\s*(.*)$`, true)
+	stats := b.MessageCallback(`(?i)^!lua stats$`, false)
+	storage := b.MessageCallback(`(?i)^!lua storage$`, false)
+	b.Ready()
+	for {
+		select {
+		case pm := <-storage.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can look at what the lua plugins have stored`)
+				continue
+			}
+			// this reads the whole brain
+			go func(pm lazlo.PatternMatch) {
+				pm.Event.Respond(luaStorageReport())
+			}(pm)
+		case pm := <-stats.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can look at the lua plugins' insides`)
+				continue
+			}
+			// this waits for whatever callback the plugins are running
+			go func(pm lazlo.PatternMatch) {
+				pm.Event.Respond(luaStats().String())
+			}(pm)
+		case pm := <-list.Chan:
+			pm.Event.Reply(luaPluginList())
+		case pm := <-change.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can change what lua plugins are allowed to do`)
+				continue
+			}
+			luaPluginsLock.Lock()
+			caps := luaPlugins[strings.TrimSuffix(pm.Match[2], `.lua`)+`.lua`]
+			luaPluginsLock.Unlock()
+			if caps == nil {
+				pm.Event.Reply(fmt.Sprintf("I'm not running a plugin called %s", pm.Match[2]))
+				continue
+			}
+			grant := strings.ToLower(pm.Match[1]) == `grant`
+			capabilities := strings.Fields(strings.ToLower(strings.Replace(pm.Match[3], `,`, ` `, -1)))
+			if err := caps.set(b, grant, capabilities); err != nil {
+				pm.Event.Reply(fmt.Sprintf("I couldn't do that: %s", err))
+				continue
+			}
+			lazlo.Logger.Info(`luaMod:: `, pm.Event.User, ` `, pm.Match[1], `ed `, caps.Plugin, ` `, capabilities)
+			granted := caps.Granted()
+			if granted == nil {
+				granted = []string{`nothing`}
+			}
+			pm.Event.Reply(fmt.Sprintf("OK, %s can use %s", caps.Plugin, strings.Join(granted, `, `)))
+		}
+	}
+}
+
+//luaPluginList describes every running plugin's capabilities
+func luaPluginList() string {
+	luaPluginsLock.Lock()
+	defer luaPluginsLock.Unlock()
+	if len(luaPlugins) == 0 {
+		return `I'm not running any lua plugins`
+	}
+	var names []string
+	for name := range luaPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var lines []string
+	for _, name := range names {
+		caps := luaPlugins[name]
+		line := name + `: `
+		switch {
+		case len(caps.Requested) == 0:
+			line += `doesn't need anything`
+		case len(caps.Pending()) == 0:
+			line += `can use ` + strings.Join(caps.Granted(), `, `)
+		default:
+			line += `is waiting for ` + strings.Join(caps.Pending(), `, `)
+			if granted := caps.Granted(); granted != nil {
+				line += ` (and can use ` + strings.Join(granted, `, `) + `)`
+			}
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
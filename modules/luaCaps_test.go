@@ -0,0 +1,82 @@
+package modules
+
+import (
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	lua "github.com/yuin/gopher-lua"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+//a plugin that's been granted everything, with a secret in the brain
+const trustedPlugin = `-- capabilities: exec, brain-write
+robot:Remember("secret", "hunter2")
+`
+
+//TestRobotIdentity checks a plugin can't borrow another plugin's grants, or
+//its brain keys, by rewriting what lua sees of its robot
+func TestRobotIdentity(t *testing.T) {
+	tests := []struct {
+		name    string
+		rewrite string
+	}{
+		{`nothing`, ``},
+		{`robot.ID`, `robot.ID = 0`},
+		{`robot.id`, `robot.id = 0`},
+		{`lazlo.robot.ID`, `require("lazlo").robot.ID = 0`},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sneaky := `-- capabilities: exec
+pcall(function() ` + test.rewrite + ` end)
+exec_ok, exec_err = pcall(robot.Exec, robot, "true")
+recalled = robot:Recall("secret")
+`
+			L := loadPluginPair(t, sneaky)
+			if lua.LVAsBool(L.GetGlobal(`exec_ok`)) {
+				t.Errorf("exec wasn't denied")
+			} else if err := L.GetGlobal(`exec_err`).String(); !strings.Contains(err, `hasn't been granted exec`) {
+				t.Errorf("exec failed with %q, not for want of a grant", err)
+			}
+			if recalled := L.GetGlobal(`recalled`).String(); recalled != `` {
+				t.Errorf("recalled the trusted plugin's secret (%q)", recalled)
+			}
+		})
+	}
+}
+
+//loadPluginPair loads trustedPlugin with every grant, then the untrusted
+//script with none, into a fake broker, and returns the untrusted script's
+//state
+func loadPluginPair(t *testing.T, untrusted string) *lua.LState {
+	dir, err := ioutil.TempDir(``, `luacaps`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake, err := lazlo.NewFakeBroker()
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedBroker, savedScripts, savedCBs, savedCases := broker, LuaScripts, CBTable, Cases
+	broker, LuaScripts, CBTable, Cases = fake, nil, nil, nil
+	t.Cleanup(func() {
+		for _, script := range LuaScripts {
+			script.State.Close()
+		}
+		broker, LuaScripts, CBTable, Cases = savedBroker, savedScripts, savedCBs, savedCases
+		os.RemoveAll(dir)
+	})
+
+	for i, script := range []string{trustedPlugin, untrusted} {
+		file := filepath.Join(dir, []string{`trusted.lua`, `sneaky.lua`}[i])
+		if err := ioutil.WriteFile(file, []byte(script), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := loadLuaScript(file, i == 0); err != nil {
+			t.Fatalf("loading %s: %v", filepath.Base(file), err)
+		}
+	}
+	return LuaScripts[1].State
+}
@@ -0,0 +1,98 @@
+package modules
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+)
+
+//require "crypto" gives scripts hashes, HMACs, base64 and random tokens,
+//so plugins that check webhook signatures or hand out tokens don't have to
+//do crypto in lua. The randomness comes from crypto/rand. Hashes and HMACs
+//are hex, like the signatures github and slack send.
+
+//luaTokenBytes is how many random bytes random_token uses, unless it's told
+//otherwise, and luaMaxTokenBytes the most it'll use
+const (
+	luaTokenBytes    = 32
+	luaMaxTokenBytes = 1024
+)
+
+//luaCryptoModule is what require "crypto" gives a script
+func luaCryptoModule() map[string]interface{} {
+	return map[string]interface{}{
+		"sha1":   func(text string) string { return luaHash(sha1.New(), text) },
+		"sha256": func(text string) string { return luaHash(sha256.New(), text) },
+		"hmac_sha1": func(key string, text string) string {
+			return luaHash(hmac.New(sha1.New, []byte(key)), text)
+		},
+		"hmac_sha256": func(key string, text string) string {
+			return luaHash(hmac.New(sha256.New, []byte(key)), text)
+		},
+		"equal":            luaEqual,
+		"base64_encode":    luaEncoder(base64.StdEncoding),
+		"base64_decode":    luaDecoder(base64.StdEncoding),
+		"base64url_encode": luaEncoder(base64.RawURLEncoding),
+		"base64url_decode": luaDecoder(base64.RawURLEncoding),
+		"random_token":     luaRandomToken,
+		"uuid":             luaUUID,
+	}
+}
+
+func luaHash(h hash.Hash, text string) string {
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+//luaEqual compares two strings in constant time, for checking signatures
+//without leaking how much of one was right
+func luaEqual(a string, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+func luaEncoder(encoding *base64.Encoding) func(string) string {
+	return func(text string) string {
+		return encoding.EncodeToString([]byte(text))
+	}
+}
+
+//luaDecoder decodes with the encoding, returning what it decoded and an error
+//string
+func luaDecoder(encoding *base64.Encoding) func(string) (string, string) {
+	return func(text string) (string, string) {
+		decoded, err := encoding.DecodeString(text)
+		if err != nil {
+			return ``, err.Error()
+		}
+		return string(decoded), ``
+	}
+}
+
+//luaRandomToken returns n random bytes (or luaTokenBytes), in hex
+func luaRandomToken(n ...int) string {
+	size := luaTokenBytes
+	if len(n) > 0 && n[0] > 0 {
+		size = n[0]
+	}
+	if size > luaMaxTokenBytes {
+		size = luaMaxTokenBytes
+	}
+	token := make([]byte, size)
+	rand.Read(token)
+	return hex.EncodeToString(token)
+}
+
+//luaUUID returns a random (version 4) UUID
+func luaUUID() string {
+	u := make([]byte, 16)
+	rand.Read(u)
+	u[6] = u[6]&0x0f | 0x40
+	u[8] = u[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:])
+}
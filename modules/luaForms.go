@@ -0,0 +1,123 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	lua "github.com/yuin/gopher-lua"
+	"reflect"
+)
+
+//luaForms are the modals lua scripts have defined, by name
+var luaForms = make(map[string]*lazlo.ModalCallback)
+
+//handleModalCB brokers modal submissions back to the lua script that defined
+//the form as fn({user=..., values={...}, meta=...})
+func handleModalCB(index int, sub lazlo.ModalSubmission) {
+	l := CBTable[index].Script.State
+	values := make(map[string]interface{})
+	for name, value := range sub.Values {
+		values[name] = value
+	}
+	form := goToLua(l, map[string]interface{}{
+		"user":   sub.User,
+		"values": values,
+		"meta":   sub.Meta,
+	})
+	if err := l.CallByParam(lua.P{
+		Fn:      CBTable[index].Func,
+		NRet:    0,
+		Protect: true,
+	}, form); err != nil {
+		lazlo.Logger.Error(`luaMod:: error handling form `, sub.Name, `: `, err)
+	}
+}
+
+//luaModal turns robot:Form's fields into a modal. Each field is either a
+//name, or a table like {name="env", label="Environment", options={"prod",
+//"staging"}, optional=true, multiline=true, pattern="^\\d+$", hint="..."}
+func luaModal(title string, fields interface{}) (*lazlo.Modal, error) {
+	list, ok := luaToGo(fields).([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("Form needs a list of fields")
+	}
+	modal := &lazlo.Modal{Title: title}
+	for _, field := range list {
+		switch f := field.(type) {
+		case string:
+			modal.Inputs = append(modal.Inputs, lazlo.ModalInput{Name: f})
+		case map[string]interface{}:
+			input := lazlo.ModalInput{}
+			input.Name, _ = f["name"].(string)
+			input.Label, _ = f["label"].(string)
+			input.Placeholder, _ = f["placeholder"].(string)
+			input.Initial, _ = f["initial"].(string)
+			input.Pattern, _ = f["pattern"].(string)
+			input.Hint, _ = f["hint"].(string)
+			input.Optional, _ = f["optional"].(bool)
+			input.Multiline, _ = f["multiline"].(bool)
+			options, _ := f["options"].([]interface{})
+			for _, option := range options {
+				input.Options = append(input.Options, fmt.Sprint(option))
+			}
+			if input.Name == "" {
+				return nil, fmt.Errorf("every field in a Form needs a name")
+			}
+			modal.Inputs = append(modal.Inputs, input)
+		default:
+			return nil, fmt.Errorf("Form fields are names or tables, not %T", field)
+		}
+	}
+	return modal, nil
+}
+
+//creates a new modal callback from robot.form
+func newModalCallback(RID int, name string, modal *lazlo.Modal, lfunc lua.LValue) {
+	// cbtable and cases indexes have to match
+	if len(CBTable) != len(Cases) {
+		panic(`cbtable != cases`)
+	}
+	cb := broker.ModalCallback(name, modal)
+	luaForms[name] = cb
+	cbEntry := CBMap{
+		Func:     lfunc,
+		Callback: reflect.ValueOf(cb),
+		Script:   &LuaScripts[RID],
+	}
+	caseEntry := reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(cb.Chan),
+	}
+	CBTable = append(CBTable, cbEntry)
+	Cases = append(Cases, caseEntry)
+}
+
+//functions exported to the lua runtime below here
+
+//lua function to define a form (a slack modal). fn is called with each
+//submission that passes validation.
+func (r Robot) Form(name string, title string, fields interface{}, lfunc lua.LValue) string {
+	modal, err := luaModal(title, fields)
+	if err != nil {
+		return err.Error()
+	}
+	newModalCallback(r.id, name, modal, lfunc)
+	return ""
+}
+
+//lua function to reply with a button that opens a form. meta is handed back
+//with the submission.
+func (pm LocalPatternMatch) Form(name string, text string, meta ...string) {
+	cb, ok := luaForms[name]
+	if !ok {
+		pm.event.Reply(fmt.Sprintf("there's no form called %s", name))
+		return
+	}
+	m := ""
+	if len(meta) > 0 {
+		m = meta[0]
+	}
+	pm.event.RespondBlocks(text,
+		lazlo.TextBlock(text),
+		lazlo.ButtonsBlock(cb.Button(cb.Modal.Title, m)),
+	)
+}
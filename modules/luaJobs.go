@@ -0,0 +1,232 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	luar "github.com/layeh/gopher-luar"
+	lua "github.com/yuin/gopher-lua"
+	"sync"
+	"time"
+)
+
+//A LuaJob is a lua function running in the background on its own goroutine
+//and its own lua state, so it can block (long polling, sleeping etc..)
+//without holding up the script's callbacks. Lua scripts start them with
+//robot:Spawn(fn, args...) and get the job back as a handle.
+type LuaJob struct {
+	lock     sync.Mutex
+	ID       int
+	scriptID int // (unexported, so lua can't borrow another script's capabilities)
+	status   string
+	err      error
+	cancel   chan struct{}
+	state    *lua.LState
+}
+
+//luaJobs lets us find (and cancel) every job a script spawned
+var luaJobs = make(map[int][]*LuaJob)
+var luaJobsLock sync.Mutex
+var nextJobID int
+
+//spawn copies fn and its arguments into a brand new lua state and runs it in
+//the background. Functions can't be shared between lua states, so the
+//spawned function can't use local variables from the scope it was defined in;
+//pass it what it needs as arguments instead.
+func spawn(scriptID int, fn *lua.LFunction, args []interface{}) (*LuaJob, error) {
+	if fn == nil || fn.IsG {
+		return nil, fmt.Errorf("Spawn needs a lua function")
+	}
+	if len(fn.Upvalues) > 0 {
+		return nil, fmt.Errorf("spawned functions can't use local variables from their enclosing scope (pass them as arguments)")
+	}
+
+	child := newLuaState()
+	largs := make([]lua.LValue, len(args))
+	for i, arg := range args {
+		larg, err := copyToState(child, arg)
+		if err != nil {
+			child.Close()
+			return nil, err
+		}
+		largs[i] = larg
+	}
+
+	luaJobsLock.Lock()
+	nextJobID++
+	job := &LuaJob{
+		ID:       nextJobID,
+		scriptID: scriptID,
+		status:   `running`,
+		cancel:   make(chan struct{}),
+		state:    child,
+	}
+	luaJobs[scriptID] = append(luaJobs[scriptID], job)
+	luaJobsLock.Unlock()
+
+	child.SetGlobal("job", luar.New(child, job))
+	childFn := &lua.LFunction{
+		Env:   child.G.Global,
+		Proto: fn.Proto,
+	}
+	go job.run(child, childFn, largs)
+	return job, nil
+}
+
+//run calls the job's function, recovering from lua errors and go panics
+func (j *LuaJob) run(L *lua.LState, fn *lua.LFunction, args []lua.LValue) {
+	defer L.Close()
+	defer func() {
+		if r := recover(); r != nil {
+			j.finish(fmt.Errorf("panic: %v", r))
+		}
+	}()
+	lazlo.Logger.Debug(`luaMod:: starting job `, j.ID)
+	err := L.CallByParam(lua.P{
+		Fn:      fn,
+		NRet:    0,
+		Protect: true,
+	}, args...)
+	j.finish(err)
+}
+
+func (j *LuaJob) finish(err error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	switch {
+	case j.status == `cancelled`:
+	case err != nil:
+		j.status = `failed`
+		j.err = err
+		lazlo.Logger.Error(`luaMod:: job `, j.ID, ` failed: `, err)
+	default:
+		j.status = `done`
+	}
+	luaJobsLock.Lock()
+	jobs := luaJobs[j.scriptID]
+	for i, job := range jobs {
+		if job == j {
+			luaJobs[j.scriptID] = append(jobs[:i], jobs[i+1:]...)
+			break
+		}
+	}
+	luaJobsLock.Unlock()
+}
+
+//cancelJobs cancels every job spawned by the given script. It's called when
+//the script's lua state is shut down.
+func cancelJobs(scriptID int) {
+	luaJobsLock.Lock()
+	jobs := append([]*LuaJob{}, luaJobs[scriptID]...)
+	luaJobsLock.Unlock()
+	for _, job := range jobs {
+		job.Cancel()
+	}
+}
+
+//copyToState converts a value from one lua state into another. Only plain
+//values (numbers, strings, booleans and tables of them) go: tables are
+//deep-copied, since lua states can't share them, and go values (robot, a
+//message) would be shared with a job on another goroutine, which they aren't
+//safe for.
+func copyToState(L *lua.LState, val interface{}) (lua.LValue, error) {
+	switch v := val.(type) {
+	case nil:
+		return lua.LNil, nil
+	case lua.LNumber, lua.LString, lua.LBool, *lua.LNilType:
+		return v.(lua.LValue), nil
+	case float64, string, bool:
+		return luar.New(L, v), nil
+	case *lua.LTable:
+		table := L.NewTable()
+		var err error
+		v.ForEach(func(key lua.LValue, value lua.LValue) {
+			if err != nil {
+				return
+			}
+			var lkey, lvalue lua.LValue
+			if lkey, err = copyToState(L, key); err != nil {
+				return
+			}
+			if lvalue, err = copyToState(L, value); err != nil {
+				return
+			}
+			table.RawSet(lkey, lvalue)
+		})
+		return table, err
+	case *lua.LFunction:
+		return nil, fmt.Errorf("can't pass functions to a spawned job")
+	case *lua.LState:
+		return nil, fmt.Errorf("can't pass coroutines to a spawned job")
+	default:
+		return nil, fmt.Errorf("can't pass %T to a spawned job (only numbers, strings, booleans and tables of them)", v)
+	}
+}
+
+//functions exported to the lua runtime below here
+
+//lua function to run fn(args...) in the background
+func (r Robot) Spawn(fn *lua.LFunction, args ...interface{}) *LuaJob {
+	job, err := spawn(r.id, fn, args)
+	if err != nil {
+		LuaScripts[r.id].State.RaiseError("%s", err)
+	}
+	return job
+}
+
+//Status returns running, done, failed, or cancelled
+func (j *LuaJob) Status() string {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	return j.status
+}
+
+//Err returns the error a failed job died with
+func (j *LuaJob) Err() string {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.err == nil {
+		return ``
+	}
+	return j.err.Error()
+}
+
+//Cancel asks the job to stop. Lua code can't be interrupted, so jobs have to
+//cooperate by checking job:Cancelled() or using job:Sleep()
+func (j *LuaJob) Cancel() {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	if j.status == `running` {
+		j.status = `cancelled`
+		close(j.cancel)
+	}
+}
+
+//Cancelled returns true if the job has been asked to stop
+func (j *LuaJob) Cancelled() bool {
+	select {
+	case <-j.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+//Sleep pauses the job for d (a number of seconds, or a duration like "5m").
+//It returns false (early) if the job is cancelled while it sleeps.
+func (j *LuaJob) Sleep(d time.Duration) bool {
+	select {
+	case <-j.cancel:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+//Say sends a message to the given channel (or the default channel). Naming
+//a channel needs the send-to-any-channel capability.
+func (j *LuaJob) Say(text string, channel ...string) {
+	if caps := LuaScripts[j.scriptID].Caps; len(channel) > 0 && !caps.Allowed(capSendAnywhere) {
+		j.state.RaiseError("%s hasn't been granted %s", caps.Plugin, capSendAnywhere)
+	}
+	broker.Say(text, channel...)
+}
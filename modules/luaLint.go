@@ -0,0 +1,389 @@
+package modules
+
+import (
+	"fmt"
+	"github.com/yuin/gopher-lua/ast"
+	"github.com/yuin/gopher-lua/parse"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// A LuaLintIssue is something wrong (or probably wrong) with a plugin
+type LuaLintIssue struct {
+	File    string
+	Line    int
+	Error   bool // errors stop the plugin working; the rest are warnings
+	Message string
+}
+
+func (i LuaLintIssue) String() string {
+	level := `warning`
+	if i.Error {
+		level = `error`
+	}
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, level, i.Message)
+}
+
+// luaAPI are the globals lazlo gives plugins, and the go types lua sees, so
+// calls to their methods can be checked against the real signatures
+var luaAPI = map[string]reflect.Type{
+	`robot`: reflect.TypeOf(&Robot{}),
+	`job`:   reflect.TypeOf(&LuaJob{}), // in spawned functions
+}
+
+// luaDeprecated are the globals plugins used to use, and what to use instead
+var luaDeprecated = map[string]string{
+	`hear`:    `robot:Hear`,
+	`respond`: `robot:Respond`,
+}
+
+// luaBuiltins are the globals lua itself provides
+var luaBuiltins = map[string]bool{}
+
+func init() {
+	for _, name := range strings.Fields(`_G _VERSION assert collectgarbage dofile error getfenv getmetatable
+		ipairs load loadfile loadstring module next pairs pcall print rawequal rawget rawset require select
+		setfenv setmetatable tonumber tostring type unpack xpcall
+		channel coroutine debug io math os package string table`) {
+		luaBuiltins[name] = true
+	}
+}
+
+// globals plugins are expected to set
+var luaExpectedGlobals = map[string]bool{`VERSION`: true}
+
+// LintLuaDir lints every plugin in the directory
+func LintLuaDir(dir string) ([]LuaLintIssue, error) {
+	files, err := filepath.Glob(filepath.Join(dir, `*.lua`))
+	if err != nil {
+		return nil, err
+	}
+	var issues []LuaLintIssue
+	for _, file := range files {
+		found, err := LintLuaFile(file)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, found...)
+	}
+	return issues, nil
+}
+
+// LintLuaFile parses a plugin and checks it for syntax errors, calls to
+// lazlo's API that can't work (unknown methods, the wrong number of
+// arguments), deprecated APIs, calls to functions that don't exist, and
+// globals it sets by accident.
+func LintLuaFile(file string) ([]LuaLintIssue, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	name := filepath.Base(file)
+	chunk, err := parse.Parse(f, name)
+	if perr, ok := err.(*parse.Error); ok {
+		issue := LuaLintIssue{File: name, Line: perr.Pos.Line, Error: true, Message: perr.Message}
+		if perr.Pos.Line == parse.EOF {
+			issue.Line, issue.Message = 0, perr.Message+` at the end of the file`
+		} else if perr.Token != `` {
+			issue.Message += fmt.Sprintf(" near '%s'", perr.Token)
+		}
+		return []LuaLintIssue{issue}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	l := &luaLinter{file: name, globals: make(map[string]bool)}
+	// the first pass finds the globals the plugin sets, so calling one from
+	// a function defined before it isn't flagged
+	l.scopes = []map[string]bool{{}}
+	l.stmts(chunk)
+	l.issues = nil
+	l.scopes = []map[string]bool{{}}
+	l.stmts(chunk)
+	sort.Sort(lintByLine(l.issues))
+	return l.issues, nil
+}
+
+// luaLinter walks a plugin's syntax tree, keeping track of which names are
+// local where
+type luaLinter struct {
+	file    string
+	globals map[string]bool // the globals the plugin sets
+	scopes  []map[string]bool
+	depth   int // how many functions deep we are
+	issues  []LuaLintIssue
+}
+
+func (l *luaLinter) report(line int, isError bool, format string, args ...interface{}) {
+	l.issues = append(l.issues, LuaLintIssue{File: l.file, Line: line, Error: isError, Message: fmt.Sprintf(format, args...)})
+}
+
+func (l *luaLinter) push() {
+	l.scopes = append(l.scopes, make(map[string]bool))
+}
+
+func (l *luaLinter) pop() {
+	l.scopes = l.scopes[:len(l.scopes)-1]
+}
+
+func (l *luaLinter) declare(names ...string) {
+	for _, name := range names {
+		l.scopes[len(l.scopes)-1][name] = true
+	}
+}
+
+func (l *luaLinter) isLocal(name string) bool {
+	for i := len(l.scopes) - 1; i >= 0; i-- {
+		if l.scopes[i][name] {
+			return true
+		}
+	}
+	return false
+}
+
+// known is whether a global name means something
+func (l *luaLinter) known(name string) bool {
+	_, api := luaAPI[name]
+	return api || luaBuiltins[name] || l.globals[name] || luaExpectedGlobals[name]
+}
+
+func (l *luaLinter) block(stmts []ast.Stmt) {
+	l.push()
+	l.stmts(stmts)
+	l.pop()
+}
+
+func (l *luaLinter) stmts(stmts []ast.Stmt) {
+	for _, stmt := range stmts {
+		l.stmt(stmt)
+	}
+}
+
+func (l *luaLinter) stmt(stmt ast.Stmt) {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		l.exprs(s.Rhs)
+		for _, lhs := range s.Lhs {
+			if ident, ok := lhs.(*ast.IdentExpr); ok {
+				l.assignGlobal(s.Line(), ident.Value)
+				continue
+			}
+			l.expr(lhs)
+		}
+	case *ast.LocalAssignStmt:
+		// local function f() ... end can call itself
+		if len(s.Names) == 1 && len(s.Exprs) == 1 {
+			if _, ok := s.Exprs[0].(*ast.FunctionExpr); ok {
+				l.declare(s.Names[0])
+			}
+		}
+		l.exprs(s.Exprs)
+		l.declare(s.Names...)
+	case *ast.FuncCallStmt:
+		l.expr(s.Expr)
+	case *ast.DoBlockStmt:
+		l.block(s.Stmts)
+	case *ast.WhileStmt:
+		l.expr(s.Condition)
+		l.block(s.Stmts)
+	case *ast.RepeatStmt:
+		l.push()
+		l.stmts(s.Stmts)
+		l.expr(s.Condition)
+		l.pop()
+	case *ast.IfStmt:
+		l.expr(s.Condition)
+		l.block(s.Then)
+		l.block(s.Else)
+	case *ast.NumberForStmt:
+		l.expr(s.Init)
+		l.expr(s.Limit)
+		l.expr(s.Step)
+		l.push()
+		l.declare(s.Name)
+		l.stmts(s.Stmts)
+		l.pop()
+	case *ast.GenericForStmt:
+		l.exprs(s.Exprs)
+		l.push()
+		l.declare(s.Names...)
+		l.stmts(s.Stmts)
+		l.pop()
+	case *ast.FuncDefStmt:
+		if ident, ok := s.Name.Func.(*ast.IdentExpr); ok {
+			l.assignGlobal(s.Line(), ident.Value)
+		} else {
+			l.expr(s.Name.Func)
+		}
+		l.function(s.Func, s.Name.Receiver != nil)
+	case *ast.ReturnStmt:
+		l.exprs(s.Exprs)
+	}
+}
+
+// assignGlobal checks an assignment to a name that may be a global
+func (l *luaLinter) assignGlobal(line int, name string) {
+	if !l.isLocal(name) {
+		l.globals[name] = true
+	}
+	switch {
+	case l.isLocal(name), luaExpectedGlobals[name]:
+	case luaAPI[name] != nil || luaBuiltins[name]:
+		l.report(line, false, "replaces the %s global", name)
+	case l.depth > 0:
+		l.report(line, false, "sets the global %s from inside a function (declare it local?)", name)
+	default:
+		l.report(line, false, "sets the global %s (declare it local)", name)
+	}
+}
+
+func (l *luaLinter) function(fn *ast.FunctionExpr, method bool) {
+	l.depth++
+	l.push()
+	if method {
+		l.declare(`self`)
+	}
+	if fn.ParList != nil {
+		l.declare(fn.ParList.Names...)
+	}
+	l.stmts(fn.Stmts)
+	l.pop()
+	l.depth--
+}
+
+func (l *luaLinter) exprs(exprs []ast.Expr) {
+	for _, expr := range exprs {
+		l.expr(expr)
+	}
+}
+
+func (l *luaLinter) expr(expr ast.Expr) {
+	switch e := expr.(type) {
+	case *ast.IdentExpr:
+		if alt, ok := luaDeprecated[e.Value]; ok && !l.isLocal(e.Value) && !l.globals[e.Value] {
+			l.report(e.Line(), true, "%s is no longer available (use %s)", e.Value, alt)
+		}
+	case *ast.AttrGetExpr:
+		l.expr(e.Object)
+		l.expr(e.Key)
+	case *ast.TableExpr:
+		for _, field := range e.Fields {
+			l.expr(field.Key)
+			l.expr(field.Value)
+		}
+	case *ast.FuncCallExpr:
+		l.call(e)
+	case *ast.LogicalOpExpr:
+		l.expr(e.Lhs)
+		l.expr(e.Rhs)
+	case *ast.RelationalOpExpr:
+		l.expr(e.Lhs)
+		l.expr(e.Rhs)
+	case *ast.StringConcatOpExpr:
+		l.expr(e.Lhs)
+		l.expr(e.Rhs)
+	case *ast.ArithmeticOpExpr:
+		l.expr(e.Lhs)
+		l.expr(e.Rhs)
+	case *ast.UnaryMinusOpExpr:
+		l.expr(e.Expr)
+	case *ast.UnaryNotOpExpr:
+		l.expr(e.Expr)
+	case *ast.UnaryLenOpExpr:
+		l.expr(e.Expr)
+	case *ast.FunctionExpr:
+		l.function(e, false)
+	}
+}
+
+func (l *luaLinter) call(e *ast.FuncCallExpr) {
+	l.exprs(e.Args)
+	if e.Receiver != nil {
+		l.expr(e.Receiver)
+		if ident, ok := e.Receiver.(*ast.IdentExpr); ok && !l.isLocal(ident.Value) {
+			l.checkMethod(e, ident.Value, e.Method)
+		}
+		return
+	}
+	switch fn := e.Func.(type) {
+	case *ast.IdentExpr:
+		if l.isLocal(fn.Value) {
+			return
+		}
+		if _, ok := luaDeprecated[fn.Value]; ok {
+			l.expr(fn)
+		} else if !l.known(fn.Value) {
+			l.report(e.Line(), true, "calls %s, which isn't defined", fn.Value)
+		}
+	case *ast.AttrGetExpr:
+		l.expr(fn)
+		// robot.Hear(...) passes the pattern as robot
+		obj, ok := fn.Object.(*ast.IdentExpr)
+		key, isString := fn.Key.(*ast.StringExpr)
+		if ok && isString && !l.isLocal(obj.Value) {
+			if t, api := luaAPI[obj.Value]; api {
+				if _, found := t.MethodByName(key.Value); found {
+					l.report(e.Line(), true, "call %s:%s with a colon, not a dot", obj.Value, key.Value)
+				}
+			}
+		}
+	default:
+		l.expr(e.Func)
+	}
+}
+
+// checkMethod checks a call to a method of one of lazlo's globals against the
+// go method lua will end up calling
+func (l *luaLinter) checkMethod(e *ast.FuncCallExpr, object string, name string) {
+	t, ok := luaAPI[object]
+	if !ok {
+		return
+	}
+	method, ok := t.MethodByName(name)
+	if !ok {
+		l.report(e.Line(), true, "%s has no method %s", object, name)
+		return
+	}
+	// the method's type includes the receiver
+	want := method.Type.NumIn() - 1
+	got := len(e.Args)
+	if got > 0 {
+		switch last := e.Args[got-1].(type) {
+		case *ast.Comma3Expr:
+			return // ... could be any number of arguments
+		case *ast.FuncCallExpr:
+			if !last.AdjustRet {
+				return // so could a call's results
+			}
+		}
+	}
+	variadic := method.Type.IsVariadic()
+	switch {
+	case variadic && got < want-1:
+		l.report(e.Line(), true, "%s:%s takes at least %s, not %d", object, name, luaArgs(want-1), got)
+	case !variadic && got != want:
+		l.report(e.Line(), true, "%s:%s takes %s, not %d", object, name, luaArgs(want), got)
+	}
+}
+
+func luaArgs(n int) string {
+	if n == 1 {
+		return `1 argument`
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+type lintByLine []LuaLintIssue
+
+func (l lintByLine) Len() int      { return len(l) }
+func (l lintByLine) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l lintByLine) Less(i, j int) bool {
+	if l[i].File != l[j].File {
+		return l[i].File < l[j].File
+	}
+	return l[i].Line < l[j].Line
+}
@@ -1,13 +1,18 @@
 package modules
 
 import (
+	"crypto/sha1"
 	"fmt"
 	lazlo "github.com/djosephsen/hustlebot/lib"
 	luar "github.com/layeh/gopher-luar"
 	lua "github.com/yuin/gopher-lua"
+	"io/ioutil"
 	"net/http"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -24,10 +29,17 @@ var LuaMod = &lazlo.Module{
 type LuaScript struct {
 	Robot *Robot
 	State *lua.LState
+	Caps  *LuaCaps
 }
 
-//Keep a local version of lazlo.Patternmatch so we can add methods to it
-type LocalPatternMatch lazlo.PatternMatch
+//LocalPatternMatch is what lua sees of a lazlo.PatternMatch. Its Event is a
+//copy without the Broker, which would hand a script everything its
+//capabilities don't; the methods use the real one.
+type LocalPatternMatch struct {
+	Event *lazlo.Event
+	Match []string
+	event *lazlo.Event
+}
 
 //A CBMap maps a specific callback Case to it's respective lua function and
 //parent lua script
@@ -37,15 +49,18 @@ type CBMap struct {
 	Script   *LuaScript
 }
 
+//Robot is what a script sees of itself. Its id is the script's index in
+//LuaScripts, which the capability checks go by, so it's unexported where
+//lua can't rewrite it.
 type Robot struct {
-	ID int
+	id int
 }
 
 func (r *Robot) GetID() int {
-	return r.ID
+	return r.id
 }
 
-//LuaScripts allows us to Retrieve lua.LState by LuaScript.Robot.ID
+//LuaScripts allows us to Retrieve lua.LState by LuaScript.Robot.id
 var LuaScripts []LuaScript
 
 //CBtable allows us to Retrieve lua.LState by callback case index
@@ -62,56 +77,242 @@ var broker *lazlo.Broker
 func luaMain(b *lazlo.Broker) {
 	broker = b
 	var luaDir *os.File
-	luaDirName := "lua"
+	luaDirName := b.SyncPath("lua", "lua")
 	if luaDirInfo, err := os.Stat(luaDirName); err == nil && luaDirInfo.IsDir() {
 		luaDir, _ = os.Open(luaDirName)
 	} else {
 		lazlo.Logger.Error("Couldn't open the Lua Plugin dir: ", err)
 	}
+	//with LAZLO_LUA_MIN_COVERAGE set, plugins have to pass their specs first
+	blocked := make(map[string]string)
+	if b.Config.LuaMinCoverage > 0 {
+		if report, err := RunLuaSpecs(luaDirName); err != nil {
+			lazlo.Logger.Error("Couldn't run the Lua plugin specs: ", err)
+		} else {
+			blocked = report.Gate(b.Config.LuaMinCoverage)
+		}
+	}
+	b.AddRuntimeStats(`lua`, func() interface{} { return luaStats() })
+	if b.Config.LuaGCInterval > 0 {
+		go luaGC(time.Duration(b.Config.LuaGCInterval) * time.Minute)
+	}
+	luaStateLock.Lock()
 	luaFiles, _ := luaDir.Readdir(0)
 	for _, f := range luaFiles {
 		if f.IsDir() {
 			continue
 		}
+		if why, ok := blocked[f.Name()]; ok {
+			lazlo.Logger.Error("Not loading the Lua plugin ", f.Name(), ": ", why)
+			continue
+		}
 
 		file := fmt.Sprintf("%s/%s", luaDirName, f.Name())
-
-		//make a new script entry
-		script := LuaScript{
-			Robot: &Robot{
-				ID: len(LuaScripts),
-			},
-			State: lua.NewState(),
-		}
+		lintLuaScript(b, file)
+		script, err := loadLuaScript(file, false)
 		defer script.State.Close()
-
-		// register hear and respond inside this lua state
-		script.State.SetGlobal("robot", luar.New(script.State, script.Robot))
-		//script.State.SetGlobal("respond", luar.New(script.State, Respond))
-		//script.State.SetGlobal("hear", luar.New(script.State, Hear))
-		LuaScripts = append(LuaScripts, script)
-
-		// the lua script will register callbacks to the Cases
-		if err := script.State.DoFile(file); err != nil {
+		defer cancelJobs(script.Robot.id)
+		if err != nil {
 			panic(err)
 		}
+		b.SetPluginVersion(f.Name(), luaVersion(script.State, file))
 	}
+	luaStateLock.Unlock()
+	b.Ready()
 	//block waiting on events from the broker
 	for {
 		index, value, _ := reflect.Select(Cases)
-		handle(index, value.Interface())
+		luaStateLock.Lock()
+		if err := handleSafely(index, value.Interface()); err != nil {
+			lazlo.Logger.Error("luaMod:: ", CBTable[index].Script.Caps.Plugin, ": ", err)
+		}
+		luaStateLock.Unlock()
 	}
 }
 
+//loadLuaScript runs a lua script in a new lua state, with the globals it
+//needs to interact with lazlo. Trusted scripts get every capability they ask
+//for; the rest get what an admin granted them (see luaCaps.go).
+func loadLuaScript(file string, trusted bool) (LuaScript, error) {
+	//make a new script entry
+	script := LuaScript{
+		Robot: &Robot{
+			id: len(LuaScripts),
+		},
+		State: newLuaState(),
+	}
+	//every script's callbacks run on luaMain's one loop, so a script that
+	//waits on a channel holds up all of them; that's what jobs are for
+	luar.SetConfig(script.State, luar.Config{NoBlocking: true})
+	caps, err := readCaps(file)
+	if err != nil {
+		return script, err
+	}
+	caps.Plugin = filepath.Base(file)
+	script.Caps = caps
+	if trusted {
+		caps.grantAll()
+	} else {
+		caps.loadGrants(broker)
+		if pending := caps.Pending(); pending != nil {
+			lazlo.Logger.Info("luaMod:: ", caps.Plugin, " is waiting for an admin to grant it ", strings.Join(pending, ", "))
+		}
+		luaPluginsLock.Lock()
+		luaPlugins[caps.Plugin] = caps
+		luaPluginsLock.Unlock()
+	}
+
+	// register hear and respond inside this lua state
+	script.State.SetGlobal("robot", luar.New(script.State, script.Robot))
+	r := script.Robot
+	// and the lot as a module, for scripts that'd rather local lazlo = require "lazlo"
+	script.State.PreloadModule("lazlo", luar.NewModule(luaModule(r)))
+	// luar.select waits on several channels at once
+	script.State.PreloadModule("luar", luar.Loader)
+	// and some of go's standard library, for scripts to require
+	for name, values := range luaPackages {
+		luar.Module(script.State, name, values)
+	}
+	// (regexp's a lua-friendly wrapper, with a cache of its own)
+	luar.Module(script.State, "regexp", luaRegexpModule(script.State))
+	luar.Module(script.State, "crypto", luaCryptoModule())
+	LuaScripts = append(LuaScripts, script)
+
+	// the lua script will register callbacks to the Cases
+	return script, script.State.DoFile(file)
+}
+
+//luaModule is what require "lazlo" gives a script: robot, its methods as
+//functions, and the bot's name
+func luaModule(r *Robot) map[string]interface{} {
+	return map[string]interface{}{
+		"robot":     r,
+		"botname":   broker.Config.Name,
+		"hear":      r.Hear,
+		"respond":   r.Respond,
+		"say":       r.Say,
+		"fetch":     r.Fetch,
+		"remember":  r.Remember,
+		"recall":    r.Recall,
+		"forget":    r.Forget,
+		"exec":      r.Exec,
+		"publish":   r.Publish,
+		"subscribe": r.Subscribe,
+		"form":      r.Form,
+		"spawn":     r.Spawn,
+		"schedule":  r.Schedule,
+		"webhook":   r.Webhook,
+		"after":     luaAfter,
+		"storage": map[string]interface{}{
+			"usage":         r.StorageUsage,
+			"on_over_quota": r.OnOverQuota,
+		},
+	}
+}
+
+//luaPackages are the bits of go's standard library scripts can require,
+//under the same names as in go. They don't need any capabilities.
+var luaPackages = map[string]map[string]interface{}{
+	"strings": luar.PackageOf(
+		strings.Contains, strings.ContainsAny, strings.Count, strings.EqualFold,
+		strings.Fields, strings.HasPrefix, strings.HasSuffix, strings.Index,
+		strings.Join, strings.LastIndex, strings.Repeat, strings.Replace,
+		strings.Split, strings.SplitN, strings.Title, strings.ToLower,
+		strings.ToUpper, strings.Trim, strings.TrimLeft, strings.TrimPrefix,
+		strings.TrimRight, strings.TrimSpace, strings.TrimSuffix,
+	),
+	"strconv": luar.PackageOf(
+		strconv.Atoi, strconv.FormatBool, strconv.FormatFloat, strconv.FormatInt,
+		strconv.Itoa, strconv.ParseBool, strconv.ParseFloat, strconv.ParseInt,
+		strconv.Quote, strconv.Unquote,
+	),
+	"time": luar.PackageOf(
+		time.Now, time.Parse, time.ParseDuration, time.Since, time.Until,
+	),
+}
+
+//luaAfter is a channel that gets the time after d (a number of seconds, or a
+//duration like "5m"), which tryreceive can check on
+func luaAfter(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+//lintLuaScript logs what's wrong with a script, and tells the admins about it
+//in LAZLO_ADMIN_CHANNEL. It doesn't stop the script loading.
+func lintLuaScript(b *lazlo.Broker, file string) {
+	issues, err := LintLuaFile(file)
+	if err != nil || len(issues) == 0 {
+		return
+	}
+	var lines []string
+	for _, issue := range issues {
+		lazlo.Logger.Warning("luaMod:: ", issue)
+		lines = append(lines, issue.String())
+	}
+	if channel := strings.TrimPrefix(b.Config.AdminChannel, "#"); channel != "" {
+		if c := b.SlackMeta.GetChannelByName(channel); c != nil {
+			channel = c.ID
+		}
+		b.Say(fmt.Sprintf("lazlo plugin lint found problems in %s:\n```%s```", filepath.Base(file), strings.Join(lines, "\n")), channel)
+	}
+}
+
+//luaVersion is the script's VERSION global if it sets one, or a hash of the
+//script otherwise
+func luaVersion(L *lua.LState, file string) string {
+	if v, ok := L.GetGlobal("VERSION").(lua.LString); ok {
+		return string(v)
+	}
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha1.Sum(data))[:7]
+}
+
 //pmTranslate makes a localized version of patternmatch so we can export it to lua
 //with a few additional methods.
 func pmTranslate(in lazlo.PatternMatch) LocalPatternMatch {
 	return LocalPatternMatch{
-		Event: in.Event,
+		Event: luaEvent(in.Event),
 		Match: in.Match,
+		event: in.Event,
 	}
 }
 
+//luaEvent is a copy of the event that's safe to hand to lua: without the
+//Broker, through which a script could write to the brain, talk anywhere or
+//read lazlo's tokens
+func luaEvent(e *lazlo.Event) *lazlo.Event {
+	if e == nil {
+		return nil
+	}
+	copied := *e
+	copied.Broker = nil
+	return &copied
+}
+
+//luaUnsafe are the globals a new lua state has that get around the
+//capabilities: os and io run commands and touch files, debug reaches into
+//other functions, and dofile and loadfile read files. (coroutine and
+//channel go too; scripts have robot:Spawn and luar.select.)
+var luaUnsafe = []string{"os", "io", "debug", "coroutine", "channel", "dofile", "loadfile"}
+
+//newLuaState makes a lua state with just the base, table, string, math and
+//package libraries
+func newLuaState() *lua.LState {
+	L := lua.NewState()
+	loaded, _ := L.GetField(L.GetGlobal("package"), "loaded").(*lua.LTable)
+	for _, name := range luaUnsafe {
+		L.SetGlobal(name, lua.LNil)
+		if loaded != nil {
+			// or require "os" would hand it back
+			loaded.RawSetH(lua.LString(name), lua.LNil)
+		}
+	}
+	return L
+}
+
 //handle takes the index and value of an event from lazlo,
 //typifies the value and calls the right function to push the data back
 //to whatever lua script asked for it.
@@ -123,8 +324,12 @@ func handle(index int, val interface{}) {
 		handleTimerCB(index, val.(time.Time))
 	case map[string]interface{}:
 		handleEventCB(index, val.(map[string]interface{}))
+	case lazlo.TopicMessage:
+		handleTopicCB(index, val.(lazlo.TopicMessage))
+	case lazlo.ModalSubmission:
+		handleModalCB(index, val.(lazlo.ModalSubmission))
 	case *http.Request:
-		handleLinkCB(index, val.(*http.Response))
+		handleLinkCB(index, val.(*http.Request))
 	default:
 		err := fmt.Errorf("luaMod handle:: unknown type: %T", val)
 		lazlo.Logger.Error(err)
@@ -147,7 +352,8 @@ func handleMessageCB(index int, message LocalPatternMatch) {
 
 //handleTimerCB brokers timer alarms back to the lua script that asked for them
 func handleTimerCB(index int, t time.Time) {
-	return
+	cb := CBTable[index].Callback.Interface().(*lazlo.TimerCallback)
+	callContext(index, broker.ScheduleContext(cb.Schedule, t))
 }
 
 //handleEventCB brokers slack rtm events back to the lua script that asked for them
@@ -155,9 +361,21 @@ func handleEventCB(index int, event map[string]interface{}) {
 	return
 }
 
-//handleLinkCB brokers http GET requests back to the lua script that asked for them
-func handleLinkCB(index int, resp *http.Response) {
-	return
+//handleLinkCB brokers http requests back to the lua script that asked for them
+func handleLinkCB(index int, req *http.Request) {
+	callContext(index, broker.WebhookContext(req))
+}
+
+//callContext calls a schedule or webhook's lua function with its context
+func callContext(index int, ctx *lazlo.Context) {
+	l := CBTable[index].Script.State
+	if err := l.CallByParam(lua.P{
+		Fn:      CBTable[index].Func,
+		NRet:    0,
+		Protect: false,
+	}, luar.New(l, luaContext(ctx))); err != nil {
+		panic(err)
+	}
 }
 
 //creates a new message callback from robot.hear/respond
@@ -184,23 +402,52 @@ func newMsgCallback(RID int, pat string, lfunc lua.LValue, isResponse bool) {
 
 //lua function to overhear a message
 func (r Robot) Hear(pat string, lfunc lua.LValue) {
-	newMsgCallback(r.ID, pat, lfunc, false)
+	newMsgCallback(r.id, pat, lfunc, false)
 }
 
-/*func Hear(id int, pat string, lfunc lua.LValue){
-	newMsgCallback(id, pat, lfunc, false)
-}*/
-
 //lua function to process a command
 func (r Robot) Respond(pat string, lfunc lua.LValue) {
-	newMsgCallback(r.ID, pat, lfunc, true)
+	newMsgCallback(r.id, pat, lfunc, true)
 }
 
-/*func Respond(id int, pat string, lfunc lua.LValue){
-	newMsgCallback(id, pat, lfunc, true)
-}*/
-
 //lua function to reply to a message passed to a lua-side callback
 func (pm LocalPatternMatch) Reply(words string) {
-	pm.Event.Reply(words)
+	pm.event.Reply(words)
+}
+
+//lua function to get the message's context, so handlers shared with
+//robot:Schedule and robot:Webhook can take the same thing
+func (pm LocalPatternMatch) Context() *lazlo.Context {
+	return luaContext(broker.MessageContext(lazlo.PatternMatch{Event: pm.event, Match: pm.Match}))
+}
+
+//luaContext is a copy of the context with a lua-safe Event (see luaEvent).
+//It still replies through the real one.
+func luaContext(ctx *lazlo.Context) *lazlo.Context {
+	copied := *ctx
+	copied.Event = luaEvent(ctx.Event)
+	return &copied
+}
+
+//lua function to get the conversation a message was said in: the messages
+//before it, the workflows running there, and the user's prefs
+func (pm LocalPatternMatch) Conversation() *lazlo.Conversation {
+	conversation := *pm.event.Conversation()
+	recent := make([]lazlo.Event, len(conversation.Recent))
+	for i := range conversation.Recent {
+		recent[i] = *luaEvent(&conversation.Recent[i])
+	}
+	conversation.Recent = recent
+	return &conversation
+}
+
+//lua function to GET a url through lazlo's shared (cached, rate-limited)
+//fetcher. Returns the body, the http status, and an error string.
+func (r Robot) Fetch(url string) (string, int, string) {
+	r.need(capHTTP)
+	resp, err := broker.Fetch(url)
+	if err != nil {
+		return ``, 0, err.Error()
+	}
+	return string(resp.Body), resp.Status, ``
 }
@@ -0,0 +1,187 @@
+package modules
+
+import (
+	lua "github.com/yuin/gopher-lua"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//require "regexp" gives scripts go's regular expressions (RE2, the same ones
+//robot:Hear and robot:Respond use), since lua's own patterns aren't
+//regexes. Every function takes a pattern or a compiled regexp, and the
+//compiled ones have the same functions as methods:
+//
+//	local regexp = require "regexp"
+//	local m = regexp.match("^deploy (?P<app>\\S+)", text)
+//	local re = regexp.compile("[0-9]+")
+//	local nums = re:find_all(text)
+//
+//Matches are tables with the whole match at [0], the groups from [1], and
+//named groups by name too. Groups that didn't match are "", as in go.
+
+//luaRegexpType names the metatable compiled regexps get
+const luaRegexpType = "lazlo.regexp"
+
+//luaRegexpCacheSize is how many patterns a script's cache keeps before it
+//starts over
+const luaRegexpCacheSize = 256
+
+//luaRegexpCache keeps a script's compiled patterns, so handlers that match
+//the same pattern on every message only compile it once
+type luaRegexpCache struct {
+	lock     sync.Mutex
+	compiled map[string]*regexp.Regexp
+}
+
+func (c *luaRegexpCache) compile(pattern string) (*regexp.Regexp, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if re, ok := c.compiled[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.compiled) >= luaRegexpCacheSize {
+		c.compiled = make(map[string]*regexp.Regexp)
+	}
+	c.compiled[pattern] = re
+	return re, nil
+}
+
+//luaRegexpModule is what require "regexp" gives the script in L
+func luaRegexpModule(L *lua.LState) map[string]interface{} {
+	cache := &luaRegexpCache{compiled: make(map[string]*regexp.Regexp)}
+	check := func(L *lua.LState) *regexp.Regexp {
+		if ud, ok := L.Get(1).(*lua.LUserData); ok {
+			if re, ok := ud.Value.(*regexp.Regexp); ok {
+				return re
+			}
+		}
+		re, err := cache.compile(L.CheckString(1))
+		if err != nil {
+			L.ArgError(1, err.Error())
+		}
+		return re
+	}
+
+	funcs := map[string]lua.LGFunction{
+		"match": func(L *lua.LState) int {
+			re, s := check(L), L.CheckString(2)
+			loc := re.FindStringSubmatchIndex(s)
+			if loc == nil {
+				L.Push(lua.LNil)
+			} else {
+				L.Push(luaMatch(L, re, s, loc))
+			}
+			return 1
+		},
+		"test": func(L *lua.LState) int {
+			re, s := check(L), L.CheckString(2)
+			L.Push(lua.LBool(re.MatchString(s)))
+			return 1
+		},
+		"find_all": func(L *lua.LState) int {
+			re, s := check(L), L.CheckString(2)
+			matches := L.NewTable()
+			for _, loc := range re.FindAllStringSubmatchIndex(s, L.OptInt(3, -1)) {
+				matches.Append(luaMatch(L, re, s, loc))
+			}
+			L.Push(matches)
+			return 1
+		},
+		"replace": func(L *lua.LState) int {
+			re, s := check(L), L.CheckString(2)
+			switch repl := L.Get(3).(type) {
+			case lua.LString:
+				// $1 and ${name} expand, as in go
+				L.Push(lua.LString(re.ReplaceAllString(s, string(repl))))
+			case *lua.LFunction:
+				L.Push(lua.LString(luaReplaceFunc(L, re, s, repl)))
+			default:
+				L.TypeError(3, lua.LTString)
+			}
+			return 1
+		},
+		"split": func(L *lua.LState) int {
+			re, s := check(L), L.CheckString(2)
+			parts := L.NewTable()
+			for _, part := range re.Split(s, L.OptInt(3, -1)) {
+				parts.Append(lua.LString(part))
+			}
+			L.Push(parts)
+			return 1
+		},
+		"pattern": func(L *lua.LState) int {
+			L.Push(lua.LString(check(L).String()))
+			return 1
+		},
+	}
+
+	meta := L.NewTypeMetatable(luaRegexpType)
+	meta.RawSetH(lua.LString("__index"), L.SetFuncs(L.NewTable(), funcs))
+	meta.RawSetH(lua.LString("__tostring"), L.NewFunction(funcs["pattern"]))
+
+	module := map[string]interface{}{
+		"compile": func(L *lua.LState) int {
+			re, err := cache.compile(L.CheckString(1))
+			if err != nil {
+				L.Push(lua.LNil)
+				L.Push(lua.LString(err.Error()))
+				return 2
+			}
+			ud := L.NewUserData()
+			ud.Value = re
+			L.SetMetatable(ud, meta)
+			L.Push(ud)
+			return 1
+		},
+		"quote": func(L *lua.LState) int {
+			L.Push(lua.LString(regexp.QuoteMeta(L.CheckString(1))))
+			return 1
+		},
+	}
+	for name, fn := range funcs {
+		module[name] = fn
+	}
+	return module
+}
+
+//luaMatch makes the table for a match at loc (from a Find...SubmatchIndex)
+func luaMatch(L *lua.LState, re *regexp.Regexp, s string, loc []int) *lua.LTable {
+	names := re.SubexpNames()
+	m := L.CreateTable(len(names)-1, 1)
+	for i, name := range names {
+		group := ""
+		if loc[2*i] >= 0 {
+			group = s[loc[2*i]:loc[2*i+1]]
+		}
+		m.RawSetInt(i, lua.LString(group))
+		if name != "" {
+			m.RawSetH(lua.LString(name), lua.LString(group))
+		}
+	}
+	return m
+}
+
+//luaReplaceFunc replaces each match of re in s with what fn returns when it's
+//given the match; nil or false leaves the match as it was, as with gsub
+func luaReplaceFunc(L *lua.LState, re *regexp.Regexp, s string, fn *lua.LFunction) string {
+	var out []string
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		L.CallByParam(lua.P{Fn: fn, NRet: 1}, luaMatch(L, re, s, loc))
+		repl := L.Get(-1)
+		L.Pop(1)
+		out = append(out, s[last:loc[0]])
+		if lua.LVAsBool(repl) {
+			out = append(out, lua.LVAsString(repl))
+		} else {
+			out = append(out, s[loc[0]:loc[1]])
+		}
+		last = loc[1]
+	}
+	return strings.Join(append(out, s[last:]), "")
+}
@@ -0,0 +1,333 @@
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	lua "github.com/yuin/gopher-lua"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+//Lua plugins can have specs: lua scripts in the plugin directory's spec
+//directory, named after the plugin they test (greet.lua's go in
+//spec/greet_spec.lua). Every spec file gets a fresh copy of every plugin,
+//loaded into a fake broker (see lazlo.NewFakeBroker), and we keep track of
+//which of the plugins' patterns the specs exercised.
+
+//A SpecReport is what running a plugin directory's specs found
+type SpecReport struct {
+	Plugins  []string
+	Results  []SpecResult
+	Patterns []*PatternCoverage
+}
+
+//A SpecResult is the outcome of one test in a spec file
+type SpecResult struct {
+	Spec string
+	Name string
+	Err  string // empty if the test passed
+}
+
+//PatternCoverage counts the messages that matched one of a plugin's patterns
+type PatternCoverage struct {
+	Plugin  string
+	Pattern string
+	Respond bool
+	Hits    int
+}
+
+//a specRun is one spec file's run against the plugins
+type specRun struct {
+	broker *lazlo.Broker
+	hits   []*PatternCoverage // by callback case index
+}
+
+//RunLuaSpecs runs the specs for the plugins in dir
+func RunLuaSpecs(dir string) (*SpecReport, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var plugins []string
+	for _, f := range files {
+		if !f.IsDir() {
+			plugins = append(plugins, f.Name())
+		}
+	}
+	specs, _ := filepath.Glob(filepath.Join(dir, `spec`, `*_spec.lua`))
+	if len(specs) == 0 {
+		//just load the plugins, to find out what they'd have to cover
+		specs = []string{``}
+	}
+	report := &SpecReport{Plugins: plugins}
+	coverage := make(map[string]*PatternCoverage)
+	for _, spec := range specs {
+		if err := runLuaSpec(dir, plugins, spec, report, coverage); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+//runLuaSpec loads the plugins into a fake broker and runs one spec file
+//against them. LuaMod's globals are put back the way they were afterwards.
+func runLuaSpec(dir string, plugins []string, spec string, report *SpecReport, coverage map[string]*PatternCoverage) error {
+	fake, err := lazlo.NewFakeBroker()
+	if err != nil {
+		return err
+	}
+	savedBroker, savedScripts, savedCBs, savedCases := broker, LuaScripts, CBTable, Cases
+	broker, LuaScripts, CBTable, Cases = fake, nil, nil, nil
+	defer func() {
+		for _, script := range LuaScripts {
+			cancelJobs(script.Robot.id)
+			script.State.Close()
+		}
+		broker, LuaScripts, CBTable, Cases = savedBroker, savedScripts, savedCBs, savedCases
+	}()
+
+	name := `(no specs)`
+	if spec != `` {
+		name = filepath.Base(spec)
+	}
+	for _, plugin := range plugins {
+		if _, err := loadLuaScript(filepath.Join(dir, plugin), true); err != nil {
+			report.Results = append(report.Results, SpecResult{Spec: name, Name: `loading ` + plugin, Err: luaErrMsg(err)})
+			return nil
+		}
+	}
+	run := &specRun{broker: fake, hits: make([]*PatternCoverage, len(CBTable))}
+	for i, entry := range CBTable {
+		cb, ok := entry.Callback.Interface().(*lazlo.MessageCallback)
+		if !ok {
+			continue
+		}
+		plugin := plugins[entry.Script.Robot.id]
+		key := fmt.Sprintf("%s %v %s", plugin, cb.Respond, cb.Pattern)
+		if coverage[key] == nil {
+			coverage[key] = &PatternCoverage{Plugin: plugin, Pattern: cb.Pattern, Respond: cb.Respond}
+			report.Patterns = append(report.Patterns, coverage[key])
+		}
+		run.hits[i] = coverage[key]
+	}
+	if spec == `` {
+		return nil
+	}
+
+	L := newLuaState()
+	defer L.Close()
+	run.register(L, name, report)
+	if err := L.DoFile(spec); err != nil {
+		report.Results = append(report.Results, SpecResult{Spec: name, Name: `(outside any test)`, Err: luaErrMsg(err)})
+	}
+	return nil
+}
+
+//register gives the spec's lua state the functions specs are written with
+func (s *specRun) register(L *lua.LState, spec string, report *SpecReport) {
+	L.SetGlobal("BOTNAME", lua.LString(s.broker.Config.Name))
+	L.SetGlobal("test", L.NewFunction(func(L *lua.LState) int {
+		result := SpecResult{Spec: spec, Name: L.CheckString(1)}
+		if err := L.CallByParam(lua.P{Fn: L.CheckFunction(2), NRet: 0, Protect: true}); err != nil {
+			result.Err = luaErrMsg(err)
+		}
+		report.Results = append(report.Results, result)
+		return 0
+	}))
+	L.SetGlobal("send", L.NewFunction(func(L *lua.LState) int {
+		return s.push(L, L.CheckString(1))
+	}))
+	L.SetGlobal("ask", L.NewFunction(func(L *lua.LState) int {
+		return s.push(L, s.broker.Config.Name+" "+L.CheckString(1))
+	}))
+	L.SetGlobal("expect", L.NewFunction(func(L *lua.LState) int {
+		if !lua.LVAsBool(L.Get(1)) {
+			L.RaiseError("%s", L.OptString(2, "expectation failed"))
+		}
+		return 0
+	}))
+	L.SetGlobal("expect_reply", L.NewFunction(func(L *lua.LState) int {
+		replies := L.CheckTable(1)
+		pattern, err := regexp.Compile(L.CheckString(2))
+		if err != nil {
+			L.RaiseError("bad pattern: %s", err)
+		}
+		var got []string
+		replies.ForEach(func(_ lua.LValue, reply lua.LValue) {
+			got = append(got, lua.LVAsString(reply))
+		})
+		for _, reply := range got {
+			if pattern.MatchString(reply) {
+				return 0
+			}
+		}
+		L.RaiseError("no reply matched %q (got %q)", pattern, got)
+		return 0
+	}))
+	L.SetGlobal("expect_no_reply", L.NewFunction(func(L *lua.LState) int {
+		if n := L.CheckTable(1).Len(); n > 0 {
+			L.RaiseError("expected no reply, got %d", n)
+		}
+		return 0
+	}))
+}
+
+//push sends text and pushes the replies onto the lua stack as a table
+func (s *specRun) push(L *lua.LState, text string) int {
+	replies, err := s.send(text)
+	if err != nil {
+		L.RaiseError("%s", err)
+	}
+	t := L.NewTable()
+	for _, reply := range replies {
+		t.Append(lua.LString(reply))
+	}
+	L.Push(t)
+	return 1
+}
+
+//send says text to the fake broker as the test user, runs every plugin
+//callback it matches, and returns whatever the plugins said
+func (s *specRun) send(text string) ([]string, error) {
+	heard := make(chan struct{})
+	go func() {
+		s.broker.Hear(lazlo.FakeUser, lazlo.FakeChannel, text)
+		close(heard)
+	}()
+	cases := append([]reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(heard)}}, Cases...)
+	var failed error
+	for {
+		index, value, _ := reflect.Select(cases)
+		if index == 0 {
+			break
+		}
+		if hit := s.hits[index-1]; hit != nil {
+			hit.Hits++
+		}
+		if err := handleSafely(index-1, value.Interface()); err != nil && failed == nil {
+			failed = err
+		}
+	}
+	var replies []string
+	for _, e := range s.broker.Said() {
+		replies = append(replies, e.Text)
+	}
+	return replies, failed
+}
+
+//handleSafely is handle, but a plugin that blows up is an error instead of
+//a panic
+func handleSafely(index int, val interface{}) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s", luaErrMsg(r))
+		}
+	}()
+	handle(index, val)
+	return nil
+}
+
+//luaErrMsg is the message of a lua error, without the stack traceback
+func luaErrMsg(err interface{}) string {
+	msg := fmt.Sprint(err)
+	if apiErr, ok := err.(*lua.ApiError); ok {
+		msg = apiErr.Object.String()
+	}
+	if i := strings.Index(msg, "\nstack traceback:"); i >= 0 {
+		msg = msg[:i]
+	}
+	//errors raised from go are prefixed with [G]: once per call they pass through
+	msg = strings.TrimSpace(msg)
+	for strings.HasPrefix(msg, "[G]:") {
+		msg = strings.TrimSpace(strings.TrimPrefix(msg, "[G]:"))
+	}
+	return msg
+}
+
+//Failed is the number of tests that failed
+func (r *SpecReport) Failed() int {
+	failed := 0
+	for _, result := range r.Results {
+		if result.Err != `` {
+			failed++
+		}
+	}
+	return failed
+}
+
+//Coverage is the percentage of patterns (of plugin's, or every plugin's if
+//plugin is empty) that the specs exercised. Plugins without patterns are
+//fully covered.
+func (r *SpecReport) Coverage(plugin string) float64 {
+	total, hit := 0, 0
+	for _, p := range r.Patterns {
+		if plugin != `` && p.Plugin != plugin {
+			continue
+		}
+		total++
+		if p.Hits > 0 {
+			hit++
+		}
+	}
+	if total == 0 {
+		return 100
+	}
+	return float64(hit) * 100 / float64(total)
+}
+
+//Gate says why each plugin shouldn't be loaded, if its specs fail or cover
+//less than min percent of its patterns
+func (r *SpecReport) Gate(min int) map[string]string {
+	blocked := make(map[string]string)
+	for _, result := range r.Results {
+		if result.Err == `` {
+			continue
+		}
+		plugin := strings.TrimSuffix(result.Spec, `_spec.lua`) + `.lua`
+		if strings.HasPrefix(result.Name, `loading `) {
+			plugin = strings.TrimPrefix(result.Name, `loading `)
+		}
+		if _, done := blocked[plugin]; !done {
+			blocked[plugin] = fmt.Sprintf("%s: %s failed", result.Spec, result.Name)
+		}
+	}
+	for _, plugin := range r.Plugins {
+		if _, done := blocked[plugin]; done {
+			continue
+		}
+		if c := r.Coverage(plugin); c < float64(min) {
+			blocked[plugin] = fmt.Sprintf("its specs cover %.0f%% of its patterns (%d%% required)", c, min)
+		}
+	}
+	return blocked
+}
+
+//String renders the report for people
+func (r *SpecReport) String() string {
+	var out bytes.Buffer
+	for _, result := range r.Results {
+		if result.Err == `` {
+			fmt.Fprintf(&out, "  ok    %s: %s\n", result.Spec, result.Name)
+		} else {
+			fmt.Fprintf(&out, "  FAIL  %s: %s\n        %s\n", result.Spec, result.Name, strings.TrimSpace(result.Err))
+		}
+	}
+	fmt.Fprintf(&out, "\ncoverage:\n")
+	for _, p := range r.Patterns {
+		how := "hear"
+		if p.Respond {
+			how = "respond"
+		}
+		mark := "  "
+		if p.Hits == 0 {
+			mark = "!!"
+		}
+		fmt.Fprintf(&out, "  %s %-20s %-7s %-40q %d hits\n", mark, p.Plugin, how, p.Pattern, p.Hits)
+	}
+	fmt.Fprintf(&out, "\n%d tests, %d failed, %.0f%% of patterns covered\n", len(r.Results), r.Failed(), r.Coverage(``))
+	return out.String()
+}
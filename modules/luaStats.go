@@ -0,0 +1,215 @@
+package modules
+
+import (
+	"fmt"
+	lua "github.com/yuin/gopher-lua"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+//luaStateLock is held while luaMain is using the scripts' lua states (loading
+//them, or running a callback), so stats can be gathered from elsewhere
+//without racing it
+var luaStateLock sync.Mutex
+
+//LuaStateStats is a look inside one script's lua state. Lua values live on
+//the go heap, so Memory is an estimate: roughly what the values reachable
+//from the state's globals, registry and stack take up.
+type LuaStateStats struct {
+	Plugin    string `json:"plugin"`
+	Memory    int    `json:"memory"`
+	Tables    int    `json:"tables"`
+	Functions int    `json:"functions"`
+	Userdata  int    `json:"userdata"`
+	Registry  int    `json:"registry"`  // entries in the lua registry
+	Stack     int    `json:"stack"`     // values on the stack
+	Callbacks int    `json:"callbacks"` // hear/respond callbacks waiting for messages
+	Jobs      int    `json:"jobs"`      // spawned jobs still running
+}
+
+//LuaStats is every script's stats, and the go GC's numbers (lua's GC is
+//go's)
+type LuaStats struct {
+	States    []LuaStateStats `json:"states"`
+	NumGC     uint32          `json:"num_gc"`
+	ForcedGCs int             `json:"forced_gcs"` // by LAZLO_LUA_GC_INTERVAL
+	LastGC    time.Time       `json:"last_gc"`
+}
+
+var luaGCs struct {
+	sync.Mutex
+	forced int
+}
+
+//luaGC frees what it can every interval, for long-lived states whose
+//garbage would otherwise hang around until the go GC got to it
+func luaGC(interval time.Duration) {
+	for range time.Tick(interval) {
+		debug.FreeOSMemory()
+		luaGCs.Lock()
+		luaGCs.forced++
+		luaGCs.Unlock()
+	}
+}
+
+//luaStats gathers the stats, waiting for luaMain to finish whatever callback
+//it's running
+func luaStats() *LuaStats {
+	stats := new(LuaStats)
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	stats.NumGC = m.NumGC
+	if m.LastGC > 0 {
+		stats.LastGC = time.Unix(0, int64(m.LastGC))
+	}
+	luaGCs.Lock()
+	stats.ForcedGCs = luaGCs.forced
+	luaGCs.Unlock()
+
+	luaJobsLock.Lock()
+	jobs := make(map[int]int)
+	for id, list := range luaJobs {
+		jobs[id] = len(list)
+	}
+	luaJobsLock.Unlock()
+
+	luaStateLock.Lock()
+	defer luaStateLock.Unlock()
+	callbacks := make(map[int]int)
+	for _, cb := range CBTable {
+		if cb.Script != nil && cb.Script.Robot != nil {
+			callbacks[cb.Script.Robot.id]++
+		}
+	}
+	for _, script := range LuaScripts {
+		s := luaStateStats(script.State)
+		if script.Caps != nil {
+			s.Plugin = script.Caps.Plugin
+		}
+		s.Callbacks = callbacks[script.Robot.id]
+		s.Jobs = jobs[script.Robot.id]
+		stats.States = append(stats.States, s)
+	}
+	return stats
+}
+
+func (ls *LuaStats) String() string {
+	if len(ls.States) == 0 {
+		return `no lua plugins are loaded`
+	}
+	var lines []string
+	for _, s := range ls.States {
+		lines = append(lines, fmt.Sprintf("%s: ~%.1fKB (%d tables, %d functions, %d userdata), %d registry entries, %d on the stack, %d callbacks, %d jobs",
+			s.Plugin, float64(s.Memory)/1e3, s.Tables, s.Functions, s.Userdata, s.Registry, s.Stack, s.Callbacks, s.Jobs))
+	}
+	sort.Strings(lines)
+	gc := fmt.Sprintf("%d GCs", ls.NumGC)
+	if ls.ForcedGCs > 0 {
+		gc += fmt.Sprintf(" (%d forced)", ls.ForcedGCs)
+	}
+	if !ls.LastGC.IsZero() {
+		gc += fmt.Sprintf(", the last %s ago", time.Since(ls.LastGC)/time.Second*time.Second)
+	}
+	return strings.Join(append(lines, gc), "\n")
+}
+
+//luaStateStats walks everything reachable from the state. Only call it
+//while holding luaStateLock (or on the state's own goroutine).
+func luaStateStats(L *lua.LState) LuaStateStats {
+	var s LuaStateStats
+	if L == nil {
+		return s
+	}
+	w := &luaWalker{stats: &s, seen: make(map[interface{}]bool)}
+	w.walk(L.G.Global)
+	w.walk(L.G.Registry)
+	for i := 1; i <= L.GetTop(); i++ {
+		w.walk(L.Get(i))
+	}
+	L.G.Registry.ForEach(func(lua.LValue, lua.LValue) { s.Registry++ })
+	s.Stack = L.GetTop()
+	return s
+}
+
+//luaWalker adds up the values it walks, each only once
+type luaWalker struct {
+	stats *LuaStateStats
+	seen  map[interface{}]bool
+}
+
+//rough sizes of lua values on the go heap, in bytes
+const (
+	luaValueSize    = 16
+	luaTableSize    = 96
+	luaEntrySize    = 32
+	luaFunctionSize = 64
+	luaUserdataSize = 48
+)
+
+func (w *luaWalker) walk(v lua.LValue) {
+	switch v := v.(type) {
+	case lua.LString:
+		w.stats.Memory += luaValueSize + len(v)
+	case *lua.LTable:
+		if v == nil || w.seen[v] {
+			return
+		}
+		w.seen[v] = true
+		w.stats.Tables++
+		w.stats.Memory += luaTableSize
+		v.ForEach(func(key lua.LValue, value lua.LValue) {
+			w.stats.Memory += luaEntrySize
+			w.walk(key)
+			w.walk(value)
+		})
+		w.walk(v.Metatable)
+	case *lua.LFunction:
+		if v == nil || w.seen[v] {
+			return
+		}
+		w.seen[v] = true
+		w.stats.Functions++
+		w.stats.Memory += luaFunctionSize + luaValueSize*len(v.Upvalues)
+		w.walkProto(v.Proto)
+		for _, uv := range v.Upvalues {
+			if uv != nil {
+				w.walk(uv.Value())
+			}
+		}
+		if v.Env != nil {
+			w.walk(v.Env)
+		}
+	case *lua.LUserData:
+		if v == nil || w.seen[v] {
+			return
+		}
+		w.seen[v] = true
+		w.stats.Userdata++
+		w.stats.Memory += luaUserdataSize
+		w.walk(v.Metatable)
+		if v.Env != nil {
+			w.walk(v.Env)
+		}
+	case nil:
+	default:
+		w.stats.Memory += luaValueSize
+	}
+}
+
+func (w *luaWalker) walkProto(p *lua.FunctionProto) {
+	if p == nil || w.seen[p] {
+		return
+	}
+	w.seen[p] = true
+	w.stats.Memory += 4*len(p.Code) + 8*len(p.DbgSourcePositions)
+	for _, c := range p.Constants {
+		w.walk(c)
+	}
+	for _, child := range p.FunctionPrototypes {
+		w.walkProto(child)
+	}
+}
@@ -0,0 +1,198 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	lua "github.com/yuin/gopher-lua"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Every lua plugin's keys live under lua:<plugin>: in the brain, and nothing
+//stopped a plugin that logged everything it heard from filling it up. Each
+//plugin can use LAZLO_LUA_STORAGE_QUOTA bytes (of values). A plugin can see
+//what it's using with robot:StorageUsage(), and register a hook with
+//robot:OnOverQuota(fn) that's called when a write would take it over, to
+//make room; if there still isn't room, the write fails. Admins can see who's
+//using what with "!lua storage".
+
+//LuaStorageUsage is how much of the brain a plugin is using
+type LuaStorageUsage struct {
+	Plugin string `json:"plugin"`
+	Keys   int    `json:"keys"`
+	Bytes  int    `json:"bytes"`
+}
+
+//luaQuotaHook is a plugin's OnOverQuota function, and its script's ID
+type luaQuotaHook struct {
+	fn lua.LValue
+	id int
+}
+
+var luaStorage = struct {
+	sync.Mutex
+	usage map[string]*LuaStorageUsage // by plugin, counted the first time it's needed
+	hooks map[string]luaQuotaHook
+}{usage: make(map[string]*LuaStorageUsage), hooks: make(map[string]luaQuotaHook)}
+
+//countLuaStorage counts what every plugin has in the brain (or just the one,
+//if plugin isn't ""), and remembers it
+func countLuaStorage(plugin string) (map[string]*LuaStorageUsage, error) {
+	keys, err := broker.Brain.Keys()
+	if err != nil {
+		return nil, err
+	}
+	counted := make(map[string]*LuaStorageUsage)
+	if plugin != `` {
+		counted[plugin] = &LuaStorageUsage{Plugin: plugin}
+	}
+	for _, key := range keys {
+		if !strings.HasPrefix(key, `lua:`) {
+			continue
+		}
+		parts := strings.SplitN(key, `:`, 3)
+		if len(parts) != 3 || (plugin != `` && parts[1] != plugin) {
+			continue
+		}
+		u, ok := counted[parts[1]]
+		if !ok {
+			u = &LuaStorageUsage{Plugin: parts[1]}
+			counted[parts[1]] = u
+		}
+		u.Keys++
+		if data, err := broker.Brain.Get(key); err == nil {
+			u.Bytes += len(data)
+		}
+	}
+	luaStorage.Lock()
+	for name, u := range counted {
+		luaStorage.usage[name] = u
+	}
+	luaStorage.Unlock()
+	return counted, nil
+}
+
+//luaStorageUsage returns what the plugin's using, counting it if it hasn't
+//been counted yet
+func luaStorageUsage(plugin string) (LuaStorageUsage, error) {
+	luaStorage.Lock()
+	u, ok := luaStorage.usage[plugin]
+	luaStorage.Unlock()
+	if !ok {
+		counted, err := countLuaStorage(plugin)
+		if err != nil {
+			return LuaStorageUsage{Plugin: plugin}, err
+		}
+		u = counted[plugin]
+	}
+	return *u, nil
+}
+
+//adjustLuaStorage adds to what the plugin's using, once a write has gone
+//through
+func adjustLuaStorage(plugin string, keys int, bytes int) {
+	luaStorage.Lock()
+	defer luaStorage.Unlock()
+	if u, ok := luaStorage.usage[plugin]; ok {
+		u.Keys += keys
+		u.Bytes += bytes
+	}
+}
+
+//makeRoom checks the plugin has room for bytes more, calling its
+//OnOverQuota hook if it doesn't. Writes that don't grow what it's using
+//always fit.
+func makeRoom(plugin string, bytes int) error {
+	quota := broker.Config.LuaStorageQuota
+	if quota <= 0 || bytes <= 0 {
+		return nil
+	}
+	u, err := luaStorageUsage(plugin)
+	if err != nil || u.Bytes+bytes <= quota {
+		return err
+	}
+	// the brain's GC might have deleted some since we counted
+	counted, err := countLuaStorage(plugin)
+	if err != nil {
+		return err
+	}
+	if counted[plugin].Bytes+bytes <= quota {
+		return nil
+	}
+	luaStorage.Lock()
+	hook, ok := luaStorage.hooks[plugin]
+	luaStorage.Unlock()
+	if ok {
+		over := counted[plugin].Bytes + bytes - quota
+		lazlo.Logger.Info(`luaMod:: `, plugin, ` is `, over, ` bytes over its storage quota; calling its OnOverQuota`)
+		if err := LuaScripts[hook.id].State.CallByParam(lua.P{
+			Fn:      hook.fn,
+			NRet:    0,
+			Protect: true,
+		}, lua.LNumber(counted[plugin].Bytes), lua.LNumber(quota), lua.LNumber(over)); err != nil {
+			lazlo.Logger.Error(`luaMod:: `, plugin, `'s OnOverQuota failed: `, err)
+		}
+		if u, err = luaStorageUsage(plugin); err == nil && u.Bytes+bytes <= quota {
+			return nil
+		}
+	}
+	lazlo.Logger.Warning(`luaMod:: `, plugin, ` is out of storage (`, counted[plugin].Bytes, ` of `, quota, ` bytes)`)
+	return fmt.Errorf("%s would go over its storage quota of %d bytes", plugin, quota)
+}
+
+//luaStorageReport ranks the plugins by how much of the brain they're using
+func luaStorageReport() string {
+	counted, err := countLuaStorage(``)
+	if err != nil {
+		return fmt.Sprintf("I couldn't look in the brain: %s", err)
+	}
+	if len(counted) == 0 {
+		return `the lua plugins haven't stored anything`
+	}
+	var usage []*LuaStorageUsage
+	for _, u := range counted {
+		usage = append(usage, u)
+	}
+	sort.Sort(byStorage(usage))
+	quota := broker.Config.LuaStorageQuota
+	lines := []string{`what each lua plugin has in the brain, biggest first:`}
+	for _, u := range usage {
+		line := fmt.Sprintf("%s: %d bytes in %d keys", u.Plugin, u.Bytes, u.Keys)
+		if quota > 0 {
+			line += fmt.Sprintf(" (%d%% of its quota)", u.Bytes*100/quota)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+type byStorage []*LuaStorageUsage
+
+func (s byStorage) Len() int      { return len(s) }
+func (s byStorage) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s byStorage) Less(i, j int) bool {
+	if s[i].Bytes != s[j].Bytes {
+		return s[i].Bytes > s[j].Bytes
+	}
+	return s[i].Plugin < s[j].Plugin
+}
+
+//lua function returning how many bytes (of values) the plugin has in the
+//brain, in how many keys, and its quota (0 if there isn't one)
+func (r Robot) StorageUsage() (int, int, int) {
+	u, err := luaStorageUsage(LuaScripts[r.id].Caps.Plugin)
+	if err != nil {
+		lazlo.Logger.Error(`luaMod:: couldn't count `, u.Plugin, `'s storage: `, err)
+	}
+	return u.Bytes, u.Keys, broker.Config.LuaStorageQuota
+}
+
+//lua function to register fn(bytes, quota, over) to be called when a write
+//would take the plugin over its quota (by over bytes), so it can make room
+//(by forgetting what it doesn't need)
+func (r Robot) OnOverQuota(lfunc lua.LValue) {
+	luaStorage.Lock()
+	luaStorage.hooks[LuaScripts[r.id].Caps.Plugin] = luaQuotaHook{fn: lfunc, id: r.id}
+	luaStorage.Unlock()
+}
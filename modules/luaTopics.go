@@ -0,0 +1,113 @@
+package modules
+
+import (
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	luar "github.com/layeh/gopher-luar"
+	lua "github.com/yuin/gopher-lua"
+	"reflect"
+)
+
+//handleTopicCB brokers published messages back to the lua script that
+//subscribed to them as fn(topic, data)
+func handleTopicCB(index int, msg lazlo.TopicMessage) {
+	l := CBTable[index].Script.State
+	if err := l.CallByParam(lua.P{
+		Fn:      CBTable[index].Func,
+		NRet:    0,
+		Protect: true,
+	}, lua.LString(msg.Topic), goToLua(l, msg.Data)); err != nil {
+		lazlo.Logger.Error(`luaMod:: error handling topic `, msg.Topic, `: `, err)
+	}
+}
+
+//creates a new topic callback from robot.subscribe
+func newTopicCallback(RID int, topic string, lfunc lua.LValue) {
+	// cbtable and cases indexes have to match
+	if len(CBTable) != len(Cases) {
+		panic(`cbtable != cases`)
+	}
+	cb := broker.TopicCallback(topic)
+	cbEntry := CBMap{
+		Func:     lfunc,
+		Callback: reflect.ValueOf(cb),
+		Script:   &LuaScripts[RID],
+	}
+	caseEntry := reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(cb.Chan),
+	}
+	CBTable = append(CBTable, cbEntry)
+	Cases = append(Cases, caseEntry)
+}
+
+//luaToGo deep-converts a lua value into plain go values. Tables with only
+//consecutive integer keys (starting at 1) become []interface{}, all other
+//tables become map[string]interface{}.
+func luaToGo(val interface{}) interface{} {
+	switch v := val.(type) {
+	case *lua.LNilType:
+		return nil
+	case lua.LBool:
+		return bool(v)
+	case lua.LNumber:
+		return float64(v)
+	case lua.LString:
+		return string(v)
+	case *lua.LUserData:
+		return v.Value
+	case *lua.LTable:
+		if n := v.Len(); n > 0 && tableSize(v) == n {
+			list := make([]interface{}, n)
+			for i := 1; i <= n; i++ {
+				list[i-1] = luaToGo(v.RawGetInt(i))
+			}
+			return list
+		}
+		m := make(map[string]interface{})
+		v.ForEach(func(key lua.LValue, val lua.LValue) {
+			m[key.String()] = luaToGo(val)
+		})
+		return m
+	default:
+		return v
+	}
+}
+
+func tableSize(t *lua.LTable) int {
+	size := 0
+	t.ForEach(func(lua.LValue, lua.LValue) { size++ })
+	return size
+}
+
+//goToLua deep-converts the plain go values luaToGo makes back into lua
+//tables. Anything else is handed to luar.
+func goToLua(L *lua.LState, val interface{}) lua.LValue {
+	switch v := val.(type) {
+	case []interface{}:
+		t := L.NewTable()
+		for _, item := range v {
+			t.Append(goToLua(L, item))
+		}
+		return t
+	case map[string]interface{}:
+		t := L.NewTable()
+		for key, item := range v {
+			t.RawSetH(lua.LString(key), goToLua(L, item))
+		}
+		return t
+	default:
+		return luar.New(L, v)
+	}
+}
+
+//functions exported to the lua runtime below here
+
+//lua function to publish data to a topic
+func (r Robot) Publish(topic string, data interface{}) {
+	broker.Publish(topic, luaToGo(data))
+}
+
+//lua function to subscribe to a topic
+func (r Robot) Subscribe(topic string, lfunc lua.LValue) {
+	newTopicCallback(r.id, topic, lfunc)
+}
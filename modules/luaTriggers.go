@@ -0,0 +1,52 @@
+package modules
+
+import (
+	"fmt"
+	lua "github.com/yuin/gopher-lua"
+	"reflect"
+	"strings"
+)
+
+//Besides messages, lua plugins can be set off on a schedule or by a webhook.
+//Their functions get a lazlo.Context (see lib/context.go) rather than a
+//message, and messages have msg:Context() so a handler can serve all three.
+
+//lua function to call fn(ctx) on a cron schedule
+func (r Robot) Schedule(schedule string, lfunc lua.LValue) {
+	cb := broker.TimerCallback(schedule)
+	if cb == nil {
+		panic(fmt.Errorf("couldn't schedule %q", schedule))
+	}
+	newContextCallback(r.id, cb, cb.Chan, lfunc)
+}
+
+//lua function to call fn(ctx) when something requests the webhook's url,
+//which it returns. Names are the plugin's own, so two plugins can both have
+//a "deploy" webhook.
+func (r Robot) Webhook(name string, lfunc lua.LValue) string {
+	cb := broker.LinkCallback(fmt.Sprintf("lua-%s-%s", strings.TrimSuffix(LuaScripts[r.id].Caps.Plugin, ".lua"), name), nil)
+	if cb == nil {
+		panic(fmt.Errorf("couldn't register the webhook %q", name))
+	}
+	newContextCallback(r.id, cb, cb.Chan, lfunc)
+	return cb.URL
+}
+
+//newContextCallback adds a callback whose lua function takes a context
+func newContextCallback(RID int, cb interface{}, ch interface{}, lfunc lua.LValue) {
+	// cbtable and cases indexes have to match
+	if len(CBTable) != len(Cases) {
+		panic(`cbtable != cases`)
+	}
+	cbEntry := CBMap{
+		Func:     lfunc,
+		Callback: reflect.ValueOf(cb),
+		Script:   &LuaScripts[RID],
+	}
+	caseEntry := reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ch),
+	}
+	CBTable = append(CBTable, cbEntry)
+	Cases = append(Cases, caseEntry)
+}
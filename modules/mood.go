@@ -0,0 +1,189 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"github.com/gorhill/cronexpr"
+	"strings"
+	"time"
+)
+
+var Mood = &lazlo.Module{
+	Name: `Mood`,
+	Usage: `"%BOTNAME% mood" : how this channel's been feeling lately (if it's opted in)
+"%BOTNAME% mood tracking on|off" : (admins only) opts this channel in or out of mood tracking`,
+	Run: moodRun,
+}
+
+// the default report schedule: mondays at 9am
+const moodReportDefault = `0 0 9 * * 1 *`
+
+// MoodDay is one channel's sentiment for one day. Only the totals are kept,
+// never who said what.
+type MoodDay struct {
+	Count    int
+	Sum      float64
+	Positive int
+	Negative int
+}
+
+func moodRun(b *lazlo.Broker) {
+	schedule := b.Config.MoodReport
+	if schedule == `` {
+		schedule = moodReportDefault
+	}
+	if _, err := cronexpr.Parse(schedule); err != nil {
+		lazlo.Logger.Error(`Mood:: bad LAZLO_MOOD_REPORT `, schedule, `: `, err)
+		return
+	}
+	scorer := b.NewSentimentScorer()
+
+	// score messages before the other modules see them, so they can read the
+	// score with pm.Event.Annotation("sentiment")
+	messages := b.PipelineCallback(`.*`, false, -20)
+	mood := b.MessageCallback(`(?i)mood$`, true)
+	tracking := b.MessageCallback(`(?i)mood tracking (on|off)$`, true)
+	report := b.TimerCallback(schedule)
+	b.Ready()
+	for {
+		select {
+		case pm := <-messages.Chan:
+			e := pm.Event
+			if e.Subtype == `` && e.User != b.SlackMeta.Self.ID && !moodCommand(b, e) && moodTracked(b, e.Channel) {
+				if score, err := scorer.Score(e.Text); err != nil {
+					lazlo.Logger.Debug(`Mood:: couldn't score a message: `, err)
+				} else {
+					e.Annotate(`sentiment`, score)
+					moodRecord(b, e.Channel, score)
+				}
+			}
+			pm.Done()
+		case pm := <-mood.Chan:
+			if !moodTracked(b, pm.Event.Channel) {
+				pm.Event.Reply(`I'm not tracking the mood in here (an admin can turn it on with "mood tracking on")`)
+				continue
+			}
+			pm.Event.Respond(moodReport(b, pm.Event.Channel))
+		case pm := <-tracking.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can turn mood tracking on or off`)
+				continue
+			}
+			key := `mood:tracked:` + pm.Event.Channel
+			if strings.ToLower(pm.Match[1]) == `on` {
+				b.Brain.Set(key, []byte(`true`))
+				pm.Event.Respond(`Ok, I'll keep track of how this channel's feeling (just the daily totals, not who said what)`)
+			} else {
+				if moodTracked(b, pm.Event.Channel) {
+					b.Brain.Delete(key)
+				}
+				moodForget(b, pm.Event.Channel)
+				pm.Event.Respond(`Ok, I've stopped tracking the mood in here, and forgotten what I had`)
+			}
+		case <-report.Chan:
+			for _, channel := range moodChannels(b) {
+				b.Say(`Here's how the week went: `+moodReport(b, channel), channel)
+			}
+		}
+	}
+}
+
+// moodCommand says whether the message was said to lazlo, rather than about
+// whatever's going on in the channel
+func moodCommand(b *lazlo.Broker, e *lazlo.Event) bool {
+	_, addressed := b.Addressed(e.Channel, e.Text)
+	return addressed
+}
+
+func moodTracked(b *lazlo.Broker, channel string) bool {
+	data, err := b.Brain.Get(`mood:tracked:` + channel)
+	return err == nil && string(data) == `true`
+}
+
+func moodChannels(b *lazlo.Broker) []string {
+	keys, _ := b.Brain.Keys()
+	var channels []string
+	for _, key := range keys {
+		if strings.HasPrefix(key, `mood:tracked:`) {
+			channels = append(channels, strings.TrimPrefix(key, `mood:tracked:`))
+		}
+	}
+	return channels
+}
+
+func moodKey(channel string, day time.Time) string {
+	return fmt.Sprintf("mood:day:%s:%s", channel, day.Format(`2006-01-02`))
+}
+
+func moodGet(b *lazlo.Broker, channel string, day time.Time) *MoodDay {
+	md := new(MoodDay)
+	if data, err := b.Brain.Get(moodKey(channel, day)); err == nil {
+		json.Unmarshal(data, md)
+	}
+	return md
+}
+
+func moodRecord(b *lazlo.Broker, channel string, score float64) {
+	md := moodGet(b, channel, time.Now())
+	md.Count++
+	md.Sum += score
+	switch {
+	case score > 0.1:
+		md.Positive++
+	case score < -0.1:
+		md.Negative++
+	}
+	data, _ := json.Marshal(md)
+	if err := b.Brain.Set(moodKey(channel, time.Now()), data); err != nil {
+		lazlo.Logger.Error(`Mood:: couldn't save the mood for `, channel, `: `, err)
+	}
+}
+
+// moodForget deletes everything we recorded about the channel's mood
+func moodForget(b *lazlo.Broker, channel string) {
+	keys, _ := b.Brain.Keys()
+	for _, key := range keys {
+		if strings.HasPrefix(key, `mood:day:`+channel+`:`) {
+			b.Brain.Delete(key)
+		}
+	}
+}
+
+// moodReport sums up the last seven days in the channel
+func moodReport(b *lazlo.Broker, channel string) string {
+	var lines []string
+	var week MoodDay
+	for i := 6; i >= 0; i-- {
+		day := time.Now().AddDate(0, 0, -i)
+		md := moodGet(b, channel, day)
+		if md.Count == 0 {
+			continue
+		}
+		week.Count += md.Count
+		week.Sum += md.Sum
+		week.Positive += md.Positive
+		week.Negative += md.Negative
+		lines = append(lines, fmt.Sprintf("%s %s (%d messages)", b.Locale(``, channel).Day(day), moodFace(md.Sum/float64(md.Count)), md.Count))
+	}
+	if week.Count == 0 {
+		return `it's been too quiet to tell`
+	}
+	summary := fmt.Sprintf("%s overall: %d positive, %d negative, %d neutral messages", moodFace(week.Sum/float64(week.Count)), week.Positive, week.Negative, week.Count-week.Positive-week.Negative)
+	return summary + "\n" + strings.Join(lines, "\n")
+}
+
+func moodFace(avg float64) string {
+	switch {
+	case avg > 0.3:
+		return `:smile:`
+	case avg > 0.1:
+		return `:slightly_smiling_face:`
+	case avg < -0.3:
+		return `:disappointed:`
+	case avg < -0.1:
+		return `:slightly_frowning_face:`
+	default:
+		return `:neutral_face:`
+	}
+}
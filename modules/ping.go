@@ -7,13 +7,15 @@ import (
 )
 
 var Syn = &lazlo.Module{
-	Name:  `Ping`,
-	Usage: `"%BOTNAME% (ping|syn)" : Test that the bot is currently running`,
-	Run:   pingRun,
+	Name:         `Ping`,
+	Usage:        `"%BOTNAME% (ping|syn)" : Test that the bot is currently running`,
+	Run:          pingRun,
+	ExternalSafe: true,
 }
 
 func pingRun(b *lazlo.Broker) {
 	cb := b.MessageCallback(`(?i)(ping|syn)`, true)
+	b.Ready()
 	for {
 		pm := <-cb.Chan
 		pm.Event.Reply(randReply())
@@ -0,0 +1,390 @@
+package modules
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Plans reviews infra plans (terraform plan output, as text or as JSON from
+// "terraform show -json") before they're applied. Plans are POSTed to
+// /linkcb/plan by CI, or uploaded with "lazlo review plan". Lazlo sums up what
+// the plan changes, and once someone with LAZLO_PLAN_ROLE approves it (by
+// reacting, like any workflow), calls LAZLO_PLAN_APPLY_URL to apply it and
+// uploads what that said.
+var Plans = &lazlo.Module{
+	Name: `Plans`,
+	Usage: `"%BOTNAME% review plan [name]" : (with a terraform plan uploaded) sums up the plan, and applies it once someone approves it
+POST a plan to /linkcb/plan?name=<name>&channel=<channel>&user=<who> (with LAZLO_PLAN_TOKEN) to have it reviewed`,
+	Run: plansRun,
+}
+
+const (
+	planMaxSize      = 5 << 20          // the biggest plan (and apply log) we'll read
+	planMaxLines     = 40               // resources listed in the summary
+	planApplyTimeout = 60 * time.Minute // how long the apply webhook gets
+)
+
+// a planSummary is what a plan changes, by resource address
+type planSummary struct {
+	Create  []string `json:"create,omitempty"`
+	Update  []string `json:"update,omitempty"`
+	Replace []string `json:"replace,omitempty"`
+	Destroy []string `json:"destroy,omitempty"`
+}
+
+// the resource lines in terraform's text output
+var planResource = regexp.MustCompile(`^\s*# (\S+)(?: is tainted, so)? (?:will be|must be) (created|updated in-place|replaced|destroyed)`)
+
+// terminal colors, which terraform puts in its output unless told not to
+var planColor = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// what isn't allowed in the apply log's file name
+var planUnsafe = regexp.MustCompile(`[^\w.-]+`)
+
+func plansRun(b *lazlo.Broker) {
+	approver := func(wi *lazlo.WorkflowInstance, t *lazlo.WorkflowTrigger) bool {
+		if planApprover(b, wi, t.User) {
+			return true
+		}
+		b.Audit(t.User, `plan approve`, wi.Data[`name`], wi.Data[`sha256`], false)
+		return false
+	}
+	engine, err := b.Approval(&lazlo.Approval{
+		Name:       `plan`,
+		Propose:    planPropose,
+		CanApprove: approver,
+		CanReject: func(wi *lazlo.WorkflowInstance, t *lazlo.WorkflowTrigger) bool {
+			return t.User == wi.User || planApprover(b, wi, t.User)
+		},
+		Expiry: time.Duration(b.Config.PlanExpiry) * time.Hour,
+		Approved: func(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+			b.Audit(wi.Data[`approver`], `plan approve`, wi.Data[`name`], wi.Data[`sha256`], true)
+			planApply(b, wi)
+		},
+		Rejected: func(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+			b.Audit(wi.Data[`rejecter`], `plan reject`, wi.Data[`name`], wi.Data[`sha256`], true)
+			b.Say(fmt.Sprintf("OK, <@%s>, I've thrown away the plan for %s", wi.Data[`rejecter`], wi.Data[`name`]), wi.Channel)
+		},
+		Expired: func(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+			b.Say(fmt.Sprintf("Nobody approved the plan for %s, so I've thrown it away", wi.Data[`name`]), wi.Channel)
+		},
+	})
+	if err != nil {
+		lazlo.Logger.Error(`Plans:: `, err)
+		return
+	}
+
+	// CI can POST plans, as long as it has the token
+	if b.Config.PlanToken != `` {
+		b.LinkCallback(`plan`, func(res http.ResponseWriter, req *http.Request) {
+			planWebhook(b, engine, res, req)
+		}, lazlo.BearerToken(b.Config.PlanToken))
+	}
+
+	review := b.MessageCallback(`(?i)review plan\s*(.*)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-review.Chan:
+			if len(pm.Event.Files) == 0 {
+				pm.Event.Reply("Upload the plan (terraform's output, or `terraform show -json`) with the comment `" + b.Config.Name + " review plan [name]`")
+				continue
+			}
+			go planFromFile(b, engine, pm.Event, strings.TrimSpace(pm.Match[1]))
+		}
+	}
+}
+
+// planApprover returns true if the user can approve the plan: they need
+// LAZLO_PLAN_ROLE (or to be an admin), and it can't be their own plan
+func planApprover(b *lazlo.Broker, wi *lazlo.WorkflowInstance, user string) bool {
+	if user == `` || user == wi.User {
+		return false
+	}
+	if b.Config.PlanRole == `` {
+		return b.IsAdmin(user)
+	}
+	return b.HasRole(user, b.Config.PlanRole)
+}
+
+// planFromFile reviews a plan someone uploaded
+func planFromFile(b *lazlo.Broker, engine *lazlo.WorkflowEngine, e *lazlo.Event, name string) {
+	file := e.Files[0]
+	if name == `` {
+		name = file.Name
+	}
+	data, err := b.Download(file, planMaxSize)
+	if err != nil {
+		e.Reply(fmt.Sprintf("I couldn't get the plan: %s", err))
+		return
+	}
+	if _, err := planStart(engine, e.Channel, e.User, name, ``, data); err != nil {
+		e.Reply(fmt.Sprintf("That doesn't look like a plan to me: %s", err))
+	}
+}
+
+// planWebhook reviews a plan CI POSTed
+func planWebhook(b *lazlo.Broker, engine *lazlo.WorkflowEngine, res http.ResponseWriter, req *http.Request) {
+	if req.Method != `POST` {
+		http.Error(res, "POST a plan", http.StatusMethodNotAllowed)
+		return
+	}
+	data, err := ioutil.ReadAll(io.LimitReader(req.Body, planMaxSize+1))
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(data) > planMaxSize {
+		http.Error(res, "that plan is too big", http.StatusRequestEntityTooLarge)
+		return
+	}
+	query := req.URL.Query()
+	name := query.Get(`name`)
+	if name == `` {
+		name = `plan`
+	}
+	channel := query.Get(`channel`)
+	if channel == `` {
+		channel = b.Config.PlanChannel
+	}
+	if channel == `` {
+		channel = b.DefaultChannel()
+	}
+	channel = strings.TrimPrefix(channel, `#`)
+	if c := b.SlackMeta.GetChannelByName(channel); c != nil {
+		channel = c.ID
+	}
+	user := strings.TrimPrefix(query.Get(`user`), `@`)
+	if u := b.SlackMeta.GetUserByName(user); u != nil {
+		user = u.ID
+	}
+	wi, err := planStart(engine, channel, user, name, query.Get(`ref`), data)
+	if err != nil {
+		http.Error(res, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res.Header().Set(`Content-Type`, `application/json`)
+	res.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(res).Encode(map[string]string{`id`: wi.ID, `sha256`: wi.Data[`sha256`]})
+}
+
+// planStart sums up the plan and starts its review
+func planStart(engine *lazlo.WorkflowEngine, channel string, user string, name string, ref string, data []byte) (*lazlo.WorkflowInstance, error) {
+	summary, err := planSummarize(data)
+	if err != nil {
+		return nil, err
+	}
+	encoded, _ := json.Marshal(summary)
+	sum := sha256.Sum256(data)
+	return engine.Start(channel, user, map[string]string{
+		`name`:    name,
+		`ref`:     ref,
+		`sha256`:  hex.EncodeToString(sum[:]),
+		`summary`: string(encoded),
+	}), nil
+}
+
+// planSummarize works out what a plan changes, from terraform's text or JSON
+// output
+func planSummarize(data []byte) (*planSummary, error) {
+	summary := new(planSummary)
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		var plan struct {
+			ResourceChanges []struct {
+				Address string `json:"address"`
+				Change  struct {
+					Actions []string `json:"actions"`
+				} `json:"change"`
+			} `json:"resource_changes"`
+		}
+		if err := json.Unmarshal(trimmed, &plan); err != nil {
+			return nil, err
+		}
+		if plan.ResourceChanges == nil {
+			return nil, fmt.Errorf("it doesn't have any resource_changes")
+		}
+		for _, rc := range plan.ResourceChanges {
+			switch strings.Join(rc.Change.Actions, `,`) {
+			case `create`:
+				summary.Create = append(summary.Create, rc.Address)
+			case `update`:
+				summary.Update = append(summary.Update, rc.Address)
+			case `delete,create`, `create,delete`:
+				summary.Replace = append(summary.Replace, rc.Address)
+			case `delete`:
+				summary.Destroy = append(summary.Destroy, rc.Address)
+			}
+		}
+		return summary, nil
+	}
+
+	text := planColor.ReplaceAllString(string(data), ``)
+	if !strings.Contains(text, `Plan: `) && !strings.Contains(text, `No changes.`) {
+		return nil, fmt.Errorf("I can't find the \"Plan:\" line")
+	}
+	for _, line := range strings.Split(text, "\n") {
+		m := planResource.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		switch m[2] {
+		case `created`:
+			summary.Create = append(summary.Create, m[1])
+		case `updated in-place`:
+			summary.Update = append(summary.Update, m[1])
+		case `replaced`:
+			summary.Replace = append(summary.Replace, m[1])
+		case `destroyed`:
+			summary.Destroy = append(summary.Destroy, m[1])
+		}
+	}
+	return summary, nil
+}
+
+// attachment sums up the plan, colored by how scary it is
+func (s *planSummary) attachment(name string) lazlo.Attachment {
+	color := `good`
+	switch {
+	case len(s.Destroy) > 0:
+		color = `danger`
+	case len(s.Replace) > 0:
+		color = `warning`
+	}
+	var lines []string
+	for _, group := range []struct {
+		sign      string
+		addresses []string
+	}{{`-`, s.Destroy}, {`-/+`, s.Replace}, {`~`, s.Update}, {`+`, s.Create}} {
+		for _, address := range group.addresses {
+			lines = append(lines, group.sign+` `+address)
+		}
+	}
+	text := `No changes.`
+	if lines != nil {
+		if len(lines) > planMaxLines {
+			lines = append(lines[:planMaxLines], fmt.Sprintf("...and %d more", len(lines)-planMaxLines))
+		}
+		text = "```" + strings.Join(lines, "\n") + "```"
+	}
+	counts := fmt.Sprintf("%d to create, %d to update, %d to replace, %d to destroy", len(s.Create), len(s.Update), len(s.Replace), len(s.Destroy))
+	return lazlo.Attachment{
+		Fallback: fmt.Sprintf("Plan for %s: %s", name, counts),
+		Color:    color,
+		Title:    `Plan for ` + name,
+		Text:     text,
+		Fields: []lazlo.AttachmentField{
+			{Title: `Create`, Value: fmt.Sprint(len(s.Create)), Short: true},
+			{Title: `Update`, Value: fmt.Sprint(len(s.Update)), Short: true},
+			{Title: `Replace`, Value: fmt.Sprint(len(s.Replace)), Short: true},
+			{Title: `Destroy`, Value: fmt.Sprint(len(s.Destroy)), Short: true},
+		},
+		MarkdownIn: []string{`text`},
+	}
+}
+
+// planPropose posts the plan's summary, which approvers react to
+func planPropose(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+	summary := new(planSummary)
+	json.Unmarshal([]byte(wi.Data[`summary`]), summary)
+	who := `Someone wants`
+	if wi.User != `` {
+		who = fmt.Sprintf("<@%s> wants", wi.User)
+	}
+	approvers := `an admin`
+	if b.Config.PlanRole != `` {
+		approvers = `someone with the ` + b.Config.PlanRole + ` role`
+	}
+	text := fmt.Sprintf("%s to apply this plan (sha256 %.12s). %s can react with :%s: to apply it, or :%s: to throw it away. It expires in %d hours.",
+		who, wi.Data[`sha256`], strings.ToUpper(approvers[:1])+approvers[1:], lazlo.ApproveReaction, lazlo.RejectReaction, b.Config.PlanExpiry)
+	reply := <-b.Send(&lazlo.Event{
+		Type:        `message`,
+		Channel:     wi.Channel,
+		Text:        text,
+		Attachments: []lazlo.Attachment{summary.attachment(wi.Data[`name`])},
+	})
+	wi.MessageTs, _ = reply[`ts`].(string)
+	if wi.MessageTs == `` {
+		lazlo.Logger.Error(`Plans:: couldn't post the plan for `, wi.Data[`name`], ` in `, wi.Channel)
+	}
+}
+
+// planApply calls the apply webhook, as a job, and uploads what it said
+func planApply(b *lazlo.Broker, wi *lazlo.WorkflowInstance) {
+	name, approver := wi.Data[`name`], wi.Data[`approver`]
+	if b.Config.PlanApplyURL == `` {
+		b.Say(fmt.Sprintf("The plan for %s is approved, but LAZLO_PLAN_APPLY_URL isn't set, so I can't apply it", name), wi.Channel)
+		return
+	}
+	payload, _ := json.Marshal(map[string]string{
+		`id`:           wi.ID,
+		`name`:         name,
+		`ref`:          wi.Data[`ref`],
+		`sha256`:       wi.Data[`sha256`],
+		`requested_by`: wi.User,
+		`approved_by`:  approver,
+		`channel`:      wi.Channel,
+	})
+	channel := wi.Channel
+	b.Jobs.Submit(`apply `+name, &lazlo.Event{Channel: channel, User: approver}, func(job *lazlo.Job) error {
+		job.Progress("calling the apply webhook")
+		log, err := planCallApply(b, payload, job.Cancelled())
+		result := `applied`
+		if err != nil {
+			result = err.Error()
+		}
+		b.Audit(approver, `plan apply`, name, wi.Data[`sha256`]+`: `+result, true)
+		if log != nil {
+			comment := fmt.Sprintf("Applied the plan for %s (approved by <@%s>)", name, approver)
+			if err != nil {
+				comment = fmt.Sprintf("Applying the plan for %s didn't work: %s", name, err)
+			}
+			if uerr := b.Upload(channel, `apply-`+planFilename(name)+`.log`, log, comment); uerr != nil {
+				job.Logf("couldn't upload the apply log: %s", uerr)
+			}
+		}
+		return err
+	})
+}
+
+// planCallApply POSTs the approved plan to the apply webhook, and returns
+// what it said
+func planCallApply(b *lazlo.Broker, payload []byte, cancel <-chan struct{}) ([]byte, error) {
+	req, err := http.NewRequest(`POST`, b.Config.PlanApplyURL, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(`Content-Type`, `application/json`)
+	if b.Config.PlanApplyToken != `` {
+		req.Header.Set(`Authorization`, `Bearer `+b.Config.PlanApplyToken)
+	}
+	req.Cancel = cancel
+	client := &http.Client{Timeout: planApplyTimeout}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	log, err := ioutil.ReadAll(io.LimitReader(res.Body, planMaxSize))
+	if err != nil {
+		return log, err
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return log, fmt.Errorf("the apply webhook said %s", res.Status)
+	}
+	return log, nil
+}
+
+// planFilename makes a plan's name safe to use in a file name
+func planFilename(name string) string {
+	return strings.Trim(planUnsafe.ReplaceAllString(name, `-`), `-.`)
+}
@@ -12,6 +12,7 @@ var QuestionTest = &lazlo.Module{
 	Run: func(b *lazlo.Broker) {
 		cb1 := b.MessageCallback(`(?i)(ask *me) (.*)`, true)
 		cb2 := b.MessageCallback(`(?i)(qtest)`, true)
+		b.Ready()
 		for {
 			select {
 			case newReq := <-cb1.Chan:
@@ -0,0 +1,482 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var Rotation = &lazlo.Module{
+	Name: `Rotation`,
+	Usage: `"%BOTNAME% rotate add <rotation> <@users...>" : adds people (or #channels, or @groups) to a rotation, creating it if need be
+"%BOTNAME% rotate remove <rotation> <@users...>" : takes people out of a rotation
+"%BOTNAME% rotate next <rotation>" : picks who's up next
+"%BOTNAME% rotate mode <rotation> roundrobin|weighted" : take turns in order, or pick at random (by weight)
+"%BOTNAME% rotate weight <rotation> <@user> <n>" : makes someone n times as likely to be picked in weighted mode
+"%BOTNAME% rotate schedule <rotation> <cron schedule>|off" : automatically picks (and announces) on a schedule in this channel
+"%BOTNAME% rotate show <rotation>" : shows who's in a rotation and who's been picked lately
+"%BOTNAME% rotate list" : lists the rotations
+"%BOTNAME% rotate delete <rotation>" : deletes a rotation (!undo puts it back)`,
+	Run: rotationRun,
+}
+
+// how many picks we remember per rotation
+const rotationHistory = 100
+
+// A TaskRotation is a list of people who take turns doing something. People
+// who are away (see lazlo.Broker.IsAway) are skipped.
+type TaskRotation struct {
+	Name     string
+	Members  []string
+	Mode     string // roundrobin or weighted
+	Weights  map[string]int
+	Next     int
+	History  []RotationPick
+	Schedule string
+	Channel  string // where scheduled picks are announced
+}
+
+// A RotationPick records who was picked when
+type RotationPick struct {
+	User string
+	Time time.Time
+}
+
+var rotationRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+// a running scheduled announcement
+type rotationTimer struct {
+	cb   *lazlo.TimerCallback
+	stop chan struct{}
+}
+
+// a deleted rotation to put back (by !undo), and where to say how that went
+type rotationRestore struct {
+	r    *TaskRotation
+	done chan error
+}
+
+func rotationRun(b *lazlo.Broker) {
+	add := b.MessageCallback(`(?i)rotate (add|remove) (\S+) (.+)$`, true)
+	next := b.MessageCallback(`(?i)rotate next (\S+)$`, true)
+	mode := b.MessageCallback(`(?i)rotate mode (\S+) (round-?robin|weighted)$`, true)
+	weight := b.MessageCallback(`(?i)rotate weight (\S+) (\S+) (\d+)$`, true)
+	schedule := b.MessageCallback(`(?i)rotate schedule (\S+) (.+)$`, true)
+	show := b.MessageCallback(`(?i)rotate show (\S+)$`, true)
+	list := b.MessageCallback(`(?i)rotate list$`, true)
+	del := b.MessageCallback(`(?i)rotate delete (\S+)$`, true)
+
+	b.OnExpunge(func(user string) ([]string, error) {
+		return rotationForget(b, user), nil
+	})
+	b.HomeSection(`Your rotations`, 20, func(user string) []lazlo.Block {
+		return rotationHome(b, user)
+	})
+	// {{oncall "payments"}} in a message mentions whoever was picked last
+	b.TemplateFunc(`oncall`, func(name string) string {
+		return rotationCurrent(b, strings.ToLower(name))
+	})
+
+	timers := make(map[string]*rotationTimer)
+	fire := make(chan string)
+	restore := make(chan rotationRestore)
+	for _, r := range rotationAll(b) {
+		if r.Schedule != `` {
+			if err := rotationSchedule(b, timers, fire, r); err != nil {
+				lazlo.Logger.Error(`Rotation:: couldn't schedule `, r.Name, `: `, err)
+			}
+		}
+	}
+
+	b.Ready()
+	for {
+		select {
+		case pm := <-add.Chan:
+			name := strings.ToLower(pm.Match[2])
+			users, err := b.ResolveUsers(strings.Fields(pm.Match[3]))
+			if err != nil {
+				pm.Event.Reply(err.Error())
+				continue
+			}
+			r := rotationGet(b, name)
+			if strings.ToLower(pm.Match[1]) == `add` {
+				if r == nil {
+					r = &TaskRotation{Name: name, Mode: `roundrobin`, Weights: make(map[string]int)}
+				}
+				for _, user := range users {
+					if rotationIndex(r, user) < 0 {
+						r.Members = append(r.Members, user)
+					}
+				}
+			} else {
+				if r == nil {
+					pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", name))
+					continue
+				}
+				for _, user := range users {
+					if i := rotationIndex(r, user); i >= 0 {
+						r.Members = append(r.Members[:i], r.Members[i+1:]...)
+						if i < r.Next {
+							r.Next--
+						}
+						delete(r.Weights, user)
+					}
+				}
+			}
+			rotationSave(b, r)
+			pm.Event.Reply(fmt.Sprintf("%s is now: %s", name, rotationNames(b, r.Members)))
+		case pm := <-next.Chan:
+			r := rotationGet(b, strings.ToLower(pm.Match[1]))
+			if r == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", pm.Match[1]))
+				continue
+			}
+			user, err := rotationPick(b, r)
+			if err != nil {
+				pm.Event.Reply(err.Error())
+				continue
+			}
+			pm.Event.Respond(fmt.Sprintf("%s you're up for %s", b.Mention(user), r.Name))
+		case pm := <-mode.Chan:
+			r := rotationGet(b, strings.ToLower(pm.Match[1]))
+			if r == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", pm.Match[1]))
+				continue
+			}
+			r.Mode = strings.Replace(strings.ToLower(pm.Match[2]), `-`, ``, -1)
+			rotationSave(b, r)
+			pm.Event.Reply(fmt.Sprintf("%s is %s now", r.Name, r.Mode))
+		case pm := <-weight.Chan:
+			r := rotationGet(b, strings.ToLower(pm.Match[1]))
+			if r == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", pm.Match[1]))
+				continue
+			}
+			users, err := b.ResolveUsers([]string{pm.Match[2]})
+			if err != nil || len(users) != 1 || rotationIndex(r, users[0]) < 0 {
+				pm.Event.Reply(fmt.Sprintf("%s isn't in %s", pm.Match[2], r.Name))
+				continue
+			}
+			n, _ := strconv.Atoi(pm.Match[3])
+			r.Weights[users[0]] = n
+			rotationSave(b, r)
+			pm.Event.Reply(fmt.Sprintf("ok, %s has a weight of %d in %s", pm.Match[2], n, r.Name))
+		case pm := <-schedule.Chan:
+			r := rotationGet(b, strings.ToLower(pm.Match[1]))
+			if r == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", pm.Match[1]))
+				continue
+			}
+			rotationUnschedule(b, timers, r.Name)
+			r.Schedule = ``
+			if sched := strings.TrimSpace(pm.Match[2]); strings.ToLower(sched) != `off` {
+				r.Schedule = sched
+				r.Channel = pm.Event.Channel
+				if err := rotationSchedule(b, timers, fire, r); err != nil {
+					pm.Event.Reply(fmt.Sprintf("Sorry, %s", err))
+					continue
+				}
+			}
+			rotationSave(b, r)
+			if r.Schedule == `` {
+				pm.Event.Reply(fmt.Sprintf("ok, I won't pick anyone for %s on my own anymore", r.Name))
+			} else {
+				pm.Event.Reply(fmt.Sprintf("ok, I'll pick someone for %s here on the schedule %s", r.Name, r.Schedule))
+			}
+		case pm := <-show.Chan:
+			r := rotationGet(b, strings.ToLower(pm.Match[1]))
+			if r == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", pm.Match[1]))
+				continue
+			}
+			pm.Event.Respond(rotationDescribe(b, r, b.Locale(pm.Event.User, pm.Event.Channel)))
+		case pm := <-list.Chan:
+			var names []string
+			for _, r := range rotationAll(b) {
+				names = append(names, fmt.Sprintf("%s (%d people)", r.Name, len(r.Members)))
+			}
+			if names == nil {
+				pm.Event.Reply(`There aren't any rotations yet`)
+				continue
+			}
+			pm.Event.Respond(strings.Join(names, "\n"))
+		case pm := <-del.Chan:
+			name := strings.ToLower(pm.Match[1])
+			r := rotationGet(b, name)
+			if r == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a rotation called %s", name))
+				continue
+			}
+			rotationUnschedule(b, timers, name)
+			b.Brain.Delete(`rotation:` + name)
+			b.Undoable(pm.Event, `rotate delete`, name, 0, func() error {
+				done := make(chan error)
+				restore <- rotationRestore{r, done}
+				return <-done
+			})
+			pm.Event.Reply(fmt.Sprintf("deleted %s (!undo to get it back)", name))
+		case rr := <-restore:
+			if rotationGet(b, rr.r.Name) != nil {
+				rr.done <- fmt.Errorf("there's a new rotation called %s", rr.r.Name)
+				continue
+			}
+			rotationSave(b, rr.r)
+			if rr.r.Schedule != `` {
+				if err := rotationSchedule(b, timers, fire, rr.r); err != nil {
+					lazlo.Logger.Error(`Rotation:: couldn't schedule `, rr.r.Name, `: `, err)
+				}
+			}
+			rr.done <- nil
+		case name := <-fire:
+			r := rotationGet(b, name)
+			if r == nil {
+				continue
+			}
+			user, err := rotationPick(b, r)
+			if err != nil {
+				b.Say(fmt.Sprintf("I couldn't pick anyone for %s: %s", r.Name, err), r.Channel)
+				continue
+			}
+			b.Say(fmt.Sprintf("%s you're up for %s", b.Mention(user), r.Name), r.Channel)
+		}
+	}
+}
+
+// rotationPick picks the next person, skipping anyone who's away, and records
+// the pick
+func rotationPick(b *lazlo.Broker, r *TaskRotation) (string, error) {
+	var candidates []string
+	for _, user := range r.Members {
+		if !b.IsAway(user) {
+			candidates = append(candidates, user)
+		}
+	}
+	if candidates == nil {
+		return ``, fmt.Errorf("everyone in %s is away", r.Name)
+	}
+
+	var user string
+	if r.Mode == `weighted` {
+		// don't pick the same person twice in a row if we can help it
+		if len(candidates) > 1 && len(r.History) > 0 {
+			last := r.History[len(r.History)-1].User
+			for i, c := range candidates {
+				if c == last {
+					candidates = append(candidates[:i], candidates[i+1:]...)
+					break
+				}
+			}
+		}
+		total := 0
+		for _, c := range candidates {
+			total += rotationWeight(r, c)
+		}
+		n := rotationRand.Intn(total)
+		for _, c := range candidates {
+			if n -= rotationWeight(r, c); n < 0 {
+				user = c
+				break
+			}
+		}
+	} else {
+		for i := 0; i < len(r.Members); i++ {
+			idx := (r.Next + i) % len(r.Members)
+			if !b.IsAway(r.Members[idx]) {
+				user = r.Members[idx]
+				r.Next = idx + 1
+				break
+			}
+		}
+	}
+
+	r.History = append(r.History, RotationPick{User: user, Time: time.Now()})
+	if len(r.History) > rotationHistory {
+		r.History = r.History[len(r.History)-rotationHistory:]
+	}
+	rotationSave(b, r)
+	return user, nil
+}
+
+// rotationForget takes an expunged user out of every rotation and its history
+func rotationForget(b *lazlo.Broker, user string) []string {
+	var removed []string
+	for _, r := range rotationAll(b) {
+		changed := false
+		if i := rotationIndex(r, user); i >= 0 {
+			r.Members = append(r.Members[:i], r.Members[i+1:]...)
+			if i < r.Next {
+				r.Next--
+			}
+			delete(r.Weights, user)
+			changed = true
+		}
+		var history []RotationPick
+		for _, pick := range r.History {
+			if pick.User != user {
+				history = append(history, pick)
+			}
+		}
+		if len(history) != len(r.History) {
+			r.History = history
+			changed = true
+		}
+		if changed {
+			rotationSave(b, r)
+			removed = append(removed, `rotation `+r.Name)
+		}
+	}
+	return removed
+}
+
+// rotationWeight returns the user's weight (1 unless they've been given one)
+func rotationWeight(r *TaskRotation, user string) int {
+	if w, ok := r.Weights[user]; ok && w > 0 {
+		return w
+	}
+	return 1
+}
+
+func rotationIndex(r *TaskRotation, user string) int {
+	for i, member := range r.Members {
+		if member == user {
+			return i
+		}
+	}
+	return -1
+}
+
+// rotationSchedule starts announcing picks for the rotation on its schedule
+func rotationSchedule(b *lazlo.Broker, timers map[string]*rotationTimer, fire chan string, r *TaskRotation) error {
+	cb := b.TimerCallback(r.Schedule)
+	if cb == nil {
+		return fmt.Errorf("%s isn't a schedule I understand (it should look like 0 0 10 * * 1-5 *)", r.Schedule)
+	}
+	t := &rotationTimer{cb: cb, stop: make(chan struct{})}
+	timers[r.Name] = t
+	go func(name string) {
+		for {
+			select {
+			case <-t.cb.Chan:
+				fire <- name
+			case <-t.stop:
+				return
+			}
+		}
+	}(r.Name)
+	return nil
+}
+
+func rotationUnschedule(b *lazlo.Broker, timers map[string]*rotationTimer, name string) {
+	if t, ok := timers[name]; ok {
+		b.DeRegisterCallback(t.cb)
+		close(t.stop)
+		delete(timers, name)
+	}
+}
+
+// rotationHome lists the rotations the user's in, for their App Home tab
+func rotationHome(b *lazlo.Broker, user string) []lazlo.Block {
+	var lines []string
+	for _, r := range rotationAll(b) {
+		if rotationIndex(r, user) < 0 {
+			continue
+		}
+		switch {
+		case len(r.History) == 0:
+			lines = append(lines, fmt.Sprintf("*%s*: nobody's been picked yet", r.Name))
+		case r.History[len(r.History)-1].User == user:
+			lines = append(lines, fmt.Sprintf("*%s*: you're up (since %s)", r.Name, b.Locale(user, ``).Day(r.History[len(r.History)-1].Time)))
+		default:
+			lines = append(lines, fmt.Sprintf("*%s*: %s is up", r.Name, rotationCurrent(b, r.Name)))
+		}
+	}
+	if lines == nil {
+		return nil
+	}
+	return []lazlo.Block{lazlo.TextBlock(strings.Join(lines, "\n"))}
+}
+
+// rotationCurrent mentions whoever was picked last in the rotation
+func rotationCurrent(b *lazlo.Broker, name string) string {
+	r := rotationGet(b, name)
+	if r == nil || len(r.History) == 0 {
+		return `(nobody's on ` + name + `)`
+	}
+	return fmt.Sprintf("<@%s>", r.History[len(r.History)-1].User)
+}
+
+func rotationGet(b *lazlo.Broker, name string) *TaskRotation {
+	data, err := b.Brain.Get(`rotation:` + name)
+	if err != nil || data == nil {
+		return nil
+	}
+	r := new(TaskRotation)
+	if err := json.Unmarshal(data, r); err != nil {
+		lazlo.Logger.Error(`Rotation:: couldn't load `, name, `: `, err)
+		return nil
+	}
+	if r.Weights == nil {
+		r.Weights = make(map[string]int)
+	}
+	return r
+}
+
+func rotationSave(b *lazlo.Broker, r *TaskRotation) {
+	data, _ := json.Marshal(r)
+	if err := b.Brain.Set(`rotation:`+r.Name, data); err != nil {
+		lazlo.Logger.Error(`Rotation:: couldn't save `, r.Name, `: `, err)
+	}
+}
+
+func rotationAll(b *lazlo.Broker) []*TaskRotation {
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		lazlo.Logger.Error(`Rotation:: couldn't list rotations: `, err)
+		return nil
+	}
+	var out []*TaskRotation
+	for _, key := range keys {
+		if strings.HasPrefix(key, `rotation:`) {
+			if r := rotationGet(b, strings.TrimPrefix(key, `rotation:`)); r != nil {
+				out = append(out, r)
+			}
+		}
+	}
+	return out
+}
+
+func rotationNames(b *lazlo.Broker, users []string) string {
+	if len(users) == 0 {
+		return `nobody`
+	}
+	var names []string
+	for _, user := range users {
+		name := b.SlackMeta.GetUserName(user)
+		if b.IsAway(user) {
+			name += ` (away)`
+		}
+		names = append(names, name)
+	}
+	return strings.Join(names, `, `)
+}
+
+func rotationDescribe(b *lazlo.Broker, r *TaskRotation, l *lazlo.Locale) string {
+	out := fmt.Sprintf("%s (%s): %s", r.Name, r.Mode, rotationNames(b, r.Members))
+	if r.Mode == `weighted` {
+		for user, w := range r.Weights {
+			out += fmt.Sprintf("\n  %s has a weight of %d", b.SlackMeta.GetUserName(user), w)
+		}
+	}
+	if r.Schedule != `` {
+		out += fmt.Sprintf("\npicks automatically on the schedule %s in <#%s>", r.Schedule, r.Channel)
+	}
+	for i := len(r.History) - 1; i >= 0 && i >= len(r.History)-5; i-- {
+		pick := r.History[i]
+		out += fmt.Sprintf("\n  %s picked %s", l.DateTime(pick.Time), b.SlackMeta.GetUserName(pick.User))
+	}
+	return out
+}
@@ -11,6 +11,7 @@ var RTMPing = &lazlo.Module{
 }
 
 func rtmrun(b *lazlo.Broker) {
+	b.Ready()
 	for {
 		// get a timer callback
 		timer := b.TimerCallback(`*/20 * * * * * *`)
@@ -0,0 +1,287 @@
+package modules
+
+import (
+	"bytes"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSH runs allowlisted commands on the hosts in the inventory, as a job (so
+// it can be watched and cancelled with !jobs), with the output going to a
+// thread. Hosts come from LAZLO_SSH_HOSTS, and from "host" entities in the
+// registry:
+//
+//	"host": {"web1": {"address": "10.0.0.5", "user": "deploy", "port": "22", "role": "web-oncall", "commands": "uptime|df -h"}}
+//
+// You need the host's role (or LAZLO_SSH_ROLE, or to be an admin) to run
+// anything on it, and the command has to match the host's commands regex (or
+// LAZLO_SSH_COMMANDS). Every attempt goes in the audit log.
+var SSH = &lazlo.Module{
+	Name: `SSH`,
+	Usage: `"%BOTNAME% ssh <host> <command>" : runs an allowlisted command on the host, with the output in a thread
+"%BOTNAME% ssh hosts" : lists the hosts, and what you need to run things on them`,
+	Run: sshRun,
+}
+
+// an sshHost is a host in the inventory
+type sshHost struct {
+	Name     string
+	Address  string
+	User     string
+	Port     string
+	Role     string // the role you need to run things on it
+	Commands string // a regex matching the commands it'll run
+}
+
+// shell metacharacters, which would let a command that matches the
+// allowlist run something that doesn't
+var sshShellChars = regexp.MustCompile("[;&|<>`$(){}\\\\\\n'\"*?!]")
+
+// how often output is posted to the thread, and the most lines that are
+const (
+	sshFlushEvery = 2 * time.Second
+	sshMaxLines   = 500
+)
+
+func sshRun(b *lazlo.Broker) {
+	hosts := b.MessageCallback(`(?i)ssh hosts$`, true)
+	run := b.MessageCallback(`(?i)ssh ([\w.-]+) (.+)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-hosts.Chan:
+			pm.Event.Respond(sshHostList(b))
+		case pm := <-run.Chan:
+			if strings.EqualFold(pm.Match[1], `hosts`) {
+				continue
+			}
+			sshCommand(b, pm)
+		}
+	}
+}
+
+// sshInventory returns the hosts in LAZLO_SSH_HOSTS and the registry (which
+// wins)
+func sshInventory(b *lazlo.Broker) map[string]*sshHost {
+	hosts := make(map[string]*sshHost)
+	for _, item := range strings.Split(b.Config.SSHHosts, `,`) {
+		parts := strings.SplitN(strings.TrimSpace(item), `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		host := &sshHost{Name: strings.ToLower(parts[0]), Address: parts[1], Role: b.Config.SSHRole, Commands: b.Config.SSHCommands}
+		if i := strings.Index(host.Address, `@`); i >= 0 {
+			host.User, host.Address = host.Address[:i], host.Address[i+1:]
+		}
+		if i := strings.LastIndex(host.Address, `:`); i >= 0 {
+			host.Address, host.Port = host.Address[:i], host.Address[i+1:]
+		}
+		hosts[host.Name] = host
+	}
+	if b.Registry != nil {
+		for _, e := range b.Registry.List(`host`) {
+			host := &sshHost{
+				Name:     strings.ToLower(e.Name),
+				Address:  e.Attrs[`address`],
+				User:     e.Attrs[`user`],
+				Port:     e.Attrs[`port`],
+				Role:     e.Attrs[`role`],
+				Commands: e.Attrs[`commands`],
+			}
+			if host.Address == `` {
+				host.Address = e.Name
+			}
+			if host.Role == `` {
+				host.Role = b.Config.SSHRole
+			}
+			if host.Commands == `` {
+				host.Commands = b.Config.SSHCommands
+			}
+			hosts[host.Name] = host
+		}
+	}
+	return hosts
+}
+
+func sshHostList(b *lazlo.Broker) string {
+	hosts := sshInventory(b)
+	if len(hosts) == 0 {
+		return `I don't know about any hosts (see LAZLO_SSH_HOSTS)`
+	}
+	var lines []string
+	for _, host := range hosts {
+		role := `admins only`
+		if host.Role != `` {
+			role = `needs the ` + host.Role + ` role`
+		}
+		commands := host.Commands
+		if commands == `` {
+			commands = `nothing`
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s, runs `%s`", host.Name, role, commands))
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// sshAllowed checks the user can run the command on the host
+func sshAllowed(b *lazlo.Broker, host *sshHost, user string, command string) error {
+	if !b.IsAdmin(user) && (host.Role == `` || !b.HasRole(user, host.Role)) {
+		if host.Role == `` {
+			return fmt.Errorf("only admins can run things on %s", host.Name)
+		}
+		return fmt.Errorf("you need the %s role to run things on %s", host.Role, host.Name)
+	}
+	if sshShellChars.MatchString(command) {
+		return fmt.Errorf("I don't run commands with shell characters in them")
+	}
+	if host.Commands == `` {
+		return fmt.Errorf("%s doesn't allow any commands", host.Name)
+	}
+	allowed, err := regexp.Compile(`^(?:` + host.Commands + `)$`)
+	if err != nil {
+		return fmt.Errorf("%s's allowed commands are broken: %s", host.Name, err)
+	}
+	if !allowed.MatchString(command) {
+		return fmt.Errorf("%s isn't allowed on %s", command, host.Name)
+	}
+	return nil
+}
+
+func sshCommand(b *lazlo.Broker, pm lazlo.PatternMatch) {
+	name, command := strings.ToLower(pm.Match[1]), strings.TrimSpace(pm.Match[2])
+	user := pm.Event.User
+	host := sshInventory(b)[name]
+	if host == nil {
+		pm.Event.Reply(fmt.Sprintf("I don't know about a host called %s", name))
+		return
+	}
+	if err := sshAllowed(b, host, user, command); err != nil {
+		b.Audit(user, `ssh`, host.Name, command, false)
+		pm.Event.Reply(fmt.Sprintf("Sorry, %s", err))
+		return
+	}
+	b.Audit(user, `ssh`, host.Name, command, true)
+
+	args := []string{`-o`, `BatchMode=yes`, `-o`, `ConnectTimeout=10`}
+	if b.Config.SSHKey != `` {
+		args = append(args, `-i`, b.Config.SSHKey)
+	}
+	if host.Port != `` {
+		args = append(args, `-p`, host.Port)
+	}
+	target := host.Address
+	if host.User != `` {
+		target = host.User + `@` + target
+	}
+	args = append(args, target, `--`, command)
+	e := pm.Event
+	b.Jobs.Submit(fmt.Sprintf("ssh %s %s", host.Name, command), e, func(job *lazlo.Job) error {
+		out := &sshOutput{}
+		cmd := exec.Command(`ssh`, args...)
+		cmd.Stdout, cmd.Stderr = out, out
+		if err := cmd.Start(); err != nil {
+			b.Audit(user, `ssh`, host.Name, command+`: `+err.Error(), true)
+			return err
+		}
+		done := make(chan error, 1)
+		go func() { done <- cmd.Wait() }()
+		timeout := time.After(time.Duration(b.Config.SSHTimeout) * time.Minute)
+		tick := time.NewTicker(sshFlushEvery)
+		defer tick.Stop()
+		posted := 0
+		flush := func() {
+			var post []string
+			for _, line := range out.lines() {
+				job.Logf("%s", line)
+				if posted < sshMaxLines {
+					post = append(post, line)
+				}
+				posted++
+			}
+			if post != nil {
+				e.RespondInThread("```\n" + strings.Join(post, "\n") + "\n```")
+			}
+		}
+		var err error
+		cancelled := false
+	wait:
+		for {
+			select {
+			case err = <-done:
+				break wait
+			case <-tick.C:
+				flush()
+			case <-job.Cancelled():
+				cmd.Process.Kill()
+				<-done
+				cancelled = true
+				break wait
+			case <-timeout:
+				cmd.Process.Kill()
+				<-done
+				err = fmt.Errorf("timed out after %d minutes", b.Config.SSHTimeout)
+				break wait
+			}
+		}
+		flush()
+		if posted > sshMaxLines {
+			e.RespondInThread(fmt.Sprintf("(that's the first %d lines of %d; !jobs logs %d has the end)", sshMaxLines, posted, job.ID))
+		}
+		result := `exit 0`
+		switch {
+		case cancelled:
+			result = `cancelled`
+		case err != nil:
+			result = err.Error()
+		}
+		b.Audit(user, `ssh`, host.Name, command+`: `+result, true)
+		job.Progress("%s", result)
+		return err
+	})
+}
+
+// sshOutput collects a command's output, a line at a time
+type sshOutput struct {
+	lock    sync.Mutex
+	partial bytes.Buffer
+	done    []string
+}
+
+func (o *sshOutput) Write(p []byte) (int, error) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.partial.Write(p)
+	for {
+		line, err := o.partial.ReadString('\n')
+		if err != nil {
+			// put back what's left of the line
+			rest := line
+			o.partial.Reset()
+			o.partial.WriteString(rest)
+			break
+		}
+		o.done = append(o.done, strings.TrimRight(line, "\r\n"))
+	}
+	return len(p), nil
+}
+
+// lines returns the lines written since it was last called (and whatever's
+// there of the current one, if it's been a while)
+func (o *sshOutput) lines() []string {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	lines := o.done
+	o.done = nil
+	if o.partial.Len() > 0 {
+		lines = append(lines, o.partial.String())
+		o.partial.Reset()
+	}
+	return lines
+}
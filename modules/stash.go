@@ -0,0 +1,292 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stash is a clipboard: it saves the message before the command (or the
+// whole thread, if the command's in one) under a name, for pasting back
+// later. Stashes are shared with the channel, or kept for just you with
+// "my".
+var Stash = &lazlo.Module{
+	Name: `Stash`,
+	Usage: `"!stash [my] <name>" : saves the message before this one (or the whole thread, in a thread) as name, for the channel (or just for you)
+"!unstash <name>" : pastes it back
+"!stash list" : lists the stashes you can unstash here
+"!stash drop [my] <name>" : deletes one (!undo puts it back)`,
+	Run: stashRun,
+}
+
+// A StashEntry is something somebody stashed
+type StashEntry struct {
+	Name     string                    `json:"name"`
+	By       string                    `json:"by"`
+	Channel  string                    `json:"channel"`
+	Thread   string                    `json:"thread,omitempty"`
+	Stashed  time.Time                 `json:"stashed"`
+	Messages []lazlo.TranscriptMessage `json:"messages"`
+}
+
+// stashes are kept under stash:<channel>:<who stashed it>:<name>, or
+// stash:<user>:<name> if they're just for the user
+func stashKey(channel string, user string, name string) string {
+	if channel == `` {
+		return lazlo.UserKey(`stash`, user, name)
+	}
+	return strings.Join([]string{`stash`, channel, user, name}, `:`)
+}
+
+func stashRun(b *lazlo.Broker) {
+	save := b.MessageCallback(`(?i)^!stash (my )?([\w.-]+)$`, false)
+	unstash := b.MessageCallback(`(?i)^!unstash ([\w.-]+)$`, false)
+	list := b.MessageCallback(`(?i)^!stash list$`, false)
+	drop := b.MessageCallback(`(?i)^!stash drop (my )?([\w.-]+)$`, false)
+
+	// stashes keep what other people said, so they have to forget it too
+	b.OnExpunge(func(user string) ([]string, error) {
+		return stashForget(b, user), nil
+	})
+
+	b.Ready()
+	for {
+		select {
+		case pm := <-save.Chan:
+			name := strings.ToLower(pm.Match[2])
+			if name == `list` || name == `drop` {
+				continue
+			}
+			channel := pm.Event.Channel
+			if pm.Match[1] != `` {
+				channel = ``
+			}
+			entry, err := stashCapture(b, pm.Event)
+			if err != nil {
+				pm.Event.Reply(fmt.Sprintf("I couldn't stash that: %s", err))
+				continue
+			}
+			entry.Name, entry.By, entry.Channel = name, pm.Event.User, channel
+			if existing := stashScoped(b, pm.Event, name, channel == ``); existing != nil && existing.By != pm.Event.User {
+				pm.Event.Reply(fmt.Sprintf("<@%s> already stashed %s here, pick another name", existing.By, name))
+				continue
+			}
+			if err := stashSave(b, entry); err != nil {
+				pm.Event.Reply(fmt.Sprintf("I couldn't stash that: %s", err))
+				continue
+			}
+			what := `that`
+			if entry.Thread != `` {
+				what = fmt.Sprintf("the thread (%d messages)", len(entry.Messages))
+			}
+			pm.Event.Reply(fmt.Sprintf("stashed %s as %s. !unstash %s to get it back", what, name, name))
+		case pm := <-unstash.Chan:
+			entry := stashFind(b, pm.Event, strings.ToLower(pm.Match[1]))
+			if entry == nil {
+				pm.Event.Reply(fmt.Sprintf("there's nothing stashed as %s", pm.Match[1]))
+				continue
+			}
+			pm.Event.Respond(stashText(entry))
+		case pm := <-list.Chan:
+			pm.Event.Respond(stashList(b, pm.Event))
+		case pm := <-drop.Chan:
+			name := strings.ToLower(pm.Match[2])
+			entry := stashScoped(b, pm.Event, name, pm.Match[1] != ``)
+			if entry == nil {
+				pm.Event.Reply(fmt.Sprintf("there's nothing stashed as %s", name))
+				continue
+			}
+			if entry.By != pm.Event.User && !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(fmt.Sprintf("Sorry, only <@%s> (or an admin) can drop %s", entry.By, name))
+				continue
+			}
+			b.Brain.Delete(stashKey(entry.Channel, entry.By, entry.Name))
+			b.Undoable(pm.Event, `stash drop`, name, 0, func() error {
+				if stashScoped(b, pm.Event, entry.Name, entry.Channel == ``) != nil {
+					return fmt.Errorf("there's a new stash called %s", entry.Name)
+				}
+				return stashSave(b, entry)
+			})
+			pm.Event.Reply(fmt.Sprintf("dropped %s (!undo to get it back)", name))
+		}
+	}
+}
+
+// stashCapture gets the thread the command was said in, or else the message
+// before it
+func stashCapture(b *lazlo.Broker, e *lazlo.Event) (*StashEntry, error) {
+	entry := &StashEntry{Stashed: time.Now()}
+	if e.ThreadTs != `` && e.ThreadTs != e.Ts {
+		t, err := b.ThreadTranscript(e.Channel, e.ThreadTs)
+		if err != nil {
+			return nil, err
+		}
+		entry.Thread = e.ThreadTs
+		for _, m := range t.Messages {
+			if m.Ts != e.Ts {
+				entry.Messages = append(entry.Messages, m)
+			}
+		}
+		return entry, nil
+	}
+	t, err := b.HistoryTranscript(e.Channel)
+	if err != nil {
+		return nil, err
+	}
+	for i := len(t.Messages) - 1; i >= 0; i-- {
+		m := t.Messages[i]
+		if m.Ts == e.Ts || m.User == b.SlackMeta.Self.ID || strings.HasPrefix(m.Text, `!stash`) || strings.HasPrefix(m.Text, `!unstash`) {
+			continue
+		}
+		entry.Messages = []lazlo.TranscriptMessage{m}
+		return entry, nil
+	}
+	return nil, fmt.Errorf("I don't remember anything said here before that")
+}
+
+// stashSave keeps the stash in the brain, redacted
+func stashSave(b *lazlo.Broker, entry *StashEntry) error {
+	stored := *entry
+	stored.Messages = transcriptRedact(b, entry.Messages)
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return b.Brain.Set(stashKey(entry.Channel, entry.By, entry.Name), data)
+}
+
+// stashAll returns every stash the user can see in the channel: theirs, and
+// the channel's
+func stashAll(b *lazlo.Broker, e *lazlo.Event) []*StashEntry {
+	keys, _ := b.Brain.Keys()
+	var entries []*StashEntry
+	for _, key := range keys {
+		parts := strings.Split(key, `:`)
+		switch {
+		case len(parts) == 3 && parts[0] == `stash` && parts[1] == e.User:
+		case len(parts) == 4 && parts[0] == `stash` && parts[1] == e.Channel:
+		default:
+			continue
+		}
+		if entry := stashLoad(b, key); entry != nil {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func stashLoad(b *lazlo.Broker, key string) *StashEntry {
+	data, err := b.Brain.Get(key)
+	if err != nil || data == nil {
+		return nil
+	}
+	entry := new(StashEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		lazlo.Logger.Error(`Stash:: `, key, `: `, err)
+		return nil
+	}
+	return entry
+}
+
+// stashFind finds the named stash, the user's own first
+func stashFind(b *lazlo.Broker, e *lazlo.Event, name string) *StashEntry {
+	if entry := stashScoped(b, e, name, true); entry != nil {
+		return entry
+	}
+	return stashScoped(b, e, name, false)
+}
+
+// stashScoped finds the user's named stash (mine), or the channel's
+func stashScoped(b *lazlo.Broker, e *lazlo.Event, name string, mine bool) *StashEntry {
+	for _, entry := range stashAll(b, e) {
+		if entry.Name == name && (entry.Channel == ``) == mine {
+			return entry
+		}
+	}
+	return nil
+}
+
+// stashText pastes the stash back
+func stashText(entry *StashEntry) string {
+	if entry.Thread == `` && len(entry.Messages) == 1 {
+		m := entry.Messages[0]
+		return fmt.Sprintf("%s\n— <@%s>", stashQuote(m.Text), m.User)
+	}
+	var lines []string
+	for _, m := range entry.Messages {
+		lines = append(lines, fmt.Sprintf("<@%s>: %s", m.User, m.Text))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// stashQuote block-quotes every line of the text
+func stashQuote(text string) string {
+	return `> ` + strings.Replace(text, "\n", "\n> ", -1)
+}
+
+func stashList(b *lazlo.Broker, e *lazlo.Event) string {
+	entries := stashAll(b, e)
+	if entries == nil {
+		return `nothing's stashed here`
+	}
+	l := b.Locale(e.User, e.Channel)
+	var mine, shared []string
+	for _, entry := range entries {
+		what := `a message`
+		if entry.Thread != `` {
+			what = fmt.Sprintf("a thread (%d messages)", len(entry.Messages))
+		}
+		line := fmt.Sprintf("%s: %s, %s", entry.Name, what, l.Ago(entry.Stashed))
+		if entry.Channel == `` {
+			mine = append(mine, line)
+		} else {
+			shared = append(shared, fmt.Sprintf("%s by <@%s>", line, entry.By))
+		}
+	}
+	sort.Strings(mine)
+	sort.Strings(shared)
+	var out []string
+	if shared != nil {
+		out = append(out, "Stashed here:\n"+strings.Join(shared, "\n"))
+	}
+	if mine != nil {
+		out = append(out, "Yours:\n"+strings.Join(mine, "\n"))
+	}
+	return strings.Join(out, "\n")
+}
+
+// stashForget takes the user's messages out of other people's stashes (the
+// stashes they made themselves are UserKeys, so they're already gone)
+func stashForget(b *lazlo.Broker, user string) []string {
+	keys, _ := b.Brain.Keys()
+	var removed []string
+	for _, key := range keys {
+		if !strings.HasPrefix(key, `stash:`) {
+			continue
+		}
+		entry := stashLoad(b, key)
+		if entry == nil {
+			continue
+		}
+		kept := entry.Messages[:0]
+		for _, m := range entry.Messages {
+			if m.User != user {
+				kept = append(kept, m)
+			}
+		}
+		if len(kept) == len(entry.Messages) {
+			continue
+		}
+		entry.Messages = kept
+		if len(kept) == 0 {
+			b.Brain.Delete(key)
+		} else {
+			stashSave(b, entry)
+		}
+		removed = append(removed, fmt.Sprintf("their messages in the stash %s", entry.Name))
+	}
+	return removed
+}
@@ -0,0 +1,257 @@
+package modules
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+var Status = &lazlo.Module{
+	Name: `Status`,
+	Usage: `"%BOTNAME% status in|out|lunch" : lets the team know you're working, done, or out to lunch
+"%BOTNAME% status team" : shows who's in right now, and how long everyone's worked today
+"%BOTNAME% status export [team]" : DMs you a calendar link with your (or the whole team's) sessions`,
+	Run: statusRun,
+}
+
+// the most sessions we keep for any one user
+const statusMaxSessions = 1000
+
+// A StatusSession is a stretch of time someone spent in or at lunch. End is
+// zero while the session's still going.
+type StatusSession struct {
+	State string
+	Start time.Time
+	End   time.Time
+}
+
+// A StatusLog is everything we know about one user's comings and goings
+type StatusLog struct {
+	User     string
+	Current  *StatusSession
+	Sessions []StatusSession
+}
+
+func statusRun(b *lazlo.Broker) {
+	set := b.MessageCallback(`(?i)status (in|out|lunch)$`, true)
+	team := b.MessageCallback(`(?i)status team$`, true)
+	export := b.MessageCallback(`(?i)status export( team)?$`, true)
+	ics := b.LinkCallback(`status.ics`, func(res http.ResponseWriter, req *http.Request) {
+		statusICS(b, res, req)
+	})
+	b.Ready()
+	for {
+		select {
+		case pm := <-set.Chan:
+			state := strings.ToLower(pm.Match[1])
+			log := statusGet(b, pm.Event.User)
+			if log.Current != nil && log.Current.State == state {
+				pm.Event.Reply(fmt.Sprintf("you've been %s since %s", state, b.Locale(pm.Event.User, pm.Event.Channel).Time(log.Current.Start)))
+				continue
+			}
+			statusSet(log, state, time.Now())
+			statusSave(b, log)
+			switch state {
+			case `in`:
+				pm.Event.Reply(`welcome back`)
+			case `lunch`:
+				pm.Event.Reply(`enjoy your lunch`)
+			case `out`:
+				pm.Event.Reply(fmt.Sprintf("see ya. you worked %s today", statusFormatDuration(statusWorked(log, statusToday()))))
+			}
+		case pm := <-team.Chan:
+			pm.Event.Respond(statusSummary(b, b.Locale(pm.Event.User, pm.Event.Channel)))
+		case pm := <-export.Chan:
+			who := pm.Event.User
+			if pm.Match[1] != `` {
+				who = `team`
+			}
+			token, err := statusToken(b, who)
+			if err != nil {
+				lazlo.Logger.Error(`Status:: couldn't make an export link: `, err)
+				pm.Event.Reply(`Sorry, I couldn't make you an export link`)
+				continue
+			}
+			b.Say(fmt.Sprintf("here's your calendar link (anyone with it can see these sessions, so keep it to yourself): %s?token=%s", ics.URL, token), b.GetDM(pm.Event.User))
+		}
+	}
+}
+
+// statusSet ends the current session (if any) and starts a new one, unless
+// the user's going out
+func statusSet(log *StatusLog, state string, now time.Time) {
+	if log.Current != nil {
+		log.Current.End = now
+		log.Sessions = append(log.Sessions, *log.Current)
+		if len(log.Sessions) > statusMaxSessions {
+			log.Sessions = log.Sessions[len(log.Sessions)-statusMaxSessions:]
+		}
+		log.Current = nil
+	}
+	if state != `out` {
+		log.Current = &StatusSession{State: state, Start: now}
+	}
+}
+
+func statusGet(b *lazlo.Broker, user string) *StatusLog {
+	log := &StatusLog{User: user}
+	if data, err := b.Brain.Get(lazlo.UserKey(`status`, user)); err == nil && data != nil {
+		json.Unmarshal(data, log)
+	}
+	return log
+}
+
+func statusSave(b *lazlo.Broker, log *StatusLog) {
+	data, _ := json.Marshal(log)
+	if err := b.Brain.Set(lazlo.UserKey(`status`, log.User), data); err != nil {
+		lazlo.Logger.Error(`Status:: couldn't save status for `, log.User, `: `, err)
+	}
+}
+
+// statusAll returns every user's log
+func statusAll(b *lazlo.Broker) []*StatusLog {
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		lazlo.Logger.Error(`Status:: couldn't list status logs: `, err)
+		return nil
+	}
+	var logs []*StatusLog
+	for _, key := range keys {
+		parts := strings.Split(key, `:`)
+		if len(parts) == 2 && parts[0] == `status` {
+			logs = append(logs, statusGet(b, parts[1]))
+		}
+	}
+	return logs
+}
+
+func statusToday() time.Time {
+	now := time.Now()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+}
+
+// statusWorked adds up the time the user was in since the given time
+func statusWorked(log *StatusLog, since time.Time) time.Duration {
+	sessions := log.Sessions
+	if log.Current != nil {
+		sessions = append(sessions, StatusSession{State: log.Current.State, Start: log.Current.Start, End: time.Now()})
+	}
+	var total time.Duration
+	for _, s := range sessions {
+		if s.State != `in` || s.End.Before(since) {
+			continue
+		}
+		start := s.Start
+		if start.Before(since) {
+			start = since
+		}
+		total += s.End.Sub(start)
+	}
+	return total
+}
+
+func statusFormatDuration(d time.Duration) string {
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// statusSummary says who's in, who's at lunch, and how long everybody's
+// worked today
+func statusSummary(b *lazlo.Broker, l *lazlo.Locale) string {
+	today := statusToday()
+	var lines []string
+	for _, log := range statusAll(b) {
+		worked := statusWorked(log, today)
+		if log.Current == nil && worked == 0 {
+			continue
+		}
+		state := `out`
+		if log.Current != nil {
+			state = fmt.Sprintf("%s since %s", log.Current.State, l.Time(log.Current.Start))
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s (%s today)", b.SlackMeta.GetUserName(log.User), state, statusFormatDuration(worked)))
+	}
+	if lines == nil {
+		return `Nobody's checked in today`
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n")
+}
+
+// statusToken returns the export token for a user (or the team), making one
+// if they don't have one yet. Tokens are kept in the brain as
+// statusics:<token>:<user> so they're expunged along with the user.
+func statusToken(b *lazlo.Broker, who string) (string, error) {
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		return ``, err
+	}
+	for _, key := range keys {
+		parts := strings.Split(key, `:`)
+		if len(parts) == 3 && parts[0] == `statusics` && parts[2] == who {
+			return parts[1], nil
+		}
+	}
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ``, err
+	}
+	token := hex.EncodeToString(buf)
+	return token, b.Brain.Set(fmt.Sprintf("statusics:%s:%s", token, who), []byte(who))
+}
+
+// statusICS serves the sessions belonging to a token as an ics calendar
+func statusICS(b *lazlo.Broker, res http.ResponseWriter, req *http.Request) {
+	token := req.URL.Query().Get(`token`)
+	who := ``
+	if keys, err := b.Brain.Keys(); err == nil && token != `` {
+		for _, key := range keys {
+			parts := strings.Split(key, `:`)
+			if len(parts) == 3 && parts[0] == `statusics` && parts[1] == token {
+				who = parts[2]
+			}
+		}
+	}
+	if who == `` {
+		http.NotFound(res, req)
+		return
+	}
+	var logs []*StatusLog
+	if who == `team` {
+		logs = statusAll(b)
+	} else {
+		logs = []*StatusLog{statusGet(b, who)}
+	}
+
+	var cal bytes.Buffer
+	stamp := time.Now().UTC().Format(`20060102T150405Z`)
+	cal.WriteString("BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//lazlo//status//EN\r\n")
+	for _, log := range logs {
+		name := b.SlackMeta.GetUserName(log.User)
+		sessions := log.Sessions
+		if log.Current != nil {
+			sessions = append(sessions, StatusSession{State: log.Current.State, Start: log.Current.Start, End: time.Now()})
+		}
+		for _, s := range sessions {
+			fmt.Fprintf(&cal, "BEGIN:VEVENT\r\nUID:%s-%d@lazlo\r\nDTSTAMP:%s\r\nDTSTART:%s\r\nDTEND:%s\r\nSUMMARY:%s\r\nEND:VEVENT\r\n",
+				log.User, s.Start.Unix(), stamp,
+				s.Start.UTC().Format(`20060102T150405Z`), s.End.UTC().Format(`20060102T150405Z`),
+				icsEscape(fmt.Sprintf("%s (%s)", name, s.State)))
+		}
+	}
+	cal.WriteString("END:VCALENDAR\r\n")
+	res.Header().Set(`Content-Type`, `text/calendar; charset=utf-8`)
+	res.Write(cal.Bytes())
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\n", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
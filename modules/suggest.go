@@ -0,0 +1,33 @@
+package modules
+
+import (
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+)
+
+// Suggestions turns lazlo's "did you mean ...?" on and off in a channel (see
+// lazlo.Broker.Suggest)
+var Suggestions = &lazlo.Module{
+	Name:         `Suggestions`,
+	Usage:        `"%BOTNAME% suggestions <on|off>" : whether I suggest commands in here when I don't understand one`,
+	Run:          suggestRun,
+	ExternalSafe: true,
+}
+
+func suggestRun(b *lazlo.Broker) {
+	toggle := b.MessageCallback(`(?i)suggestions (on|off)$`, true)
+	b.Ready()
+	for {
+		pm := <-toggle.Chan
+		on := strings.ToLower(pm.Match[1]) == `on`
+		if err := b.SetSuggestions(pm.Event.Channel, on); err != nil {
+			pm.Event.Reply(`I couldn't change that: ` + err.Error())
+			continue
+		}
+		if on {
+			pm.Event.Respond(`Ok, when I don't understand a command in here, I'll suggest the closest ones I know`)
+		} else {
+			pm.Event.Respond(`Ok, I'll keep my suggestions to myself in here`)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strings"
+	"time"
+)
+
+// Threads watches the channels in LAZLO_THREAD_CHANNELS for long
+// back-and-forth conversations between a few people, and asks them to take
+// it to a thread
+var Threads = &lazlo.Module{
+	Name:  `Threads`,
+	Usage: `(no commands) asks people having long conversations in busy channels to move them into a thread`,
+	Run:   threadsRun,
+}
+
+// a top-level message we've seen recently
+type threadsMsg struct {
+	ts   string
+	user string
+	when time.Time
+}
+
+func threadsRun(b *lazlo.Broker) {
+	var channels []string
+	for _, name := range strings.Split(b.Config.ThreadChannels, `,`) {
+		name = strings.TrimPrefix(strings.TrimSpace(name), `#`)
+		if name == `` {
+			continue
+		}
+		if c := b.SlackMeta.GetChannelByName(name); c != nil {
+			name = c.ID
+		}
+		channels = append(channels, name)
+	}
+	if channels == nil {
+		lazlo.Logger.Info(`Threads:: LAZLO_THREAD_CHANNELS isn't set, not nudging anyone`)
+		return
+	}
+	threshold := b.Config.ThreadMessages
+	window := time.Duration(b.Config.ThreadWindow) * time.Minute
+
+	recent := make(map[string][]threadsMsg)
+	quiet := make(map[string]time.Time) // don't nudge a channel again until then
+	messages := b.MessageCallback(`.*`, false)
+	b.Ready()
+	for {
+		pm := <-messages.Chan
+		e := pm.Event
+		if !threadsWatched(channels, e.Channel) || e.Subtype != `` || e.User == `` || e.User == b.SlackMeta.Self.ID {
+			continue
+		}
+		if e.ThreadTs != `` && e.ThreadTs != e.Ts {
+			continue // already in a thread, which is the point
+		}
+
+		// keep the last few top-level messages that are inside the window
+		msgs := append(recent[e.Channel], threadsMsg{ts: e.Ts, user: e.User, when: time.Now()})
+		for len(msgs) > 0 && (len(msgs) > threshold || time.Since(msgs[0].when) > window) {
+			msgs = msgs[1:]
+		}
+		recent[e.Channel] = msgs
+		if len(msgs) < threshold || time.Now().Before(quiet[e.Channel]) {
+			continue
+		}
+		users := threadsConversation(msgs, b.Config.ThreadUsers)
+		if users == nil {
+			continue
+		}
+		threadsNudge(b, e.Channel, msgs[0].ts, users)
+		recent[e.Channel] = nil
+		quiet[e.Channel] = time.Now().Add(window)
+	}
+}
+
+func threadsWatched(channels []string, channel string) bool {
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// threadsConversation returns the people talking if the messages look like
+// a back-and-forth between two and maxUsers people (the speaker changes at
+// least every other message), and nil otherwise
+func threadsConversation(msgs []threadsMsg, maxUsers int) []string {
+	var users []string
+	seen := make(map[string]bool)
+	switches := 0
+	for i, m := range msgs {
+		if !seen[m.user] {
+			seen[m.user] = true
+			users = append(users, m.user)
+		}
+		if i > 0 && m.user != msgs[i-1].user {
+			switches++
+		}
+	}
+	if len(users) < 2 || len(users) > maxUsers || switches < len(msgs)/2 {
+		return nil
+	}
+	return users
+}
+
+// threadsNudge asks the people in a conversation to move it into a thread,
+// either the thread of the message that started it, or (in anchor mode) a
+// fresh thread the bot starts for them
+func threadsNudge(b *lazlo.Broker, channel string, firstTs string, users []string) {
+	who := b.Mentions(users)
+	if b.Config.ThreadMode != `anchor` {
+		b.Say(fmt.Sprintf("%s: looks like a great conversation! Mind moving it into a thread so the channel stays readable? You can pick it up here: %s", who, b.Permalink(channel, firstTs)), channel)
+		return
+	}
+	reply := <-b.Say(fmt.Sprintf(":thread: %s, here's a thread for your conversation, so the channel stays readable. Reply in here :point_down:", who), channel)
+	anchor, _ := reply[`ts`].(string)
+	if anchor == `` {
+		lazlo.Logger.Error(`Threads:: couldn't start a thread in `, channel)
+		return
+	}
+	b.Send(&lazlo.Event{
+		Type:     `message`,
+		Channel:  channel,
+		Text:     fmt.Sprintf("picking up from %s", b.Permalink(channel, firstTs)),
+		ThreadTs: anchor,
+	})
+}
@@ -0,0 +1,163 @@
+package modules
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var Transcripts = &lazlo.Module{
+	Name: `Transcripts`,
+	Usage: `"%BOTNAME% transcript <link to a thread> [json]" : (admins only) uploads a redacted transcript of the thread (markdown unless you say json)
+"%BOTNAME% transcript here [json]" : (admins only) uploads a redacted transcript of what I remember of this channel
+"%BOTNAME% transcript link <link to a thread>|here" : (admins only) DMs you a download link (good for a week) instead`,
+	Run: transcriptsRun,
+}
+
+// how long download links last
+const transcriptTTL = 7 * 24 * time.Hour
+
+// https://team.slack.com/archives/C123/p1234567890123456 (optionally with
+// ?thread_ts=... if it links to a reply)
+var transcriptLink = regexp.MustCompile(`/archives/(\w+)/p(\d{10})(\d{6})(?:\S*?thread_ts=(\d+\.\d+))?`)
+
+func transcriptsRun(b *lazlo.Broker) {
+	cb := b.MessageCallback(`(?i)transcript (link )?(<?https?://\S+>?|here)( json)?$`, true)
+	download := b.LinkCallback(`transcript`, func(res http.ResponseWriter, req *http.Request) {
+		transcriptServe(b, res, req)
+	})
+	b.Ready()
+	for {
+		pm := <-cb.Chan
+		if !b.IsAdmin(pm.Event.User) {
+			pm.Event.Reply(`Sorry, only admins can export transcripts`)
+			continue
+		}
+		var t *lazlo.Transcript
+		var err error
+		if strings.ToLower(pm.Match[2]) == `here` {
+			t, err = b.HistoryTranscript(pm.Event.Channel)
+		} else if m := transcriptLink.FindStringSubmatch(pm.Match[2]); m != nil {
+			ts := m[2] + `.` + m[3]
+			if m[4] != `` {
+				ts = m[4]
+			}
+			t, err = b.ThreadTranscript(m[1], ts)
+		} else {
+			pm.Event.Reply(`That doesn't look like a link to a slack message`)
+			continue
+		}
+		if err != nil {
+			pm.Event.Reply(fmt.Sprintf("I couldn't export that: %s", err))
+			continue
+		}
+		if len(t.Messages) == 0 {
+			pm.Event.Reply(`There's nothing to export`)
+			continue
+		}
+
+		if pm.Match[1] != `` {
+			token, err := transcriptSave(b, t)
+			if err != nil {
+				pm.Event.Reply(fmt.Sprintf("I couldn't save the transcript: %s", err))
+				continue
+			}
+			url := fmt.Sprintf("%s?token=%s", download.URL, token)
+			b.Say(fmt.Sprintf("Here's the transcript of %s (good until %s): %s (markdown), %s&format=json (json)",
+				t.ChannelName, b.Locale(pm.Event.User, ``).Day(time.Now().Add(transcriptTTL)), url, url), b.GetDM(pm.Event.User))
+			continue
+		}
+		name := transcriptFilename(t)
+		data := []byte(t.Markdown(b))
+		if pm.Match[3] != `` {
+			name += `.json`
+			data = t.JSON()
+		} else {
+			name += `.md`
+		}
+		if err := b.Upload(pm.Event.Channel, name, data, fmt.Sprintf("%d messages from %s", len(t.Messages), t.ChannelName)); err != nil {
+			pm.Event.Reply(fmt.Sprintf("I couldn't upload the transcript: %s", err))
+		}
+	}
+}
+
+func transcriptFilename(t *lazlo.Transcript) string {
+	return fmt.Sprintf("transcript-%s-%s", strings.TrimPrefix(t.ChannelName, `#`), t.Exported.Format(`20060102-1504`))
+}
+
+// transcriptRedact returns the messages with their text redacted, for
+// keeping in the brain
+func transcriptRedact(b *lazlo.Broker, msgs []lazlo.TranscriptMessage) []lazlo.TranscriptMessage {
+	redacted := make([]lazlo.TranscriptMessage, len(msgs))
+	for i, m := range msgs {
+		m.Text = b.Redact(m.Text)
+		redacted[i] = m
+	}
+	return redacted
+}
+
+// transcriptSave keeps the transcript for a week under an unguessable token
+func transcriptSave(b *lazlo.Broker, t *lazlo.Transcript) (string, error) {
+	transcriptPrune(b)
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return ``, err
+	}
+	token := hex.EncodeToString(buf)
+	stored := *t
+	stored.Messages = transcriptRedact(b, t.Messages)
+	data, _ := json.Marshal(stored)
+	return token, b.Brain.Set(`transcript:`+token, data)
+}
+
+func transcriptGet(b *lazlo.Broker, token string) *lazlo.Transcript {
+	data, err := b.Brain.Get(`transcript:` + token)
+	if err != nil {
+		return nil
+	}
+	t := new(lazlo.Transcript)
+	if json.Unmarshal(data, t) != nil || time.Since(t.Exported) > transcriptTTL {
+		return nil
+	}
+	return t
+}
+
+// transcriptPrune forgets transcripts that have expired
+func transcriptPrune(b *lazlo.Broker) {
+	keys, _ := b.Brain.Keys()
+	for _, key := range keys {
+		if strings.HasPrefix(key, `transcript:`) && transcriptGet(b, strings.TrimPrefix(key, `transcript:`)) == nil {
+			b.Brain.Delete(key)
+		}
+	}
+}
+
+// transcriptServe hands out a saved transcript as markdown, or json with
+// format=json
+func transcriptServe(b *lazlo.Broker, res http.ResponseWriter, req *http.Request) {
+	token := req.URL.Query().Get(`token`)
+	var t *lazlo.Transcript
+	if token != `` {
+		t = transcriptGet(b, token)
+	}
+	if t == nil {
+		http.NotFound(res, req)
+		return
+	}
+	name := transcriptFilename(t)
+	if req.URL.Query().Get(`format`) == `json` {
+		res.Header().Set(`Content-Type`, `application/json`)
+		res.Header().Set(`Content-Disposition`, fmt.Sprintf("attachment; filename=%q", name+`.json`))
+		res.Write(t.JSON())
+		return
+	}
+	res.Header().Set(`Content-Type`, `text/markdown; charset=utf-8`)
+	res.Header().Set(`Content-Disposition`, fmt.Sprintf("attachment; filename=%q", name+`.md`))
+	res.Write([]byte(t.Markdown(b)))
+}
@@ -0,0 +1,310 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"github.com/gorhill/cronexpr"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var Triage = &lazlo.Module{
+	Name: `Triage`,
+	Usage: `"%BOTNAME% triage list" : lists the unhandled messages in the triage channel
+"%BOTNAME% triage claim <n>" : claims triage item n (same as reacting with :eyes:)
+"%BOTNAME% triage close <n>" : closes triage item n (same as reacting with :white_check_mark:)`,
+	Run: triageRun,
+}
+
+// the reactions that mark a triage item's state
+const (
+	triageNew     = `new`
+	triageClaimed = `eyes`
+	triageDone    = `white_check_mark`
+)
+
+// the default summary schedule: weekdays at 9am
+const triageSummaryDefault = `0 0 9 * * 1-5 *`
+
+// closed items are forgotten after this long
+const triageKeep = 7 * 24 * time.Hour
+
+// A TriageItem is a message posted to the triage channel
+type TriageItem struct {
+	Num     int
+	Ts      string
+	User    string
+	Text    string
+	State   string // one of the triage reactions above
+	Claimer string
+	Posted  time.Time
+	Closed  time.Time
+}
+
+func triageRun(b *lazlo.Broker) {
+	if b.Config.TriageChannel == `` {
+		lazlo.Logger.Info(`Triage:: LAZLO_TRIAGE_CHANNEL isn't set, not triaging anything`)
+		return
+	}
+	channel := strings.TrimPrefix(b.Config.TriageChannel, `#`)
+	if c := b.SlackMeta.GetChannelByName(channel); c != nil {
+		channel = c.ID
+	}
+	schedule := b.Config.TriageSummary
+	if schedule == `` {
+		schedule = triageSummaryDefault
+	}
+	if _, err := cronexpr.Parse(schedule); err != nil {
+		lazlo.Logger.Error(`Triage:: bad LAZLO_TRIAGE_SUMMARY `, schedule, `: `, err)
+		return
+	}
+
+	posts := b.MessageCallback(`.*`, false, channel)
+	reactions := b.EventCallback(`type`, `^reaction_(added|removed)$`)
+	list := b.MessageCallback(`(?i)triage list$`, true)
+	claim := b.MessageCallback(`(?i)triage (claim|close) #?(\d+)$`, true)
+	summary := b.TimerCallback(schedule)
+	b.HomeSection(`Your triage items`, 30, func(user string) []lazlo.Block {
+		var mine []*TriageItem
+		for _, item := range triageOpen(b) {
+			if item.State == triageClaimed && item.Claimer == user {
+				mine = append(mine, item)
+			}
+		}
+		if len(mine) == 0 {
+			return nil
+		}
+		return []lazlo.Block{lazlo.TextBlock(triageFormat(b, channel, mine))}
+	})
+	b.Ready()
+	for {
+		select {
+		case pm := <-posts.Chan:
+			e := pm.Event
+			// messages addressed to the bot are commands, not triage items
+			_, command := b.Addressed(e.Channel, e.Text)
+			if e.Subtype != `` || e.User == `` || e.User == b.SlackMeta.Self.ID || command {
+				continue
+			}
+			item := &TriageItem{
+				Num:    triageNextNum(b),
+				Ts:     e.Ts,
+				User:   e.User,
+				Text:   e.Text,
+				State:  triageNew,
+				Posted: time.Now(),
+			}
+			triageSave(b, item)
+			triageReact(b, channel, item, triageNew)
+		case event := <-reactions.Chan:
+			triageReaction(b, channel, event)
+		case pm := <-list.Chan:
+			open := triageOpen(b)
+			if len(open) == 0 {
+				pm.Event.Reply(`Nothing needs triage right now`)
+				continue
+			}
+			pm.Event.Respond(triageFormat(b, channel, open))
+		case pm := <-claim.Chan:
+			num, _ := strconv.Atoi(pm.Match[2])
+			item := triageFind(b, num)
+			if item == nil {
+				pm.Event.Reply(fmt.Sprintf("I don't have a triage item #%d", num))
+				continue
+			}
+			if strings.ToLower(pm.Match[1]) == `claim` {
+				triageSet(b, item, triageClaimed, pm.Event.User)
+				triageReact(b, channel, item, triageClaimed)
+				pm.Event.Reply(fmt.Sprintf("#%d is all yours", num))
+			} else {
+				triageSet(b, item, triageDone, pm.Event.User)
+				triageReact(b, channel, item, triageDone)
+				pm.Event.Reply(fmt.Sprintf("closed #%d", num))
+			}
+		case <-summary.Chan:
+			triagePrune(b)
+			if open := triageOpen(b); len(open) > 0 {
+				b.SayAs(`Triage`, triageFormat(b, channel, open), channel)
+			}
+		}
+	}
+}
+
+// triageReaction updates an item when someone reacts to it in the channel
+func triageReaction(b *lazlo.Broker, channel string, event map[string]interface{}) {
+	item, _ := event[`item`].(map[string]interface{})
+	if item[`channel`] != channel {
+		return
+	}
+	ts, _ := item[`ts`].(string)
+	user, _ := event[`user`].(string)
+	reaction, _ := event[`reaction`].(string)
+	if user == b.SlackMeta.Self.ID {
+		return
+	}
+	ti := triageGet(b, ts)
+	if ti == nil {
+		return
+	}
+	if event[`type`] == `reaction_added` {
+		switch reaction {
+		case triageClaimed:
+			if ti.State == triageNew {
+				triageSet(b, ti, triageClaimed, user)
+			}
+		case triageDone:
+			triageSet(b, ti, triageDone, user)
+		}
+		return
+	}
+	// reaction_removed
+	switch {
+	case reaction == triageDone && ti.State == triageDone:
+		if ti.Claimer != `` {
+			triageSet(b, ti, triageClaimed, ti.Claimer)
+		} else {
+			triageSet(b, ti, triageNew, ``)
+		}
+	case reaction == triageClaimed && ti.State == triageClaimed && user == ti.Claimer:
+		triageSet(b, ti, triageNew, ``)
+	}
+}
+
+func triageSet(b *lazlo.Broker, item *TriageItem, state string, user string) {
+	item.State = state
+	switch state {
+	case triageNew:
+		item.Claimer = ``
+	case triageClaimed:
+		item.Claimer = user
+		item.Closed = time.Time{}
+	case triageDone:
+		if item.Claimer == `` {
+			item.Claimer = user
+		}
+		item.Closed = time.Now()
+	}
+	triageSave(b, item)
+}
+
+func triageReact(b *lazlo.Broker, channel string, item *TriageItem, emoji string) {
+	if err := b.React(channel, item.Ts, emoji); err != nil {
+		lazlo.Logger.Error(`Triage:: item `, item.Num, `: `, err)
+	}
+}
+
+func triageKey(ts string) string {
+	return `triage:item:` + ts
+}
+
+func triageSave(b *lazlo.Broker, item *TriageItem) {
+	data, _ := json.Marshal(item)
+	if err := b.Brain.Set(triageKey(item.Ts), data); err != nil {
+		lazlo.Logger.Error(`Triage:: couldn't save item `, item.Num, `: `, err)
+	}
+}
+
+func triageGet(b *lazlo.Broker, ts string) *TriageItem {
+	data, err := b.Brain.Get(triageKey(ts))
+	if err != nil || data == nil {
+		return nil
+	}
+	item := new(TriageItem)
+	if err := json.Unmarshal(data, item); err != nil {
+		return nil
+	}
+	return item
+}
+
+// triageAll returns every item in the brain, oldest first
+func triageAll(b *lazlo.Broker) []*TriageItem {
+	keys, err := b.Brain.Keys()
+	if err != nil {
+		lazlo.Logger.Error(`Triage:: couldn't list items: `, err)
+		return nil
+	}
+	var items []*TriageItem
+	for _, key := range keys {
+		if strings.HasPrefix(key, triageKey(``)) {
+			if item := triageGet(b, strings.TrimPrefix(key, triageKey(``))); item != nil {
+				items = append(items, item)
+			}
+		}
+	}
+	sort.Sort(byTriageNum(items))
+	return items
+}
+
+func triageOpen(b *lazlo.Broker) []*TriageItem {
+	var open []*TriageItem
+	for _, item := range triageAll(b) {
+		if item.State != triageDone {
+			open = append(open, item)
+		}
+	}
+	return open
+}
+
+func triageFind(b *lazlo.Broker, num int) *TriageItem {
+	for _, item := range triageAll(b) {
+		if item.Num == num {
+			return item
+		}
+	}
+	return nil
+}
+
+// triagePrune forgets items that were closed a while ago
+func triagePrune(b *lazlo.Broker) {
+	for _, item := range triageAll(b) {
+		if item.State == triageDone && time.Since(item.Closed) > triageKeep {
+			b.Brain.Delete(triageKey(item.Ts))
+		}
+	}
+}
+
+func triageNextNum(b *lazlo.Broker) int {
+	num := 1
+	if data, err := b.Brain.Get(`triage:next`); err == nil && data != nil {
+		num, _ = strconv.Atoi(string(data))
+		if num < 1 {
+			num = 1
+		}
+	}
+	b.Brain.Set(`triage:next`, []byte(strconv.Itoa(num+1)))
+	return num
+}
+
+func triageFormat(b *lazlo.Broker, channel string, items []*TriageItem) string {
+	lines := []string{fmt.Sprintf("%d unhandled in triage:", len(items))}
+	for _, item := range items {
+		text := lazlo.TruncateWidth(item.Text, 60, `...`)
+		line := fmt.Sprintf("#%d :%s: <%s|%s> (%s old)", item.Num, item.State, b.Permalink(channel, item.Ts), text, triageAge(item.Posted))
+		if item.State == triageClaimed {
+			line += fmt.Sprintf(" claimed by <@%s>", item.Claimer)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func triageAge(t time.Time) string {
+	age := time.Since(t)
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm", int(age.Minutes()))
+	case age < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd", int(age.Hours()/24))
+	}
+}
+
+type byTriageNum []*TriageItem
+
+func (s byTriageNum) Len() int           { return len(s) }
+func (s byTriageNum) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s byTriageNum) Less(i, j int) bool { return s[i].Num < s[j].Num }
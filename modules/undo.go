@@ -0,0 +1,79 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Undo takes back destructive commands that modules made undoable (see
+// lazlo.Broker.Undoable), after asking the person who ran them to confirm.
+var Undo = &lazlo.Module{
+	Name: `Undo`,
+	Usage: `"!undo [n]" : takes back the last thing (or the last n things) you did in here that can be undone, once you confirm
+"!undo list" : lists what you can undo in here, and for how long`,
+	Run: undoRun,
+}
+
+func undoRun(b *lazlo.Broker) {
+	undo := b.MessageCallback(`(?i)^!undo( \d+)?$`, false)
+	list := b.MessageCallback(`(?i)^!undo list$`, false)
+	b.Ready()
+	for {
+		select {
+		case pm := <-undo.Chan:
+			// confirming blocks until they answer
+			go undoSteps(b, pm)
+		case pm := <-list.Chan:
+			actions := b.Undos(pm.Event.User, pm.Event.Channel)
+			if actions == nil {
+				pm.Event.Reply(`there's nothing you can undo in here`)
+				continue
+			}
+			lines := []string{`you can undo, latest first:`}
+			for i, u := range actions {
+				lines = append(lines, fmt.Sprintf("%d. %s (at %s, until %s)", i+1, u, u.Done.Format(time.Kitchen), u.Expires.Format(time.Kitchen)))
+			}
+			pm.Event.Reply(strings.Join(lines, "\n"))
+		}
+	}
+}
+
+// undoSteps undoes the user's last n actions in the channel, latest first,
+// stopping at the first that can't be undone
+func undoSteps(b *lazlo.Broker, pm lazlo.PatternMatch) {
+	n := 1
+	if pm.Match[1] != `` {
+		n, _ = strconv.Atoi(strings.TrimSpace(pm.Match[1]))
+	}
+	actions := b.Undos(pm.Event.User, pm.Event.Channel)
+	if actions == nil {
+		pm.Event.Reply(`there's nothing you can undo in here`)
+		return
+	}
+	if n < 1 || n > len(actions) {
+		pm.Event.Reply(fmt.Sprintf("you can only undo %d things in here (!undo list shows them)", len(actions)))
+		return
+	}
+	actions = actions[:n]
+	var what []string
+	for _, u := range actions {
+		what = append(what, u.String())
+	}
+	if !b.Confirm(pm.Event.User, fmt.Sprintf("Undo %s?", strings.Join(what, `, then `))) {
+		pm.Event.Reply(`ok, I won't undo anything`)
+		return
+	}
+	for i, u := range actions {
+		if err := b.Undo(u); err != nil {
+			pm.Event.Reply(fmt.Sprintf("I couldn't undo %s: %s", u, err))
+			if rest := len(actions) - i - 1; rest > 0 {
+				pm.Event.Reply(fmt.Sprintf("so I stopped there, and didn't undo the %d before it", rest))
+			}
+			return
+		}
+		pm.Event.Reply(fmt.Sprintf("undid %s", u))
+	}
+}
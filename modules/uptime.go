@@ -0,0 +1,483 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"github.com/gorhill/cronexpr"
+	"html/template"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Uptime checks the HTTP(S) URLs and TCP ports in LAZLO_UPTIME_CHECKS (and
+// the registry's check entities) on the LAZLO_UPTIME_SCHEDULE schedule, says
+// so when one goes down or comes back up, and keeps uptime stats in the
+// brain. The stats are on a status page at /linkcb/uptime.
+var Uptime = &lazlo.Module{
+	Name: `Uptime`,
+	Usage: `"!status" : shows whether everything I'm checking is up
+"!status <check>" : shows a check's state, response time and uptime`,
+	Run: uptimeRun,
+}
+
+// the default schedule: every minute
+const uptimeScheduleDefault = `0 * * * * * *`
+
+// how long a check gets to answer
+const uptimeTimeout = 10 * time.Second
+
+// how many days of stats we keep
+const uptimeDays = 90
+
+// An UptimeCheck is something to check: an http(s) URL (which is up if it
+// answers with the Expect status, 2xx or 3xx by default, and Contains the
+// given text) or a tcp://host:port (which is up if it takes connections)
+type UptimeCheck struct {
+	Name     string
+	URL      string
+	Expect   int
+	Contains string
+	Channel  string // where to say it's down (LAZLO_UPTIME_CHANNEL if empty)
+}
+
+// An UptimeDay counts a day's checks, and how many were up
+type UptimeDay struct {
+	Date   string
+	Checks int
+	Up     int
+}
+
+// UptimeStats is what we know about a check, kept in the brain
+type UptimeStats struct {
+	Name     string
+	Up       bool
+	Since    time.Time // when it went up or down
+	Failures int       // failed checks in a row
+	Checked  time.Time
+	Latency  time.Duration
+	Err      string // why the last check failed
+	Days     []UptimeDay
+}
+
+// an uptimeResult is how one check went
+type uptimeResult struct {
+	check   *UptimeCheck
+	at      time.Time
+	latency time.Duration
+	err     error
+}
+
+func uptimeRun(b *lazlo.Broker) {
+	schedule := b.Config.UptimeSchedule
+	if schedule == `` {
+		schedule = uptimeScheduleDefault
+	}
+	if _, err := cronexpr.Parse(schedule); err != nil {
+		lazlo.Logger.Error(`Uptime:: bad LAZLO_UPTIME_SCHEDULE `, schedule, `: `, err)
+		return
+	}
+	list := b.MessageCallback(`(?i)^!status$`, false)
+	show := b.MessageCallback(`(?i)^!status ([\w.-]+)$`, false)
+	page := b.LinkCallback(`uptime`, func(res http.ResponseWriter, req *http.Request) {
+		uptimePage(b, res, req)
+	})
+	timer := b.TimerCallback(schedule)
+	results := make(chan []uptimeResult)
+	checking := false
+	b.Ready()
+	for {
+		select {
+		case <-timer.Chan:
+			checks := uptimeChecks(b)
+			if checking || len(checks) == 0 {
+				continue
+			}
+			checking = true
+			go func() { results <- uptimeCheckAll(checks) }()
+		case done := <-results:
+			checking = false
+			for _, result := range done {
+				uptimeRecord(b, result)
+			}
+		case pm := <-list.Chan:
+			checks := uptimeChecks(b)
+			if len(checks) == 0 {
+				pm.Event.Reply(`I'm not checking anything (see LAZLO_UPTIME_CHECKS)`)
+				continue
+			}
+			r := uptimeList(b, pm.Event, checks)
+			if page != nil {
+				r.Text = page.URL
+			}
+			pm.Event.RespondResult(r)
+		case pm := <-show.Chan:
+			check := uptimeChecks(b)[strings.ToLower(pm.Match[1])]
+			if check == nil {
+				pm.Event.Reply(fmt.Sprintf("I'm not checking %s", pm.Match[1]))
+				continue
+			}
+			pm.Event.RespondResult(uptimeShow(b, pm.Event, check))
+		}
+	}
+}
+
+// uptimeChecks returns the checks in LAZLO_UPTIME_CHECKS and the registry
+// (which wins), by name
+func uptimeChecks(b *lazlo.Broker) map[string]*UptimeCheck {
+	checks := make(map[string]*UptimeCheck)
+	for _, item := range strings.Split(b.Config.UptimeChecks, `,`) {
+		parts := strings.SplitN(strings.TrimSpace(item), `=`, 2)
+		if len(parts) != 2 {
+			continue
+		}
+		name := strings.ToLower(parts[0])
+		checks[name] = &UptimeCheck{Name: name, URL: parts[1]}
+	}
+	if b.Registry != nil {
+		for _, e := range b.Registry.List(`check`) {
+			check := &UptimeCheck{
+				Name:     strings.ToLower(e.Name),
+				URL:      e.Attrs[`url`],
+				Contains: e.Attrs[`contains`],
+				Channel:  e.Attrs[`channel`],
+			}
+			check.Expect, _ = strconv.Atoi(e.Attrs[`expect`])
+			checks[check.Name] = check
+		}
+	}
+	return checks
+}
+
+// uptimeCheckAll runs the checks at once
+func uptimeCheckAll(checks map[string]*UptimeCheck) []uptimeResult {
+	done := make(chan uptimeResult)
+	for _, check := range checks {
+		go func(check *UptimeCheck) {
+			start := time.Now()
+			err := uptimeProbe(check)
+			done <- uptimeResult{check: check, at: start, latency: time.Since(start), err: err}
+		}(check)
+	}
+	var results []uptimeResult
+	for range checks {
+		results = append(results, <-done)
+	}
+	return results
+}
+
+var uptimeClient = &http.Client{Timeout: uptimeTimeout}
+
+// uptimeProbe returns why the check is down, or nil if it's up
+func uptimeProbe(check *UptimeCheck) error {
+	u, err := url.Parse(check.URL)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case `tcp`:
+		conn, err := net.DialTimeout(`tcp`, u.Host, uptimeTimeout)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	case `http`, `https`:
+	default:
+		return fmt.Errorf("can't check %s URLs", u.Scheme)
+	}
+	req, err := http.NewRequest(`GET`, check.URL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(`User-Agent`, `lazlo-uptime`)
+	res, err := uptimeClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if check.Expect != 0 && res.StatusCode != check.Expect {
+		return fmt.Errorf("got %s, wanted %d", res.Status, check.Expect)
+	}
+	if check.Expect == 0 && res.StatusCode >= 400 {
+		return fmt.Errorf("got %s", res.Status)
+	}
+	if check.Contains != `` {
+		body, err := ioutil.ReadAll(io.LimitReader(res.Body, 1<<20))
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(body), check.Contains) {
+			return fmt.Errorf("the page doesn't say %q", check.Contains)
+		}
+	}
+	return nil
+}
+
+func uptimeKey(name string) string {
+	return `uptime:` + name
+}
+
+func uptimeGet(b *lazlo.Broker, name string) *UptimeStats {
+	stats := &UptimeStats{Name: name, Up: true}
+	if data, err := b.Brain.Get(uptimeKey(name)); err == nil && data != nil {
+		json.Unmarshal(data, stats)
+	}
+	return stats
+}
+
+func uptimeSave(b *lazlo.Broker, stats *UptimeStats) {
+	data, _ := json.Marshal(stats)
+	if err := b.Brain.Set(uptimeKey(stats.Name), data); err != nil {
+		lazlo.Logger.Error(`Uptime:: couldn't save stats for `, stats.Name, `: `, err)
+	}
+}
+
+// uptimeRecord adds the result to the check's stats, saying so if the check
+// went down (after LAZLO_UPTIME_FAILURES failures in a row) or came back up
+func uptimeRecord(b *lazlo.Broker, result uptimeResult) {
+	stats := uptimeGet(b, result.check.Name)
+	if stats.Since.IsZero() {
+		stats.Since = result.at
+	}
+	stats.Checked = result.at
+	date := result.at.Format(`2006-01-02`)
+	if n := len(stats.Days); n == 0 || stats.Days[n-1].Date != date {
+		stats.Days = append(stats.Days, UptimeDay{Date: date})
+		if len(stats.Days) > uptimeDays {
+			stats.Days = stats.Days[len(stats.Days)-uptimeDays:]
+		}
+	}
+	day := &stats.Days[len(stats.Days)-1]
+	day.Checks++
+
+	channel := result.check.Channel
+	if channel == `` {
+		channel = b.Config.UptimeChannel
+	}
+	if channel = strings.TrimPrefix(channel, `#`); channel == `` {
+		channel = b.DefaultChannel()
+	} else if c := b.SlackMeta.GetChannelByName(channel); c != nil {
+		channel = c.ID
+	}
+	l := b.Locale(``, channel)
+
+	if result.err == nil {
+		day.Up++
+		stats.Latency = result.latency
+		stats.Failures, stats.Err = 0, ``
+		if !stats.Up {
+			b.Say(fmt.Sprintf(":white_check_mark: %s is back up (it was down for %s)", stats.Name, l.Duration(result.at.Sub(stats.Since))), channel)
+			stats.Up, stats.Since = true, result.at
+		}
+		uptimeSave(b, stats)
+		return
+	}
+	stats.Failures++
+	stats.Err = result.err.Error()
+	if stats.Up && stats.Failures >= b.Config.UptimeFailures {
+		b.Say(fmt.Sprintf(":rotating_light: %s is down: %s", stats.Name, stats.Err), channel)
+		stats.Up, stats.Since = false, result.at
+	}
+	uptimeSave(b, stats)
+}
+
+// uptimePercent is the percentage of the last days' checks that were up,
+// or -1 if there weren't any
+func uptimePercent(stats *UptimeStats, days int) float64 {
+	checks, up := 0, 0
+	cutoff := time.Now().AddDate(0, 0, -days).Format(`2006-01-02`)
+	for _, day := range stats.Days {
+		if day.Date > cutoff {
+			checks += day.Checks
+			up += day.Up
+		}
+	}
+	if checks == 0 {
+		return -1
+	}
+	return 100 * float64(up) / float64(checks)
+}
+
+func uptimeFormatPercent(l *lazlo.Locale, percent float64) string {
+	if percent < 0 {
+		return `-`
+	}
+	return l.Number(percent, 2) + `%`
+}
+
+func uptimeState(stats *UptimeStats) string {
+	if stats.Checked.IsZero() {
+		return `not checked yet`
+	}
+	if stats.Up {
+		return `up`
+	}
+	return `down`
+}
+
+func uptimeList(b *lazlo.Broker, e *lazlo.Event, checks map[string]*UptimeCheck) *lazlo.Result {
+	l := b.Locale(e.User, e.Channel)
+	r := &lazlo.Result{Title: `Status`, Status: lazlo.ResultOK}
+	r.Table = &lazlo.ResultTable{Header: []string{`Check`, `State`, `Since`, `24h`, `30d`}}
+	for _, name := range uptimeNames(checks) {
+		stats := uptimeGet(b, name)
+		if !stats.Up {
+			r.Status = lazlo.ResultError
+		}
+		since := ``
+		if !stats.Checked.IsZero() {
+			since = l.Ago(stats.Since)
+		}
+		r.Row(name, uptimeState(stats), since, uptimeFormatPercent(l, uptimePercent(stats, 1)), uptimeFormatPercent(l, uptimePercent(stats, 30)))
+	}
+	return r
+}
+
+func uptimeShow(b *lazlo.Broker, e *lazlo.Event, check *UptimeCheck) *lazlo.Result {
+	l := b.Locale(e.User, e.Channel)
+	stats := uptimeGet(b, check.Name)
+	r := &lazlo.Result{Title: check.Name, Status: lazlo.ResultOK}
+	if !stats.Up {
+		r.Status = lazlo.ResultError
+	}
+	r.Field(`State`, uptimeState(stats))
+	if !stats.Checked.IsZero() {
+		r.Field(`Since`, l.Ago(stats.Since))
+		r.Field(`Last checked`, l.Ago(stats.Checked))
+	}
+	if stats.Latency > 0 {
+		r.Field(`Response time`, stats.Latency.String())
+	}
+	r.Field(`Uptime (24h)`, uptimeFormatPercent(l, uptimePercent(stats, 1)))
+	r.Field(`Uptime (7d)`, uptimeFormatPercent(l, uptimePercent(stats, 7)))
+	r.Field(`Uptime (30d)`, uptimeFormatPercent(l, uptimePercent(stats, 30)))
+	if stats.Err != `` {
+		if stats.Up {
+			r.Status = lazlo.ResultWarning
+		}
+		r.Text = `last check failed: ` + stats.Err
+	}
+	return r
+}
+
+func uptimeNames(checks map[string]*UptimeCheck) []string {
+	var names []string
+	for name := range checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// the status page
+var uptimeTemplate = template.Must(template.New(`uptime`).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="60">
+<title>Status</title>
+<style>
+body { font-family: sans-serif; max-width: 50em; margin: 2em auto; color: #333; }
+.check { border-bottom: 1px solid #ddd; padding: 1em 0; }
+.state { float: right; font-weight: bold; }
+.up { color: #2a2; } .down { color: #c22; } .unknown { color: #999; }
+.days { display: flex; margin-top: .5em; }
+.day { flex: 1; height: 2em; margin-right: 1px; background: #ddd; }
+.day.up { background: #2a2; } .day.degraded { background: #e90; } .day.down { background: #c22; }
+.uptime { color: #777; font-size: small; }
+</style>
+</head>
+<body>
+<h1>Status</h1>
+{{range .}}<div class="check">
+<span class="state {{.Class}}">{{.State}}</span>
+<strong>{{.Name}}</strong>
+<div class="days">{{range .Days}}<div class="day {{.Class}}" title="{{.Date}}: {{.Percent}}"></div>{{end}}</div>
+<div class="uptime">24h: {{.Day}} &middot; 7d: {{.Week}} &middot; 30d: {{.Month}} &middot; 90d: {{.Quarter}}</div>
+</div>
+{{else}}<p>Nothing's being checked.</p>
+{{end}}</body>
+</html>
+`))
+
+type uptimePageDay struct {
+	Date    string
+	Class   string
+	Percent string
+}
+
+type uptimePageCheck struct {
+	Name    string
+	State   string
+	Class   string
+	Day     string
+	Week    string
+	Month   string
+	Quarter string
+	Days    []uptimePageDay
+}
+
+// uptimePage serves the status page, or the stats as JSON with ?format=json.
+// It names the checks, but not what they check.
+func uptimePage(b *lazlo.Broker, res http.ResponseWriter, req *http.Request) {
+	checks := uptimeChecks(b)
+	if req.URL.Query().Get(`format`) == `json` {
+		var all []*UptimeStats
+		for _, name := range uptimeNames(checks) {
+			all = append(all, uptimeGet(b, name))
+		}
+		res.Header().Set(`Content-Type`, `application/json`)
+		json.NewEncoder(res).Encode(all)
+		return
+	}
+	l := b.Locale(``, ``)
+	var page []uptimePageCheck
+	for _, name := range uptimeNames(checks) {
+		stats := uptimeGet(b, name)
+		check := uptimePageCheck{
+			Name:    name,
+			State:   uptimeState(stats),
+			Class:   uptimeState(stats),
+			Day:     uptimeFormatPercent(l, uptimePercent(stats, 1)),
+			Week:    uptimeFormatPercent(l, uptimePercent(stats, 7)),
+			Month:   uptimeFormatPercent(l, uptimePercent(stats, 30)),
+			Quarter: uptimeFormatPercent(l, uptimePercent(stats, uptimeDays)),
+		}
+		if stats.Checked.IsZero() {
+			check.Class = `unknown`
+		}
+		byDate := make(map[string]UptimeDay)
+		for _, day := range stats.Days {
+			byDate[day.Date] = day
+		}
+		for i := uptimeDays - 1; i >= 0; i-- {
+			date := time.Now().AddDate(0, 0, -i).Format(`2006-01-02`)
+			day := uptimePageDay{Date: date, Percent: `no data`}
+			if d, ok := byDate[date]; ok && d.Checks > 0 {
+				percent := 100 * float64(d.Up) / float64(d.Checks)
+				day.Percent = uptimeFormatPercent(l, percent)
+				switch {
+				case percent >= 99.9:
+					day.Class = `up`
+				case percent >= 95:
+					day.Class = `degraded`
+				default:
+					day.Class = `down`
+				}
+			}
+			check.Days = append(check.Days, day)
+		}
+		page = append(page, check)
+	}
+	res.Header().Set(`Content-Type`, `text/html; charset=utf-8`)
+	if err := uptimeTemplate.Execute(res, page); err != nil {
+		lazlo.Logger.Error(`Uptime:: couldn't render the status page: `, err)
+	}
+}
@@ -0,0 +1,164 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+var Vacation = &lazlo.Module{
+	Name: `Vacation`,
+	Usage: `"%BOTNAME% ooo until YYYY-MM-DD [delegate @user]" : you're out from today until then (and who to ask instead)
+"%BOTNAME% ooo from YYYY-MM-DD until YYYY-MM-DD [delegate @user]" : you'll be out later on
+"%BOTNAME% ooo" : shows when you're out
+"%BOTNAME% back" : you're not out after all
+"%BOTNAME% who's out" : lists everyone who's out or about to be`,
+	Run: vacationRun,
+}
+
+// The away period is kept in the away_from, away_until and delegate prefs, so
+// other modules (like Rotation) can skip people who are out with
+// lazlo.Broker.IsAway.
+
+var vacationMention = regexp.MustCompile(`<@(U\w+)(?:\|[^>]*)?>`)
+
+func vacationRun(b *lazlo.Broker) {
+	set := b.MessageCallback(`(?i)ooo (?:from (\d{4}-\d{2}-\d{2}) )?(?:until|to) (\d{4}-\d{2}-\d{2})(?: delegate <@(\w+)(?:\|[^>]*)?>)?$`, true)
+	show := b.MessageCallback(`(?i)ooo$`, true)
+	back := b.MessageCallback(`(?i)(?:i'?m )?back$`, true)
+	list := b.MessageCallback(`(?i)who'?s (?:out|ooo)\??$`, true)
+	messages := b.MessageCallback(`<@U`, false)
+
+	// we only tell each channel about each person once a day
+	told := make(map[string]string)
+	b.Ready()
+	for {
+		select {
+		case pm := <-set.Chan:
+			from, until, delegate := pm.Match[1], pm.Match[2], pm.Match[3]
+			if err := vacationCheck(b.Locale(pm.Event.User, pm.Event.Channel), from, until); err != nil {
+				pm.Event.Reply(err.Error())
+				continue
+			}
+			user := pm.Event.User
+			b.SetPref(user, `away_from`, from)
+			b.SetPref(user, `away_until`, until)
+			b.SetPref(user, `delegate`, delegate)
+			pm.Event.Reply(`Ok, ` + vacationDescribe(b, user, b.Locale(user, pm.Event.Channel)) + `. Enjoy!`)
+		case pm := <-show.Chan:
+			if b.GetPref(pm.Event.User, `away_until`) == `` {
+				pm.Event.Reply(`you're not out (as far as I know)`)
+				continue
+			}
+			pm.Event.Reply(vacationDescribe(b, pm.Event.User, b.Locale(pm.Event.User, pm.Event.Channel)))
+		case pm := <-back.Chan:
+			user := pm.Event.User
+			if b.GetPref(user, `away_until`) == `` {
+				pm.Event.Reply(`I didn't know you were gone!`)
+				continue
+			}
+			vacationClear(b, user)
+			pm.Event.Reply(`welcome back!`)
+		case pm := <-list.Chan:
+			var lines []string
+			l := b.Locale(pm.Event.User, pm.Event.Channel)
+			for _, user := range vacationUsers(b) {
+				lines = append(lines, b.SlackMeta.GetUserName(user)+`: `+vacationDescribe(b, user, l))
+			}
+			if lines == nil {
+				pm.Event.Respond(`Everyone's in`)
+				continue
+			}
+			sort.Strings(lines)
+			pm.Event.Respond(strings.Join(lines, "\n"))
+		case pm := <-messages.Chan:
+			e := pm.Event
+			if e.Subtype != `` || e.User == `` || e.User == b.SlackMeta.Self.ID {
+				continue
+			}
+			today := time.Now().Format(`2006-01-02`)
+			for _, m := range vacationMention.FindAllStringSubmatch(e.Text, -1) {
+				user := m[1]
+				if user == e.User || !b.IsAway(user) || told[e.Channel+`:`+user] == today {
+					continue
+				}
+				told[e.Channel+`:`+user] = today
+				e.RespondInThread(fmt.Sprintf("Heads up: %s is out of the office until %s",
+					b.SlackMeta.GetUserName(user), vacationDate(b.Locale(``, e.Channel), b.GetPref(user, `away_until`))) + vacationDelegate(b, user))
+			}
+		}
+	}
+}
+
+// vacationCheck makes sure the dates parse, are in order, and aren't over
+func vacationCheck(l *lazlo.Locale, from, until string) error {
+	end, err := time.ParseInLocation(`2006-01-02`, until, l.Location)
+	if err != nil {
+		return fmt.Errorf("%s isn't a date I understand", until)
+	}
+	if until < time.Now().Format(`2006-01-02`) {
+		return fmt.Errorf("%s has already come and gone", l.Day(end))
+	}
+	if from == `` {
+		return nil
+	}
+	if _, err := time.Parse(`2006-01-02`, from); err != nil {
+		return fmt.Errorf("%s isn't a date I understand", from)
+	}
+	if from > until {
+		return fmt.Errorf("you can't come back before you leave")
+	}
+	return nil
+}
+
+func vacationDate(l *lazlo.Locale, day string) string {
+	t, err := time.ParseInLocation(`2006-01-02`, day, l.Location)
+	if err != nil {
+		return day
+	}
+	return l.Day(t)
+}
+
+func vacationDelegate(b *lazlo.Broker, user string) string {
+	if delegate := b.GetPref(user, `delegate`); delegate != `` {
+		return fmt.Sprintf(" (<@%s> is covering)", delegate)
+	}
+	return ``
+}
+
+func vacationDescribe(b *lazlo.Broker, user string, l *lazlo.Locale) string {
+	s := `out until ` + vacationDate(l, b.GetPref(user, `away_until`))
+	if from := b.GetPref(user, `away_from`); from != `` && from > time.Now().Format(`2006-01-02`) {
+		s = `out from ` + vacationDate(l, from) + ` until ` + vacationDate(l, b.GetPref(user, `away_until`))
+	}
+	return s + vacationDelegate(b, user)
+}
+
+func vacationClear(b *lazlo.Broker, user string) {
+	for _, pref := range []string{`away_from`, `away_until`, `delegate`} {
+		b.SetPref(user, pref, ``)
+	}
+}
+
+// vacationUsers returns everyone who's out or going to be, and forgets the
+// away periods that are over
+func vacationUsers(b *lazlo.Broker) []string {
+	keys, _ := b.Brain.Keys()
+	today := time.Now().Format(`2006-01-02`)
+	var users []string
+	for _, key := range keys {
+		parts := strings.Split(key, `:`)
+		if len(parts) != 3 || parts[0] != `prefs` || parts[2] != `away_until` {
+			continue
+		}
+		if b.GetPref(parts[1], `away_until`) < today {
+			vacationClear(b, parts[1])
+			continue
+		}
+		users = append(users, parts[1])
+	}
+	return users
+}
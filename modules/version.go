@@ -0,0 +1,87 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"sort"
+	"strings"
+)
+
+// Version tells people what lazlo's running. The same thing's available as
+// json at /health.
+var Version = &lazlo.Module{
+	Name: `Version`,
+	Usage: `"%BOTNAME% version" : what version of lazlo this is, how long it's been up, and what it's running
+"!version" : same thing
+"!modules" : every module and plugin, and which of them use deprecated APIs`,
+	Run:          versionRun,
+	ExternalSafe: true,
+}
+
+func versionRun(b *lazlo.Broker) {
+	respond := b.MessageCallback(`(?i)(?:version|build info)$`, true)
+	bang := b.MessageCallback(`^!version$`, false)
+	modules := b.MessageCallback(`^!modules$`, false)
+	b.Ready()
+	for {
+		select {
+		case pm := <-respond.Chan:
+			pm.Event.Respond(b.BuildInfo().String())
+		case pm := <-bang.Chan:
+			pm.Event.Respond(b.BuildInfo().String())
+		case pm := <-modules.Chan:
+			pm.Event.Respond(modulesReport(b.BuildInfo()))
+		}
+	}
+}
+
+// modulesReport lists the modules and plugins with their versions, and
+// what each is still using that's deprecated
+func modulesReport(bi *lazlo.BuildInfo) string {
+	uses := make(map[string][]string)
+	for _, d := range bi.Deprecations {
+		use := d.API
+		if d.Replacement != `` {
+			use += ` (use ` + d.Replacement + `)`
+		}
+		uses[d.Plugin] = append(uses[d.Plugin], use)
+	}
+	line := func(name string, version string) string {
+		s := `  ` + name
+		if version != `` {
+			s += ` ` + version
+		}
+		if list, ok := uses[name]; ok {
+			s += `: uses deprecated ` + strings.Join(list, `, `)
+		}
+		return s
+	}
+	var modules, plugins []string
+	for name, version := range bi.Modules {
+		modules = append(modules, line(name, version))
+		delete(uses, name)
+	}
+	for name, version := range bi.Plugins {
+		plugins = append(plugins, line(name, version))
+		delete(uses, name)
+	}
+	// uses from code that isn't a module or a plugin
+	var others []string
+	for name, list := range uses {
+		others = append(others, fmt.Sprintf("  %s: uses deprecated %s", name, strings.Join(list, `, `)))
+	}
+	sort.Strings(modules)
+	sort.Strings(plugins)
+	sort.Strings(others)
+	lines := append([]string{`modules:`}, modules...)
+	if len(plugins) > 0 {
+		lines = append(append(lines, `plugins:`), plugins...)
+	}
+	if len(others) > 0 {
+		lines = append(append(lines, `elsewhere:`), others...)
+	}
+	if len(bi.Deprecations) == 0 {
+		lines = append(lines, `nothing is using a deprecated API`)
+	}
+	return strings.Join(lines, "\n")
+}
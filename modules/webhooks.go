@@ -0,0 +1,64 @@
+package modules
+
+import (
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"strconv"
+	"strings"
+)
+
+var Webhooks = &lazlo.Module{
+	Name: `Webhooks`,
+	Usage: `"%BOTNAME% webhooks [N]" : (admins only) lists the last N webhook requests lazlo received
+"%BOTNAME% webhook <id>" : (admins only) shows the headers and body of a webhook request`,
+	Run: webhooksRun,
+}
+
+func webhooksRun(b *lazlo.Broker) {
+	list := b.MessageCallback(`(?i)webhooks *(\d*)$`, true)
+	show := b.MessageCallback(`(?i)webhook #?(\d+)$`, true)
+	b.Ready()
+	for {
+		select {
+		case pm := <-list.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can look at webhooks`)
+				continue
+			}
+			n, err := strconv.Atoi(pm.Match[1])
+			if err != nil {
+				n = 5
+			}
+			traces := b.Traces(n)
+			if len(traces) == 0 {
+				pm.Event.Reply(`I haven't seen any webhooks lately`)
+				continue
+			}
+			var lines []string
+			for _, t := range traces {
+				lines = append(lines, t.Summary())
+			}
+			pm.Event.Respond("```" + strings.Join(lines, "\n") + "```")
+		case pm := <-show.Chan:
+			if !b.IsAdmin(pm.Event.User) {
+				pm.Event.Reply(`Sorry, only admins can look at webhooks`)
+				continue
+			}
+			id, _ := strconv.Atoi(pm.Match[1])
+			t := b.GetTrace(id)
+			if t == nil {
+				pm.Event.Reply(fmt.Sprintf("Sorry, I don't have webhook #%d anymore", id))
+				continue
+			}
+			b.Say(formatTrace(t), b.GetDM(pm.Event.User))
+		}
+	}
+}
+
+func formatTrace(t *lazlo.Trace) string {
+	out := t.Summary()
+	for key, vals := range t.Header {
+		out = fmt.Sprintf("%s\n%s: %s", out, key, strings.Join(vals, `, `))
+	}
+	return fmt.Sprintf("```%s\n\n%s\n\nresponse:\n%s```\nreplay it with: lazlo replay %d", out, t.Body, t.Response, t.ID)
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"github.com/djosephsen/hustlebot/modules"
+	"os"
+)
+
+// plugin runs the specs for a directory of lua plugins (see docs/lua.md) and
+// reports which of the plugins' patterns they exercised, or lints them.
+//
+//   lazlo plugin test [-min 80] <lua dir>
+//   lazlo plugin lint [-strict] <lua dir or file>
+func plugin(args []string) error {
+	if len(args) > 0 && args[0] == `lint` {
+		return pluginLint(args[1:])
+	}
+	if len(args) == 0 || args[0] != `test` {
+		return fmt.Errorf("usage: lazlo plugin test [-min <percent>] <lua dir>\n       lazlo plugin lint [-strict] <lua dir or file>")
+	}
+	flags := flag.NewFlagSet(`plugin test`, flag.ExitOnError)
+	min := flags.Int(`min`, 0, `fail unless the specs cover at least this percentage of every plugin's patterns`)
+	flags.Parse(args[1:])
+	dir := `lua`
+	if flags.NArg() > 0 {
+		dir = flags.Arg(0)
+	}
+
+	report, err := modules.RunLuaSpecs(dir)
+	if err != nil {
+		return err
+	}
+	fmt.Print(report)
+	if failed := report.Failed(); failed > 0 {
+		return fmt.Errorf("%d of %d tests failed", failed, len(report.Results))
+	}
+	if blocked := report.Gate(*min); len(blocked) > 0 {
+		for name, why := range blocked {
+			fmt.Printf("%s: %s\n", name, why)
+		}
+		return fmt.Errorf("%d plugins aren't covered well enough", len(blocked))
+	}
+	return nil
+}
+
+// pluginLint lints the plugins, failing if any of them have errors (or
+// warnings, with -strict)
+func pluginLint(args []string) error {
+	flags := flag.NewFlagSet(`plugin lint`, flag.ExitOnError)
+	strict := flags.Bool(`strict`, false, `fail on warnings too`)
+	flags.Parse(args)
+	path := `lua`
+	if flags.NArg() > 0 {
+		path = flags.Arg(0)
+	}
+
+	var issues []modules.LuaLintIssue
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		issues, err = modules.LintLuaDir(path)
+	} else {
+		issues, err = modules.LintLuaFile(path)
+	}
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, issue := range issues {
+		fmt.Println(issue)
+		if issue.Error {
+			failed++
+		}
+	}
+	if failed > 0 || (*strict && len(issues) > 0) {
+		return fmt.Errorf("%d errors and %d warnings", failed, len(issues)-failed)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	lazlo "github.com/djosephsen/hustlebot/lib"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// replay re-sends a captured webhook request to a running lazlo. The capture
+// is either a trace ID (fetched from the running lazlo, with the debug token)
+// or a json file.
+//
+//   lazlo replay [-url http://localhost:5000] [-token <debug token>] <trace id|file.json>
+func replay(args []string) error {
+	flags := flag.NewFlagSet(`replay`, flag.ExitOnError)
+	baseURL := flags.String(`url`, `http://localhost:`+os.Getenv(`PORT`), `the lazlo to replay against`)
+	token := flags.String(`token`, os.Getenv(`LAZLO_DEBUG_TOKEN`), `the lazlo's LAZLO_DEBUG_TOKEN`)
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return fmt.Errorf("usage: lazlo replay [-url <lazlo url>] [-token <debug token>] <trace id|file.json>")
+	}
+
+	trace := new(lazlo.Trace)
+	var data []byte
+	if id, err := strconv.Atoi(flags.Arg(0)); err == nil {
+		if *token == `` {
+			return fmt.Errorf("fetching trace %d needs the lazlo's LAZLO_DEBUG_TOKEN (or -token)", id)
+		}
+		req, err := http.NewRequest(`GET`, fmt.Sprintf("%s/debug/webhooks/%d", *baseURL, id), nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set(`Authorization`, `Bearer `+*token)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("couldn't fetch trace %d: %s", id, resp.Status)
+		}
+		if data, err = ioutil.ReadAll(resp.Body); err != nil {
+			return err
+		}
+	} else if data, err = ioutil.ReadFile(flags.Arg(0)); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, trace); err != nil {
+		return fmt.Errorf("couldn't decode trace: %v", err)
+	}
+
+	resp, err := lazlo.ReplayTrace(trace, *baseURL)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+	fmt.Printf("replayed %s %s: %s\n%s", trace.Method, trace.URI(), resp.Status, body)
+	return nil
+}